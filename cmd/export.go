@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"github.com/CaelRowley/geth-indexer-service/pkg/export"
+)
+
+// runExport implements the export subcommand, writing one file per table and
+// block range partition into the output directory.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	from := fs.Uint64("from", 0, "First block number to export")
+	to := fs.Uint64("to", 0, "Last block number to export")
+	format := fs.String("format", string(export.FormatParquet), "Output format: csv, ndjson or parquet")
+	tables := fs.String("tables", "blocks,transactions,logs", "Comma separated tables to export")
+	out := fs.String("out", "export", "Output directory")
+	partitionSize := fs.Uint64("partition-size", export.DefaultPartitionSize, "Number of blocks per output file")
+	chainID := fs.Uint64("chain", 0, "Chain id to export, 0 exports the rows indexed before migration 15 that weren't assigned a chain")
+	fs.Parse(args)
+
+	if *to < *from {
+		return errors.New("-to must not be less than -from")
+	}
+	f, err := export.ParseFormat(*format)
+	if err != nil {
+		return err
+	}
+	var ts []export.Table
+	for _, name := range strings.Split(*tables, ",") {
+		t, err := export.ParseTable(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+		ts = append(ts, t)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dbConn, err := db.NewConnection(os.Getenv("DB_URL"), db.Config{})
+	if err != nil {
+		return err
+	}
+	defer dbConn.Close()
+
+	files, err := export.WriteDir(ctx, dbConn.ForChain(*chainID), *out, ts, f, *from, *to, *partitionSize)
+	if err != nil {
+		return fmt.Errorf("failed to export: %w", err)
+	}
+	slog.Info("export complete", "files", len(files), "dir", *out)
+	return nil
+}
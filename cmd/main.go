@@ -7,8 +7,12 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/CaelRowley/geth-indexer-service/pkg/eth"
+	"github.com/CaelRowley/geth-indexer-service/pkg/retention"
 	"github.com/CaelRowley/geth-indexer-service/pkg/server"
+	"github.com/CaelRowley/geth-indexer-service/pkg/stats"
 	"github.com/joho/godotenv"
 )
 
@@ -18,12 +22,52 @@ func main() {
 		os.Exit(1)
 	}
 
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "export":
+			if err := runExport(os.Args[2:]); err != nil {
+				slog.Error("export failed", "err", err)
+				os.Exit(1)
+			}
+			return
+		case "migrate":
+			if err := runMigrate(os.Args[2:]); err != nil {
+				slog.Error("migrate failed", "err", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	var serverCfg server.ServerConfig
+	var chainsPath string
 	flag.StringVar(&serverCfg.Port, "port", "8080", "Port where the service will run")
 	flag.BoolVar(&serverCfg.Sync, "sync", false, "Sync blocks on node with db")
+	flag.Uint64Var(&serverCfg.Retention.Blocks, "retention-blocks", 0, "Only keep the most recent N blocks, 0 keeps all")
+	flag.Uint64Var(&serverCfg.Retention.Days, "retention-days", 0, "Only keep blocks from the last N days, 0 keeps all")
+	flag.DurationVar(&serverCfg.PruneInterval, "prune-interval", retention.DefaultInterval, "How often blocks outside the retention window are deleted")
+	flag.DurationVar(&serverCfg.StatsInterval, "stats-interval", stats.DefaultInterval, "How often the hourly and daily statistics are rolled up")
+	flag.StringVar(&serverCfg.Tracer, "tracer", eth.TracerNone, `Index internal transactions with "debug" (debug_traceBlockByHash) or "trace" (trace_block), empty disables tracing`)
+	flag.IntVar(&serverCfg.DB.MaxOpenConns, "db-max-open-conns", 0, "Maximum open connections per database pool, 0 is unlimited")
+	flag.IntVar(&serverCfg.DB.MaxIdleConns, "db-max-idle-conns", 0, "Maximum idle connections per database pool, 0 keeps the default of 2")
+	flag.DurationVar(&serverCfg.DB.ConnMaxLifetime, "db-conn-max-lifetime", 0, "Maximum time a database connection is reused, 0 is unlimited")
+	flag.DurationVar(&serverCfg.DB.ConnMaxIdleTime, "db-conn-max-idle-time", 0, "Maximum time a database connection stays idle, 0 is unlimited")
+	flag.DurationVar(&serverCfg.DB.Timeouts.Read, "db-read-timeout", 30*time.Second, "Timeout for database queries, 0 disables it")
+	flag.DurationVar(&serverCfg.DB.Timeouts.Write, "db-write-timeout", 30*time.Second, "Timeout for database inserts and deletes, 0 disables it")
+	flag.StringVar(&chainsPath, "chains", "", "JSON file listing the chains to index, replaces NODE_URL, -tracer and the retention flags")
 	flag.Parse()
 
-	slog.Info("flags set", "Port", serverCfg.Port, "Sync", serverCfg.Sync)
+	if chainsPath != "" {
+		chains, err := server.LoadChains(chainsPath)
+		if err != nil {
+			slog.Error("failed to load chains", "err", err)
+			os.Exit(1)
+		}
+		serverCfg.Chains = chains
+	}
+
+	slog.Info("flags set", "Port", serverCfg.Port, "Sync", serverCfg.Sync,
+		"RetentionBlocks", serverCfg.Retention.Blocks, "RetentionDays", serverCfg.Retention.Days, "Tracer", serverCfg.Tracer, "Chains", chainsPath)
 
 	s, err := server.New(serverCfg)
 	if err != nil {
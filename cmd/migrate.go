@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+const migrateUsage = "usage: migrate up | down | status | to <version> | repartition | assign-chain <chainId>"
+
+// runMigrate implements the migrate subcommand.
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	g, err := db.Open(os.Getenv("DB_URL"))
+	if err != nil {
+		return err
+	}
+	defer g.Close()
+	migrator, err := db.NewMigrator(g)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		return migrator.Up()
+	case "down":
+		return migrator.Down()
+	case "to":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %w", args[1], err)
+		}
+		return migrator.To(version)
+	case "repartition":
+		for _, table := range []string{"blocks", "transactions"} {
+			if err := g.Repartition(table); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "assign-chain":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		chainID, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid chain id %q: %w", args[1], err)
+		}
+		return g.AssignChain(chainID)
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+		for _, s := range status {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		return w.Flush()
+	default:
+		return errors.New(migrateUsage)
+	}
+}
@@ -32,7 +32,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := db.Exec("DELETE FROM blocks; DELETE FROM transactions;").Error; err != nil {
+	if err := db.Exec("DELETE FROM blocks; DELETE FROM transactions; DELETE FROM logs;").Error; err != nil {
 		slog.Error("failed to clear tables", "err", err)
 		os.Exit(1)
 	}
@@ -77,17 +77,18 @@ func createTxs() []data.Transaction {
 	var txs []data.Transaction
 	for i := 0; i < TxCount; i++ {
 		newTx := data.Transaction{
-			Hash:      "0x" + fmt.Sprintf("%04d", i) + "efabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
-			From:      "0x000000000000000000000000000000000000" + fmt.Sprintf("%04d", i),
-			Contract:  "0x" + fmt.Sprintf("%04d", i) + "efabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
-			Value:     200 + uint64(i),
-			Data:      []byte("some data " + strconv.Itoa(i)),
-			Gas:       500000 + uint64(i),
-			GasPrice:  1000000 + uint64(i),
-			Cost:      1000000000 + uint64(i),
-			Nonce:     0,
-			Status:    uint64(i),
-			BlockHash: "0x" + fmt.Sprintf("%04d", i%BlockCount) + "efabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+			Hash:        "0x" + fmt.Sprintf("%04d", i) + "efabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+			From:        "0x000000000000000000000000000000000000" + fmt.Sprintf("%04d", i),
+			Contract:    "0x" + fmt.Sprintf("%04d", i) + "efabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+			Value:       200 + uint64(i),
+			Data:        []byte("some data " + strconv.Itoa(i)),
+			Gas:         500000 + uint64(i),
+			GasPrice:    1000000 + uint64(i),
+			Cost:        1000000000 + uint64(i),
+			Nonce:       0,
+			Status:      uint64(i),
+			BlockHash:   "0x" + fmt.Sprintf("%04d", i%BlockCount) + "efabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+			BlockNumber: uint64(i % BlockCount),
 		}
 		if i%2 == 0 {
 			newTx.To = "0x000000000000000000000000000000000000" + fmt.Sprintf("%04d", i+1)
@@ -0,0 +1,115 @@
+// Package client is a typed Go client for the indexer's HTTP API. The
+// operations in client.gen.go are generated from pkg/handlers/openapi.json.
+package client
+
+//go:generate go run ./internal/gen -spec ../handlers/openapi.json -out client.gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// New creates a client for the API served at baseURL. A nil httpClient uses
+// http.DefaultClient.
+func New(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+// ForChain returns a client for the routes of chainID, see GetChains. The
+// routes served only at the root, like GetChains, aren't available on it.
+func (c *Client) ForChain(chainID uint64) *Client {
+	return &Client{
+		baseURL:    fmt.Sprintf("%s/chains/%d", c.baseURL, chainID),
+		httpClient: c.httpClient,
+	}
+}
+
+func (e APIError) Error() string {
+	return fmt.Sprintf("api error: %d: %v", e.StatusCode, e.Msg)
+}
+
+// UnexpectedResponseError is returned for non-2xx responses without an
+// APIError body.
+type UnexpectedResponseError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *UnexpectedResponseError) Error() string {
+	return fmt.Sprintf("unexpected response %d: %s", e.StatusCode, e.Body)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body any, accept string, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(resp.Body)
+		var apiErr APIError
+		if err := json.Unmarshal(respBody, &apiErr); err == nil && apiErr.StatusCode != 0 {
+			return apiErr
+		}
+		return &UnexpectedResponseError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	switch v := out.(type) {
+	case nil:
+		return nil
+	case *string:
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		*v = string(b)
+		return nil
+	default:
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return New(server.URL+"/", nil)
+}
+
+func TestGetBlock(t *testing.T) {
+	c := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/block/get-block/12", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"hash":"0xabc","number":12,"extraData":"AQI="}`))
+	})
+
+	block, err := c.GetBlock(context.Background(), 12)
+	assert.NoError(t, err)
+	assert.Equal(t, &Block{Hash: "0xabc", Number: 12, ExtraData: []byte{1, 2}}, block)
+}
+
+func TestForChain(t *testing.T) {
+	c := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chains/137/block/get-block/12", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"hash":"0xabc","number":12}`))
+	})
+
+	block, err := c.ForChain(137).GetBlock(context.Background(), 12)
+	assert.NoError(t, err)
+	assert.Equal(t, &Block{Hash: "0xabc", Number: 12}, block)
+}
+
+func TestGetTxsAPIError(t *testing.T) {
+	c := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"statusCode":500,"msg":"interal server error"}`))
+	})
+
+	txs, err := c.GetTxs(context.Background(), nil)
+	assert.Nil(t, txs)
+	assert.Equal(t, APIError{StatusCode: 500, Msg: "interal server error"}, err)
+	assert.Equal(t, "api error: 500: interal server error", err.Error())
+}
+
+func TestUnexpectedResponse(t *testing.T) {
+	c := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+
+	_, err := c.GetTx(context.Background(), "0xabc", nil)
+	assert.Equal(t, &UnexpectedResponseError{StatusCode: http.StatusNotFound, Body: "not found\n"}, err)
+}
+
+func TestGraphQL(t *testing.T) {
+	c := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var req GraphQLRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "{ blocks { number } }", req.Query)
+		w.Write([]byte(`{"data":{"blocks":[{"number":1}]}}`))
+	})
+
+	resp, err := c.GraphQL(context.Background(), GraphQLRequest{Query: "{ blocks { number } }"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"blocks":[{"number":1}]}`, string(resp.Data))
+	assert.Empty(t, resp.Errors)
+}
+
+func TestHealthCheck(t *testing.T) {
+	c := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Healthy!"))
+	})
+
+	status, err := c.HealthCheck(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "Healthy!", status)
+}
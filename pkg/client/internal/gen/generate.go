@@ -0,0 +1,353 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var methodOrder = map[string]int{"get": 0, "post": 1, "put": 2, "patch": 3, "delete": 4}
+
+func generate(s *spec, pkg string) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by pkg/client/internal/gen from pkg/handlers/openapi.json. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	buf.WriteString("import (\n\t\"context\"\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"net/url\"\n)\n\n")
+	buf.WriteString("var (\n\t_ = json.RawMessage{}\n\t_ = fmt.Sprint\n)\n\n")
+
+	if s.Components.Schemas != nil {
+		for _, name := range s.Components.Schemas.Keys {
+			if err := writeStruct(&buf, name, s.Components.Schemas.Values[name]); err != nil {
+				return nil, fmt.Errorf("schema %s: %w", name, err)
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(s.Paths))
+	for path := range s.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		methods := make([]string, 0, len(s.Paths[path]))
+		for method := range s.Paths[path] {
+			methods = append(methods, method)
+		}
+		sort.Slice(methods, func(i, j int) bool { return methodOrder[methods[i]] < methodOrder[methods[j]] })
+		for _, method := range methods {
+			op := s.Paths[path][method]
+			if err := writeOperation(&buf, s, path, method, op); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+		}
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w\n%s", err, buf.String())
+	}
+	return src, nil
+}
+
+func writeStruct(buf *bytes.Buffer, name string, sc *schema) error {
+	if sc.Type != "object" || sc.Properties == nil {
+		t, err := goType(sc)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(buf, "type %s %s\n\n", name, t)
+		return nil
+	}
+	if sc.Description != "" {
+		fmt.Fprintf(buf, "// %s %s\n", name, sc.Description)
+	}
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	for _, prop := range sc.Properties.Keys {
+		propSchema := sc.Properties.Values[prop]
+		t, err := goType(propSchema)
+		if err != nil {
+			return fmt.Errorf("%s: %w", prop, err)
+		}
+		if propSchema.Description != "" {
+			fmt.Fprintf(buf, "\t// %s\n", propSchema.Description)
+		}
+		tag := prop
+		if !contains(sc.Required, prop) {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(buf, "\t%s %s `json:\"%s\"`\n", exported(prop), t, tag)
+	}
+	buf.WriteString("}\n\n")
+	return nil
+}
+
+func writeOperation(buf *bytes.Buffer, s *spec, path, method string, op *operation) error {
+	if op.OperationID == "" {
+		return fmt.Errorf("missing operationId")
+	}
+	name := exported(op.OperationID)
+
+	var queryParams []*parameter
+	args := []string{"ctx context.Context"}
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			t, err := goType(p.Schema)
+			if err != nil {
+				return err
+			}
+			args = append(args, fmt.Sprintf("%s %s", unexported(p.Name), t))
+		case "query":
+			queryParams = append(queryParams, p)
+		default:
+			return fmt.Errorf("unsupported parameter location %q", p.In)
+		}
+	}
+
+	if len(queryParams) > 0 {
+		fmt.Fprintf(buf, "// %sParams holds the query parameters of %s.\n", name, name)
+		fmt.Fprintf(buf, "type %sParams struct {\n", name)
+		for _, p := range queryParams {
+			t, err := goType(p.Schema)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(buf, "\t%s *%s\n", exported(p.Name), t)
+		}
+		buf.WriteString("}\n\n")
+		args = append(args, fmt.Sprintf("params *%sParams", name))
+	}
+
+	body := "nil"
+	if op.RequestBody != nil {
+		media, ok := op.RequestBody.Content["application/json"]
+		if !ok {
+			return fmt.Errorf("unsupported request body content type")
+		}
+		t, err := goType(media.Schema)
+		if err != nil {
+			return err
+		}
+		args = append(args, "body "+t)
+		body = "body"
+	}
+
+	resultType, contentType, err := successType(s, op)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(buf, "// %s calls %s %s", name, strings.ToUpper(method), path)
+	if op.Summary != "" {
+		fmt.Fprintf(buf, ": %s", strings.TrimSuffix(op.Summary, "."))
+	}
+	buf.WriteString(".\n")
+	returns := "error"
+	if resultType != "" {
+		returns = fmt.Sprintf("(%s, error)", returnType(resultType))
+	}
+	fmt.Fprintf(buf, "func (c *Client) %s(%s) %s {\n", name, strings.Join(args, ", "), returns)
+
+	fmt.Fprintf(buf, "\tpath := %s\n", pathExpr(path, op.Parameters))
+	buf.WriteString("\tquery := url.Values{}\n")
+	if len(queryParams) > 0 {
+		buf.WriteString("\tif params != nil {\n")
+		for _, p := range queryParams {
+			field := exported(p.Name)
+			fmt.Fprintf(buf, "\t\tif params.%s != nil {\n\t\t\tquery.Set(%q, fmt.Sprint(*params.%s))\n\t\t}\n", field, p.Name, field)
+		}
+		buf.WriteString("\t}\n")
+	}
+
+	switch {
+	case resultType == "":
+		fmt.Fprintf(buf, "\treturn c.do(ctx, %q, path, query, %s, %q, nil)\n", strings.ToUpper(method), body, contentType)
+	case strings.HasPrefix(resultType, "[]") || strings.HasPrefix(resultType, "map[") || resultType == "any" || resultType == "string":
+		fmt.Fprintf(buf, "\tvar out %s\n", resultType)
+		fmt.Fprintf(buf, "\tif err := c.do(ctx, %q, path, query, %s, %q, &out); err != nil {\n\t\treturn out, err\n\t}\n", strings.ToUpper(method), body, contentType)
+		buf.WriteString("\treturn out, nil\n")
+	default:
+		fmt.Fprintf(buf, "\tvar out %s\n", resultType)
+		fmt.Fprintf(buf, "\tif err := c.do(ctx, %q, path, query, %s, %q, &out); err != nil {\n\t\treturn nil, err\n\t}\n", strings.ToUpper(method), body, contentType)
+		buf.WriteString("\treturn &out, nil\n")
+	}
+	buf.WriteString("}\n\n")
+	return nil
+}
+
+func successType(s *spec, op *operation) (string, string, error) {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return "", "", fmt.Errorf("no success response")
+	}
+	sort.Strings(codes)
+	resp := s.resolveResponse(op.Responses[codes[0]])
+	if resp == nil {
+		return "", "", fmt.Errorf("unresolved response %s", codes[0])
+	}
+	if len(resp.Content) == 0 {
+		return "", "", nil
+	}
+	for _, contentType := range []string{"application/json", "application/x-ndjson", "text/csv", "text/plain", "application/octet-stream"} {
+		media, ok := resp.Content[contentType]
+		if !ok {
+			continue
+		}
+		if contentType != "application/json" {
+			return "string", contentType, nil
+		}
+		t, err := goType(media.Schema)
+		return t, contentType, err
+	}
+	return "", "", fmt.Errorf("unsupported response content type")
+}
+
+func returnType(t string) string {
+	if strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || t == "any" || t == "string" {
+		return t
+	}
+	return "*" + t
+}
+
+func pathExpr(path string, params []*parameter) string {
+	var parts []string
+	rest := path
+	for {
+		start := strings.Index(rest, "{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest, "}")
+		parts = append(parts, fmt.Sprintf("%q", rest[:start]))
+		name := rest[start+1 : end]
+		arg := unexported(name)
+		if isString(params, name) {
+			parts = append(parts, fmt.Sprintf("url.PathEscape(%s)", arg))
+		} else {
+			parts = append(parts, fmt.Sprintf("url.PathEscape(fmt.Sprint(%s))", arg))
+		}
+		rest = rest[end+1:]
+	}
+	if rest != "" || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%q", rest))
+	}
+	return strings.Join(parts, " + ")
+}
+
+func isString(params []*parameter, name string) bool {
+	for _, p := range params {
+		if p.Name == name {
+			return p.Schema != nil && p.Schema.Type == "string"
+		}
+	}
+	return false
+}
+
+func goType(sc *schema) (string, error) {
+	if sc == nil {
+		return "any", nil
+	}
+	if sc.GoType != "" {
+		return sc.GoType, nil
+	}
+	if sc.Ref != "" {
+		return refName(sc.Ref), nil
+	}
+	if len(sc.OneOf) > 0 {
+		return "any", nil
+	}
+	switch sc.Type {
+	case "":
+		return "any", nil
+	case "string":
+		if sc.Format == "byte" {
+			return "[]byte", nil
+		}
+		return "string", nil
+	case "integer":
+		switch sc.Format {
+		case "uint64", "int64", "int32", "uint32", "uint":
+			return sc.Format, nil
+		default:
+			return "int", nil
+		}
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		t, err := goType(sc.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + t, nil
+	case "object":
+		if sc.Properties != nil {
+			return "", fmt.Errorf("inline object schemas are not supported, use a component")
+		}
+		if len(sc.AdditionalProperties) > 0 && string(sc.AdditionalProperties) != "true" {
+			var ap schema
+			if err := json.Unmarshal(sc.AdditionalProperties, &ap); err != nil {
+				return "", err
+			}
+			t, err := goType(&ap)
+			if err != nil {
+				return "", err
+			}
+			return "map[string]" + t, nil
+		}
+		return "map[string]any", nil
+	}
+	return "", fmt.Errorf("unsupported schema type %q", sc.Type)
+}
+
+func exported(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '-' || r == '_' || r == '.' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	for _, initialism := range []string{"Id", "Url", "Api", "Abi", "Nft", "Uri"} {
+		if strings.HasSuffix(s, initialism) {
+			s = strings.TrimSuffix(s, initialism) + strings.ToUpper(initialism)
+		}
+	}
+	return s
+}
+
+func unexported(name string) string {
+	s := exported(name)
+	if strings.ToUpper(s) == s {
+		return strings.ToLower(s)
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratedClientIsUpToDate(t *testing.T) {
+	specData, err := os.ReadFile("../../../handlers/openapi.json")
+	assert.NoError(t, err)
+	var s spec
+	assert.NoError(t, json.Unmarshal(specData, &s))
+
+	src, err := generate(&s, "client")
+	assert.NoError(t, err)
+
+	existing, err := os.ReadFile("../../client.gen.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(existing), string(src), "client.gen.go is stale, run go generate ./pkg/client")
+}
+
+func TestPathExpr(t *testing.T) {
+	params := []*parameter{
+		{Name: "addr", In: "path", Schema: &schema{Type: "string"}},
+		{Name: "id", In: "path", Schema: &schema{Type: "integer", Format: "uint64"}},
+	}
+	assert.Equal(t, `"/token/" + url.PathEscape(addr) + "/" + url.PathEscape(fmt.Sprint(id)) + "/owner"`, pathExpr("/token/{addr}/{id}/owner", params))
+	assert.Equal(t, `"/tx/get-txs"`, pathExpr("/tx/get-txs", nil))
+}
+
+func TestExported(t *testing.T) {
+	assert.Equal(t, "ChainID", exported("chainId"))
+	assert.Equal(t, "FromBlock", exported("fromBlock"))
+	assert.Equal(t, "GasPrice", exported("gas_price"))
+}
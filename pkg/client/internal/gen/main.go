@@ -0,0 +1,37 @@
+// Command gen generates the typed API client in pkg/client from the OpenAPI
+// document served by pkg/handlers.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log/slog"
+	"os"
+)
+
+func main() {
+	specPath := flag.String("spec", "", "Path to the OpenAPI document")
+	outPath := flag.String("out", "", "Path of the generated Go file")
+	pkg := flag.String("package", "client", "Package name of the generated file")
+	flag.Parse()
+
+	specData, err := os.ReadFile(*specPath)
+	if err != nil {
+		slog.Error("failed to read spec", "err", err)
+		os.Exit(1)
+	}
+	var s spec
+	if err := json.Unmarshal(specData, &s); err != nil {
+		slog.Error("failed to parse spec", "err", err)
+		os.Exit(1)
+	}
+	src, err := generate(&s, *pkg)
+	if err != nil {
+		slog.Error("failed to generate client", "err", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*outPath, src, 0644); err != nil {
+		slog.Error("failed to write client", "err", err)
+		os.Exit(1)
+	}
+}
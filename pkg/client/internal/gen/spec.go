@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type spec struct {
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components struct {
+		Schemas   *orderedSchemas      `json:"schemas"`
+		Responses map[string]*response `json:"responses"`
+	} `json:"components"`
+}
+
+type operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Parameters  []*parameter         `json:"parameters"`
+	RequestBody *requestBody         `json:"requestBody"`
+	Responses   map[string]*response `json:"responses"`
+}
+
+type parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*mediaType `json:"content"`
+}
+
+type response struct {
+	Ref         string                `json:"$ref"`
+	Description string                `json:"description"`
+	Content     map[string]*mediaType `json:"content"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type schema struct {
+	Ref                  string          `json:"$ref"`
+	Type                 string          `json:"type"`
+	Format               string          `json:"format"`
+	Description          string          `json:"description"`
+	GoType               string          `json:"x-go-type"`
+	Items                *schema         `json:"items"`
+	Properties           *orderedSchemas `json:"properties"`
+	AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	Required             []string        `json:"required"`
+	OneOf                []*schema       `json:"oneOf"`
+}
+
+// orderedSchemas keeps the order of a JSON object so generated structs list
+// their fields in the same order as the spec.
+type orderedSchemas struct {
+	Keys   []string
+	Values map[string]*schema
+}
+
+func (o *orderedSchemas) UnmarshalJSON(b []byte) error {
+	o.Values = make(map[string]*schema)
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("unexpected token %v", tok)
+		}
+		var s schema
+		if err := dec.Decode(&s); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		o.Keys = append(o.Keys, key)
+		o.Values[key] = &s
+	}
+	return nil
+}
+
+func (s *spec) resolveResponse(r *response) *response {
+	if r.Ref == "" {
+		return r
+	}
+	return s.Components.Responses[refName(r.Ref)]
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
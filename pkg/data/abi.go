@@ -0,0 +1,38 @@
+package data
+
+// ContractABI is the JSON ABI uploaded for a contract, used to decode the
+// calls to it and the logs it emits.
+type ContractABI struct {
+	ChainID uint64 `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	Address string `json:"address" gorm:"column:address;type:char(42);primaryKey"`
+	ABI     string `json:"abi" gorm:"column:abi;type:text;not null"`
+}
+
+// Signature is the canonical text signature of a function or event, such as
+// transfer(address,uint256), keyed by its selector: the first 4 bytes of the
+// keccak-256 hash for functions and the whole hash for events. Different
+// signatures can share a function selector.
+type Signature struct {
+	Hash      string `json:"hash" gorm:"column:hash;type:varchar(66);primaryKey"`
+	Signature string `json:"signature" gorm:"column:signature;type:text;primaryKey"`
+}
+
+// Decoded is a function call or event decoded with the ABI of its contract,
+// Source "abi", or a known signature, Source "signature". Arguments decoded
+// from a signature have no names.
+type Decoded struct {
+	Name      string       `json:"name"`
+	Signature string       `json:"signature"`
+	Source    string       `json:"source"`
+	Args      []DecodedArg `json:"args"`
+}
+
+// DecodedArg is a decoded argument. Integers are decimal strings, bytes are
+// 0x prefixed hex, arrays are JSON arrays and tuples are arrays of
+// DecodedArg. Indexed event arguments of dynamic types only have their hash.
+type DecodedArg struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Indexed bool   `json:"indexed,omitempty"`
+	Value   any    `json:"value"`
+}
@@ -0,0 +1,29 @@
+package data
+
+// Address summarizes the transactions an address sent and received, kept up
+// to date as transactions are stored. The recipient of a contract creation is
+// the deployed contract. Values only count successful transactions and are
+// uint256 decimal strings in wei. GasSpent is the gas used by the sent
+// transactions.
+type Address struct {
+	ChainID        uint64 `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	Address        string `json:"address" gorm:"column:address;type:char(42);primaryKey"`
+	FirstSeenBlock uint64 `json:"firstSeenBlock" gorm:"column:first_seen_block;type:numeric;not null"`
+	LastSeenBlock  uint64 `json:"lastSeenBlock" gorm:"column:last_seen_block;type:numeric;not null"`
+	TxsSent        uint64 `json:"txsSent" gorm:"column:txs_sent;type:numeric;not null"`
+	TxsReceived    uint64 `json:"txsReceived" gorm:"column:txs_received;type:numeric;not null"`
+	ValueSent      string `json:"valueSent" gorm:"column:value_sent;type:varchar(78);not null"`
+	ValueReceived  string `json:"valueReceived" gorm:"column:value_received;type:varchar(78);not null"`
+	GasSpent       uint64 `json:"gasSpent" gorm:"column:gas_spent;type:numeric;not null"`
+}
+
+// AddressSummary is an address's transaction summary with its contract
+// registration, latest balance snapshot and token balances. Contract and
+// Balance are nil when the address isn't a known contract or has no
+// snapshot.
+type AddressSummary struct {
+	Address
+	Contract *Contract       `json:"contract"`
+	Balance  *Balance        `json:"balance"`
+	Tokens   []*TokenHolding `json:"tokens"`
+}
@@ -0,0 +1,24 @@
+package data
+
+// Balance is the ETH balance and nonce of an address after a block that
+// touched it, as a sender, recipient, miner, withdrawal recipient or
+// internal transaction participant. Balance is a uint256 decimal string in
+// wei. Time is the block's timestamp.
+type Balance struct {
+	ChainID     uint64 `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	Address     string `json:"address" gorm:"column:address;type:char(42);primaryKey"`
+	BlockNumber uint64 `json:"blockNumber" gorm:"column:block_number;type:numeric;primaryKey"`
+	BlockHash   string `json:"blockHash" gorm:"column:block_hash;type:char(66);not null"`
+	Time        uint64 `json:"time" gorm:"column:time;type:numeric;not null"`
+	Balance     string `json:"balance" gorm:"column:balance;type:varchar(78);not null"`
+	Nonce       uint64 `json:"nonce" gorm:"column:nonce;type:numeric;not null"`
+}
+
+// BalancePoint is the last balance of an address in a time bucket starting
+// at Time.
+type BalancePoint struct {
+	Time        uint64 `json:"time"`
+	BlockNumber uint64 `json:"blockNumber"`
+	Balance     string `json:"balance"`
+	Nonce       uint64 `json:"nonce"`
+}
@@ -1,19 +1,37 @@
 package data
 
 type Block struct {
+	// ChainID is set by the db package from the chain its connection is
+	// scoped to, like on every other indexed row.
+	ChainID     uint64 `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
 	Hash        string `json:"hash" gorm:"column:hash;type:char(66);primaryKey"`
-	Number      uint64 `json:"number" gorm:"column:number;type:numeric;not null;unique;index:,sort:asc"`
+	Number      uint64 `json:"number" gorm:"column:number;type:numeric;not null;index:,sort:asc"`
 	GasLimit    uint64 `json:"gasLimit" gorm:"column:gas_limit;type:numeric;not null"`
 	GasUsed     uint64 `json:"gasUsed" gorm:"column:gas_used;type:numeric;not null"`
 	Difficulty  uint64 `json:"difficulty" gorm:"column:difficulty;type:numeric;not null"`
-	Time        uint64 `json:"time" gorm:"column:time;type:numeric;not null"`
+	Time        uint64 `json:"time" gorm:"column:time;type:numeric;not null;index"`
 	ParentHash  string `json:"parentHash" gorm:"column:parent_hash;type:char(66);not null"`
 	Nonce       uint64 `json:"nonce" gorm:"column:nonce;type:numeric;not null"`
-	Miner       string `json:"miner" gorm:"column:miner;type:char(42);not null"`
+	Miner       string `json:"miner" gorm:"column:miner;type:char(42);not null;index"`
 	Size        uint64 `json:"size" gorm:"column:size;type:numeric;not null"`
 	RootHash    string `json:"rootHash" gorm:"column:root_hash;type:char(66);not null"`
 	UncleHash   string `json:"uncleHash" gorm:"column:uncle_hash;type:char(66);not null"`
 	TxHash      string `json:"txHash" gorm:"column:tx_hash;type:char(66);not null"`
 	ReceiptHash string `json:"receiptHash" gorm:"column:receipt_hash;type:char(66);not null"`
 	ExtraData   []byte `json:"extraData" gorm:"column:extra_data;type:bytea"`
+	// BaseFee is the base fee per gas, zero before London.
+	BaseFee uint64 `json:"baseFee" gorm:"column:base_fee;type:numeric;not null"`
+	// PriorityFees are the tips paid to the miner, BaseFeeBurned is the base
+	// fee times the gas used and BlobFeeBurned the blob fees of the
+	// transactions. BlockReward and UncleInclusionReward are the miner's
+	// static proof of work rewards, 0 after the merge. They are uint256
+	// decimal strings in wei, 0 for blocks indexed before migration 14.
+	PriorityFees         string `json:"priorityFees" gorm:"column:priority_fees;type:varchar(78);not null"`
+	BaseFeeBurned        string `json:"baseFeeBurned" gorm:"column:base_fee_burned;type:numeric(78);not null"`
+	BlobFeeBurned        string `json:"blobFeeBurned" gorm:"column:blob_fee_burned;type:numeric(78);not null"`
+	BlockReward          string `json:"blockReward" gorm:"column:block_reward;type:varchar(78);not null"`
+	UncleInclusionReward string `json:"uncleInclusionReward" gorm:"column:uncle_inclusion_reward;type:varchar(78);not null"`
+	// Uncles are the uncles included by the block, set by API responses
+	// for a single block.
+	Uncles []*Uncle `json:"uncles,omitempty" gorm:"-"`
 }
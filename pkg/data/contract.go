@@ -0,0 +1,26 @@
+package data
+
+// Standards a contract can implement.
+const (
+	StandardERC20   = "ERC20"
+	StandardERC165  = "ERC165"
+	StandardERC721  = "ERC721"
+	StandardERC1155 = "ERC1155"
+)
+
+// Contract is a deployed contract. Contracts created by a contract call are
+// Internal, their Deployer is the creating contract and they are only found
+// when tracing is enabled. BytecodeHash and Interfaces are read from the
+// runtime code at the latest block, so they are empty for contracts that
+// self-destructed.
+type Contract struct {
+	ChainID      uint64   `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	Address      string   `json:"address" gorm:"column:address;type:char(42);primaryKey"`
+	Deployer     string   `json:"deployer" gorm:"column:deployer;type:char(42);not null;index"`
+	TxHash       string   `json:"txHash" gorm:"column:tx_hash;type:char(66);not null"`
+	BlockHash    string   `json:"blockHash" gorm:"column:block_hash;type:char(66);not null"`
+	BlockNumber  uint64   `json:"blockNumber" gorm:"column:block_number;type:numeric;not null;index"`
+	BytecodeHash string   `json:"bytecodeHash" gorm:"column:bytecode_hash;type:varchar(66);not null"`
+	Interfaces   []string `json:"interfaces" gorm:"column:interfaces;serializer:json;not null"`
+	Internal     bool     `json:"internal" gorm:"column:internal;not null"`
+}
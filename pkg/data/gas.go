@@ -0,0 +1,31 @@
+package data
+
+// GasOracle suggests fees per gas for a transaction to be included in the
+// next blocks, from the fees paid in the blocks FromBlock to ToBlock.
+type GasOracle struct {
+	FromBlock   uint64        `json:"fromBlock"`
+	ToBlock     uint64        `json:"toBlock"`
+	BaseFee     uint64        `json:"baseFee"`
+	NextBaseFee uint64        `json:"nextBaseFee"`
+	Slow        FeeSuggestion `json:"slow"`
+	Standard    FeeSuggestion `json:"standard"`
+	Fast        FeeSuggestion `json:"fast"`
+}
+
+type FeeSuggestion struct {
+	MaxPriorityFee uint64 `json:"maxPriorityFeePerGas"`
+	MaxFee         uint64 `json:"maxFeePerGas"`
+}
+
+// GasHistory summarises the fees of a block.
+type GasHistory struct {
+	Number         uint64 `json:"number"`
+	Time           uint64 `json:"time"`
+	BaseFee        uint64 `json:"baseFee"`
+	GasUsed        uint64 `json:"gasUsed"`
+	GasLimit       uint64 `json:"gasLimit"`
+	Txs            uint64 `json:"txs"`
+	MinPriorityFee uint64 `json:"minPriorityFee"`
+	AvgPriorityFee uint64 `json:"avgPriorityFee"`
+	MaxPriorityFee uint64 `json:"maxPriorityFee"`
+}
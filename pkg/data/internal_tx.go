@@ -0,0 +1,28 @@
+package data
+
+// InternalTx is a call frame below the top level of a transaction's trace,
+// numbered by TraceIndex in depth-first order. Type is CALL, CALLCODE,
+// DELEGATECALL, STATICCALL, CREATE, CREATE2 or SELFDESTRUCT. Error is the
+// frame's own error, Reverted is also set when a parent frame failed, so
+// the frame's value didn't move. Value is a uint256 decimal string. GasUsed
+// is 0 for failed frames traced with trace_block, which omits their result.
+type InternalTx struct {
+	ChainID     uint64 `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	BlockHash   string `json:"blockHash" gorm:"column:block_hash;type:char(66);primaryKey"`
+	TxHash      string `json:"txHash" gorm:"column:tx_hash;type:char(66);primaryKey"`
+	TraceIndex  uint   `json:"traceIndex" gorm:"column:trace_index;type:numeric;primaryKey"`
+	BlockNumber uint64 `json:"blockNumber" gorm:"column:block_number;type:numeric;not null;index"`
+	Depth       uint   `json:"depth" gorm:"column:depth;type:numeric;not null"`
+	Type        string `json:"type" gorm:"column:type;not null"`
+	From        string `json:"from" gorm:"column:from;type:char(42);not null;index"`
+	To          string `json:"to" gorm:"column:to;type:char(42);not null;index"`
+	Value       string `json:"value" gorm:"column:value;type:varchar(78);not null"`
+	Gas         uint64 `json:"gas" gorm:"column:gas;type:numeric;not null"`
+	GasUsed     uint64 `json:"gasUsed" gorm:"column:gas_used;type:numeric;not null"`
+	Error       string `json:"error" gorm:"column:error;not null"`
+	Reverted    bool   `json:"reverted" gorm:"column:reverted;not null"`
+}
+
+func (InternalTx) TableName() string {
+	return "internal_txs"
+}
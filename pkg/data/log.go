@@ -0,0 +1,29 @@
+package data
+
+type Log struct {
+	ChainID     uint64 `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	BlockHash   string `json:"blockHash" gorm:"column:block_hash;type:char(66);primaryKey"`
+	Index       uint   `json:"index" gorm:"column:log_index;type:numeric;primaryKey"`
+	BlockNumber uint64 `json:"blockNumber" gorm:"column:block_number;type:numeric;not null;index"`
+	TxHash      string `json:"txHash" gorm:"column:tx_hash;type:char(66);not null;index"`
+	Address     string `json:"address" gorm:"column:address;type:char(42);not null;index"`
+	Topic0      string `json:"topic0" gorm:"column:topic0;type:char(66)"`
+	Topic1      string `json:"topic1" gorm:"column:topic1;type:char(66)"`
+	Topic2      string `json:"topic2" gorm:"column:topic2;type:char(66)"`
+	Topic3      string `json:"topic3" gorm:"column:topic3;type:char(66)"`
+	Data        []byte `json:"data" gorm:"column:data;type:bytea"`
+	// Event is the decoded log, set by API responses when the contract's ABI
+	// or the topic's signature is known.
+	Event *Decoded `json:"event,omitempty" gorm:"-"`
+}
+
+func (l Log) Topics() []string {
+	var topics []string
+	for _, topic := range []string{l.Topic0, l.Topic1, l.Topic2, l.Topic3} {
+		if topic == "" {
+			break
+		}
+		topics = append(topics, topic)
+	}
+	return topics
+}
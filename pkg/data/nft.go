@@ -0,0 +1,52 @@
+package data
+
+// NFT is a token of an ERC-721 or ERC-1155 contract. URI is read with
+// eth_call each time the token is transferred, it is empty when the contract
+// doesn't implement the metadata extension. Token ids are uint256, so they
+// are decimal strings.
+type NFT struct {
+	ChainID  uint64      `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	Contract string      `json:"contract" gorm:"column:contract;type:char(42);primaryKey"`
+	TokenID  string      `json:"tokenId" gorm:"column:token_id;type:varchar(78);primaryKey"`
+	Standard string      `json:"standard" gorm:"column:standard;not null"`
+	URI      string      `json:"uri" gorm:"column:uri;not null"`
+	Owners   []*NFTOwner `json:"owners" gorm:"-"`
+}
+
+// NFTTransfer is a decoded ERC-721 Transfer or ERC-1155 TransferSingle or
+// TransferBatch log. A TransferBatch log has a transfer per token id,
+// numbered by BatchIndex. Amount is 1 for ERC-721 transfers.
+type NFTTransfer struct {
+	ChainID     uint64 `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	BlockHash   string `json:"blockHash" gorm:"column:block_hash;type:char(66);primaryKey"`
+	LogIndex    uint   `json:"logIndex" gorm:"column:log_index;type:numeric;primaryKey"`
+	BatchIndex  uint   `json:"batchIndex" gorm:"column:batch_index;type:numeric;primaryKey"`
+	BlockNumber uint64 `json:"blockNumber" gorm:"column:block_number;type:numeric;not null;index"`
+	TxHash      string `json:"txHash" gorm:"column:tx_hash;type:char(66);not null"`
+	Contract    string `json:"contract" gorm:"column:contract;type:char(42);not null"`
+	TokenID     string `json:"tokenId" gorm:"column:token_id;type:varchar(78);not null"`
+	Standard    string `json:"standard" gorm:"column:standard;not null"`
+	Operator    string `json:"operator" gorm:"column:operator;type:char(42);not null"`
+	From        string `json:"from" gorm:"column:from;type:char(42);not null;index"`
+	To          string `json:"to" gorm:"column:to;type:char(42);not null;index"`
+	Amount      string `json:"amount" gorm:"column:amount;type:varchar(78);not null"`
+}
+
+// NFTOwner is the number of copies of a token held by Owner, summed like a
+// TokenBalance. An ERC-721 token has a single owner with a balance of 1.
+type NFTOwner struct {
+	ChainID  uint64 `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	Contract string `json:"-" gorm:"column:contract;type:char(42);primaryKey"`
+	TokenID  string `json:"-" gorm:"column:token_id;type:varchar(78);primaryKey"`
+	Owner    string `json:"owner" gorm:"column:owner;type:char(42);primaryKey"`
+	Balance  string `json:"balance" gorm:"column:balance;type:varchar(78);not null"`
+}
+
+// NFTHolding is a token held by an address with its metadata.
+type NFTHolding struct {
+	Contract string `json:"contract"`
+	TokenID  string `json:"tokenId"`
+	Standard string `json:"standard"`
+	URI      string `json:"uri"`
+	Balance  string `json:"balance"`
+}
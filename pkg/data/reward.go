@@ -0,0 +1,38 @@
+package data
+
+// MinerRewards totals what a fee recipient earned from the blocks and uncles
+// it mined, kept up to date as they are stored. Amounts are uint256 decimal
+// strings in wei. Total is the sum of the rewards and priority fees.
+type MinerRewards struct {
+	ChainID               uint64 `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	Miner                 string `json:"miner" gorm:"column:miner;type:char(42);primaryKey"`
+	Blocks                uint64 `json:"blocks" gorm:"column:blocks;type:numeric;not null"`
+	Uncles                uint64 `json:"uncles" gorm:"column:uncles;type:numeric;not null"`
+	BlockRewards          string `json:"blockRewards" gorm:"column:block_rewards;type:varchar(78);not null"`
+	UncleInclusionRewards string `json:"uncleInclusionRewards" gorm:"column:uncle_inclusion_rewards;type:varchar(78);not null"`
+	UncleRewards          string `json:"uncleRewards" gorm:"column:uncle_rewards;type:varchar(78);not null"`
+	PriorityFees          string `json:"priorityFees" gorm:"column:priority_fees;type:varchar(78);not null"`
+	Total                 string `json:"total" gorm:"-"`
+}
+
+func (MinerRewards) TableName() string {
+	return "miner_rewards"
+}
+
+// BurnPoint is the ETH burned by the blocks of an hour or day starting at
+// Time, as uint256 decimal strings in wei. Burned is the sum of the base and
+// blob fees burned.
+type BurnPoint struct {
+	Time          uint64 `json:"time"`
+	BaseFeeBurned string `json:"baseFeeBurned"`
+	BlobFeeBurned string `json:"blobFeeBurned"`
+	Burned        string `json:"burned"`
+}
+
+// BurnStats is the ETH burned in a time range and in each of its buckets.
+type BurnStats struct {
+	BaseFeeBurned string       `json:"baseFeeBurned"`
+	BlobFeeBurned string       `json:"blobFeeBurned"`
+	Burned        string       `json:"burned"`
+	Buckets       []*BurnPoint `json:"buckets"`
+}
@@ -0,0 +1,22 @@
+package data
+
+// Stats aggregates the blocks mined in an hour or a day, starting at Time.
+type Stats struct {
+	ChainID             uint64  `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	Time                uint64  `json:"time" gorm:"column:time;primaryKey;autoIncrement:false"`
+	Blocks              uint64  `json:"blocks" gorm:"column:blocks;not null"`
+	Txs                 uint64  `json:"txs" gorm:"column:txs;not null"`
+	GasUsed             uint64  `json:"gasUsed" gorm:"column:gas_used;type:numeric;not null"`
+	AvgGasPrice         uint64  `json:"avgGasPrice" gorm:"column:avg_gas_price;type:numeric;not null"`
+	MedianGasPrice      uint64  `json:"medianGasPrice" gorm:"column:median_gas_price;type:numeric;not null"`
+	ActiveAddresses     uint64  `json:"activeAddresses" gorm:"column:active_addresses;not null"`
+	ContractDeployments uint64  `json:"contractDeployments" gorm:"column:contract_deployments;not null"`
+	FailedTxs           uint64  `json:"failedTxs" gorm:"column:failed_txs;not null"`
+	FailedTxRatio       float64 `json:"failedTxRatio" gorm:"column:failed_tx_ratio;not null"`
+	// AvgBlockTime is the mean number of seconds between the blocks.
+	AvgBlockTime float64 `json:"avgBlockTime" gorm:"column:avg_block_time;not null"`
+	// BaseFeeBurned and BlobFeeBurned are the fees burned by the blocks, as
+	// uint256 decimal strings in wei.
+	BaseFeeBurned string `json:"baseFeeBurned" gorm:"column:base_fee_burned;type:numeric(78);not null"`
+	BlobFeeBurned string `json:"blobFeeBurned" gorm:"column:blob_fee_burned;type:numeric(78);not null"`
+}
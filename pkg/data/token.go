@@ -0,0 +1,58 @@
+package data
+
+// Token is the metadata of an ERC-20 contract, read with eth_call when its
+// first transfer is indexed. Fields the contract doesn't implement are empty.
+type Token struct {
+	ChainID  uint64 `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	Address  string `json:"address" gorm:"column:address;type:char(42);primaryKey"`
+	Name     string `json:"name" gorm:"column:name;not null"`
+	Symbol   string `json:"symbol" gorm:"column:symbol;not null"`
+	Decimals uint8  `json:"decimals" gorm:"column:decimals;not null"`
+}
+
+// TokenTransfer is a decoded ERC-20 Transfer log. Mints are transfers from
+// the zero address, burns transfers to it. Token amounts are uint256, so
+// Value is a decimal string.
+type TokenTransfer struct {
+	ChainID     uint64 `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	BlockHash   string `json:"blockHash" gorm:"column:block_hash;type:char(66);primaryKey"`
+	LogIndex    uint   `json:"logIndex" gorm:"column:log_index;type:numeric;primaryKey"`
+	BlockNumber uint64 `json:"blockNumber" gorm:"column:block_number;type:numeric;not null;index"`
+	TxHash      string `json:"txHash" gorm:"column:tx_hash;type:char(66);not null"`
+	Token       string `json:"token" gorm:"column:token;type:char(42);not null;index"`
+	From        string `json:"from" gorm:"column:from;type:char(42);not null;index"`
+	To          string `json:"to" gorm:"column:to;type:char(42);not null;index"`
+	Value       string `json:"value" gorm:"column:value;type:varchar(78);not null"`
+}
+
+// TokenApproval is a decoded ERC-20 Approval log.
+type TokenApproval struct {
+	ChainID     uint64 `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	BlockHash   string `json:"blockHash" gorm:"column:block_hash;type:char(66);primaryKey"`
+	LogIndex    uint   `json:"logIndex" gorm:"column:log_index;type:numeric;primaryKey"`
+	BlockNumber uint64 `json:"blockNumber" gorm:"column:block_number;type:numeric;not null;index"`
+	TxHash      string `json:"txHash" gorm:"column:tx_hash;type:char(66);not null"`
+	Token       string `json:"token" gorm:"column:token;type:char(42);not null"`
+	Owner       string `json:"owner" gorm:"column:owner;type:char(42);not null;index"`
+	Spender     string `json:"spender" gorm:"column:spender;type:char(42);not null"`
+	Value       string `json:"value" gorm:"column:value;type:varchar(78);not null"`
+}
+
+// TokenBalance is the sum of the indexed transfers of Token to Holder minus
+// those from Holder. It matches the on-chain balance once the blocks since
+// the token was deployed are indexed, before that it can be negative.
+type TokenBalance struct {
+	ChainID uint64 `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	Token   string `json:"token" gorm:"column:token;type:char(42);primaryKey"`
+	Holder  string `json:"holder" gorm:"column:holder;type:char(42);primaryKey"`
+	Balance string `json:"balance" gorm:"column:balance;type:varchar(78);not null"`
+}
+
+// TokenHolding is a balance of an address with the metadata of its token.
+type TokenHolding struct {
+	Token    string `json:"token"`
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals uint8  `json:"decimals"`
+	Balance  string `json:"balance"`
+}
@@ -1,16 +1,25 @@
 package data
 
 type Transaction struct {
-	Hash      string `json:"hash" gorm:"column:hash;type:char(66);primaryKey"`
-	From      string `json:"from" gorm:"column:from;type:char(42);not null"`
-	To        string `json:"to" gorm:"column:to;type:char(42)"`
-	Contract  string `json:"contract" gorm:"column:contract;type:char(66);not null"`
-	Value     uint64 `json:"value" gorm:"column:value;type:numeric;not null"`
-	Data      []byte `json:"data" gorm:"column:data;type:bytea;not null"`
-	Gas       uint64 `json:"gas" gorm:"column:gas;type:numeric;not null"`
-	GasPrice  uint64 `json:"gasPrice" gorm:"column:gas_price;type:numeric;not null"`
-	Cost      uint64 `json:"cost" gorm:"column:cost;type:numeric;not null"`
-	Nonce     uint64 `json:"nonce" gorm:"column:nonce;type:numeric;not null"`
-	Status    uint64 `json:"status" gorm:"column:status;type:numeric;not null"`
-	BlockHash string `json:"blockHash" gorm:"column:block_hash;type:char(66);not null"`
+	ChainID     uint64 `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	Hash        string `json:"hash" gorm:"column:hash;type:char(66);primaryKey"`
+	From        string `json:"from" gorm:"column:from;type:char(42);not null;index"`
+	To          string `json:"to" gorm:"column:to;type:char(42);index"`
+	Contract    string `json:"contract" gorm:"column:contract;type:char(66);not null"`
+	Value       uint64 `json:"value" gorm:"column:value;type:numeric;not null"`
+	Data        []byte `json:"data" gorm:"column:data;type:bytea;not null"`
+	Gas         uint64 `json:"gas" gorm:"column:gas;type:numeric;not null"`
+	GasPrice    uint64 `json:"gasPrice" gorm:"column:gas_price;type:numeric;not null"`
+	Cost        uint64 `json:"cost" gorm:"column:cost;type:numeric;not null"`
+	Nonce       uint64 `json:"nonce" gorm:"column:nonce;type:numeric;not null"`
+	Status      uint64 `json:"status" gorm:"column:status;type:numeric;not null"`
+	BlockHash   string `json:"blockHash" gorm:"column:block_hash;type:char(66);not null;index"`
+	BlockNumber uint64 `json:"blockNumber" gorm:"column:block_number;type:numeric;not null;index"`
+	// PriorityFee is the tip per gas paid to the block's miner.
+	PriorityFee uint64 `json:"priorityFee" gorm:"column:priority_fee;type:numeric;not null"`
+	// GasUsed is the gas used by the transaction, from its receipt.
+	GasUsed uint64 `json:"gasUsed" gorm:"column:gas_used;type:numeric;not null"`
+	// Method is the decoded calldata, set by API responses when the
+	// contract's ABI or the selector's signature is known.
+	Method *Decoded `json:"method,omitempty" gorm:"-"`
 }
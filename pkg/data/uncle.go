@@ -0,0 +1,21 @@
+package data
+
+// Uncle is an uncle (ommer) header included by the block BlockHash at
+// position UncleIndex. Number is the uncle's own height, BlockNumber the
+// including block's. Reward is the uint256 decimal string in wei credited
+// to the uncle's miner by the chain's proof of work reward rules.
+type Uncle struct {
+	ChainID     uint64 `json:"-" gorm:"column:chain_id;type:numeric;primaryKey;autoIncrement:false"`
+	BlockHash   string `json:"blockHash" gorm:"column:block_hash;type:char(66);primaryKey"`
+	UncleIndex  uint   `json:"uncleIndex" gorm:"column:uncle_index;type:numeric;primaryKey"`
+	BlockNumber uint64 `json:"blockNumber" gorm:"column:block_number;type:numeric;not null;index"`
+	Hash        string `json:"hash" gorm:"column:hash;type:char(66);not null;index"`
+	Number      uint64 `json:"number" gorm:"column:number;type:numeric;not null"`
+	ParentHash  string `json:"parentHash" gorm:"column:parent_hash;type:char(66);not null"`
+	Miner       string `json:"miner" gorm:"column:miner;type:char(42);not null;index"`
+	Difficulty  uint64 `json:"difficulty" gorm:"column:difficulty;type:numeric;not null"`
+	GasLimit    uint64 `json:"gasLimit" gorm:"column:gas_limit;type:numeric;not null"`
+	GasUsed     uint64 `json:"gasUsed" gorm:"column:gas_used;type:numeric;not null"`
+	Time        uint64 `json:"time" gorm:"column:time;type:numeric;not null"`
+	Reward      string `json:"reward" gorm:"column:reward;type:varchar(78);not null"`
+}
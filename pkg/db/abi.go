@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"gorm.io/gorm/clause"
+)
+
+// InsertContractABI stores the ABI of a contract, replacing the one uploaded
+// before.
+func (g *GormDB) InsertContractABI(ctx context.Context, contractABI data.ContractABI) error {
+	db, cancel := g.write(ctx)
+	defer cancel()
+	contractABI.ChainID = g.chainID
+	return db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&contractABI).Error
+}
+
+// InsertSignatures adds signatures to the signature database, ignoring the
+// ones it already has. Signatures are shared by every chain.
+func (g *GormDB) InsertSignatures(ctx context.Context, signatures []data.Signature) error {
+	if len(signatures) == 0 {
+		return nil
+	}
+	db, cancel := g.write(ctx)
+	defer cancel()
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&signatures).Error
+}
+
+func (g *GormDB) GetContractABIs(ctx context.Context, addresses []string) ([]*data.ContractABI, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var abis []*data.ContractABI
+	if err := db.Where("chain_id = ? AND address IN ?", g.chainID, addresses).Find(&abis).Error; err != nil {
+		return nil, err
+	}
+	return abis, nil
+}
+
+// GetSignatures returns the signatures of the selectors in hashes, ordered
+// by signature within a selector.
+func (g *GormDB) GetSignatures(ctx context.Context, hashes []string) ([]*data.Signature, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var signatures []*data.Signature
+	err := db.Where("hash IN ?", hashes).Order("hash asc, signature asc").Find(&signatures).Error
+	if err != nil {
+		return nil, err
+	}
+	return signatures, nil
+}
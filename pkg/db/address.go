@@ -0,0 +1,201 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HasAddress reports whether an address has sent or received a transaction,
+// mined a block or emitted a log.
+func (g *GormDB) HasAddress(ctx context.Context, address string) (bool, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var exists bool
+	err := db.Raw(`SELECT EXISTS (SELECT 1 FROM transactions WHERE chain_id = @chain AND "from" = @address)
+		OR EXISTS (SELECT 1 FROM transactions WHERE chain_id = @chain AND "to" = @address)
+		OR EXISTS (SELECT 1 FROM blocks WHERE chain_id = @chain AND miner = @address)
+		OR EXISTS (SELECT 1 FROM logs WHERE chain_id = @chain AND address = @address)`,
+		map[string]any{"chain": g.chainID, "address": address}).
+		Scan(&exists).Error
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// GetAddressSummary returns the transaction summary, contract registration,
+// latest balance snapshot and token balances of an address. It returns
+// gorm.ErrRecordNotFound when none of them is known.
+func (g *GormDB) GetAddressSummary(ctx context.Context, address string) (*data.AddressSummary, error) {
+	summary := &data.AddressSummary{
+		Address: data.Address{Address: address, ValueSent: "0", ValueReceived: "0"},
+	}
+	db, cancel := g.read(ctx)
+	err := db.Take(&summary.Address, "chain_id = ? AND address = ?", g.chainID, address).Error
+	cancel()
+	seen := err == nil
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	summary.Contract, err = g.GetContract(ctx, address)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	summary.Balance, err = g.GetBalance(ctx, address, nil)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	summary.Tokens, err = g.GetAddressTokens(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	if !seen && summary.Contract == nil && summary.Balance == nil && len(summary.Tokens) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return summary, nil
+}
+
+// applyAddressTx adds a stored transaction to the summaries of its sender
+// and recipient, or removes it from them when sign is negative.
+func applyAddressTx(db *gorm.DB, tx *data.Transaction, sign int) error {
+	value := new(big.Int)
+	if tx.Status == 1 {
+		value.SetUint64(tx.Value)
+	}
+	if sign < 0 {
+		value.Neg(value)
+	}
+	err := updateAddress(db, tx.ChainID, tx.From, tx.BlockNumber, sign, func(a *data.Address) error {
+		a.TxsSent = addCount(a.TxsSent, sign)
+		if sign > 0 {
+			a.GasSpent += tx.GasUsed
+		} else {
+			a.GasSpent -= min(a.GasSpent, tx.GasUsed)
+		}
+		return addDecimal(&a.ValueSent, value)
+	})
+	if err != nil {
+		return err
+	}
+	recipient := strings.TrimSpace(tx.To)
+	if recipient == "" && tx.Status == 1 {
+		recipient = strings.TrimSpace(tx.Contract)
+	}
+	if recipient == "" {
+		return nil
+	}
+	return updateAddress(db, tx.ChainID, recipient, tx.BlockNumber, sign, func(a *data.Address) error {
+		a.TxsReceived = addCount(a.TxsReceived, sign)
+		return addDecimal(&a.ValueReceived, value)
+	})
+}
+
+// updateAddress applies update to the summary of address, creating it when
+// a transaction at block is added and deleting it when its last transaction
+// is removed. The row is read for update like a token balance.
+func updateAddress(db *gorm.DB, chainID uint64, address string, block uint64, sign int, update func(*data.Address) error) error {
+	if sign > 0 {
+		row := data.Address{
+			ChainID:        chainID,
+			Address:        address,
+			FirstSeenBlock: block,
+			LastSeenBlock:  block,
+			ValueSent:      "0",
+			ValueReceived:  "0",
+		}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+			return err
+		}
+	}
+	var summary data.Address
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Take(&summary, "chain_id = ? AND address = ?", chainID, address).Error
+	if err != nil {
+		if sign < 0 && errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if err := update(&summary); err != nil {
+		return err
+	}
+	if summary.TxsSent == 0 && summary.TxsReceived == 0 {
+		return db.Where("chain_id = ? AND address = ?", chainID, address).Delete(&data.Address{}).Error
+	}
+	if sign > 0 {
+		summary.FirstSeenBlock = min(summary.FirstSeenBlock, block)
+		summary.LastSeenBlock = max(summary.LastSeenBlock, block)
+	}
+	// Not Save, which takes chain 0 for a missing key and inserts.
+	return db.Where("chain_id = ? AND address = ?", chainID, address).Select("*").Updates(&summary).Error
+}
+
+// revertAddressTxs removes the transactions of a reorged block from the
+// address summaries, before the transactions are deleted. The first and
+// last seen blocks are recomputed from the address's other transactions,
+// counting contract creations for the contracts they deployed.
+func revertAddressTxs(db *gorm.DB, chainID, number uint64, hash string) error {
+	var txs []*data.Transaction
+	err := db.Where("chain_id = ? AND block_number = ? AND block_hash = ?", chainID, number, hash).Find(&txs).Error
+	if err != nil {
+		return err
+	}
+	touched := map[string]bool{}
+	for _, tx := range txs {
+		if err := applyAddressTx(db, tx, -1); err != nil {
+			return err
+		}
+		touched[tx.From] = true
+		if to := strings.TrimSpace(tx.To); to != "" {
+			touched[to] = true
+		} else if contract := strings.TrimSpace(tx.Contract); contract != "" && tx.Status == 1 {
+			touched[contract] = true
+		}
+	}
+	for address := range touched {
+		var seen struct{ First, Last *uint64 }
+		err := db.Model(&data.Transaction{}).
+			Select("MIN(block_number) AS first, MAX(block_number) AS last").
+			Where(`chain_id = ? AND ("from" = ? OR "to" = ? OR (COALESCE("to", '') = '' AND status = 1 AND contract = ?)) AND block_hash <> ?`,
+				chainID, address, address, address, hash).
+			Scan(&seen).Error
+		if err != nil {
+			return err
+		}
+		if seen.First == nil {
+			continue
+		}
+		err = db.Model(&data.Address{}).Where("chain_id = ? AND address = ?", chainID, address).
+			Updates(map[string]any{"first_seen_block": *seen.First, "last_seen_block": *seen.Last}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addCount(n uint64, sign int) uint64 {
+	if sign < 0 {
+		if n == 0 {
+			return 0
+		}
+		return n - 1
+	}
+	return n + 1
+}
+
+// addDecimal adds delta to the uint256 decimal string at sum.
+func addDecimal(sum *string, delta *big.Int) error {
+	n, ok := new(big.Int).SetString(*sum, 10)
+	if !ok {
+		return fmt.Errorf("invalid decimal %q", *sum)
+	}
+	*sum = n.Add(n, delta).String()
+	return nil
+}
@@ -0,0 +1,24 @@
+package db
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasAddress(t *testing.T) {
+	s := newSuite(t)
+
+	address := "0x0000000000000000000000000000000000000001"
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM transactions WHERE chain_id = $1 AND "from" = $2)`)).
+		WithArgs(mockChainID, address, mockChainID, address, mockChainID, address, mockChainID, address).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	found, err := s.dbMock.HasAddress(context.Background(), address)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
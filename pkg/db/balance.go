@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"gorm.io/gorm/clause"
+)
+
+// InsertBalance stores a balance snapshot, replacing one at the same height
+// from a reorged block.
+func (g *GormDB) InsertBalance(ctx context.Context, balance data.Balance) error {
+	db, cancel := g.write(ctx)
+	defer cancel()
+	balance.ChainID = g.chainID
+	return db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&balance).Error
+}
+
+// GetBalance returns the latest balance snapshot of an address at or before
+// block, or the latest one if block is nil.
+func (g *GormDB) GetBalance(ctx context.Context, address string, block *uint64) (*data.Balance, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	query := db.Where("chain_id = ? AND address = ?", g.chainID, address)
+	if block != nil {
+		query = query.Where("block_number <= ?", *block)
+	}
+	var balance data.Balance
+	if err := query.Order("block_number desc").First(&balance).Error; err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// GetBalanceChart returns the last balance snapshot of an address in each
+// bucket of filter.Period that has one, oldest first.
+func (g *GormDB) GetBalanceChart(ctx context.Context, filter BalanceChartFilter) ([]*data.BalancePoint, error) {
+	seconds, ok := statsPeriodSeconds[filter.Period]
+	if !ok {
+		return nil, &FilterError{"period", "must be hourly or daily"}
+	}
+	bucket := fmt.Sprintf("time - time %% %d", seconds)
+	db, cancel := g.read(ctx)
+	defer cancel()
+	last := db.Model(&data.Balance{}).
+		Select("MAX(block_number)").
+		Where("chain_id = ? AND address = ?", g.chainID, filter.Address).
+		Group(bucket)
+	if filter.FromTime != nil {
+		last = last.Where("time >= ?", filter.Period.Bucket(*filter.FromTime))
+	}
+	if filter.ToTime != nil {
+		last = last.Where("time < ?", filter.Period.Bucket(*filter.ToTime)+seconds)
+	}
+	points := []*data.BalancePoint{}
+	err := db.Model(&data.Balance{}).
+		Select(bucket+" AS time, block_number, balance, nonce").
+		Where("chain_id = ? AND address = ? AND block_number IN (?)", g.chainID, filter.Address, last).
+		Order("block_number asc").
+		Limit(filter.limit()).Offset(filter.offset()).
+		Scan(&points).Error
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
@@ -1,32 +1,225 @@
 package db
 
 import (
+	"context"
+	"errors"
+	"log/slog"
+
 	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"gorm.io/gorm"
 )
 
-func (g *GormDB) InsertBlock(block data.Block) error {
-	return g.Create(&block).Error
+func (g *GormDB) InsertBlock(ctx context.Context, block data.Block) error {
+	db, cancel := g.write(ctx)
+	defer cancel()
+	block.ChainID = g.chainID
+	// Blocks published before the burn was computed have none, the numeric
+	// columns of Postgres reject empty strings.
+	if block.BaseFeeBurned == "" {
+		block.BaseFeeBurned = "0"
+	}
+	if block.BlobFeeBurned == "" {
+		block.BlobFeeBurned = "0"
+	}
+	g.ensurePartition(db, "blocks", block.Number)
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := removeReorgedBlock(tx, block); err != nil {
+			return err
+		}
+		if err := tx.Create(&block).Error; err != nil {
+			return err
+		}
+		if err := applyMinerBlock(tx, &block, 1); err != nil {
+			return err
+		}
+		return markStatsDirty(tx, block.ChainID, block.Time)
+	})
+}
+
+// removeReorgedBlock deletes the block stored at the height of block under
+// another hash, with its transactions, logs, internal transactions, token and
+// NFT transfers, balance snapshots, uncles and contracts deployed in it, as
+// block replaced it on the canonical chain. The token balances, NFT owners
+// and address summaries are reverted and the hour of the removed block is
+// queued for a rollup. Only rows of block's chain are touched.
+func removeReorgedBlock(tx *gorm.DB, block data.Block) error {
+	var old data.Block
+	err := tx.Take(&old, "chain_id = ? AND number = ? AND hash <> ?", block.ChainID, block.Number, block.Hash).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	slog.Info("replacing reorged block", "number", old.Number, "old", old.Hash, "new", block.Hash)
+	if err := removeTokenEvents(tx, old.ChainID, old.Number, old.Hash); err != nil {
+		return err
+	}
+	if err := removeNFTTransfers(tx, old.ChainID, old.Number, old.Hash); err != nil {
+		return err
+	}
+	err = tx.Where("chain_id = ? AND block_number = ? AND block_hash = ?", old.ChainID, old.Number, old.Hash).Delete(&data.Contract{}).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Where("chain_id = ? AND block_number = ? AND block_hash = ?", old.ChainID, old.Number, old.Hash).Delete(&data.InternalTx{}).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Where("chain_id = ? AND block_number = ? AND block_hash = ?", old.ChainID, old.Number, old.Hash).Delete(&data.Balance{}).Error
+	if err != nil {
+		return err
+	}
+	if err := revertMinerUncles(tx, old.ChainID, old.Number, old.Hash); err != nil {
+		return err
+	}
+	err = tx.Where("chain_id = ? AND block_number = ? AND block_hash = ?", old.ChainID, old.Number, old.Hash).Delete(&data.Uncle{}).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Where("chain_id = ? AND block_number = ? AND block_hash = ?", old.ChainID, old.Number, old.Hash).Delete(&data.Log{}).Error
+	if err != nil {
+		return err
+	}
+	if err := revertAddressTxs(tx, old.ChainID, old.Number, old.Hash); err != nil {
+		return err
+	}
+	err = tx.Where("chain_id = ? AND block_number = ? AND block_hash = ?", old.ChainID, old.Number, old.Hash).Delete(&data.Transaction{}).Error
+	if err != nil {
+		return err
+	}
+	if err := applyMinerBlock(tx, &old, -1); err != nil {
+		return err
+	}
+	err = tx.Where("chain_id = ? AND number = ? AND hash = ?", old.ChainID, old.Number, old.Hash).Delete(&data.Block{}).Error
+	if err != nil {
+		return err
+	}
+	return markStatsDirty(tx, old.ChainID, old.Time)
+}
+
+func (g *GormDB) GetBlockByNumber(ctx context.Context, number uint64) (*data.Block, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var block data.Block
+	if err := db.First(&block, "chain_id = ? AND number = ?", g.chainID, number).Error; err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+func (g *GormDB) GetBlockByHash(ctx context.Context, hash string) (*data.Block, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var block data.Block
+	if err := db.Take(&block, "chain_id = ? AND hash = ?", g.chainID, hash).Error; err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+func (g *GormDB) GetFirstBlock(ctx context.Context) (*data.Block, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var block data.Block
+	if err := db.Where("chain_id = ?", g.chainID).Order("number asc").First(&block).Error; err != nil {
+		return nil, err
+	}
+	return &block, nil
 }
 
-func (g *GormDB) GetBlockByNumber(number uint64) (*data.Block, error) {
+func (g *GormDB) GetLatestBlock(ctx context.Context) (*data.Block, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
 	var block data.Block
-	if err := g.First(&block, "number = ?", number).Error; err != nil {
+	if err := db.Where("chain_id = ?", g.chainID).Order("number desc").First(&block).Error; err != nil {
 		return nil, err
 	}
 	return &block, nil
 }
 
-func (g *GormDB) GetFirstBlock() (*data.Block, error) {
+// GetFirstBlockSince returns the earliest block with a timestamp at or after t.
+func (g *GormDB) GetFirstBlockSince(ctx context.Context, t uint64) (*data.Block, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	return blockAtOrAfter(db, g.chainID, t)
+}
+
+// GetBlockAtTime returns the latest block with a timestamp at or before t.
+func (g *GormDB) GetBlockAtTime(ctx context.Context, t uint64) (*data.Block, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	return blockAtOrBefore(db, g.chainID, t)
+}
+
+// blockAtOrAfter and blockAtOrBefore binary search the index on block time
+// rather than probing block numbers, so gaps left by retention or a partial
+// sync don't matter.
+func blockAtOrAfter(db *gorm.DB, chainID, t uint64) (*data.Block, error) {
 	var block data.Block
-	if err := g.Order("number asc").First(&block).Error; err != nil {
+	err := db.Where("chain_id = ? AND time >= ?", chainID, t).Order("time asc, number asc").Take(&block).Error
+	if err != nil {
 		return nil, err
 	}
 	return &block, nil
 }
 
-func (g *GormDB) GetBlocks() ([]*data.Block, error) {
+func blockAtOrBefore(db *gorm.DB, chainID, t uint64) (*data.Block, error) {
+	var block data.Block
+	err := db.Where("chain_id = ? AND time <= ?", chainID, t).Order("time desc, number desc").Take(&block).Error
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+func (g *GormDB) GetBlocks(ctx context.Context) ([]*data.Block, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var blocks []*data.Block
+	if err := db.Find(&blocks, "chain_id = ?", g.chainID).Error; err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+func (g *GormDB) GetBlocksByHashes(ctx context.Context, hashes []string, numbers []uint64) ([]*data.Block, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var blocks []*data.Block
+	query := inBlocks(db.Where("chain_id = ? AND hash IN ?", g.chainID, hashes), "number", numbers)
+	if err := query.Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+func (g *GormDB) FindBlocks(ctx context.Context, filter BlockFilter) ([]*data.Block, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	found, err := resolveTimeRange(db, g.chainID, &filter.FromNumber, &filter.ToNumber, filter.FromTime, filter.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return []*data.Block{}, nil
+	}
+
+	query := db.Model(&data.Block{}).Where("chain_id = ?", g.chainID)
+	if filter.Miner != "" {
+		query = query.Where("miner = ?", filter.Miner)
+	}
+	if filter.FromNumber != nil {
+		query = query.Where("number >= ?", *filter.FromNumber)
+	}
+	if filter.ToNumber != nil {
+		query = query.Where("number <= ?", *filter.ToNumber)
+	}
 	var blocks []*data.Block
-	if err := g.Find(&blocks).Error; err != nil {
+	err = query.Order("number asc").
+		Limit(filter.limit()).Offset(filter.offset()).
+		Find(&blocks).Error
+	if err != nil {
 		return nil, err
 	}
 	return blocks, nil
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"regexp"
 	"testing"
 
@@ -49,18 +50,37 @@ var mockBlocks = []data.Block{
 func TestInsertBlock(t *testing.T) {
 	s := newSuite(t)
 
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(
+		`CREATE TABLE IF NOT EXISTS "blocks_p000000000000" PARTITION OF "blocks" FOR VALUES FROM (0) TO (1000000)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
 	s.sqlMock.ExpectBegin()
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "blocks" WHERE chain_id = $1 AND number = $2 AND hash <> $3 LIMIT $4`)).
+		WithArgs(mockChainID, mockBlocks[0].Number, mockBlocks[0].Hash, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}))
 	s.sqlMock.ExpectExec(regexp.QuoteMeta(
-		`INSERT INTO "blocks" ("hash","number","gas_limit","gas_used","difficulty","time","parent_hash","nonce","miner","size","root_hash","uncle_hash","tx_hash","receipt_hash","extra_data") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15)`)).
+		`INSERT INTO "blocks" ("chain_id","hash","number","gas_limit","gas_used","difficulty","time","parent_hash","nonce","miner","size","root_hash","uncle_hash","tx_hash","receipt_hash","extra_data","base_fee","priority_fees","base_fee_burned","blob_fee_burned","block_reward","uncle_inclusion_reward") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22)`)).
 		WithArgs(
-			mockBlocks[0].Hash, mockBlocks[0].Number, mockBlocks[0].GasLimit, mockBlocks[0].GasUsed, mockBlocks[0].Difficulty,
+			mockChainID, mockBlocks[0].Hash, mockBlocks[0].Number, mockBlocks[0].GasLimit, mockBlocks[0].GasUsed, mockBlocks[0].Difficulty,
 			mockBlocks[0].Time, mockBlocks[0].ParentHash, mockBlocks[0].Nonce, mockBlocks[0].Miner, mockBlocks[0].Size,
 			mockBlocks[0].RootHash, mockBlocks[0].UncleHash, mockBlocks[0].TxHash, mockBlocks[0].ReceiptHash, mockBlocks[0].ExtraData,
+			mockBlocks[0].BaseFee, mockBlocks[0].PriorityFees, "0", "0",
+			mockBlocks[0].BlockReward, mockBlocks[0].UncleInclusionReward,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "miner_rewards"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "miner_rewards" WHERE chain_id = $1 AND miner = $2 LIMIT $3 FOR UPDATE`)).
+		WithArgs(mockChainID, mockBlocks[0].Miner, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"miner", "blocks", "uncles", "block_rewards", "uncle_inclusion_rewards", "uncle_rewards", "priority_fees"}).
+			AddRow(mockBlocks[0].Miner, 0, 0, "0", "0", "0", "0"))
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(`UPDATE "miner_rewards" SET`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO "stats_dirty" ("chain_id", "period", "time") VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`)).
+		WithArgs(mockChainID, "hourly", uint64(1625810400)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
 	s.sqlMock.ExpectCommit()
 
-	err := s.dbMock.InsertBlock(mockBlocks[0])
+	err := s.dbMock.InsertBlock(context.Background(), mockBlocks[0])
 	assert.NoError(t, err)
 	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
 }
@@ -69,8 +89,8 @@ func TestGetBlockByNumber(t *testing.T) {
 	s := newSuite(t)
 
 	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT * FROM "blocks" WHERE number = $1 ORDER BY "blocks"."hash" LIMIT $2`)).
-		WithArgs(mockBlocks[0].Number, 1).
+		`SELECT * FROM "blocks" WHERE chain_id = $1 AND number = $2 ORDER BY "blocks"."chain_id" LIMIT $3`)).
+		WithArgs(mockChainID, mockBlocks[0].Number, 1).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"hash", "number", "gas_limit", "gas_used", "difficulty", "time",
 			"parent_hash", "nonce", "miner", "size", "root_hash", "uncle_hash",
@@ -81,7 +101,7 @@ func TestGetBlockByNumber(t *testing.T) {
 			mockBlocks[0].RootHash, mockBlocks[0].UncleHash, mockBlocks[0].TxHash, mockBlocks[0].ReceiptHash, mockBlocks[0].ExtraData,
 		))
 
-	retrievedBlock, err := s.dbMock.GetBlockByNumber(mockBlocks[0].Number)
+	retrievedBlock, err := s.dbMock.GetBlockByNumber(context.Background(), mockBlocks[0].Number)
 	assert.NoError(t, err)
 	assert.Equal(t, &mockBlocks[0], retrievedBlock)
 	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
@@ -91,7 +111,8 @@ func TestGetFirstBlock(t *testing.T) {
 	s := newSuite(t)
 
 	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT * FROM "blocks" ORDER BY number asc,"blocks"."hash" LIMIT $1`)).
+		`SELECT * FROM "blocks" WHERE chain_id = $1 ORDER BY number asc,"blocks"."chain_id" LIMIT $2`)).
+		WithArgs(mockChainID, 1).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"hash", "number", "gas_limit", "gas_used", "difficulty", "time",
 			"parent_hash", "nonce", "miner", "size", "root_hash", "uncle_hash",
@@ -102,7 +123,29 @@ func TestGetFirstBlock(t *testing.T) {
 			mockBlocks[0].RootHash, mockBlocks[0].UncleHash, mockBlocks[0].TxHash, mockBlocks[0].ReceiptHash, mockBlocks[0].ExtraData,
 		))
 
-	retrievedBlock, err := s.dbMock.GetFirstBlock()
+	retrievedBlock, err := s.dbMock.GetFirstBlock(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &mockBlocks[0], retrievedBlock)
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+func TestGetLatestBlock(t *testing.T) {
+	s := newSuite(t)
+
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "blocks" WHERE chain_id = $1 ORDER BY number desc,"blocks"."chain_id" LIMIT $2`)).
+		WithArgs(mockChainID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"hash", "number", "gas_limit", "gas_used", "difficulty", "time",
+			"parent_hash", "nonce", "miner", "size", "root_hash", "uncle_hash",
+			"tx_hash", "receipt_hash", "extra_data",
+		}).AddRow(
+			mockBlocks[0].Hash, mockBlocks[0].Number, mockBlocks[0].GasLimit, mockBlocks[0].GasUsed, mockBlocks[0].Difficulty,
+			mockBlocks[0].Time, mockBlocks[0].ParentHash, mockBlocks[0].Nonce, mockBlocks[0].Miner, mockBlocks[0].Size,
+			mockBlocks[0].RootHash, mockBlocks[0].UncleHash, mockBlocks[0].TxHash, mockBlocks[0].ReceiptHash, mockBlocks[0].ExtraData,
+		))
+
+	retrievedBlock, err := s.dbMock.GetLatestBlock(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, &mockBlocks[0], retrievedBlock)
 	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
@@ -127,7 +170,7 @@ func TestGetBlocks(t *testing.T) {
 			mockBlocks[1].RootHash, mockBlocks[1].UncleHash, mockBlocks[1].TxHash, mockBlocks[1].ReceiptHash, mockBlocks[1].ExtraData,
 		))
 
-	retrievedBlocks, err := s.dbMock.GetBlocks()
+	retrievedBlocks, err := s.dbMock.GetBlocks(context.Background())
 	assert.NoError(t, err)
 	assert.Len(t, retrievedBlocks, len(mockBlocks))
 	for i, retrievedBlock := range retrievedBlocks {
@@ -135,3 +178,67 @@ func TestGetBlocks(t *testing.T) {
 	}
 	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
 }
+
+func TestGetBlocksByHashes(t *testing.T) {
+	s := newSuite(t)
+
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "blocks" WHERE (chain_id = $1 AND hash IN ($2,$3)) AND number IN ($4,$5)`)).
+		WithArgs(mockChainID, mockBlocks[0].Hash, mockBlocks[1].ParentHash, mockBlocks[0].Number, mockBlocks[1].Number-1).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"hash", "number", "gas_limit", "gas_used", "difficulty", "time",
+			"parent_hash", "nonce", "miner", "size", "root_hash", "uncle_hash",
+			"tx_hash", "receipt_hash", "extra_data",
+		}).AddRow(
+			mockBlocks[0].Hash, mockBlocks[0].Number, mockBlocks[0].GasLimit, mockBlocks[0].GasUsed, mockBlocks[0].Difficulty,
+			mockBlocks[0].Time, mockBlocks[0].ParentHash, mockBlocks[0].Nonce, mockBlocks[0].Miner, mockBlocks[0].Size,
+			mockBlocks[0].RootHash, mockBlocks[0].UncleHash, mockBlocks[0].TxHash, mockBlocks[0].ReceiptHash, mockBlocks[0].ExtraData,
+		))
+
+	retrievedBlocks, err := s.dbMock.GetBlocksByHashes(context.Background(), []string{mockBlocks[0].Hash, mockBlocks[1].ParentHash},
+		[]uint64{mockBlocks[0].Number, mockBlocks[1].Number - 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []*data.Block{&mockBlocks[0]}, retrievedBlocks)
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+func TestFindBlocks(t *testing.T) {
+	s := newSuite(t)
+
+	from, to := uint64(1), uint64(2)
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "blocks" WHERE chain_id = $1 AND number >= $2 AND number <= $3 ORDER BY number asc LIMIT $4 OFFSET $5`)).
+		WithArgs(mockChainID, from, to, 10, 20).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"hash", "number", "gas_limit", "gas_used", "difficulty", "time",
+			"parent_hash", "nonce", "miner", "size", "root_hash", "uncle_hash",
+			"tx_hash", "receipt_hash", "extra_data",
+		}).AddRow(
+			mockBlocks[1].Hash, mockBlocks[1].Number, mockBlocks[1].GasLimit, mockBlocks[1].GasUsed, mockBlocks[1].Difficulty,
+			mockBlocks[1].Time, mockBlocks[1].ParentHash, mockBlocks[1].Nonce, mockBlocks[1].Miner, mockBlocks[1].Size,
+			mockBlocks[1].RootHash, mockBlocks[1].UncleHash, mockBlocks[1].TxHash, mockBlocks[1].ReceiptHash, mockBlocks[1].ExtraData,
+		))
+
+	retrievedBlocks, err := s.dbMock.FindBlocks(context.Background(), BlockFilter{
+		FromNumber: &from,
+		ToNumber:   &to,
+		Page:       Page{Limit: 10, Offset: 20},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*data.Block{&mockBlocks[1]}, retrievedBlocks)
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+func TestFindBlocksDefaultPage(t *testing.T) {
+	s := newSuite(t)
+
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "blocks" WHERE chain_id = $1 ORDER BY number asc LIMIT $2`)).
+		WithArgs(mockChainID, MaxPageSize).
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+
+	retrievedBlocks, err := s.dbMock.FindBlocks(context.Background(), BlockFilter{Page: Page{Limit: MaxPageSize + 1}})
+	assert.NoError(t, err)
+	assert.Empty(t, retrievedBlocks)
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
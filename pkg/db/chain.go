@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// chainTables are the tables keyed by chain id since migration 0015.
+// Signatures are shared by every chain.
+var chainTables = []string{
+	"blocks", "transactions", "logs",
+	"stats_hourly", "stats_daily", "stats_dirty",
+	"tokens", "token_transfers", "token_approvals", "token_balances",
+	"nfts", "nft_transfers", "nft_owners",
+	"contracts", "contract_abis", "internal_txs", "balances", "addresses",
+	"uncles", "miner_rewards",
+}
+
+// AssignChain moves the rows indexed before migration 0015, which belong to
+// chain 0, to chainID. Writes must be stopped while it runs. Each table is
+// moved in its own transaction, so it can be rerun after an interruption.
+func (g *GormDB) AssignChain(chainID uint64) error {
+	if chainID == 0 {
+		return errors.New("chain id must not be 0")
+	}
+	for _, table := range chainTables {
+		res := g.Exec(fmt.Sprintf(`UPDATE %q SET "chain_id" = ? WHERE "chain_id" = 0`, table), chainID)
+		if res.Error != nil {
+			return fmt.Errorf("failed to assign %s to chain %d: %w", table, chainID, res.Error)
+		}
+		slog.Info("assigned chain", "table", table, "chain", chainID, "rows", res.RowsAffected)
+	}
+	return nil
+}
+
+// UnassignedTables returns the tables still holding rows of chain 0, which
+// AssignChain moves to their chain.
+func (g *GormDB) UnassignedTables(ctx context.Context) ([]string, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var tables []string
+	for _, table := range chainTables {
+		var exists bool
+		err := db.Raw(fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %q WHERE "chain_id" = 0)`, table)).Scan(&exists).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s for chain 0 rows: %w", table, err)
+		}
+		if exists {
+			tables = append(tables, table)
+		}
+	}
+	return tables, nil
+}
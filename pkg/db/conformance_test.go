@@ -0,0 +1,1272 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// The conformance suite runs the same behaviour tests against every backend.
+// SQLite always runs, Postgres runs when TEST_DB_URL points at a disposable
+// database, which is reset before each test.
+
+type backend struct {
+	name string
+	open func(t *testing.T) DB
+}
+
+func backends(t *testing.T) []backend {
+	bs := []backend{{
+		name: "sqlite",
+		open: func(t *testing.T) DB {
+			return openMigrated(t, sqliteScheme+filepath.Join(t.TempDir(), "indexer.db"), false)
+		},
+	}}
+	if url := os.Getenv("TEST_DB_URL"); url != "" {
+		bs = append(bs, backend{
+			name: "postgres",
+			open: func(t *testing.T) DB {
+				return openMigrated(t, url, true)
+			},
+		})
+	} else {
+		t.Log("TEST_DB_URL is not set, skipping postgres conformance tests")
+	}
+	return bs
+}
+
+func openMigrated(t *testing.T, url string, reset bool) DB {
+	g, err := Open(url)
+	require.NoError(t, err)
+	t.Cleanup(func() { g.Close() })
+	m, err := NewMigrator(g)
+	require.NoError(t, err)
+	if reset {
+		require.NoError(t, m.To(0))
+	}
+	require.NoError(t, m.Up())
+	return g
+}
+
+func runConformance(t *testing.T, test func(t *testing.T, db DB)) {
+	for _, b := range backends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			test(t, b.open(t))
+		})
+	}
+}
+
+func hash(n byte) string {
+	h := []byte("0x0000000000000000000000000000000000000000000000000000000000000000")
+	h[len(h)-1] = '0' + n%10
+	h[len(h)-2] = '0' + n/10
+	return string(h)
+}
+
+func address(n byte) string {
+	return "0x" + hash(n)[len(hash(n))-40:]
+}
+
+func conformanceBlock(n uint64) data.Block {
+	return data.Block{
+		Hash:        hash(byte(n)),
+		Number:      n,
+		GasLimit:    30000000,
+		GasUsed:     21000 * n,
+		Difficulty:  n,
+		Time:        1700000000 + 12*n,
+		ParentHash:  hash(byte(n - 1)),
+		Nonce:       n,
+		Miner:       address(byte(n % 2)),
+		Size:        1000 + n,
+		RootHash:    hash(90),
+		UncleHash:   hash(91),
+		TxHash:      hash(92),
+		ReceiptHash: hash(93),
+		ExtraData:   []byte{byte(n)},
+
+		PriorityFees:         fmt.Sprint(1000 * n),
+		BaseFeeBurned:        fmt.Sprintf("%d0000000000000000000", n),
+		BlobFeeBurned:        fmt.Sprint(n),
+		BlockReward:          "2000000000000000000",
+		UncleInclusionReward: "0",
+	}
+}
+
+func conformanceTx(n byte, block uint64, from, to string) data.Transaction {
+	return data.Transaction{
+		Hash:        hash(40 + n),
+		From:        from,
+		To:          to,
+		Contract:    address(0),
+		Value:       uint64(n),
+		Data:        []byte{0xa9, n},
+		Gas:         21000,
+		GasPrice:    1000000000,
+		Cost:        21000000000000,
+		Nonce:       uint64(n),
+		Status:      1,
+		BlockHash:   hash(byte(block)),
+		BlockNumber: block,
+	}
+}
+
+func conformanceLog(block uint64, index uint, tx string, addr string) data.Log {
+	return data.Log{
+		BlockHash:   hash(byte(block)),
+		Index:       index,
+		BlockNumber: block,
+		TxHash:      tx,
+		Address:     addr,
+		Topic0:      hash(70),
+		Topic1:      hash(71),
+		Topic2:      hash(72),
+		Topic3:      hash(73),
+		Data:        []byte{byte(index)},
+	}
+}
+
+func seedBlocks(t *testing.T, db DB, numbers ...uint64) []data.Block {
+	var blocks []data.Block
+	for _, n := range numbers {
+		b := conformanceBlock(n)
+		require.NoError(t, db.InsertBlock(context.Background(), b))
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+func TestConformanceBlocks(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		blocks := seedBlocks(t, db, 3, 1, 2)
+
+		block, err := db.GetBlockByNumber(context.Background(), 2)
+		require.NoError(t, err)
+		assert.Equal(t, blocks[2], *block)
+
+		_, err = db.GetBlockByNumber(context.Background(), 4)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		first, err := db.GetFirstBlock(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), first.Number)
+
+		byHash, err := db.GetBlocksByHashes(context.Background(), []string{blocks[0].Hash, hash(99)}, nil)
+		require.NoError(t, err)
+		require.Len(t, byHash, 1)
+		assert.Equal(t, blocks[0], *byHash[0])
+
+		from := uint64(2)
+		found, err := db.FindBlocks(context.Background(), BlockFilter{FromNumber: &from})
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{2, 3}, blockNumbers(found))
+
+		found, err = db.FindBlocks(context.Background(), BlockFilter{Page: Page{Limit: 1, Offset: 1}})
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{2}, blockNumbers(found))
+
+		assert.Error(t, db.InsertBlock(context.Background(), blocks[0]), "duplicate block hash")
+	})
+}
+
+func TestConformanceBlockLookups(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		blocks := seedBlocks(t, db, 1, 2, 3, 5)
+
+		block, err := db.GetBlockByHash(ctx, blocks[1].Hash)
+		require.NoError(t, err)
+		assert.Equal(t, blocks[1], *block)
+
+		_, err = db.GetBlockByHash(ctx, hash(99))
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		block, err = db.GetBlockAtTime(ctx, blocks[1].Time)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), block.Number)
+
+		// Block 4 is missing, its time resolves to block 3.
+		block, err = db.GetBlockAtTime(ctx, conformanceBlock(4).Time)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), block.Number)
+
+		_, err = db.GetBlockAtTime(ctx, blocks[0].Time-1)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		found, err := db.FindBlocks(ctx, BlockFilter{Miner: address(1)})
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{1, 3, 5}, blockNumbers(found))
+
+		fromTime, toTime := blocks[1].Time, conformanceBlock(4).Time
+		found, err = db.FindBlocks(ctx, BlockFilter{Miner: address(1), FromTime: &fromTime, ToTime: &toTime})
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{3}, blockNumbers(found))
+
+		future := conformanceBlock(6).Time
+		found, err = db.FindBlocks(ctx, BlockFilter{FromTime: &future})
+		require.NoError(t, err)
+		assert.Empty(t, found)
+	})
+}
+
+func TestConformanceTxs(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		txs := []data.Transaction{
+			conformanceTx(3, 2, address(10), address(11)),
+			conformanceTx(1, 1, address(10), address(12)),
+			conformanceTx(2, 2, address(11), address(12)),
+		}
+		for _, tx := range txs {
+			require.NoError(t, db.InsertTx(context.Background(), tx))
+		}
+
+		tx, err := db.GetTxByHash(context.Background(), txs[1].Hash, nil)
+		require.NoError(t, err)
+		assert.Equal(t, txs[1], *tx)
+
+		_, err = db.GetTxByHash(context.Background(), hash(99), nil)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		// A block number limits the lookup to that block.
+		tx, err = db.GetTxByHash(context.Background(), txs[1].Hash, &txs[1].BlockNumber)
+		require.NoError(t, err)
+		assert.Equal(t, txs[1], *tx)
+		_, err = db.GetTxByHash(context.Background(), txs[1].Hash, &txs[0].BlockNumber)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		byHash, err := db.GetTxsByHashes(context.Background(), []string{txs[0].Hash, txs[1].Hash}, []uint64{2})
+		require.NoError(t, err)
+		assert.Equal(t, []string{txs[0].Hash}, txHashes(byHash))
+
+		byBlock, err := db.GetTxsByBlockHashes(context.Background(), []string{hash(2)}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{txs[2].Hash, txs[0].Hash}, txHashes(byBlock))
+		byBlock, err = db.GetTxsByBlockHashes(context.Background(), []string{hash(2)}, []uint64{2})
+		require.NoError(t, err)
+		assert.Equal(t, []string{txs[2].Hash, txs[0].Hash}, txHashes(byBlock))
+
+		found, err := db.FindTxs(context.Background(), TxFilter{From: address(10)})
+		require.NoError(t, err)
+		assert.Equal(t, []string{txs[1].Hash, txs[0].Hash}, txHashes(found))
+
+		from := uint64(2)
+		found, err = db.FindTxs(context.Background(), TxFilter{To: address(12), FromBlock: &from})
+		require.NoError(t, err)
+		assert.Equal(t, []string{txs[2].Hash}, txHashes(found))
+
+		found, err = db.FindTxs(context.Background(), TxFilter{Page: Page{Limit: 2, Offset: 1}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{txs[2].Hash, txs[0].Hash}, txHashes(found))
+	})
+}
+
+func TestConformanceTxFilters(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		seedBlocks(t, db, 1, 2, 3)
+		failed := conformanceTx(1, 1, address(10), address(11))
+		failed.Status = 0
+		failed.Value = 500
+		creation := conformanceTx(2, 2, address(10), "")
+		creation.Data = []byte{0x60, 0x80, 0x60, 0x40}
+		call := conformanceTx(3, 3, address(12), address(11))
+		call.GasPrice = 5000000000
+		for _, tx := range []data.Transaction{failed, creation, call} {
+			require.NoError(t, db.InsertTx(context.Background(), tx))
+		}
+
+		ctx := context.Background()
+		status := uint64(0)
+		found, err := db.FindTxs(ctx, TxFilter{To: address(11), Status: &status})
+		require.NoError(t, err)
+		assert.Equal(t, []string{failed.Hash}, txHashes(found))
+
+		yes := true
+		found, err = db.FindTxs(ctx, TxFilter{ContractCreation: &yes})
+		require.NoError(t, err)
+		assert.Equal(t, []string{creation.Hash}, txHashes(found))
+
+		_, err = db.FindTxs(ctx, TxFilter{From: address(10), MethodSelector: []byte{0xa9, 1}})
+		assert.Error(t, err)
+		found, err = db.FindTxs(ctx, TxFilter{From: address(10), MethodSelector: creation.Data})
+		require.NoError(t, err)
+		assert.Equal(t, []string{creation.Hash}, txHashes(found))
+
+		fromTime, toTime := conformanceBlock(2).Time, conformanceBlock(3).Time-1
+		found, err = db.FindTxs(ctx, TxFilter{FromTime: &fromTime, ToTime: &toTime})
+		require.NoError(t, err)
+		assert.Equal(t, []string{creation.Hash}, txHashes(found))
+
+		future := conformanceBlock(4).Time
+		found, err = db.FindTxs(ctx, TxFilter{FromTime: &future})
+		require.NoError(t, err)
+		assert.Empty(t, found)
+
+		minValue, minGasPrice := uint64(100), uint64(2000000000)
+		found, err = db.FindTxs(ctx, TxFilter{To: address(11), MinValue: &minValue})
+		require.NoError(t, err)
+		assert.Equal(t, []string{failed.Hash}, txHashes(found))
+		found, err = db.FindTxs(ctx, TxFilter{To: address(11), MinGasPrice: &minGasPrice})
+		require.NoError(t, err)
+		assert.Equal(t, []string{call.Hash}, txHashes(found))
+
+		found, err = db.FindTxs(ctx, TxFilter{To: address(11), Sort: TxSortValueDesc})
+		require.NoError(t, err)
+		assert.Equal(t, []string{failed.Hash, call.Hash}, txHashes(found))
+	})
+}
+
+func TestConformanceLogs(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		logs := []data.Log{
+			conformanceLog(2, 0, hash(41), address(20)),
+			conformanceLog(1, 1, hash(40), address(21)),
+			conformanceLog(1, 0, hash(40), address(20)),
+		}
+		for _, l := range logs {
+			require.NoError(t, db.InsertLog(context.Background(), l))
+		}
+
+		byTx, err := db.GetLogsByTxHashes(context.Background(), []string{hash(40)})
+		require.NoError(t, err)
+		require.Len(t, byTx, 2)
+		assert.Equal(t, logs[2], *byTx[0])
+		assert.Equal(t, logs[1], *byTx[1])
+
+		found, err := db.FindLogs(context.Background(), LogFilter{Address: address(20)})
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+		assert.Equal(t, uint64(1), found[0].BlockNumber)
+		assert.Equal(t, uint64(2), found[1].BlockNumber)
+
+		to := uint64(1)
+		found, err = db.FindLogs(context.Background(), LogFilter{Topic0: hash(70), ToBlock: &to})
+		require.NoError(t, err)
+		assert.Len(t, found, 2)
+	})
+}
+
+func TestConformanceHasAddress(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		seedBlocks(t, db, 1)
+		require.NoError(t, db.InsertTx(context.Background(), conformanceTx(1, 1, address(10), address(11))))
+		require.NoError(t, db.InsertLog(context.Background(), conformanceLog(1, 0, hash(41), address(20))))
+
+		for _, addr := range []string{address(1), address(10), address(11), address(20)} {
+			ok, err := db.HasAddress(context.Background(), addr)
+			require.NoError(t, err)
+			assert.True(t, ok, addr)
+		}
+		ok, err := db.HasAddress(context.Background(), address(30))
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestConformanceStream(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		seedBlocks(t, db, 4, 2, 3, 1)
+
+		var numbers []uint64
+		err := db.StreamBlocks(context.Background(), 2, 3, func(b *data.Block) error {
+			numbers = append(numbers, b.Number)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{2, 3}, numbers)
+	})
+}
+
+func blockNumbers(blocks []*data.Block) []uint64 {
+	numbers := make([]uint64, 0, len(blocks))
+	for _, b := range blocks {
+		numbers = append(numbers, b.Number)
+	}
+	return numbers
+}
+
+func txHashes(txs []*data.Transaction) []string {
+	hashes := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		hashes = append(hashes, tx.Hash)
+	}
+	return hashes
+}
+
+func TestConformancePrune(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		seedBlocks(t, db, 1, 2, 3, 4)
+		for _, tx := range []data.Transaction{
+			conformanceTx(1, 1, address(10), address(11)),
+			conformanceTx(3, 3, address(10), address(11)),
+		} {
+			require.NoError(t, db.InsertTx(context.Background(), tx))
+		}
+		require.NoError(t, db.InsertLog(context.Background(), conformanceLog(1, 0, hash(41), address(20))))
+		require.NoError(t, db.InsertLog(context.Background(), conformanceLog(3, 0, hash(43), address(20))))
+
+		latest, err := db.GetLatestBlock(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(4), latest.Number)
+
+		since, err := db.GetFirstBlockSince(context.Background(), conformanceBlock(2).Time)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), since.Number)
+
+		_, err = db.GetFirstBlockSince(context.Background(), conformanceBlock(5).Time)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		deleted, err := db.DeleteBlockRange(context.Background(), 0, 3)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+
+		first, err := db.GetFirstBlock(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), first.Number)
+
+		txs, err := db.GetTxs(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{hash(43)}, txHashes(txs))
+
+		logs, err := db.GetLogsByTxHashes(context.Background(), []string{hash(41), hash(43)})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Equal(t, hash(43), logs[0].TxHash)
+
+		require.NoError(t, db.Ping(context.Background()))
+	})
+}
+
+func TestConformanceStats(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		seedBlocks(t, db, 1, 2, 3, 4)
+		cheap := conformanceTx(1, 1, address(10), address(11))
+		failed := conformanceTx(2, 2, address(10), address(11))
+		failed.GasPrice, failed.Status = 3000000000, 0
+		deploy := conformanceTx(3, 3, address(12), "")
+		deploy.GasPrice = 2000000000
+		for _, tx := range []data.Transaction{cheap, failed, deploy} {
+			require.NoError(t, db.InsertTx(ctx, tx))
+		}
+
+		hour := StatsHourly.Bucket(conformanceBlock(1).Time)
+		hours, err := db.GetDirtyStats(ctx, StatsHourly, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{hour}, hours)
+
+		require.NoError(t, db.RollupStats(ctx, StatsHourly, hour))
+		hours, err = db.GetDirtyStats(ctx, StatsHourly, 10)
+		require.NoError(t, err)
+		assert.Empty(t, hours)
+		day := StatsDaily.Bucket(hour)
+		days, err := db.GetDirtyStats(ctx, StatsDaily, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{day}, days)
+		require.NoError(t, db.RollupStats(ctx, StatsDaily, day))
+
+		expected := data.Stats{
+			Blocks:              4,
+			Txs:                 3,
+			GasUsed:             21000 * (1 + 2 + 3 + 4),
+			AvgGasPrice:         2000000000,
+			MedianGasPrice:      2000000000,
+			ActiveAddresses:     3,
+			ContractDeployments: 1,
+			FailedTxs:           1,
+			FailedTxRatio:       1.0 / 3,
+			AvgBlockTime:        12,
+			BaseFeeBurned:       "100000000000000000000",
+			BlobFeeBurned:       "10",
+		}
+		for period, start := range map[StatsPeriod]uint64{StatsHourly: hour, StatsDaily: day} {
+			stats, err := db.FindStats(ctx, StatsFilter{Period: period})
+			require.NoError(t, err)
+			require.Len(t, stats, 1, period)
+			expected.Time = start
+			assert.Equal(t, expected, *stats[0], period)
+		}
+
+		// A block replacing a stored one at the same height removes the old
+		// block's transactions and queues the hour again.
+		reorged := conformanceBlock(3)
+		reorged.Hash = hash(63)
+		require.NoError(t, db.InsertBlock(ctx, reorged))
+		block, err := db.GetBlockByNumber(ctx, 3)
+		require.NoError(t, err)
+		assert.Equal(t, hash(63), block.Hash)
+		_, err = db.GetTxByHash(ctx, deploy.Hash, nil)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		hours, err = db.GetDirtyStats(ctx, StatsHourly, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{hour}, hours)
+		require.NoError(t, db.RollupStats(ctx, StatsHourly, hour))
+		stats, err := db.FindStats(ctx, StatsFilter{Period: StatsHourly, FromTime: &hour})
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		assert.Equal(t, uint64(2), stats[0].Txs)
+		assert.Equal(t, uint64(2000000000), stats[0].MedianGasPrice)
+		assert.Zero(t, stats[0].ContractDeployments)
+
+		_, err = db.FindStats(ctx, StatsFilter{Period: "weekly"})
+		var filterErr *FilterError
+		assert.ErrorAs(t, err, &filterErr)
+	})
+}
+
+func TestConformanceGas(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		_, err := db.GetGasOracle(ctx, 3)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		for n := uint64(1); n <= 4; n++ {
+			block := conformanceBlock(n)
+			block.BaseFee = n * 1000000000
+			require.NoError(t, db.InsertBlock(ctx, block))
+		}
+		tips := []struct {
+			n     byte
+			block uint64
+			tip   uint64
+		}{{1, 1, 100000000000}, {2, 2, 1000000000}, {3, 3, 2000000000}, {4, 3, 5000000000}, {5, 4, 3000000000}}
+		for _, tt := range tips {
+			tx := conformanceTx(tt.n, tt.block, address(10), address(11))
+			tx.PriorityFee = tt.tip
+			require.NoError(t, db.InsertTx(ctx, tx))
+		}
+
+		oracle, err := db.GetGasOracle(ctx, 3)
+		require.NoError(t, err)
+		next := uint64(3502800000)
+		assert.Equal(t, &data.GasOracle{
+			FromBlock:   2,
+			ToBlock:     4,
+			BaseFee:     4000000000,
+			NextBaseFee: next,
+			Slow:        data.FeeSuggestion{MaxPriorityFee: 1000000000, MaxFee: next + 1000000000},
+			Standard:    data.FeeSuggestion{MaxPriorityFee: 2000000000, MaxFee: next + 2000000000},
+			Fast:        data.FeeSuggestion{MaxPriorityFee: 3000000000, MaxFee: next + 3000000000},
+		}, oracle)
+
+		history, err := db.GetGasHistory(ctx, 3)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		assert.Equal(t, []uint64{2, 3, 4}, []uint64{history[0].Number, history[1].Number, history[2].Number})
+		assert.Equal(t, data.GasHistory{
+			Number:         3,
+			Time:           conformanceBlock(3).Time,
+			BaseFee:        3000000000,
+			GasUsed:        conformanceBlock(3).GasUsed,
+			GasLimit:       conformanceBlock(3).GasLimit,
+			Txs:            2,
+			MinPriorityFee: 2000000000,
+			AvgPriorityFee: 3500000000,
+			MaxPriorityFee: 5000000000,
+		}, *history[1])
+
+		history, err = db.GetGasHistory(ctx, 100)
+		require.NoError(t, err)
+		assert.Len(t, history, 4)
+	})
+}
+
+func conformanceTransfer(block uint64, index uint, from, to, value string) data.TokenTransfer {
+	return data.TokenTransfer{
+		BlockHash:   hash(byte(block)),
+		LogIndex:    index,
+		BlockNumber: block,
+		TxHash:      hash(40 + byte(block)),
+		Token:       address(30),
+		From:        from,
+		To:          to,
+		Value:       value,
+	}
+}
+
+func TestConformanceTokens(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		seedBlocks(t, db, 1, 2, 3)
+		token := data.Token{Address: address(30), Name: "Token", Symbol: "TKN", Decimals: 18}
+		require.NoError(t, db.InsertToken(ctx, token))
+		token.Name = "Renamed"
+		require.NoError(t, db.InsertToken(ctx, token))
+
+		// Values overflow uint64.
+		mint := conformanceTransfer(1, 0, address(0), address(10), "5000000000000000000000")
+		send := conformanceTransfer(2, 0, address(10), address(11), "800000000000000000000")
+		reorged := conformanceTransfer(3, 1, address(11), address(12), "800000000000000000000")
+		for _, transfer := range []data.TokenTransfer{mint, send, send, reorged} {
+			require.NoError(t, db.InsertTokenTransfer(ctx, transfer))
+		}
+		require.NoError(t, db.InsertTokenApproval(ctx, data.TokenApproval{
+			BlockHash: hash(3), LogIndex: 0, BlockNumber: 3, TxHash: hash(43),
+			Token: address(30), Owner: address(11), Spender: address(13), Value: "1",
+		}))
+		bad := conformanceTransfer(3, 2, address(11), address(12), "0x10")
+		assert.Error(t, db.InsertTokenTransfer(ctx, bad))
+
+		holders, err := db.GetTokenHolders(ctx, address(30), Page{})
+		require.NoError(t, err)
+		assert.Equal(t, []*data.TokenBalance{
+			{Token: address(30), Holder: address(10), Balance: "4200000000000000000000"},
+			{Token: address(30), Holder: address(12), Balance: "800000000000000000000"},
+		}, holders)
+
+		from := uint64(2)
+		transfers, err := db.FindTokenTransfers(ctx, TokenTransferFilter{Token: address(30), FromBlock: &from})
+		require.NoError(t, err)
+		require.Len(t, transfers, 2)
+		assert.Equal(t, send, *transfers[0])
+
+		holdings, err := db.GetAddressTokens(ctx, address(10))
+		require.NoError(t, err)
+		assert.Equal(t, []*data.TokenHolding{
+			{Token: address(30), Name: "Renamed", Symbol: "TKN", Decimals: 18, Balance: "4200000000000000000000"},
+		}, holdings)
+
+		// Replacing block 3 reverts its transfer.
+		block := conformanceBlock(3)
+		block.Hash = hash(63)
+		require.NoError(t, db.InsertBlock(ctx, block))
+		holders, err = db.GetTokenHolders(ctx, address(30), Page{})
+		require.NoError(t, err)
+		assert.Equal(t, []*data.TokenBalance{
+			{Token: address(30), Holder: address(10), Balance: "4200000000000000000000"},
+			{Token: address(30), Holder: address(11), Balance: "800000000000000000000"},
+		}, holders)
+		holdings, err = db.GetAddressTokens(ctx, address(12))
+		require.NoError(t, err)
+		assert.Empty(t, holdings)
+
+		// Pruning keeps the balances.
+		_, err = db.DeleteBlockRange(ctx, 0, 2)
+		require.NoError(t, err)
+		transfers, err = db.FindTokenTransfers(ctx, TokenTransferFilter{Token: address(30)})
+		require.NoError(t, err)
+		assert.Equal(t, []*data.TokenTransfer{&send}, transfers)
+		holders, err = db.GetTokenHolders(ctx, address(30), Page{Limit: 1, Offset: 1})
+		require.NoError(t, err)
+		require.Len(t, holders, 1)
+		assert.Equal(t, address(11), holders[0].Holder)
+	})
+}
+
+func conformanceNFTTransfer(block uint64, batch uint, tokenID, from, to, amount string) data.NFTTransfer {
+	return data.NFTTransfer{
+		BlockHash:   hash(byte(block)),
+		LogIndex:    0,
+		BatchIndex:  batch,
+		BlockNumber: block,
+		TxHash:      hash(40 + byte(block)),
+		Contract:    address(31),
+		TokenID:     tokenID,
+		Standard:    data.StandardERC1155,
+		Operator:    from,
+		From:        from,
+		To:          to,
+		Amount:      amount,
+	}
+}
+
+func TestConformanceNFTs(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		seedBlocks(t, db, 1, 2, 3)
+		// Token ids overflow uint64.
+		maxID := "115792089237316195423570985008687907853269984665640564039457584007913129639935"
+		for _, id := range []string{"2", "10", maxID} {
+			require.NoError(t, db.InsertNFT(ctx, data.NFT{Contract: address(31), TokenID: id, Standard: data.StandardERC1155}))
+		}
+		require.NoError(t, db.InsertNFT(ctx, data.NFT{Contract: address(31), TokenID: "2", Standard: data.StandardERC1155, URI: "ipfs://2"}))
+
+		for _, transfer := range []data.NFTTransfer{
+			conformanceNFTTransfer(1, 0, "2", address(0), address(10), "5"),
+			conformanceNFTTransfer(1, 1, "10", address(0), address(10), "1"),
+			conformanceNFTTransfer(1, 2, maxID, address(0), address(10), "1"),
+			conformanceNFTTransfer(2, 0, "2", address(10), address(11), "3"),
+			conformanceNFTTransfer(2, 0, "2", address(10), address(11), "3"),
+			conformanceNFTTransfer(3, 0, "10", address(10), address(12), "1"),
+		} {
+			require.NoError(t, db.InsertNFTTransfer(ctx, transfer))
+		}
+
+		nft, err := db.GetNFT(ctx, address(31), "2")
+		require.NoError(t, err)
+		assert.Equal(t, "ipfs://2", nft.URI)
+		assert.Equal(t, []*data.NFTOwner{
+			{Contract: address(31), TokenID: "2", Owner: address(11), Balance: "3"},
+			{Contract: address(31), TokenID: "2", Owner: address(10), Balance: "2"},
+		}, nft.Owners)
+		_, err = db.GetNFT(ctx, address(31), "3")
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		holdings, err := db.GetAddressNFTs(ctx, NFTOwnerFilter{Owner: address(10)})
+		require.NoError(t, err)
+		assert.Equal(t, []*data.NFTHolding{
+			{Contract: address(31), TokenID: "2", Standard: data.StandardERC1155, URI: "ipfs://2", Balance: "2"},
+			{Contract: address(31), TokenID: maxID, Standard: data.StandardERC1155, Balance: "1"},
+		}, holdings)
+
+		// Replacing block 3 gives token 10 back to its previous owner.
+		block := conformanceBlock(3)
+		block.Hash = hash(63)
+		require.NoError(t, db.InsertBlock(ctx, block))
+		nft, err = db.GetNFT(ctx, address(31), "10")
+		require.NoError(t, err)
+		require.Len(t, nft.Owners, 1)
+		assert.Equal(t, address(10), nft.Owners[0].Owner)
+
+		history, err := db.FindNFTTransfers(ctx, NFTTransferFilter{Contract: address(31), TokenID: "2"})
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, address(11), history[1].To)
+
+		holdings, err = db.GetAddressNFTs(ctx, NFTOwnerFilter{Owner: address(10), Contract: address(31), Page: Page{Offset: 1, Limit: 1}})
+		require.NoError(t, err)
+		require.Len(t, holdings, 1)
+		assert.Equal(t, "10", holdings[0].TokenID)
+	})
+}
+
+func TestConformanceContracts(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		seedBlocks(t, db, 1, 2)
+
+		// Migration 8 registers the contracts deployed before it.
+		m, err := NewMigrator(db.(*GormDB))
+		require.NoError(t, err)
+		require.NoError(t, m.To(7))
+		deploy := conformanceTx(1, 1, address(10), "")
+		deploy.Contract = address(50)
+		// Transactions have no gas used or chain id at migration 7.
+		require.NoError(t, db.(*GormDB).Omit("gas_used", "chain_id").Create(&deploy).Error)
+		require.NoError(t, m.Up())
+		contract, err := db.GetContract(ctx, address(50))
+		require.NoError(t, err)
+		assert.Equal(t, &data.Contract{
+			Address:     address(50),
+			Deployer:    address(10),
+			TxHash:      deploy.Hash,
+			BlockHash:   hash(1),
+			BlockNumber: 1,
+			Interfaces:  []string{},
+		}, contract)
+
+		token := data.Contract{
+			Address:      address(51),
+			Deployer:     address(10),
+			TxHash:       hash(42),
+			BlockHash:    hash(2),
+			BlockNumber:  2,
+			BytecodeHash: hash(80),
+			Interfaces:   []string{data.StandardERC165, data.StandardERC721},
+		}
+		internal := data.Contract{
+			Address:     address(52),
+			Deployer:    address(51),
+			TxHash:      hash(42),
+			BlockHash:   hash(2),
+			BlockNumber: 2,
+			Internal:    true,
+		}
+		for _, c := range []data.Contract{token, internal} {
+			require.NoError(t, db.InsertContract(ctx, c))
+		}
+		contract, err = db.GetContract(ctx, address(51))
+		require.NoError(t, err)
+		assert.Equal(t, token, *contract)
+
+		found, err := db.FindContracts(ctx, ContractFilter{Deployer: address(10)})
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+		assert.Equal(t, address(50), found[0].Address)
+		assert.Equal(t, address(51), found[1].Address)
+		found, err = db.FindContracts(ctx, ContractFilter{Page: Page{Offset: 2}})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.True(t, found[0].Internal)
+
+		// Contracts deployed in a reorged block are removed.
+		block := conformanceBlock(2)
+		block.Hash = hash(62)
+		require.NoError(t, db.InsertBlock(ctx, block))
+		_, err = db.GetContract(ctx, address(51))
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+		found, err = db.FindContracts(ctx, ContractFilter{})
+		require.NoError(t, err)
+		assert.Len(t, found, 1)
+	})
+}
+
+func TestConformanceABIs(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		require.NoError(t, db.InsertContractABI(ctx, data.ContractABI{Address: address(1), ABI: "[]"}))
+		require.NoError(t, db.InsertContractABI(ctx, data.ContractABI{Address: address(1), ABI: `[{"type":"fallback"}]`}))
+		abis, err := db.GetContractABIs(ctx, []string{address(1), address(2)})
+		require.NoError(t, err)
+		assert.Equal(t, []*data.ContractABI{{Address: address(1), ABI: `[{"type":"fallback"}]`}}, abis)
+
+		transfer := data.Signature{Hash: "0xa9059cbb", Signature: "transfer(address,uint256)"}
+		collision := data.Signature{Hash: "0xa9059cbb", Signature: "many_msg_babbage(bytes1)"}
+		require.NoError(t, db.InsertSignatures(ctx, []data.Signature{transfer}))
+		require.NoError(t, db.InsertSignatures(ctx, []data.Signature{transfer, collision}))
+		require.NoError(t, db.InsertSignatures(ctx, nil))
+		signatures, err := db.GetSignatures(ctx, []string{"0xa9059cbb", "0x095ea7b3"})
+		require.NoError(t, err)
+		assert.Equal(t, []*data.Signature{&collision, &transfer}, signatures)
+	})
+}
+
+func TestConformanceInternalTxs(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		seedBlocks(t, db, 1, 3)
+
+		internalTx := func(block, index byte, txHash, from, to string) data.InternalTx {
+			return data.InternalTx{
+				BlockHash:   hash(block),
+				TxHash:      txHash,
+				TraceIndex:  uint(index),
+				BlockNumber: uint64(block),
+				Depth:       1,
+				Type:        "CALL",
+				From:        from,
+				To:          to,
+				Value:       "1000000000000000000000",
+				Gas:         21000,
+			}
+		}
+		call := internalTx(1, 0, hash(41), address(10), address(11))
+		nested := internalTx(1, 1, hash(41), address(11), address(12))
+		nested.Depth = 2
+		nested.Error = "execution reverted"
+		nested.Reverted = true
+		later := internalTx(2, 0, hash(42), address(12), address(10))
+		reorged := internalTx(3, 0, hash(43), address(10), address(13))
+		for _, tx := range []data.InternalTx{later, call, nested, call, reorged} {
+			require.NoError(t, db.InsertInternalTx(ctx, tx))
+		}
+
+		found, err := db.FindInternalTxs(ctx, InternalTxFilter{TxHash: hash(41)})
+		require.NoError(t, err)
+		assert.Equal(t, []*data.InternalTx{&call, &nested}, found)
+
+		found, err = db.FindInternalTxs(ctx, InternalTxFilter{Address: address(10)})
+		require.NoError(t, err)
+		assert.Equal(t, []*data.InternalTx{&call, &later, &reorged}, found)
+		found, err = db.FindInternalTxs(ctx, InternalTxFilter{Address: address(10), Page: Page{Limit: 1, Offset: 1}})
+		require.NoError(t, err)
+		assert.Equal(t, []*data.InternalTx{&later}, found)
+
+		// Internal transactions of a reorged block are removed.
+		block := conformanceBlock(3)
+		block.Hash = hash(63)
+		require.NoError(t, db.InsertBlock(ctx, block))
+		found, err = db.FindInternalTxs(ctx, InternalTxFilter{Address: address(13)})
+		require.NoError(t, err)
+		assert.Empty(t, found)
+
+		_, err = db.DeleteBlockRange(ctx, 0, 2)
+		require.NoError(t, err)
+		found, err = db.FindInternalTxs(ctx, InternalTxFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []*data.InternalTx{&later}, found)
+	})
+}
+
+func TestConformanceBalances(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		seedBlocks(t, db, 1, 2, 3)
+
+		hour := uint64(1700002800)
+		balance := func(block uint64, time uint64, wei string, nonce uint64) data.Balance {
+			return data.Balance{
+				Address: address(10), BlockNumber: block, BlockHash: hash(byte(block)),
+				Time: time, Balance: wei, Nonce: nonce,
+			}
+		}
+		first := balance(1, hour-10, "100000000000000000000", 0)
+		second := balance(2, hour+10, "99000000000000000000", 1)
+		third := balance(3, hour+20, "98000000000000000000", 2)
+		other := data.Balance{Address: address(11), BlockNumber: 2, BlockHash: hash(2), Time: hour + 10, Balance: "1"}
+		for _, b := range []data.Balance{third, first, second, second, other} {
+			require.NoError(t, db.InsertBalance(ctx, b))
+		}
+
+		latest, err := db.GetBalance(ctx, address(10), nil)
+		require.NoError(t, err)
+		assert.Equal(t, third, *latest)
+		block := uint64(2)
+		atBlock, err := db.GetBalance(ctx, address(10), &block)
+		require.NoError(t, err)
+		assert.Equal(t, second, *atBlock)
+		block = 0
+		_, err = db.GetBalance(ctx, address(10), &block)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		chart, err := db.GetBalanceChart(ctx, BalanceChartFilter{Address: address(10), Period: StatsHourly})
+		require.NoError(t, err)
+		assert.Equal(t, []*data.BalancePoint{
+			{Time: hour - 3600, BlockNumber: 1, Balance: first.Balance},
+			{Time: hour, BlockNumber: 3, Balance: third.Balance, Nonce: 2},
+		}, chart)
+		from := hour + 1
+		chart, err = db.GetBalanceChart(ctx, BalanceChartFilter{Address: address(10), Period: StatsDaily, FromTime: &from})
+		require.NoError(t, err)
+		assert.Equal(t, []*data.BalancePoint{{Time: hour - hour%86400, BlockNumber: 3, Balance: third.Balance, Nonce: 2}}, chart)
+		to := hour - 1
+		chart, err = db.GetBalanceChart(ctx, BalanceChartFilter{Address: address(10), Period: StatsHourly, ToTime: &to})
+		require.NoError(t, err)
+		assert.Len(t, chart, 1)
+		_, err = db.GetBalanceChart(ctx, BalanceChartFilter{Address: address(10), Period: "weekly"})
+		var filterErr *FilterError
+		assert.True(t, errors.As(err, &filterErr))
+
+		// Snapshots of a reorged block are removed, pruning deletes the
+		// snapshots of pruned blocks.
+		reorged := conformanceBlock(3)
+		reorged.Hash = hash(63)
+		require.NoError(t, db.InsertBlock(ctx, reorged))
+		latest, err = db.GetBalance(ctx, address(10), nil)
+		require.NoError(t, err)
+		assert.Equal(t, second, *latest)
+		_, err = db.DeleteBlockRange(ctx, 0, 2)
+		require.NoError(t, err)
+		_, err = db.GetBalance(ctx, address(10), &block)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+		block = 1
+		_, err = db.GetBalance(ctx, address(10), &block)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+	})
+}
+
+func TestConformanceAddresses(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		seedBlocks(t, db, 1, 2, 3)
+
+		// Migration 12 summarizes the transactions indexed before it.
+		m, err := NewMigrator(db.(*GormDB))
+		require.NoError(t, err)
+		require.NoError(t, m.To(11))
+		old := conformanceTx(1, 1, address(10), address(11))
+		old.Value = 1000
+		require.NoError(t, db.(*GormDB).Omit("gas_used", "chain_id").Create(&old).Error)
+		require.NoError(t, m.Up())
+
+		failed := conformanceTx(2, 2, address(11), address(10))
+		failed.Value, failed.Status, failed.GasUsed = 300, 0, 25000
+		deploy := conformanceTx(3, 3, address(10), "")
+		deploy.Contract, deploy.Value, deploy.GasUsed = address(50), 7, 100000
+		for _, tx := range []data.Transaction{failed, deploy} {
+			require.NoError(t, db.InsertTx(ctx, tx))
+		}
+		require.Error(t, db.InsertTx(ctx, deploy), "redelivered transactions aren't counted twice")
+		require.NoError(t, db.InsertContract(ctx, data.Contract{
+			Address: address(50), Deployer: address(10), TxHash: deploy.Hash, BlockHash: hash(3), BlockNumber: 3,
+		}))
+		require.NoError(t, db.InsertBalance(ctx, data.Balance{Address: address(10), BlockNumber: 3, BlockHash: hash(3), Balance: "42", Nonce: 2}))
+
+		summary, err := db.GetAddressSummary(ctx, address(10))
+		require.NoError(t, err)
+		assert.Equal(t, data.Address{
+			Address: address(10), FirstSeenBlock: 1, LastSeenBlock: 3, TxsSent: 2, TxsReceived: 1,
+			ValueSent: "1007", ValueReceived: "0", GasSpent: 100000,
+		}, summary.Address)
+		assert.Nil(t, summary.Contract)
+		require.NotNil(t, summary.Balance)
+		assert.Equal(t, "42", summary.Balance.Balance)
+		assert.Empty(t, summary.Tokens)
+
+		summary, err = db.GetAddressSummary(ctx, address(11))
+		require.NoError(t, err)
+		assert.Equal(t, data.Address{
+			Address: address(11), FirstSeenBlock: 1, LastSeenBlock: 2, TxsSent: 1, TxsReceived: 1,
+			ValueSent: "0", ValueReceived: "1000", GasSpent: 25000,
+		}, summary.Address)
+
+		summary, err = db.GetAddressSummary(ctx, address(50))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), summary.TxsReceived)
+		assert.Equal(t, "7", summary.ValueReceived)
+		require.NotNil(t, summary.Contract)
+		assert.Equal(t, address(10), summary.Contract.Deployer)
+
+		_, err = db.GetAddressSummary(ctx, address(99))
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		// Transactions of a reorged block are removed from the summaries.
+		block := conformanceBlock(3)
+		block.Hash = hash(63)
+		require.NoError(t, db.InsertBlock(ctx, block))
+		summary, err = db.GetAddressSummary(ctx, address(10))
+		require.NoError(t, err)
+		assert.Equal(t, data.Address{
+			Address: address(10), FirstSeenBlock: 1, LastSeenBlock: 2, TxsSent: 1, TxsReceived: 1,
+			ValueSent: "1000", ValueReceived: "0",
+		}, summary.Address)
+		_, err = db.GetAddressSummary(ctx, address(50))
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		// Pruning keeps the summaries.
+		_, err = db.DeleteBlockRange(ctx, 0, 3)
+		require.NoError(t, err)
+		summary, err = db.GetAddressSummary(ctx, address(11))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), summary.TxsSent)
+	})
+}
+
+func TestConformanceAddressContractReorg(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		seedBlocks(t, db, 1, 2, 3)
+
+		deploy := conformanceTx(2, 2, address(10), "")
+		deploy.Contract = address(51)
+		call := conformanceTx(3, 3, address(10), address(51))
+		for _, tx := range []data.Transaction{deploy, call} {
+			require.NoError(t, db.InsertTx(ctx, tx))
+		}
+
+		// The contract's first and last seen blocks are recomputed from its
+		// creation when the block calling it is reorged.
+		block := conformanceBlock(3)
+		block.Hash = hash(63)
+		require.NoError(t, db.InsertBlock(ctx, block))
+		summary, err := db.GetAddressSummary(ctx, address(51))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), summary.FirstSeenBlock)
+		assert.Equal(t, uint64(2), summary.LastSeenBlock)
+		assert.Equal(t, uint64(1), summary.TxsReceived)
+	})
+}
+
+func TestConformanceUncles(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		seedBlocks(t, db, 1, 2, 3)
+
+		uncle := func(block uint64, index uint, number uint64) data.Uncle {
+			return data.Uncle{
+				BlockHash: hash(byte(block)), UncleIndex: index, BlockNumber: block,
+				Hash: hash(byte(100 + 10*block + uint64(index))), Number: number, ParentHash: hash(byte(number - 1)),
+				Miner: address(byte(20 + index)), Difficulty: 1000, GasLimit: 30000000, GasUsed: 21000,
+				Time: 1700000000 + number, Reward: "1750000000000000000",
+			}
+		}
+		first, second, other := uncle(3, 0, 2), uncle(3, 1, 1), uncle(2, 0, 1)
+		for _, u := range []data.Uncle{second, first, first, other} {
+			require.NoError(t, db.InsertUncle(ctx, u))
+		}
+
+		uncles, err := db.GetUncles(ctx, hash(3))
+		require.NoError(t, err)
+		assert.Equal(t, []*data.Uncle{&first, &second}, uncles)
+		uncles, err = db.GetUncles(ctx, hash(1))
+		require.NoError(t, err)
+		assert.Empty(t, uncles)
+
+		// Uncles of a reorged block are removed, pruning deletes the uncles
+		// of pruned blocks.
+		reorged := conformanceBlock(3)
+		reorged.Hash = hash(63)
+		require.NoError(t, db.InsertBlock(ctx, reorged))
+		uncles, err = db.GetUncles(ctx, hash(3))
+		require.NoError(t, err)
+		assert.Empty(t, uncles)
+		_, err = db.DeleteBlockRange(ctx, 0, 3)
+		require.NoError(t, err)
+		uncles, err = db.GetUncles(ctx, hash(2))
+		require.NoError(t, err)
+		assert.Empty(t, uncles)
+	})
+}
+
+func TestConformanceRewardsBackfill(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		seedBlocks(t, db, 1, 2, 3)
+		require.NoError(t, db.InsertUncle(ctx, data.Uncle{
+			BlockHash: hash(3), BlockNumber: 3, Hash: hash(103), Number: 2, ParentHash: hash(1),
+			Miner: address(5), Reward: "1750000000000000000",
+		}))
+
+		// Migration 14 counts the blocks and uncles indexed before it.
+		m, err := NewMigrator(db.(*GormDB))
+		require.NoError(t, err)
+		require.NoError(t, m.To(13))
+		require.NoError(t, m.Up())
+
+		rewards, err := db.GetMinerRewards(ctx, address(1))
+		require.NoError(t, err)
+		assert.Equal(t, data.MinerRewards{
+			Miner: address(1), Blocks: 2, BlockRewards: "0", UncleInclusionRewards: "0",
+			UncleRewards: "0", PriorityFees: "0", Total: "0",
+		}, *rewards)
+		rewards, err = db.GetMinerRewards(ctx, address(5))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), rewards.Uncles)
+		assert.Equal(t, "1750000000000000000", rewards.UncleRewards)
+	})
+}
+
+func TestConformanceRewards(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		seedBlocks(t, db, 1, 2, 3, 4)
+		uncle := data.Uncle{
+			BlockHash: hash(3), BlockNumber: 3, Hash: hash(103), Number: 2, ParentHash: hash(1),
+			Miner: address(5), Reward: "1750000000000000000",
+		}
+		require.NoError(t, db.InsertUncle(ctx, uncle))
+		require.NoError(t, db.InsertUncle(ctx, uncle), "redelivered uncles aren't counted twice")
+
+		rewards, err := db.GetMinerRewards(ctx, address(1))
+		require.NoError(t, err)
+		assert.Equal(t, data.MinerRewards{
+			Miner: address(1), Blocks: 2, BlockRewards: "4000000000000000000", UncleInclusionRewards: "0",
+			UncleRewards: "0", PriorityFees: "4000", Total: "4000000000000004000",
+		}, *rewards)
+		rewards, err = db.GetMinerRewards(ctx, address(5))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), rewards.Uncles)
+		assert.Equal(t, "1750000000000000000", rewards.Total)
+		_, err = db.GetMinerRewards(ctx, address(9))
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		require.NoError(t, db.RollupStats(ctx, StatsHourly, StatsHourly.Bucket(conformanceBlock(1).Time)))
+		burn, err := db.GetBurnStats(ctx, StatsFilter{Period: StatsHourly})
+		require.NoError(t, err)
+		assert.Equal(t, &data.BurnStats{
+			BaseFeeBurned: "100000000000000000000", BlobFeeBurned: "10", Burned: "100000000000000000010",
+			Buckets: []*data.BurnPoint{{
+				Time:          StatsHourly.Bucket(conformanceBlock(1).Time),
+				BaseFeeBurned: "100000000000000000000", BlobFeeBurned: "10", Burned: "100000000000000000010",
+			}},
+		}, burn)
+		later := conformanceBlock(4).Time + 3600
+		burn, err = db.GetBurnStats(ctx, StatsFilter{Period: StatsHourly, FromTime: &later})
+		require.NoError(t, err)
+		assert.Equal(t, &data.BurnStats{BaseFeeBurned: "0", BlobFeeBurned: "0", Burned: "0", Buckets: []*data.BurnPoint{}}, burn)
+		_, err = db.GetBurnStats(ctx, StatsFilter{Period: "weekly"})
+		var filterErr *FilterError
+		assert.ErrorAs(t, err, &filterErr)
+
+		// A reorged block and its uncles are removed from their miners'
+		// totals, pruning keeps them.
+		reorged := conformanceBlock(3)
+		reorged.Hash, reorged.Miner = hash(63), address(7)
+		require.NoError(t, db.InsertBlock(ctx, reorged))
+		rewards, err = db.GetMinerRewards(ctx, address(1))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), rewards.Blocks)
+		assert.Equal(t, "1000", rewards.PriorityFees)
+		_, err = db.GetMinerRewards(ctx, address(5))
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+		_, err = db.DeleteBlockRange(ctx, 0, 5)
+		require.NoError(t, err)
+		rewards, err = db.GetMinerRewards(ctx, address(7))
+		require.NoError(t, err)
+		assert.Equal(t, "2000000000000003000", rewards.Total)
+	})
+}
+
+func TestConformanceChains(t *testing.T) {
+	runConformance(t, func(t *testing.T, db DB) {
+		ctx := context.Background()
+		// Rows indexed before chains were configured belong to chain 0.
+		seedBlocks(t, db, 1)
+		require.NoError(t, db.InsertTx(ctx, conformanceTx(1, 1, address(10), address(11))))
+		tables, err := db.UnassignedTables(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"blocks", "transactions", "stats_dirty", "addresses", "miner_rewards"}, tables)
+		require.NoError(t, db.(*GormDB).AssignChain(1))
+		tables, err = db.UnassignedTables(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, tables)
+
+		mainnet, other := db.ForChain(1), db.ForChain(5)
+		seedBlocks(t, mainnet, 2, 3)
+		seedBlocks(t, other, 1, 2)
+		require.NoError(t, other.InsertTx(ctx, conformanceTx(2, 2, address(10), address(12))))
+		require.NoError(t, mainnet.InsertTokenTransfer(ctx, conformanceTransfer(2, 0, address(10), address(11), "5")))
+		require.NoError(t, other.InsertTokenTransfer(ctx, conformanceTransfer(2, 0, address(10), address(11), "7")))
+
+		_, err = db.GetLatestBlock(ctx)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound), "every row was assigned")
+		latest, err := mainnet.GetLatestBlock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), latest.Number)
+		latest, err = other.GetLatestBlock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), latest.Number)
+
+		txs, err := mainnet.FindTxs(ctx, TxFilter{From: address(10)})
+		require.NoError(t, err)
+		assert.Equal(t, []string{hash(41)}, txHashes(txs))
+		_, err = other.GetTxByHash(ctx, hash(41), nil)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+		summary, err := other.GetAddressSummary(ctx, address(10))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), summary.FirstSeenBlock)
+
+		holders, err := mainnet.GetTokenHolders(ctx, address(30), Page{})
+		require.NoError(t, err)
+		require.Len(t, holders, 1)
+		assert.Equal(t, "5", holders[0].Balance)
+
+		rewards, err := other.GetMinerRewards(ctx, address(1))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), rewards.Blocks)
+
+		// Reorgs and pruning only touch their own chain.
+		reorged := conformanceBlock(2)
+		reorged.Hash = hash(62)
+		require.NoError(t, other.InsertBlock(ctx, reorged))
+		block, err := mainnet.GetBlockByNumber(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, hash(2), block.Hash)
+		holders, err = mainnet.GetTokenHolders(ctx, address(30), Page{})
+		require.NoError(t, err)
+		assert.Len(t, holders, 1)
+		deleted, err := mainnet.DeleteBlockRange(ctx, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), deleted)
+		block, err = other.GetBlockByNumber(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, hash(1), block.Hash)
+
+		hours, err := mainnet.GetDirtyStats(ctx, StatsHourly, 10)
+		require.NoError(t, err)
+		require.Len(t, hours, 1)
+		require.NoError(t, other.RollupStats(ctx, StatsHourly, hours[0]))
+		stats, err := other.FindStats(ctx, StatsFilter{Period: StatsHourly})
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		assert.Equal(t, uint64(2), stats[0].Blocks)
+		stats, err = mainnet.FindStats(ctx, StatsFilter{Period: StatsHourly})
+		require.NoError(t, err)
+		assert.Empty(t, stats)
+
+		// Signatures are shared.
+		transfer := data.Signature{Hash: "0xa9059cbb", Signature: "transfer(address,uint256)"}
+		require.NoError(t, mainnet.InsertSignatures(ctx, []data.Signature{transfer}))
+		signatures, err := other.GetSignatures(ctx, []string{transfer.Hash})
+		require.NoError(t, err)
+		assert.Equal(t, []*data.Signature{&transfer}, signatures)
+	})
+}
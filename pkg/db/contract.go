@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"gorm.io/gorm/clause"
+)
+
+// InsertContract registers a contract, replacing a registration at the same
+// address.
+func (g *GormDB) InsertContract(ctx context.Context, contract data.Contract) error {
+	db, cancel := g.write(ctx)
+	defer cancel()
+	contract.ChainID = g.chainID
+	if contract.Interfaces == nil {
+		contract.Interfaces = []string{}
+	}
+	return db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&contract).Error
+}
+
+func (g *GormDB) GetContract(ctx context.Context, address string) (*data.Contract, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var contract data.Contract
+	if err := db.Take(&contract, "chain_id = ? AND address = ?", g.chainID, address).Error; err != nil {
+		return nil, err
+	}
+	return &contract, nil
+}
+
+func (g *GormDB) FindContracts(ctx context.Context, filter ContractFilter) ([]*data.Contract, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	query := db.Model(&data.Contract{}).Where("chain_id = ?", g.chainID)
+	if filter.Deployer != "" {
+		query = query.Where("deployer = ?", filter.Deployer)
+	}
+	contracts := []*data.Contract{}
+	err := query.Order("block_number asc, address asc").
+		Limit(filter.limit()).Offset(filter.offset()).
+		Find(&contracts).Error
+	if err != nil {
+		return nil, err
+	}
+	return contracts, nil
+}
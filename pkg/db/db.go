@@ -1,60 +1,260 @@
 package db
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 type DB interface {
-	InsertBlock(data.Block) error
-	GetBlockByNumber(uint64) (*data.Block, error)
-	GetFirstBlock() (*data.Block, error)
-	GetBlocks() ([]*data.Block, error)
-	InsertTx(data.Transaction) error
-	GetTxByHash(string) (*data.Transaction, error)
-	GetTxs() ([]*data.Transaction, error)
+	InsertBlock(context.Context, data.Block) error
+	GetBlockByNumber(context.Context, uint64) (*data.Block, error)
+	GetBlockByHash(context.Context, string) (*data.Block, error)
+	GetFirstBlock(context.Context) (*data.Block, error)
+	GetLatestBlock(context.Context) (*data.Block, error)
+	GetFirstBlockSince(context.Context, uint64) (*data.Block, error)
+	GetBlockAtTime(context.Context, uint64) (*data.Block, error)
+	GetBlocks(context.Context) ([]*data.Block, error)
+	GetBlocksByHashes(ctx context.Context, hashes []string, numbers []uint64) ([]*data.Block, error)
+	FindBlocks(context.Context, BlockFilter) ([]*data.Block, error)
+	InsertTx(context.Context, data.Transaction) error
+	GetTxByHash(ctx context.Context, hash string, blockNumber *uint64) (*data.Transaction, error)
+	GetTxs(context.Context) ([]*data.Transaction, error)
+	GetTxsByHashes(ctx context.Context, hashes []string, blockNumbers []uint64) ([]*data.Transaction, error)
+	GetTxsByBlockHashes(ctx context.Context, hashes []string, numbers []uint64) ([]*data.Transaction, error)
+	FindTxs(context.Context, TxFilter) ([]*data.Transaction, error)
+	InsertLog(context.Context, data.Log) error
+	GetLogsByTxHashes(context.Context, []string) ([]*data.Log, error)
+	FindLogs(context.Context, LogFilter) ([]*data.Log, error)
+	HasAddress(context.Context, string) (bool, error)
+	GetAddressSummary(context.Context, string) (*data.AddressSummary, error)
+	StreamBlocks(ctx context.Context, fromNumber, toNumber uint64, fn func(*data.Block) error) error
+	StreamTxs(ctx context.Context, fromBlock, toBlock uint64, fn func(*data.Transaction) error) error
+	StreamLogs(ctx context.Context, fromBlock, toBlock uint64, fn func(*data.Log) error) error
+	DeleteBlockRange(ctx context.Context, fromNumber, toNumber uint64) (int64, error)
+	GetDirtyStats(ctx context.Context, period StatsPeriod, limit int) ([]uint64, error)
+	RollupStats(ctx context.Context, period StatsPeriod, start uint64) error
+	FindStats(context.Context, StatsFilter) ([]*data.Stats, error)
+	GetGasOracle(ctx context.Context, blocks int) (*data.GasOracle, error)
+	GetGasHistory(ctx context.Context, blocks int) ([]*data.GasHistory, error)
+	InsertToken(context.Context, data.Token) error
+	InsertTokenTransfer(context.Context, data.TokenTransfer) error
+	InsertTokenApproval(context.Context, data.TokenApproval) error
+	FindTokenTransfers(context.Context, TokenTransferFilter) ([]*data.TokenTransfer, error)
+	GetTokenHolders(ctx context.Context, token string, page Page) ([]*data.TokenBalance, error)
+	GetAddressTokens(ctx context.Context, holder string) ([]*data.TokenHolding, error)
+	InsertNFT(context.Context, data.NFT) error
+	InsertNFTTransfer(context.Context, data.NFTTransfer) error
+	GetNFT(ctx context.Context, contract, tokenID string) (*data.NFT, error)
+	FindNFTTransfers(context.Context, NFTTransferFilter) ([]*data.NFTTransfer, error)
+	GetAddressNFTs(context.Context, NFTOwnerFilter) ([]*data.NFTHolding, error)
+	InsertContract(context.Context, data.Contract) error
+	GetContract(context.Context, string) (*data.Contract, error)
+	FindContracts(context.Context, ContractFilter) ([]*data.Contract, error)
+	InsertContractABI(context.Context, data.ContractABI) error
+	InsertSignatures(context.Context, []data.Signature) error
+	GetContractABIs(context.Context, []string) ([]*data.ContractABI, error)
+	GetSignatures(context.Context, []string) ([]*data.Signature, error)
+	InsertInternalTx(context.Context, data.InternalTx) error
+	FindInternalTxs(context.Context, InternalTxFilter) ([]*data.InternalTx, error)
+	InsertBalance(context.Context, data.Balance) error
+	GetBalance(ctx context.Context, address string, block *uint64) (*data.Balance, error)
+	GetBalanceChart(context.Context, BalanceChartFilter) ([]*data.BalancePoint, error)
+	InsertUncle(context.Context, data.Uncle) error
+	GetUncles(ctx context.Context, blockHash string) ([]*data.Uncle, error)
+	GetMinerRewards(ctx context.Context, miner string) (*data.MinerRewards, error)
+	GetBurnStats(context.Context, StatsFilter) (*data.BurnStats, error)
+	Primary() DB
+	ForChain(chainID uint64) DB
+	UnassignedTables(context.Context) ([]string, error)
+	Ping(context.Context) error
 	Close() error
 }
 
+// Config holds the connection settings besides the primary URL. Zero values
+// keep the database/sql defaults and don't bound queries.
+type Config struct {
+	ReplicaURLs     []string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	Timeouts        Timeouts
+}
+
+// Timeouts bounds each kind of operation on top of the caller's context.
+// Streams are only bounded by the caller's context.
+type Timeouts struct {
+	Read  time.Duration
+	Write time.Duration
+}
+
+type noWriteTimeoutKey struct{}
+
+// WithoutWriteTimeout returns a context whose writes are only bounded by ctx,
+// not by Timeouts.Write. The Kafka consumer stores messages with it, as a
+// write given up on would be lost once the next message's offset is stored.
+func WithoutWriteTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noWriteTimeoutKey{}, true)
+}
+
+const (
+	dialectPostgres = "postgres"
+	dialectSQLite   = "sqlite"
+
+	sqliteScheme  = "sqlite://"
+	sqlitePragmas = "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+)
+
 type GormDB struct {
 	*gorm.DB
+	partitions *partitionCache
+	replicas   []*sql.DB
+	timeouts   Timeouts
+	chainID    uint64
+}
+
+func newGormDB(db *gorm.DB) *GormDB {
+	return &GormDB{
+		DB:         db,
+		partitions: &partitionCache{known: map[string]bool{}},
+	}
+}
+
+// ForChain returns a DB sharing g's connections whose reads and writes are
+// scoped to chainID. Rows written before chains were configured belong to
+// chain 0, the chain of a DB that was never scoped.
+func (g *GormDB) ForChain(chainID uint64) DB {
+	scoped := *g
+	scoped.chainID = chainID
+	return &scoped
+}
+
+// NewConnection connects to the primary database at url, applies any pending
+// migrations and routes reads to the replicas in cfg, if any.
+func NewConnection(url string, cfg Config) (DB, error) {
+	g, err := Open(url)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := NewMigrator(g)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrator.Up(); err != nil {
+		g.Close()
+		return nil, err
+	}
+	if err := g.useReplicas(cfg.ReplicaURLs); err != nil {
+		g.Close()
+		return nil, err
+	}
+	if err := g.configure(cfg); err != nil {
+		g.Close()
+		return nil, err
+	}
+
+	return g, nil
 }
 
-func NewConnection(url string) (DB, error) {
-	db, err := gorm.Open(postgres.Open(url),
+// Open connects to the database at url without running migrations. URLs
+// starting with sqlite:// open a SQLite database file, sqlite://:memory: an
+// in-memory one, anything else is passed to the Postgres driver.
+func Open(url string) (*GormDB, error) {
+	dialector := postgres.Open(url)
+	path, isSQLite := strings.CutPrefix(url, sqliteScheme)
+	if isSQLite {
+		dialector = sqlite.Open(path + sqlitePragmas)
+	}
+	db, err := gorm.Open(dialector,
 		&gorm.Config{
 			SkipDefaultTransaction: true,
 		})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to db: %w", err)
 	}
+	if isSQLite {
+		// SQLite allows a single writer, and each connection to :memory: would
+		// open a separate database.
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get db connection: %w", err)
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+	return newGormDB(db), nil
+}
 
-	if err := runMigrations(db); err != nil {
-		return nil, err
+// configure applies the pool settings to the primary and replica pools. A
+// SQLite database keeps its single connection.
+func (g *GormDB) configure(cfg Config) error {
+	g.timeouts = cfg.Timeouts
+	primary, err := g.DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get db connection: %w", err)
+	}
+	for _, pool := range append([]*sql.DB{primary}, g.replicas...) {
+		if cfg.MaxOpenConns > 0 && g.Dialector.Name() != dialectSQLite {
+			pool.SetMaxOpenConns(cfg.MaxOpenConns)
+		}
+		if cfg.MaxIdleConns > 0 {
+			pool.SetMaxIdleConns(cfg.MaxIdleConns)
+		}
+		if cfg.ConnMaxLifetime > 0 {
+			pool.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		}
+		if cfg.ConnMaxIdleTime > 0 {
+			pool.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+		}
 	}
+	return nil
+}
 
-	return &GormDB{db}, nil
+func (g *GormDB) read(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	return g.withTimeout(ctx, g.timeouts.Read)
 }
 
-func (g *GormDB) Close() error {
+func (g *GormDB) write(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	if ctx.Value(noWriteTimeoutKey{}) != nil {
+		return g.withTimeout(ctx, 0)
+	}
+	return g.withTimeout(ctx, g.timeouts.Write)
+}
+
+func (g *GormDB) withTimeout(ctx context.Context, timeout time.Duration) (*gorm.DB, context.CancelFunc) {
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	return g.WithContext(ctx), cancel
+}
+
+func (g *GormDB) Ping(ctx context.Context) error {
 	db, err := g.DB.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get db connection: %w", err)
 	}
-	return db.Close()
+	if g.timeouts.Read > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.timeouts.Read)
+		defer cancel()
+	}
+	return db.PingContext(ctx)
 }
 
-func runMigrations(g *gorm.DB) error {
-	err := g.AutoMigrate(&data.Block{})
-	if err != nil {
-		return fmt.Errorf("failed to run migration: %w", err)
-	}
-	err = g.AutoMigrate(&data.Transaction{})
+func (g *GormDB) Close() error {
+	db, err := g.DB.DB()
 	if err != nil {
-		return fmt.Errorf("failed to run migration: %w", err)
+		return fmt.Errorf("failed to get db connection: %w", err)
 	}
-	return nil
+	return errors.Join(db.Close(), closeAll(g.replicas))
 }
@@ -1,8 +1,12 @@
 package db
 
 import (
+	"context"
+	"regexp"
 	"testing"
+	"time"
 
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/postgres"
@@ -14,6 +18,9 @@ type suite struct {
 	sqlMock sqlmock.Sqlmock
 }
 
+// mockChainID is the chain the suite's DB is scoped to.
+const mockChainID uint64 = 1
+
 func newSuite(t *testing.T) suite {
 	sqlDB, sqlMock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -26,32 +33,44 @@ func newSuite(t *testing.T) suite {
 	gormDB, err := gorm.Open(dialector, &gorm.Config{})
 	assert.NoError(t, err)
 	return suite{
-		dbMock:  &GormDB{gormDB},
+		dbMock:  newGormDB(gormDB).ForChain(mockChainID),
 		sqlMock: sqlMock,
 	}
 }
 
-func TestRunMigrations(t *testing.T) {
-	sqlDB, sqlMock, err := sqlmock.New()
-	assert.NoError(t, err)
-	dialector := postgres.New(postgres.Config{
-		DSN:                  "sqlmock_db_0",
-		DriverName:           "postgres",
-		Conn:                 sqlDB,
-		PreferSimpleProtocol: true,
-	})
-	gormDB, err := gorm.Open(dialector, &gorm.Config{})
+func TestReadTimeout(t *testing.T) {
+	s := newSuite(t)
+	s.dbMock.(*GormDB).timeouts = Timeouts{Read: 10 * time.Millisecond}
 
-	sqlMock.ExpectQuery(`^SELECT count\(\*\) FROM information_schema\.tables WHERE table_schema = CURRENT_SCHEMA\(\) AND table_name = \$1 AND table_type = \$2$`).
-		WithArgs("blocks", "BASE TABLE").
-		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
-	sqlMock.ExpectExec(`^CREATE TABLE "blocks"`).WillReturnResult(sqlmock.NewResult(0, 1))
-	sqlMock.ExpectExec(`^CREATE INDEX IF NOT EXISTS "idx_blocks_number" ON "blocks" \("number" asc\)`).WillReturnResult(sqlmock.NewResult(0, 1))
-	sqlMock.ExpectExec(`^CREATE TABLE "transactions" \("hash" char\(66\),"from" char\(42\) NOT NULL,"to" char\(42\),"contract" char\(66\) NOT NULL,"value" numeric NOT NULL,"data" bytea NOT NULL,"gas" numeric NOT NULL,"gas_price" numeric NOT NULL,"cost" numeric NOT NULL,"nonce" numeric NOT NULL,"status" numeric NOT NULL,"block_hash" char\(66\) NOT NULL,PRIMARY KEY \("hash"\)\)$`).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "blocks"`)).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}))
 
-	err = runMigrations(gormDB)
-	assert.NoError(t, err)
+	_, err := s.dbMock.GetBlocks(context.Background())
+	assert.Error(t, err)
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+func TestWithoutWriteTimeout(t *testing.T) {
+	s := newSuite(t)
+	s.dbMock.(*GormDB).timeouts = Timeouts{Write: 10 * time.Millisecond}
 
-	err = sqlMock.ExpectationsWereMet()
+	s.sqlMock.ExpectBegin()
+	s.sqlMock.ExpectExec(`^INSERT INTO "logs"`).
+		WillDelayFor(100 * time.Millisecond).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.sqlMock.ExpectCommit()
+
+	err := s.dbMock.InsertLog(WithoutWriteTimeout(context.Background()), data.Log{})
 	assert.NoError(t, err)
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+func TestCancelledContext(t *testing.T) {
+	s := newSuite(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := s.dbMock.InsertLog(ctx, data.Log{})
+	assert.ErrorIs(t, err, context.Canceled)
 }
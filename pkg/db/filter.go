@@ -0,0 +1,240 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+// maxUnindexedBlocks and maxUnindexedTime bound the block or time range an
+// unindexed transaction filter may scan when it has no indexed equality.
+const (
+	maxUnindexedBlocks = 10000
+	maxUnindexedTime   = 24 * time.Hour
+)
+
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+type BlockFilter struct {
+	FromNumber *uint64
+	ToNumber   *uint64
+	Miner      string
+	// FromTime and ToTime are unix timestamps.
+	FromTime *uint64
+	ToTime   *uint64
+	Page
+}
+
+type TxFilter struct {
+	From      string
+	To        string
+	BlockHash string
+	// ContractCreation selects transactions without (true) or with (false) a
+	// recipient.
+	ContractCreation *bool
+	Status           *uint64
+	FromBlock        *uint64
+	ToBlock          *uint64
+	// FromTime and ToTime are unix timestamps, matched against the time of
+	// the transaction's block.
+	FromTime    *uint64
+	ToTime      *uint64
+	MinValue    *uint64
+	MaxValue    *uint64
+	MinGasPrice *uint64
+	MaxGasPrice *uint64
+	// MethodSelector matches the first 4 bytes of the transaction data.
+	MethodSelector []byte
+	Sort           TxSort
+	Page
+}
+
+// TxSort orders transactions by a column, prefixed with - for descending.
+type TxSort string
+
+const (
+	TxSortBlockNumber     TxSort = "blockNumber"
+	TxSortBlockNumberDesc TxSort = "-blockNumber"
+	TxSortValue           TxSort = "value"
+	TxSortValueDesc       TxSort = "-value"
+	TxSortGasPrice        TxSort = "gasPrice"
+	TxSortGasPriceDesc    TxSort = "-gasPrice"
+)
+
+var txSortOrders = map[TxSort]string{
+	"":                    "block_number asc, hash asc",
+	TxSortBlockNumber:     "block_number asc, hash asc",
+	TxSortBlockNumberDesc: "block_number desc, hash desc",
+	TxSortValue:           "value asc, hash asc",
+	TxSortValueDesc:       "value desc, hash desc",
+	TxSortGasPrice:        "gas_price asc, hash asc",
+	TxSortGasPriceDesc:    "gas_price desc, hash desc",
+}
+
+// FilterError reports a filter field that is invalid or that would need a
+// full table scan.
+type FilterError struct {
+	Field string
+	Msg   string
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("invalid filter %s: %s", e.Field, e.Msg)
+}
+
+// narrowed reports whether the filter matches an indexed column by equality.
+// Contract creations are found through the index on "to".
+func (f TxFilter) narrowed() bool {
+	return f.From != "" || f.To != "" || f.BlockHash != "" ||
+		(f.ContractCreation != nil && *f.ContractCreation)
+}
+
+// bounded reports whether the filter selects a closed block range or a time
+// range small enough to scan. An open time range ends now.
+func (f TxFilter) bounded() bool {
+	if f.FromBlock != nil && f.ToBlock != nil && *f.ToBlock-*f.FromBlock < maxUnindexedBlocks {
+		return true
+	}
+	if f.FromTime != nil {
+		to := uint64(time.Now().Unix())
+		if f.ToTime != nil {
+			to = *f.ToTime
+		}
+		return to < *f.FromTime || to-*f.FromTime <= uint64(maxUnindexedTime.Seconds())
+	}
+	return false
+}
+
+// Validate rejects malformed filters, and filters and sorts on unindexed
+// columns that are not narrowed down by an indexed one.
+func (f TxFilter) Validate() error {
+	if f.To != "" && f.ContractCreation != nil && *f.ContractCreation {
+		return &FilterError{"contractCreation", "can't be combined with to"}
+	}
+	if f.MethodSelector != nil && len(f.MethodSelector) != 4 {
+		return &FilterError{"method", "must be 4 bytes"}
+	}
+	if _, ok := txSortOrders[f.Sort]; !ok {
+		return &FilterError{"sort", "unsupported sort order"}
+	}
+	// Sorting by an unindexed column reads every matching row before the
+	// first page, so only from, to and blockHash narrow it enough.
+	if f.Sort != "" && f.Sort != TxSortBlockNumber && f.Sort != TxSortBlockNumberDesc &&
+		f.From == "" && f.To == "" && f.BlockHash == "" {
+		return &FilterError{"sort", "value and gasPrice sorts need from, to or blockHash"}
+	}
+	ranges := []struct {
+		field    string
+		min, max *uint64
+	}{
+		{"block", f.FromBlock, f.ToBlock},
+		{"time", f.FromTime, f.ToTime},
+		{"value", f.MinValue, f.MaxValue},
+		{"gasPrice", f.MinGasPrice, f.MaxGasPrice},
+	}
+	for _, r := range ranges {
+		if r.min != nil && r.max != nil && *r.min > *r.max {
+			return &FilterError{r.field, "range start is after its end"}
+		}
+	}
+	unindexed := f.Status != nil || (f.ContractCreation != nil && !*f.ContractCreation) ||
+		f.MinValue != nil || f.MaxValue != nil || f.MinGasPrice != nil || f.MaxGasPrice != nil ||
+		f.MethodSelector != nil
+	if unindexed && !f.narrowed() && !f.bounded() {
+		return &FilterError{"filter", fmt.Sprintf(
+			"status, value, gas price, method and contractCreation=false filters need from, to, blockHash, contractCreation=true, a block range of at most %d blocks or a time range of at most %d hours",
+			maxUnindexedBlocks, maxUnindexedTime/time.Hour)}
+	}
+	return nil
+}
+
+type LogFilter struct {
+	Address   string
+	Topic0    string
+	TxHash    string
+	FromBlock *uint64
+	ToBlock   *uint64
+	Page
+}
+
+type TokenTransferFilter struct {
+	Token     string
+	FromBlock *uint64
+	ToBlock   *uint64
+	Page
+}
+
+type NFTTransferFilter struct {
+	Contract string
+	TokenID  string
+	Page
+}
+
+type NFTOwnerFilter struct {
+	Owner    string
+	Contract string
+	Page
+}
+
+type ContractFilter struct {
+	Deployer string
+	Page
+}
+
+type InternalTxFilter struct {
+	TxHash  string
+	Address string
+	Page
+}
+
+type BalanceChartFilter struct {
+	Address string
+	Period  StatsPeriod
+	// FromTime and ToTime are unix timestamps, the buckets holding them are
+	// included.
+	FromTime *uint64
+	ToTime   *uint64
+	Page
+}
+
+type StatsFilter struct {
+	Period StatsPeriod
+	// FromTime and ToTime are unix timestamps, the buckets holding them are
+	// included.
+	FromTime *uint64
+	ToTime   *uint64
+	Page
+}
+
+func (p Page) limit() int {
+	if p.Limit <= 0 {
+		return DefaultPageSize
+	}
+	if p.Limit > MaxPageSize {
+		return MaxPageSize
+	}
+	return p.Limit
+}
+
+func (p Page) offset() int {
+	if p.Offset < 0 {
+		return 0
+	}
+	return p.Offset
+}
+
+// Next returns the page following p when p returned n rows, or false when p
+// was the last page because it returned fewer rows than its limit.
+func (p Page) Next(n int) (Page, bool) {
+	if n < p.limit() {
+		return Page{}, false
+	}
+	return Page{Limit: p.limit(), Offset: p.offset() + p.limit()}, true
+}
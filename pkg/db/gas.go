@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"gorm.io/gorm"
+)
+
+// MaxGasBlocks bounds the number of blocks the oracle and history look at.
+const MaxGasBlocks = 1024
+
+// gasPercentiles are the percentiles of the fees paid in recent blocks behind
+// the slow, standard and fast suggestions.
+var gasPercentiles = [3]int64{25, 50, 90}
+
+// GetGasOracle suggests fees from the last blocks stored. Each suggestion tips
+// a percentile of the priority fees paid and covers the same percentile of the
+// base fees, or the next base fee if it is higher.
+func (g *GormDB) GetGasOracle(ctx context.Context, blocks int) (*data.GasOracle, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	latest, from, err := gasWindow(db, g.chainID, blocks)
+	if err != nil {
+		return nil, err
+	}
+	oracle := &data.GasOracle{
+		FromBlock:   from,
+		ToBlock:     latest.Number,
+		BaseFee:     latest.BaseFee,
+		NextBaseFee: nextBaseFee(latest),
+	}
+
+	blocksInRange := func() *gorm.DB {
+		return db.Model(&data.Block{}).Where("chain_id = ? AND number >= ? AND number <= ?", g.chainID, from, latest.Number)
+	}
+	txsInRange := func() *gorm.DB {
+		return db.Model(&data.Transaction{}).Where("chain_id = ? AND block_number >= ? AND block_number <= ?", g.chainID, from, latest.Number)
+	}
+	var blockCount, txCount int64
+	if err := blocksInRange().Count(&blockCount).Error; err != nil {
+		return nil, err
+	}
+	if err := txsInRange().Count(&txCount).Error; err != nil {
+		return nil, err
+	}
+
+	suggestions := []*data.FeeSuggestion{&oracle.Slow, &oracle.Standard, &oracle.Fast}
+	for i, p := range gasPercentiles {
+		baseFee, err := percentile(blocksInRange(), "base_fee", blockCount, p)
+		if err != nil {
+			return nil, err
+		}
+		tip, err := percentile(txsInRange(), "priority_fee", txCount, p)
+		if err != nil {
+			return nil, err
+		}
+		*suggestions[i] = data.FeeSuggestion{
+			MaxPriorityFee: tip,
+			MaxFee:         max(baseFee, oracle.NextBaseFee) + tip,
+		}
+	}
+	return oracle, nil
+}
+
+// GetGasHistory returns the fees of the last blocks stored, oldest first.
+func (g *GormDB) GetGasHistory(ctx context.Context, blocks int) ([]*data.GasHistory, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	latest, from, err := gasWindow(db, g.chainID, blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []*data.GasHistory
+	err = db.Model(&data.Block{}).
+		Select("number, time, base_fee, gas_used, gas_limit").
+		Where("chain_id = ? AND number >= ? AND number <= ?", g.chainID, from, latest.Number).
+		Order("number asc").
+		Scan(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	var fees []struct {
+		BlockNumber    uint64
+		Txs            uint64
+		MinPriorityFee uint64
+		AvgPriorityFee float64
+		MaxPriorityFee uint64
+	}
+	err = db.Model(&data.Transaction{}).
+		Select(`block_number, COUNT(*) AS txs, MIN(priority_fee) AS min_priority_fee,
+			AVG(priority_fee) AS avg_priority_fee, MAX(priority_fee) AS max_priority_fee`).
+		Where("chain_id = ? AND block_number >= ? AND block_number <= ?", g.chainID, from, latest.Number).
+		Group("block_number").
+		Scan(&fees).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byNumber := make(map[uint64]*data.GasHistory, len(history))
+	for _, h := range history {
+		byNumber[h.Number] = h
+	}
+	for _, f := range fees {
+		if h, ok := byNumber[f.BlockNumber]; ok {
+			h.Txs = f.Txs
+			h.MinPriorityFee = f.MinPriorityFee
+			h.AvgPriorityFee = uint64(f.AvgPriorityFee)
+			h.MaxPriorityFee = f.MaxPriorityFee
+		}
+	}
+	return history, nil
+}
+
+// gasWindow returns the latest block and the first block number of the last
+// blocks, at most MaxGasBlocks of them.
+func gasWindow(db *gorm.DB, chainID uint64, blocks int) (*data.Block, uint64, error) {
+	var latest data.Block
+	if err := db.Where("chain_id = ?", chainID).Order("number desc").Take(&latest).Error; err != nil {
+		return nil, 0, err
+	}
+	n := uint64(min(max(blocks, 1), MaxGasBlocks))
+	if n > latest.Number {
+		return &latest, 0, nil
+	}
+	return &latest, latest.Number + 1 - n, nil
+}
+
+// percentile returns the p-th percentile of column over the count rows
+// matched by query, rounding the rank down.
+func percentile(query *gorm.DB, column string, count int64, p int64) (uint64, error) {
+	if count == 0 {
+		return 0, nil
+	}
+	var values []uint64
+	err := query.Order(column+" asc").
+		Offset(int((count-1)*p/100)).Limit(1).
+		Pluck(column, &values).Error
+	if err != nil || len(values) == 0 {
+		return 0, err
+	}
+	return values[0], nil
+}
+
+// nextBaseFee applies the EIP-1559 update rule to the block: the base fee
+// moves by up to 1/8 towards keeping blocks half full.
+func nextBaseFee(block *data.Block) uint64 {
+	target := block.GasLimit / 2
+	if block.BaseFee == 0 || target == 0 || block.GasUsed == target {
+		return block.BaseFee
+	}
+	delta := new(big.Int).SetUint64(block.BaseFee)
+	if block.GasUsed > target {
+		delta.Mul(delta, new(big.Int).SetUint64(block.GasUsed-target))
+	} else {
+		delta.Mul(delta, new(big.Int).SetUint64(target-block.GasUsed))
+	}
+	delta.Div(delta, new(big.Int).SetUint64(target))
+	delta.Div(delta, big.NewInt(8))
+	if block.GasUsed > target {
+		return block.BaseFee + max(delta.Uint64(), 1)
+	}
+	return block.BaseFee - delta.Uint64()
+}
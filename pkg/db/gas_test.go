@@ -0,0 +1,27 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextBaseFee(t *testing.T) {
+	tests := []struct {
+		name     string
+		block    data.Block
+		expected uint64
+	}{
+		{"pre london", data.Block{GasLimit: 30000000, GasUsed: 30000000}, 0},
+		{"at target", data.Block{BaseFee: 1000000000, GasLimit: 30000000, GasUsed: 15000000}, 1000000000},
+		{"full block", data.Block{BaseFee: 1000000000, GasLimit: 30000000, GasUsed: 30000000}, 1125000000},
+		{"empty block", data.Block{BaseFee: 1000000000, GasLimit: 30000000}, 875000000},
+		{"rises by at least one", data.Block{BaseFee: 7, GasLimit: 30000000, GasUsed: 15000001}, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, nextBaseFee(&tt.block))
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"gorm.io/gorm/clause"
+)
+
+func (g *GormDB) InsertInternalTx(ctx context.Context, internalTx data.InternalTx) error {
+	db, cancel := g.write(ctx)
+	defer cancel()
+	internalTx.ChainID = g.chainID
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&internalTx).Error
+}
+
+// FindInternalTxs returns the internal transactions of a transaction, or
+// those sent from or to an address, in execution order.
+func (g *GormDB) FindInternalTxs(ctx context.Context, filter InternalTxFilter) ([]*data.InternalTx, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	query := db.Model(&data.InternalTx{}).Where("chain_id = ?", g.chainID)
+	if filter.TxHash != "" {
+		query = query.Where("tx_hash = ?", filter.TxHash)
+	}
+	if filter.Address != "" {
+		query = query.Where(`("from" = ? OR "to" = ?)`, filter.Address, filter.Address)
+	}
+	internalTxs := []*data.InternalTx{}
+	err := query.Order("block_number asc, tx_hash asc, trace_index asc").
+		Limit(filter.limit()).Offset(filter.offset()).
+		Find(&internalTxs).Error
+	if err != nil {
+		return nil, err
+	}
+	return internalTxs, nil
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+)
+
+func (g *GormDB) InsertLog(ctx context.Context, log data.Log) error {
+	db, cancel := g.write(ctx)
+	defer cancel()
+	log.ChainID = g.chainID
+	return db.Create(&log).Error
+}
+
+func (g *GormDB) GetLogsByTxHashes(ctx context.Context, hashes []string) ([]*data.Log, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var logs []*data.Log
+	err := db.Where("chain_id = ? AND tx_hash IN ?", g.chainID, hashes).Order("block_number asc, log_index asc").Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+func (g *GormDB) FindLogs(ctx context.Context, filter LogFilter) ([]*data.Log, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	query := db.Model(&data.Log{}).Where("chain_id = ?", g.chainID)
+	if filter.Address != "" {
+		query = query.Where("address = ?", filter.Address)
+	}
+	if filter.Topic0 != "" {
+		query = query.Where("topic0 = ?", filter.Topic0)
+	}
+	if filter.TxHash != "" {
+		query = query.Where("tx_hash = ?", filter.TxHash)
+	}
+	if filter.FromBlock != nil {
+		query = query.Where("block_number >= ?", *filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		query = query.Where("block_number <= ?", *filter.ToBlock)
+	}
+	var logs []*data.Log
+	err := query.Order("block_number asc, log_index asc").
+		Limit(filter.limit()).Offset(filter.offset()).
+		Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var mockLogs = []data.Log{
+	{
+		BlockHash:   "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+		Index:       0,
+		BlockNumber: 1,
+		TxHash:      "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+		Address:     "0x0000000000000000000000000000000000000001",
+		Topic0:      "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+		Topic1:      "0x0000000000000000000000000000000000000000000000000000000000000001",
+		Topic2:      "0x0000000000000000000000000000000000000000000000000000000000000002",
+		Data:        []byte("some log data"),
+	},
+	{
+		BlockHash:   "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+		Index:       1,
+		BlockNumber: 1,
+		TxHash:      "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+		Address:     "0x0000000000000000000000000000000000000002",
+		Data:        []byte("some other log data"),
+	},
+}
+
+func TestInsertLog(t *testing.T) {
+	s := newSuite(t)
+
+	s.sqlMock.ExpectBegin()
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO "logs" ("chain_id","block_hash","log_index","block_number","tx_hash","address","topic0","topic1","topic2","topic3","data") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`)).
+		WithArgs(
+			mockChainID, mockLogs[0].BlockHash, mockLogs[0].Index, mockLogs[0].BlockNumber, mockLogs[0].TxHash, mockLogs[0].Address,
+			mockLogs[0].Topic0, mockLogs[0].Topic1, mockLogs[0].Topic2, mockLogs[0].Topic3, mockLogs[0].Data,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	s.sqlMock.ExpectCommit()
+
+	err := s.dbMock.InsertLog(context.Background(), mockLogs[0])
+	assert.NoError(t, err)
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+func TestGetLogsByTxHashes(t *testing.T) {
+	s := newSuite(t)
+
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "logs" WHERE chain_id = $1 AND tx_hash IN ($2) ORDER BY block_number asc, log_index asc`)).
+		WithArgs(mockChainID, mockLogs[0].TxHash).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"block_hash", "log_index", "block_number", "tx_hash", "address", "topic0", "topic1", "topic2", "topic3", "data",
+		}).AddRow(
+			mockLogs[0].BlockHash, mockLogs[0].Index, mockLogs[0].BlockNumber, mockLogs[0].TxHash, mockLogs[0].Address,
+			mockLogs[0].Topic0, mockLogs[0].Topic1, mockLogs[0].Topic2, mockLogs[0].Topic3, mockLogs[0].Data,
+		).AddRow(
+			mockLogs[1].BlockHash, mockLogs[1].Index, mockLogs[1].BlockNumber, mockLogs[1].TxHash, mockLogs[1].Address,
+			mockLogs[1].Topic0, mockLogs[1].Topic1, mockLogs[1].Topic2, mockLogs[1].Topic3, mockLogs[1].Data,
+		))
+
+	retrievedLogs, err := s.dbMock.GetLogsByTxHashes(context.Background(), []string{mockLogs[0].TxHash})
+	assert.NoError(t, err)
+	assert.Len(t, retrievedLogs, len(mockLogs))
+	for i, retrievedLog := range retrievedLogs {
+		assert.Equal(t, &mockLogs[i], retrievedLog)
+	}
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+func TestFindLogs(t *testing.T) {
+	s := newSuite(t)
+
+	toBlock := uint64(10)
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "logs" WHERE chain_id = $1 AND address = $2 AND topic0 = $3 AND block_number <= $4 ORDER BY block_number asc, log_index asc LIMIT $5`)).
+		WithArgs(mockChainID, mockLogs[0].Address, mockLogs[0].Topic0, toBlock, 5).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"block_hash", "log_index", "block_number", "tx_hash", "address", "topic0", "topic1", "topic2", "topic3", "data",
+		}).AddRow(
+			mockLogs[0].BlockHash, mockLogs[0].Index, mockLogs[0].BlockNumber, mockLogs[0].TxHash, mockLogs[0].Address,
+			mockLogs[0].Topic0, mockLogs[0].Topic1, mockLogs[0].Topic2, mockLogs[0].Topic3, mockLogs[0].Data,
+		))
+
+	retrievedLogs, err := s.dbMock.FindLogs(context.Background(), LogFilter{
+		Address: mockLogs[0].Address,
+		Topic0:  mockLogs[0].Topic0,
+		ToBlock: &toBlock,
+		Page:    Page{Limit: 5},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*data.Log{&mockLogs[0]}, retrievedLogs)
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,266 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMigratorSuite(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	s := newSuite(t)
+	m, err := NewMigrator(s.dbMock.(*GormDB))
+	require.NoError(t, err)
+	return m, s.sqlMock
+}
+
+// expectLocked sets up the expectations shared by every migrator operation,
+// the applied versions are returned from schema_migrations.
+func expectLocked(sqlMock sqlmock.Sqlmock, applied ...uint64) {
+	sqlMock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_lock($1)`)).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectExec(regexp.QuoteMeta(createSchemaMigrations[dialectPostgres])).WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"version", "name", "applied_at"})
+	for _, v := range applied {
+		rows.AddRow(v, "migration", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	}
+	sqlMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "schema_migrations" ORDER BY version asc`)).WillReturnRows(rows)
+}
+
+func expectUnlock(sqlMock sqlmock.Sqlmock) {
+	sqlMock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectApply(sqlMock sqlmock.Sqlmock, mig Migration) {
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(regexp.QuoteMeta(mig.Up)).WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "schema_migrations" ("version","name","applied_at") VALUES ($1,$2,$3)`)).
+		WithArgs(mig.Version, mig.Name, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlMock.ExpectCommit()
+}
+
+func expectRevert(sqlMock sqlmock.Sqlmock, mig Migration) {
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(regexp.QuoteMeta(mig.Down)).WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "schema_migrations" WHERE version = $1`)).
+		WithArgs(mig.Version).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlMock.ExpectCommit()
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	m, _ := newMigratorSuite(t)
+	require.NotEmpty(t, m.migrations)
+	for i, mig := range m.migrations {
+		assert.Equal(t, uint64(i+1), mig.Version, "migration versions must be contiguous")
+		assert.NotEmpty(t, mig.Up)
+		assert.NotEmpty(t, mig.Down)
+	}
+}
+
+func TestDialectMigrationsMatch(t *testing.T) {
+	postgres, err := dialectMigrations(dialectPostgres)
+	require.NoError(t, err)
+	sqlite, err := dialectMigrations(dialectSQLite)
+	require.NoError(t, err)
+	require.Len(t, sqlite, len(postgres))
+	for i := range postgres {
+		assert.Equal(t, postgres[i].Version, sqlite[i].Version)
+		assert.Equal(t, postgres[i].Name, sqlite[i].Name)
+	}
+}
+
+func TestLoadMigrationsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		err   string
+	}{
+		{
+			name:  "missing down",
+			files: fstest.MapFS{"0001_init.up.sql": {Data: []byte("SELECT 1")}},
+			err:   "migration 1_init must have up and down files",
+		},
+		{
+			name: "duplicate version",
+			files: fstest.MapFS{
+				"0001_init.up.sql":  {Data: []byte("SELECT 1")},
+				"0001_other.up.sql": {Data: []byte("SELECT 1")},
+			},
+			err: "duplicate migration version 1",
+		},
+		{
+			name:  "invalid name",
+			files: fstest.MapFS{"init.sql": {Data: []byte("SELECT 1")}},
+			err:   "invalid migration file name: init.sql",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadMigrations(tt.files)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+// TestMigrateUpEachVersion migrates to each version from the one before it.
+func TestMigrateUpEachVersion(t *testing.T) {
+	m, _ := newMigratorSuite(t)
+	for i, mig := range m.migrations {
+		t.Run(mig.Name, func(t *testing.T) {
+			m, sqlMock := newMigratorSuite(t)
+			var applied []uint64
+			for _, prev := range m.migrations[:i] {
+				applied = append(applied, prev.Version)
+			}
+			expectLocked(sqlMock, applied...)
+			expectApply(sqlMock, mig)
+			expectUnlock(sqlMock)
+
+			assert.NoError(t, m.To(mig.Version))
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestMigrateDownEachVersion reverts each version with all earlier ones applied.
+func TestMigrateDownEachVersion(t *testing.T) {
+	m, _ := newMigratorSuite(t)
+	for i, mig := range m.migrations {
+		t.Run(mig.Name, func(t *testing.T) {
+			m, sqlMock := newMigratorSuite(t)
+			var applied []uint64
+			for _, prev := range m.migrations[:i+1] {
+				applied = append(applied, prev.Version)
+			}
+			expectLocked(sqlMock, applied...)
+			expectRevert(sqlMock, mig)
+			expectUnlock(sqlMock)
+
+			assert.NoError(t, m.Down())
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMigrateUp(t *testing.T) {
+	m, sqlMock := newMigratorSuite(t)
+	expectLocked(sqlMock)
+	for _, mig := range m.migrations {
+		expectApply(sqlMock, mig)
+	}
+	expectUnlock(sqlMock)
+
+	assert.NoError(t, m.Up())
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestMigrateUpToDate(t *testing.T) {
+	m, sqlMock := newMigratorSuite(t)
+	var applied []uint64
+	for _, mig := range m.migrations {
+		applied = append(applied, mig.Version)
+	}
+	expectLocked(sqlMock, applied...)
+	expectUnlock(sqlMock)
+
+	assert.NoError(t, m.Up())
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestMigrateToZero(t *testing.T) {
+	m, sqlMock := newMigratorSuite(t)
+	var applied []uint64
+	for _, mig := range m.migrations {
+		applied = append(applied, mig.Version)
+	}
+	expectLocked(sqlMock, applied...)
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		expectRevert(sqlMock, m.migrations[i])
+	}
+	expectUnlock(sqlMock)
+
+	assert.NoError(t, m.To(0))
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestMigrateToUnknownVersion(t *testing.T) {
+	m, sqlMock := newMigratorSuite(t)
+	assert.EqualError(t, m.To(m.Latest()+1), fmt.Sprintf("unknown migration version %d", m.Latest()+1))
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestMigrationStatus(t *testing.T) {
+	m, sqlMock := newMigratorSuite(t)
+	expectLocked(sqlMock, 1)
+	expectUnlock(sqlMock)
+
+	status, err := m.Status()
+	require.NoError(t, err)
+	require.Len(t, status, len(m.migrations))
+	assert.Equal(t, uint64(1), status[0].Version)
+	assert.Equal(t, "initial_schema", status[0].Name)
+	require.NotNil(t, status[0].AppliedAt)
+	assert.Equal(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), *status[0].AppliedAt)
+	for _, s := range status[1:] {
+		assert.Nil(t, s.AppliedAt)
+	}
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// baselineSchema is the schema gorm AutoMigrate created before migrations
+// were versioned.
+var baselineSchema = []string{
+	`CREATE TABLE "blocks" ("hash" char(66),"number" numeric NOT NULL,"gas_limit" numeric NOT NULL,"gas_used" numeric NOT NULL,"difficulty" numeric NOT NULL,"time" numeric NOT NULL,"parent_hash" char(66) NOT NULL,"nonce" numeric NOT NULL,"miner" char(42) NOT NULL,"size" numeric NOT NULL,"root_hash" char(66) NOT NULL,"uncle_hash" char(66) NOT NULL,"tx_hash" char(66) NOT NULL,"receipt_hash" char(66) NOT NULL,"extra_data" bytea,PRIMARY KEY ("hash"),CONSTRAINT "uni_blocks_number" UNIQUE ("number"))`,
+	`CREATE INDEX IF NOT EXISTS "idx_blocks_number" ON "blocks" ("number" asc)`,
+	`CREATE TABLE "transactions" ("hash" char(66),"from" char(42) NOT NULL,"to" char(42),"contract" char(66) NOT NULL,"value" numeric NOT NULL,"data" bytea NOT NULL,"gas" numeric NOT NULL,"gas_price" numeric NOT NULL,"cost" numeric NOT NULL,"nonce" numeric NOT NULL,"status" numeric NOT NULL,"block_hash" char(66) NOT NULL,PRIMARY KEY ("hash"))`,
+}
+
+// TestMigrateFromBaseline migrates a database populated by the baseline
+// schema, whose transactions have no block number and which has no logs.
+func TestMigrateFromBaseline(t *testing.T) {
+	urls := map[string]string{"sqlite": sqliteScheme + filepath.Join(t.TempDir(), "indexer.db")}
+	if url := os.Getenv("TEST_DB_URL"); url != "" {
+		urls["postgres"] = url
+	}
+	for name, url := range urls {
+		t.Run(name, func(t *testing.T) {
+			g, err := Open(url)
+			require.NoError(t, err)
+			t.Cleanup(func() { g.Close() })
+			m, err := NewMigrator(g)
+			require.NoError(t, err)
+			require.NoError(t, m.To(0))
+			require.NoError(t, g.Exec(`DROP TABLE IF EXISTS "schema_migrations"`).Error)
+
+			for _, stmt := range baselineSchema {
+				require.NoError(t, g.Exec(stmt).Error)
+			}
+			block := conformanceBlock(7)
+			require.NoError(t, g.Exec(`INSERT INTO "blocks" VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				block.Hash, block.Number, block.GasLimit, block.GasUsed, block.Difficulty, block.Time, block.ParentHash,
+				block.Nonce, block.Miner, block.Size, block.RootHash, block.UncleHash, block.TxHash, block.ReceiptHash, block.ExtraData).Error)
+			tx := conformanceTx(1, 7, address(10), address(11))
+			require.NoError(t, g.Exec(`INSERT INTO "transactions" VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				tx.Hash, tx.From, tx.To, tx.Contract, tx.Value, tx.Data, tx.Gas, tx.GasPrice, tx.Cost, tx.Nonce, tx.Status, tx.BlockHash).Error)
+
+			m, err = NewMigrator(g)
+			require.NoError(t, err)
+			require.NoError(t, m.Up())
+
+			ctx := context.Background()
+			stored, err := g.GetTxByHash(ctx, tx.Hash, nil)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(7), stored.BlockNumber)
+			require.NoError(t, g.InsertLog(ctx, conformanceLog(7, 0, tx.Hash, address(20))))
+		})
+	}
+}
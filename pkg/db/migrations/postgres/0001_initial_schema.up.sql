@@ -0,0 +1,39 @@
+-- Matches the schema created by gorm AutoMigrate before migrations were
+-- versioned, so existing databases adopt this version without changes.
+-- Migration 0002 adds what was auto migrated since.
+CREATE TABLE IF NOT EXISTS "blocks" (
+    "hash" char(66) NOT NULL,
+    "number" numeric NOT NULL,
+    "gas_limit" numeric NOT NULL,
+    "gas_used" numeric NOT NULL,
+    "difficulty" numeric NOT NULL,
+    "time" numeric NOT NULL,
+    "parent_hash" char(66) NOT NULL,
+    "nonce" numeric NOT NULL,
+    "miner" char(42) NOT NULL,
+    "size" numeric NOT NULL,
+    "root_hash" char(66) NOT NULL,
+    "uncle_hash" char(66) NOT NULL,
+    "tx_hash" char(66) NOT NULL,
+    "receipt_hash" char(66) NOT NULL,
+    "extra_data" bytea,
+    PRIMARY KEY ("hash"),
+    CONSTRAINT "uni_blocks_number" UNIQUE ("number")
+);
+CREATE INDEX IF NOT EXISTS "idx_blocks_number" ON "blocks" ("number" asc);
+
+CREATE TABLE IF NOT EXISTS "transactions" (
+    "hash" char(66) NOT NULL,
+    "from" char(42) NOT NULL,
+    "to" char(42),
+    "contract" char(66) NOT NULL,
+    "value" numeric NOT NULL,
+    "data" bytea NOT NULL,
+    "gas" numeric NOT NULL,
+    "gas_price" numeric NOT NULL,
+    "cost" numeric NOT NULL,
+    "nonce" numeric NOT NULL,
+    "status" numeric NOT NULL,
+    "block_hash" char(66) NOT NULL,
+    PRIMARY KEY ("hash")
+);
@@ -0,0 +1,18 @@
+-- Copy every partition back into unpartitioned tables with the schema of 0001.
+
+CREATE TABLE "blocks_unpartitioned" (LIKE "blocks" INCLUDING DEFAULTS);
+INSERT INTO "blocks_unpartitioned" SELECT * FROM "blocks";
+DROP TABLE "blocks";
+ALTER TABLE "blocks_unpartitioned" RENAME TO "blocks";
+ALTER TABLE "blocks" ADD CONSTRAINT "blocks_pkey" PRIMARY KEY ("hash");
+ALTER TABLE "blocks" ADD CONSTRAINT "uni_blocks_number" UNIQUE ("number");
+CREATE INDEX "idx_blocks_number" ON "blocks" ("number" asc);
+
+CREATE TABLE "transactions_unpartitioned" (LIKE "transactions" INCLUDING DEFAULTS);
+INSERT INTO "transactions_unpartitioned" SELECT * FROM "transactions";
+DROP TABLE "transactions";
+ALTER TABLE "transactions_unpartitioned" RENAME TO "transactions";
+ALTER TABLE "transactions" ADD CONSTRAINT "transactions_pkey" PRIMARY KEY ("hash");
+ALTER TABLE "transactions" DROP COLUMN "block_number";
+
+DROP TABLE IF EXISTS "logs";
@@ -0,0 +1,127 @@
+-- Range partition blocks and transactions on block number. Partitions for new
+-- ranges are created by the application as the chain head advances, see
+-- pkg/db/partition.go for the partition sizes.
+--
+-- Existing rows are kept in a single legacy partition covering every block up
+-- to the next partition boundary, so this migration does not copy data. Run
+-- `migrate repartition` afterwards to split it into regular partitions.
+
+-- Databases created before migrations were versioned may lack the block
+-- number of transactions, the logs table and the indexes auto migrated after
+-- the schema of 0001. The block number is added nullable, backfilled from the
+-- transaction's block and only then required.
+ALTER TABLE "transactions" ADD COLUMN IF NOT EXISTS "block_number" numeric;
+UPDATE "transactions" SET "block_number" = "blocks"."number"
+FROM "blocks"
+WHERE "transactions"."block_number" IS NULL AND "blocks"."hash" = "transactions"."block_hash";
+DO $$
+DECLARE
+    orphans bigint;
+BEGIN
+    SELECT count(*) INTO orphans FROM "transactions" WHERE "block_number" IS NULL;
+    IF orphans > 0 THEN
+        RAISE EXCEPTION '% transactions belong to blocks that are not stored, index the blocks or delete the transactions before migrating', orphans;
+    END IF;
+END $$;
+ALTER TABLE "transactions" ALTER COLUMN "block_number" SET NOT NULL;
+CREATE INDEX IF NOT EXISTS "idx_blocks_miner" ON "blocks" ("miner");
+CREATE INDEX IF NOT EXISTS "idx_transactions_from" ON "transactions" ("from");
+CREATE INDEX IF NOT EXISTS "idx_transactions_to" ON "transactions" ("to");
+CREATE INDEX IF NOT EXISTS "idx_transactions_block_hash" ON "transactions" ("block_hash");
+CREATE INDEX IF NOT EXISTS "idx_transactions_block_number" ON "transactions" ("block_number");
+
+CREATE TABLE IF NOT EXISTS "logs" (
+    "block_hash" char(66) NOT NULL,
+    "log_index" numeric NOT NULL,
+    "block_number" numeric NOT NULL,
+    "tx_hash" char(66) NOT NULL,
+    "address" char(42) NOT NULL,
+    "topic0" char(66),
+    "topic1" char(66),
+    "topic2" char(66),
+    "topic3" char(66),
+    "data" bytea,
+    PRIMARY KEY ("block_hash", "log_index")
+);
+CREATE INDEX IF NOT EXISTS "idx_logs_block_number" ON "logs" ("block_number");
+CREATE INDEX IF NOT EXISTS "idx_logs_tx_hash" ON "logs" ("tx_hash");
+CREATE INDEX IF NOT EXISTS "idx_logs_address" ON "logs" ("address");
+
+ALTER TABLE "blocks" RENAME TO "blocks_legacy";
+ALTER TABLE "blocks_legacy" RENAME CONSTRAINT "blocks_pkey" TO "blocks_legacy_pkey";
+ALTER TABLE "blocks_legacy" RENAME CONSTRAINT "uni_blocks_number" TO "uni_blocks_legacy_number";
+ALTER INDEX "idx_blocks_number" RENAME TO "idx_blocks_legacy_number";
+ALTER INDEX "idx_blocks_miner" RENAME TO "idx_blocks_legacy_miner";
+
+CREATE TABLE "blocks" (
+    "hash" char(66) NOT NULL,
+    "number" numeric NOT NULL,
+    "gas_limit" numeric NOT NULL,
+    "gas_used" numeric NOT NULL,
+    "difficulty" numeric NOT NULL,
+    "time" numeric NOT NULL,
+    "parent_hash" char(66) NOT NULL,
+    "nonce" numeric NOT NULL,
+    "miner" char(42) NOT NULL,
+    "size" numeric NOT NULL,
+    "root_hash" char(66) NOT NULL,
+    "uncle_hash" char(66) NOT NULL,
+    "tx_hash" char(66) NOT NULL,
+    "receipt_hash" char(66) NOT NULL,
+    "extra_data" bytea,
+    PRIMARY KEY ("number", "hash"),
+    CONSTRAINT "uni_blocks_number" UNIQUE ("number")
+) PARTITION BY RANGE ("number");
+CREATE INDEX "idx_blocks_hash" ON "blocks" ("hash");
+CREATE INDEX "idx_blocks_miner" ON "blocks" ("miner");
+
+ALTER TABLE "transactions" RENAME TO "transactions_legacy";
+ALTER TABLE "transactions_legacy" RENAME CONSTRAINT "transactions_pkey" TO "transactions_legacy_pkey";
+ALTER INDEX "idx_transactions_from" RENAME TO "idx_transactions_legacy_from";
+ALTER INDEX "idx_transactions_to" RENAME TO "idx_transactions_legacy_to";
+ALTER INDEX "idx_transactions_block_hash" RENAME TO "idx_transactions_legacy_block_hash";
+ALTER INDEX "idx_transactions_block_number" RENAME TO "idx_transactions_legacy_block_number";
+
+CREATE TABLE "transactions" (
+    "hash" char(66) NOT NULL,
+    "from" char(42) NOT NULL,
+    "to" char(42),
+    "contract" char(66) NOT NULL,
+    "value" numeric NOT NULL,
+    "data" bytea NOT NULL,
+    "gas" numeric NOT NULL,
+    "gas_price" numeric NOT NULL,
+    "cost" numeric NOT NULL,
+    "nonce" numeric NOT NULL,
+    "status" numeric NOT NULL,
+    "block_hash" char(66) NOT NULL,
+    "block_number" numeric NOT NULL,
+    PRIMARY KEY ("block_number", "hash")
+) PARTITION BY RANGE ("block_number");
+CREATE INDEX "idx_transactions_hash" ON "transactions" ("hash");
+CREATE INDEX "idx_transactions_from" ON "transactions" ("from");
+CREATE INDEX "idx_transactions_to" ON "transactions" ("to");
+CREATE INDEX "idx_transactions_block_hash" ON "transactions" ("block_hash");
+
+-- The CHECK constraint matches the partition bound, which lets ATTACH skip
+-- scanning the legacy table a second time.
+DO $$
+DECLARE
+    bound numeric;
+BEGIN
+    SELECT (floor(max("number") / 1000000) + 1) * 1000000 INTO bound FROM "blocks_legacy";
+    IF bound IS NULL THEN
+        DROP TABLE "blocks_legacy";
+    ELSE
+        EXECUTE format('ALTER TABLE "blocks_legacy" ADD CONSTRAINT "blocks_legacy_bound" CHECK ("number" >= 0 AND "number" < %s)', bound);
+        EXECUTE format('ALTER TABLE "blocks" ATTACH PARTITION "blocks_legacy" FOR VALUES FROM (0) TO (%s)', bound);
+    END IF;
+
+    SELECT (floor(max("block_number") / 100000) + 1) * 100000 INTO bound FROM "transactions_legacy";
+    IF bound IS NULL THEN
+        DROP TABLE "transactions_legacy";
+    ELSE
+        EXECUTE format('ALTER TABLE "transactions_legacy" ADD CONSTRAINT "transactions_legacy_bound" CHECK ("block_number" >= 0 AND "block_number" < %s)', bound);
+        EXECUTE format('ALTER TABLE "transactions" ATTACH PARTITION "transactions_legacy" FOR VALUES FROM (0) TO (%s)', bound);
+    END IF;
+END $$;
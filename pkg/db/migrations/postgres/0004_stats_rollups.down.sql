@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "stats_dirty";
+DROP TABLE IF EXISTS "stats_daily";
+DROP TABLE IF EXISTS "stats_hourly";
@@ -0,0 +1,43 @@
+-- Hourly and daily chain statistics, see pkg/stats. Inserting a block or
+-- transaction marks the hour of its block in "stats_dirty", rolling up an hour
+-- marks its day.
+
+CREATE TABLE IF NOT EXISTS "stats_hourly" (
+    "time" bigint NOT NULL,
+    "blocks" bigint NOT NULL,
+    "txs" bigint NOT NULL,
+    "gas_used" numeric NOT NULL,
+    "avg_gas_price" numeric NOT NULL,
+    "median_gas_price" numeric NOT NULL,
+    "active_addresses" bigint NOT NULL,
+    "contract_deployments" bigint NOT NULL,
+    "failed_txs" bigint NOT NULL,
+    "failed_tx_ratio" double precision NOT NULL,
+    "avg_block_time" double precision NOT NULL,
+    PRIMARY KEY ("time")
+);
+
+CREATE TABLE IF NOT EXISTS "stats_daily" (
+    "time" bigint NOT NULL,
+    "blocks" bigint NOT NULL,
+    "txs" bigint NOT NULL,
+    "gas_used" numeric NOT NULL,
+    "avg_gas_price" numeric NOT NULL,
+    "median_gas_price" numeric NOT NULL,
+    "active_addresses" bigint NOT NULL,
+    "contract_deployments" bigint NOT NULL,
+    "failed_txs" bigint NOT NULL,
+    "failed_tx_ratio" double precision NOT NULL,
+    "avg_block_time" double precision NOT NULL,
+    PRIMARY KEY ("time")
+);
+
+CREATE TABLE IF NOT EXISTS "stats_dirty" (
+    "period" text NOT NULL,
+    "time" bigint NOT NULL,
+    PRIMARY KEY ("period", "time")
+);
+
+-- Roll up the history indexed before this migration.
+INSERT INTO "stats_dirty" ("period", "time")
+SELECT DISTINCT 'hourly', "time" - "time" % 3600 FROM "blocks";
@@ -0,0 +1,2 @@
+ALTER TABLE "transactions" DROP COLUMN "priority_fee";
+ALTER TABLE "blocks" DROP COLUMN "base_fee";
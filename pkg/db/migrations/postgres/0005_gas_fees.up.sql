@@ -0,0 +1,4 @@
+-- Fees backing the gas oracle. Rows indexed before this migration keep a zero
+-- base fee and priority fee.
+ALTER TABLE "blocks" ADD COLUMN "base_fee" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "transactions" ADD COLUMN "priority_fee" numeric NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "token_balances";
+DROP TABLE IF EXISTS "token_approvals";
+DROP TABLE IF EXISTS "token_transfers";
+DROP TABLE IF EXISTS "tokens";
@@ -0,0 +1,49 @@
+-- ERC-20 tokens, see pkg/db/token.go. Transfers and approvals are decoded
+-- from logs. Balances are kept up to date as transfers are inserted and
+-- reorged blocks are removed. Token amounts are uint256 decimal strings.
+
+CREATE TABLE IF NOT EXISTS "tokens" (
+    "address" char(42) NOT NULL,
+    "name" text NOT NULL,
+    "symbol" text NOT NULL,
+    "decimals" smallint NOT NULL,
+    PRIMARY KEY ("address")
+);
+
+CREATE TABLE IF NOT EXISTS "token_transfers" (
+    "block_hash" char(66) NOT NULL,
+    "log_index" numeric NOT NULL,
+    "block_number" numeric NOT NULL,
+    "tx_hash" char(66) NOT NULL,
+    "token" char(42) NOT NULL,
+    "from" char(42) NOT NULL,
+    "to" char(42) NOT NULL,
+    "value" varchar(78) NOT NULL,
+    PRIMARY KEY ("block_hash", "log_index")
+);
+CREATE INDEX IF NOT EXISTS "idx_token_transfers_block_number" ON "token_transfers" ("block_number");
+CREATE INDEX IF NOT EXISTS "idx_token_transfers_token" ON "token_transfers" ("token", "block_number");
+CREATE INDEX IF NOT EXISTS "idx_token_transfers_from" ON "token_transfers" ("from");
+CREATE INDEX IF NOT EXISTS "idx_token_transfers_to" ON "token_transfers" ("to");
+
+CREATE TABLE IF NOT EXISTS "token_approvals" (
+    "block_hash" char(66) NOT NULL,
+    "log_index" numeric NOT NULL,
+    "block_number" numeric NOT NULL,
+    "tx_hash" char(66) NOT NULL,
+    "token" char(42) NOT NULL,
+    "owner" char(42) NOT NULL,
+    "spender" char(42) NOT NULL,
+    "value" varchar(78) NOT NULL,
+    PRIMARY KEY ("block_hash", "log_index")
+);
+CREATE INDEX IF NOT EXISTS "idx_token_approvals_block_number" ON "token_approvals" ("block_number");
+CREATE INDEX IF NOT EXISTS "idx_token_approvals_owner" ON "token_approvals" ("owner");
+
+CREATE TABLE IF NOT EXISTS "token_balances" (
+    "token" char(42) NOT NULL,
+    "holder" char(42) NOT NULL,
+    "balance" varchar(78) NOT NULL,
+    PRIMARY KEY ("token", "holder")
+);
+CREATE INDEX IF NOT EXISTS "idx_token_balances_holder" ON "token_balances" ("holder");
@@ -0,0 +1,40 @@
+-- ERC-721 and ERC-1155 tokens, see pkg/db/nft.go. Transfers are decoded from
+-- logs, "nft_owners" is kept up to date like "token_balances". Token ids and
+-- amounts are uint256 decimal strings.
+
+CREATE TABLE IF NOT EXISTS "nfts" (
+    "contract" char(42) NOT NULL,
+    "token_id" varchar(78) NOT NULL,
+    "standard" text NOT NULL,
+    "uri" text NOT NULL,
+    PRIMARY KEY ("contract", "token_id")
+);
+
+CREATE TABLE IF NOT EXISTS "nft_transfers" (
+    "block_hash" char(66) NOT NULL,
+    "log_index" numeric NOT NULL,
+    "batch_index" numeric NOT NULL,
+    "block_number" numeric NOT NULL,
+    "tx_hash" char(66) NOT NULL,
+    "contract" char(42) NOT NULL,
+    "token_id" varchar(78) NOT NULL,
+    "standard" text NOT NULL,
+    "operator" char(42) NOT NULL,
+    "from" char(42) NOT NULL,
+    "to" char(42) NOT NULL,
+    "amount" varchar(78) NOT NULL,
+    PRIMARY KEY ("block_hash", "log_index", "batch_index")
+);
+CREATE INDEX IF NOT EXISTS "idx_nft_transfers_block_number" ON "nft_transfers" ("block_number");
+CREATE INDEX IF NOT EXISTS "idx_nft_transfers_token" ON "nft_transfers" ("contract", "token_id", "block_number");
+CREATE INDEX IF NOT EXISTS "idx_nft_transfers_from" ON "nft_transfers" ("from");
+CREATE INDEX IF NOT EXISTS "idx_nft_transfers_to" ON "nft_transfers" ("to");
+
+CREATE TABLE IF NOT EXISTS "nft_owners" (
+    "contract" char(42) NOT NULL,
+    "token_id" varchar(78) NOT NULL,
+    "owner" char(42) NOT NULL,
+    "balance" varchar(78) NOT NULL,
+    PRIMARY KEY ("contract", "token_id", "owner")
+);
+CREATE INDEX IF NOT EXISTS "idx_nft_owners_owner" ON "nft_owners" ("owner", "contract");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "signatures";
+DROP TABLE IF EXISTS "contract_abis";
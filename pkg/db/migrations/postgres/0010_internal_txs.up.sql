@@ -0,0 +1,23 @@
+-- Internal transactions flattened from call traces when tracing is enabled,
+-- see pkg/eth/trace.go.
+
+CREATE TABLE IF NOT EXISTS "internal_txs" (
+    "block_hash" char(66) NOT NULL,
+    "tx_hash" char(66) NOT NULL,
+    "trace_index" numeric NOT NULL,
+    "block_number" numeric NOT NULL,
+    "depth" numeric NOT NULL,
+    "type" text NOT NULL,
+    "from" char(42) NOT NULL,
+    "to" char(42) NOT NULL,
+    "value" varchar(78) NOT NULL,
+    "gas" numeric NOT NULL,
+    "gas_used" numeric NOT NULL,
+    "error" text NOT NULL,
+    "reverted" boolean NOT NULL,
+    PRIMARY KEY ("block_hash", "tx_hash", "trace_index")
+);
+CREATE INDEX IF NOT EXISTS "idx_internal_txs_block_number" ON "internal_txs" ("block_number");
+CREATE INDEX IF NOT EXISTS "idx_internal_txs_tx_hash" ON "internal_txs" ("tx_hash");
+CREATE INDEX IF NOT EXISTS "idx_internal_txs_from" ON "internal_txs" ("from", "block_number");
+CREATE INDEX IF NOT EXISTS "idx_internal_txs_to" ON "internal_txs" ("to", "block_number");
@@ -0,0 +1,14 @@
+-- Balance and nonce snapshots of the addresses touched by each block, see
+-- pkg/eth/balance.go.
+
+CREATE TABLE IF NOT EXISTS "balances" (
+    "address" char(42) NOT NULL,
+    "block_number" numeric NOT NULL,
+    "block_hash" char(66) NOT NULL,
+    "time" numeric NOT NULL,
+    "balance" varchar(78) NOT NULL,
+    "nonce" numeric NOT NULL,
+    PRIMARY KEY ("address", "block_number")
+);
+CREATE INDEX IF NOT EXISTS "idx_balances_block_number" ON "balances" ("block_number");
+CREATE INDEX IF NOT EXISTS "idx_balances_address_time" ON "balances" ("address", "time");
@@ -0,0 +1,32 @@
+-- Per address transaction summaries, see pkg/db/address.go. Transactions
+-- indexed before this migration keep a zero gas used.
+ALTER TABLE "transactions" ADD COLUMN "gas_used" numeric NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS "addresses" (
+    "address" char(42) NOT NULL,
+    "first_seen_block" numeric NOT NULL,
+    "last_seen_block" numeric NOT NULL,
+    "txs_sent" numeric NOT NULL,
+    "txs_received" numeric NOT NULL,
+    "value_sent" varchar(78) NOT NULL,
+    "value_received" varchar(78) NOT NULL,
+    "gas_spent" numeric NOT NULL,
+    PRIMARY KEY ("address")
+);
+
+-- Summarize the transactions indexed before this migration.
+INSERT INTO "addresses" ("address", "first_seen_block", "last_seen_block", "txs_sent", "txs_received", "value_sent", "value_received", "gas_spent")
+SELECT "address", MIN("block_number"), MAX("block_number"), SUM("txs_sent"), SUM("txs_received"),
+    SUM("value_sent")::text, SUM("value_received")::text, SUM("gas_spent")
+FROM (
+    SELECT "from" AS "address", "block_number", 1 AS "txs_sent", 0 AS "txs_received",
+        CASE WHEN "status" = 1 THEN "value" ELSE 0 END AS "value_sent", 0 AS "value_received", "gas_used" AS "gas_spent"
+    FROM "transactions"
+    UNION ALL
+    SELECT CASE WHEN COALESCE("to", '') = '' THEN "contract" ELSE "to" END, "block_number", 0, 1,
+        0, CASE WHEN "status" = 1 THEN "value" ELSE 0 END, 0
+    FROM "transactions"
+    WHERE COALESCE("to", '') <> '' OR "status" = 1
+) AS "touched"
+GROUP BY "address"
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,20 @@
+-- Uncle headers included by pre-merge blocks, see pkg/eth/uncle.go.
+
+CREATE TABLE IF NOT EXISTS "uncles" (
+    "block_hash" char(66) NOT NULL,
+    "uncle_index" numeric NOT NULL,
+    "block_number" numeric NOT NULL,
+    "hash" char(66) NOT NULL,
+    "number" numeric NOT NULL,
+    "parent_hash" char(66) NOT NULL,
+    "miner" char(42) NOT NULL,
+    "difficulty" numeric NOT NULL,
+    "gas_limit" numeric NOT NULL,
+    "gas_used" numeric NOT NULL,
+    "time" numeric NOT NULL,
+    "reward" varchar(78) NOT NULL,
+    PRIMARY KEY ("block_hash", "uncle_index")
+);
+CREATE INDEX IF NOT EXISTS "idx_uncles_block_number" ON "uncles" ("block_number");
+CREATE INDEX IF NOT EXISTS "idx_uncles_hash" ON "uncles" ("hash");
+CREATE INDEX IF NOT EXISTS "idx_uncles_miner" ON "uncles" ("miner");
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS "miner_rewards";
+ALTER TABLE "stats_daily" DROP COLUMN "blob_fee_burned";
+ALTER TABLE "stats_daily" DROP COLUMN "base_fee_burned";
+ALTER TABLE "stats_hourly" DROP COLUMN "blob_fee_burned";
+ALTER TABLE "stats_hourly" DROP COLUMN "base_fee_burned";
+ALTER TABLE "blocks" DROP COLUMN "uncle_inclusion_reward";
+ALTER TABLE "blocks" DROP COLUMN "block_reward";
+ALTER TABLE "blocks" DROP COLUMN "blob_fee_burned";
+ALTER TABLE "blocks" DROP COLUMN "base_fee_burned";
+ALTER TABLE "blocks" DROP COLUMN "priority_fees";
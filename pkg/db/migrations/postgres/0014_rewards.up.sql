@@ -0,0 +1,38 @@
+-- Block rewards and fee burn, see pkg/eth/reward.go, with per miner totals
+-- kept by pkg/db/reward.go. Blocks indexed before this migration keep zero
+-- rewards and burn, and are only counted in the totals.
+ALTER TABLE "blocks" ADD COLUMN "priority_fees" varchar(78) NOT NULL DEFAULT '0';
+ALTER TABLE "blocks" ADD COLUMN "base_fee_burned" varchar(78) NOT NULL DEFAULT '0';
+ALTER TABLE "blocks" ADD COLUMN "blob_fee_burned" varchar(78) NOT NULL DEFAULT '0';
+ALTER TABLE "blocks" ADD COLUMN "block_reward" varchar(78) NOT NULL DEFAULT '0';
+ALTER TABLE "blocks" ADD COLUMN "uncle_inclusion_reward" varchar(78) NOT NULL DEFAULT '0';
+
+ALTER TABLE "stats_hourly" ADD COLUMN "base_fee_burned" varchar(78) NOT NULL DEFAULT '0';
+ALTER TABLE "stats_hourly" ADD COLUMN "blob_fee_burned" varchar(78) NOT NULL DEFAULT '0';
+ALTER TABLE "stats_daily" ADD COLUMN "base_fee_burned" varchar(78) NOT NULL DEFAULT '0';
+ALTER TABLE "stats_daily" ADD COLUMN "blob_fee_burned" varchar(78) NOT NULL DEFAULT '0';
+
+CREATE TABLE IF NOT EXISTS "miner_rewards" (
+    "miner" char(42) NOT NULL,
+    "blocks" numeric NOT NULL,
+    "uncles" numeric NOT NULL,
+    "block_rewards" varchar(78) NOT NULL,
+    "uncle_inclusion_rewards" varchar(78) NOT NULL,
+    "uncle_rewards" varchar(78) NOT NULL,
+    "priority_fees" varchar(78) NOT NULL,
+    PRIMARY KEY ("miner")
+);
+
+-- Count the blocks and uncles indexed before this migration. Their rewards
+-- and fees weren't recorded and stay 0, only the uncle rewards are summed.
+INSERT INTO "miner_rewards" ("miner", "blocks", "uncles", "block_rewards", "uncle_inclusion_rewards", "uncle_rewards", "priority_fees")
+SELECT "miner", SUM("blocks"), SUM("uncles"), '0', '0', SUM("uncle_rewards")::text, '0'
+FROM (
+    SELECT "miner", 1 AS "blocks", 0 AS "uncles", 0 AS "uncle_rewards"
+    FROM "blocks"
+    UNION ALL
+    SELECT "miner", 0, 1, "reward"::numeric
+    FROM "uncles"
+) AS "mined"
+GROUP BY "miner"
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,85 @@
+-- Fails if rows of several chains share a key, delete the other chains
+-- first. The legacy partition constraints dropped by the up migration are not
+-- restored.
+
+ALTER TABLE "miner_rewards" DROP CONSTRAINT "miner_rewards_pkey";
+ALTER TABLE "miner_rewards" ADD PRIMARY KEY ("miner");
+ALTER TABLE "miner_rewards" DROP COLUMN "chain_id";
+
+ALTER TABLE "uncles" DROP CONSTRAINT "uncles_pkey";
+ALTER TABLE "uncles" ADD PRIMARY KEY ("block_hash", "uncle_index");
+ALTER TABLE "uncles" DROP COLUMN "chain_id";
+
+ALTER TABLE "addresses" DROP CONSTRAINT "addresses_pkey";
+ALTER TABLE "addresses" ADD PRIMARY KEY ("address");
+ALTER TABLE "addresses" DROP COLUMN "chain_id";
+
+ALTER TABLE "balances" DROP CONSTRAINT "balances_pkey";
+ALTER TABLE "balances" ADD PRIMARY KEY ("address", "block_number");
+ALTER TABLE "balances" DROP COLUMN "chain_id";
+
+ALTER TABLE "internal_txs" DROP CONSTRAINT "internal_txs_pkey";
+ALTER TABLE "internal_txs" ADD PRIMARY KEY ("block_hash", "tx_hash", "trace_index");
+ALTER TABLE "internal_txs" DROP COLUMN "chain_id";
+
+ALTER TABLE "contract_abis" DROP CONSTRAINT "contract_abis_pkey";
+ALTER TABLE "contract_abis" ADD PRIMARY KEY ("address");
+ALTER TABLE "contract_abis" DROP COLUMN "chain_id";
+
+ALTER TABLE "contracts" DROP CONSTRAINT "contracts_pkey";
+ALTER TABLE "contracts" ADD PRIMARY KEY ("address");
+ALTER TABLE "contracts" DROP COLUMN "chain_id";
+
+ALTER TABLE "nft_owners" DROP CONSTRAINT "nft_owners_pkey";
+ALTER TABLE "nft_owners" ADD PRIMARY KEY ("contract", "token_id", "owner");
+ALTER TABLE "nft_owners" DROP COLUMN "chain_id";
+
+ALTER TABLE "nft_transfers" DROP CONSTRAINT "nft_transfers_pkey";
+ALTER TABLE "nft_transfers" ADD PRIMARY KEY ("block_hash", "log_index", "batch_index");
+ALTER TABLE "nft_transfers" DROP COLUMN "chain_id";
+
+ALTER TABLE "nfts" DROP CONSTRAINT "nfts_pkey";
+ALTER TABLE "nfts" ADD PRIMARY KEY ("contract", "token_id");
+ALTER TABLE "nfts" DROP COLUMN "chain_id";
+
+ALTER TABLE "token_balances" DROP CONSTRAINT "token_balances_pkey";
+ALTER TABLE "token_balances" ADD PRIMARY KEY ("token", "holder");
+ALTER TABLE "token_balances" DROP COLUMN "chain_id";
+
+ALTER TABLE "token_approvals" DROP CONSTRAINT "token_approvals_pkey";
+ALTER TABLE "token_approvals" ADD PRIMARY KEY ("block_hash", "log_index");
+ALTER TABLE "token_approvals" DROP COLUMN "chain_id";
+
+ALTER TABLE "token_transfers" DROP CONSTRAINT "token_transfers_pkey";
+ALTER TABLE "token_transfers" ADD PRIMARY KEY ("block_hash", "log_index");
+ALTER TABLE "token_transfers" DROP COLUMN "chain_id";
+
+ALTER TABLE "tokens" DROP CONSTRAINT "tokens_pkey";
+ALTER TABLE "tokens" ADD PRIMARY KEY ("address");
+ALTER TABLE "tokens" DROP COLUMN "chain_id";
+
+ALTER TABLE "stats_dirty" DROP CONSTRAINT "stats_dirty_pkey";
+ALTER TABLE "stats_dirty" ADD PRIMARY KEY ("period", "time");
+ALTER TABLE "stats_dirty" DROP COLUMN "chain_id";
+
+ALTER TABLE "stats_daily" DROP CONSTRAINT "stats_daily_pkey";
+ALTER TABLE "stats_daily" ADD PRIMARY KEY ("time");
+ALTER TABLE "stats_daily" DROP COLUMN "chain_id";
+
+ALTER TABLE "stats_hourly" DROP CONSTRAINT "stats_hourly_pkey";
+ALTER TABLE "stats_hourly" ADD PRIMARY KEY ("time");
+ALTER TABLE "stats_hourly" DROP COLUMN "chain_id";
+
+ALTER TABLE "logs" DROP CONSTRAINT "logs_pkey";
+ALTER TABLE "logs" ADD PRIMARY KEY ("block_hash", "log_index");
+ALTER TABLE "logs" DROP COLUMN "chain_id";
+
+ALTER TABLE "transactions" DROP CONSTRAINT "transactions_pkey";
+ALTER TABLE "transactions" ADD PRIMARY KEY ("block_number", "hash");
+ALTER TABLE "transactions" DROP COLUMN "chain_id";
+
+ALTER TABLE "blocks" DROP CONSTRAINT "uni_blocks_number";
+ALTER TABLE "blocks" ADD CONSTRAINT "uni_blocks_number" UNIQUE ("number");
+ALTER TABLE "blocks" DROP CONSTRAINT "blocks_pkey";
+ALTER TABLE "blocks" ADD PRIMARY KEY ("number", "hash");
+ALTER TABLE "blocks" DROP COLUMN "chain_id";
@@ -0,0 +1,112 @@
+-- Index several chains in one database. Every table but signatures gets a
+-- chain_id column leading its primary key, see pkg/db.GormDB.ForChain. Rows
+-- indexed before this migration belong to chain 0, run
+-- `migrate assign-chain <chainId>` to move them to their chain.
+--
+-- The constraints of the legacy partitions left by migration 0002 don't
+-- include the chain and are dropped, the partitioned tables' keys cover them.
+ALTER TABLE IF EXISTS "blocks_legacy" DROP CONSTRAINT IF EXISTS "blocks_legacy_pkey";
+ALTER TABLE IF EXISTS "blocks_legacy" DROP CONSTRAINT IF EXISTS "uni_blocks_legacy_number";
+ALTER TABLE IF EXISTS "transactions_legacy" DROP CONSTRAINT IF EXISTS "transactions_legacy_pkey";
+
+ALTER TABLE "blocks" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "blocks" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "blocks" DROP CONSTRAINT "blocks_pkey";
+ALTER TABLE "blocks" ADD PRIMARY KEY ("chain_id", "number", "hash");
+ALTER TABLE "blocks" DROP CONSTRAINT "uni_blocks_number";
+ALTER TABLE "blocks" ADD CONSTRAINT "uni_blocks_number" UNIQUE ("chain_id", "number");
+
+ALTER TABLE "transactions" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "transactions" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "transactions" DROP CONSTRAINT "transactions_pkey";
+ALTER TABLE "transactions" ADD PRIMARY KEY ("chain_id", "block_number", "hash");
+
+ALTER TABLE "logs" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "logs" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "logs" DROP CONSTRAINT "logs_pkey";
+ALTER TABLE "logs" ADD PRIMARY KEY ("chain_id", "block_hash", "log_index");
+
+ALTER TABLE "stats_hourly" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "stats_hourly" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "stats_hourly" DROP CONSTRAINT "stats_hourly_pkey";
+ALTER TABLE "stats_hourly" ADD PRIMARY KEY ("chain_id", "time");
+
+ALTER TABLE "stats_daily" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "stats_daily" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "stats_daily" DROP CONSTRAINT "stats_daily_pkey";
+ALTER TABLE "stats_daily" ADD PRIMARY KEY ("chain_id", "time");
+
+ALTER TABLE "stats_dirty" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "stats_dirty" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "stats_dirty" DROP CONSTRAINT "stats_dirty_pkey";
+ALTER TABLE "stats_dirty" ADD PRIMARY KEY ("chain_id", "period", "time");
+
+ALTER TABLE "tokens" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "tokens" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "tokens" DROP CONSTRAINT "tokens_pkey";
+ALTER TABLE "tokens" ADD PRIMARY KEY ("chain_id", "address");
+
+ALTER TABLE "token_transfers" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "token_transfers" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "token_transfers" DROP CONSTRAINT "token_transfers_pkey";
+ALTER TABLE "token_transfers" ADD PRIMARY KEY ("chain_id", "block_hash", "log_index");
+
+ALTER TABLE "token_approvals" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "token_approvals" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "token_approvals" DROP CONSTRAINT "token_approvals_pkey";
+ALTER TABLE "token_approvals" ADD PRIMARY KEY ("chain_id", "block_hash", "log_index");
+
+ALTER TABLE "token_balances" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "token_balances" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "token_balances" DROP CONSTRAINT "token_balances_pkey";
+ALTER TABLE "token_balances" ADD PRIMARY KEY ("chain_id", "token", "holder");
+
+ALTER TABLE "nfts" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "nfts" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "nfts" DROP CONSTRAINT "nfts_pkey";
+ALTER TABLE "nfts" ADD PRIMARY KEY ("chain_id", "contract", "token_id");
+
+ALTER TABLE "nft_transfers" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "nft_transfers" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "nft_transfers" DROP CONSTRAINT "nft_transfers_pkey";
+ALTER TABLE "nft_transfers" ADD PRIMARY KEY ("chain_id", "block_hash", "log_index", "batch_index");
+
+ALTER TABLE "nft_owners" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "nft_owners" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "nft_owners" DROP CONSTRAINT "nft_owners_pkey";
+ALTER TABLE "nft_owners" ADD PRIMARY KEY ("chain_id", "contract", "token_id", "owner");
+
+ALTER TABLE "contracts" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "contracts" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "contracts" DROP CONSTRAINT "contracts_pkey";
+ALTER TABLE "contracts" ADD PRIMARY KEY ("chain_id", "address");
+
+ALTER TABLE "contract_abis" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "contract_abis" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "contract_abis" DROP CONSTRAINT "contract_abis_pkey";
+ALTER TABLE "contract_abis" ADD PRIMARY KEY ("chain_id", "address");
+
+ALTER TABLE "internal_txs" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "internal_txs" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "internal_txs" DROP CONSTRAINT "internal_txs_pkey";
+ALTER TABLE "internal_txs" ADD PRIMARY KEY ("chain_id", "block_hash", "tx_hash", "trace_index");
+
+ALTER TABLE "balances" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "balances" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "balances" DROP CONSTRAINT "balances_pkey";
+ALTER TABLE "balances" ADD PRIMARY KEY ("chain_id", "address", "block_number");
+
+ALTER TABLE "addresses" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "addresses" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "addresses" DROP CONSTRAINT "addresses_pkey";
+ALTER TABLE "addresses" ADD PRIMARY KEY ("chain_id", "address");
+
+ALTER TABLE "uncles" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "uncles" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "uncles" DROP CONSTRAINT "uncles_pkey";
+ALTER TABLE "uncles" ADD PRIMARY KEY ("chain_id", "block_hash", "uncle_index");
+
+ALTER TABLE "miner_rewards" ADD COLUMN "chain_id" numeric NOT NULL DEFAULT 0;
+ALTER TABLE "miner_rewards" ALTER COLUMN "chain_id" DROP DEFAULT;
+ALTER TABLE "miner_rewards" DROP CONSTRAINT "miner_rewards_pkey";
+ALTER TABLE "miner_rewards" ADD PRIMARY KEY ("chain_id", "miner");
@@ -0,0 +1,23 @@
+ALTER TABLE "stats_daily"
+    ALTER COLUMN "blob_fee_burned" DROP DEFAULT,
+    ALTER COLUMN "blob_fee_burned" TYPE varchar(78) USING "blob_fee_burned"::text,
+    ALTER COLUMN "blob_fee_burned" SET DEFAULT '0',
+    ALTER COLUMN "base_fee_burned" DROP DEFAULT,
+    ALTER COLUMN "base_fee_burned" TYPE varchar(78) USING "base_fee_burned"::text,
+    ALTER COLUMN "base_fee_burned" SET DEFAULT '0';
+
+ALTER TABLE "stats_hourly"
+    ALTER COLUMN "blob_fee_burned" DROP DEFAULT,
+    ALTER COLUMN "blob_fee_burned" TYPE varchar(78) USING "blob_fee_burned"::text,
+    ALTER COLUMN "blob_fee_burned" SET DEFAULT '0',
+    ALTER COLUMN "base_fee_burned" DROP DEFAULT,
+    ALTER COLUMN "base_fee_burned" TYPE varchar(78) USING "base_fee_burned"::text,
+    ALTER COLUMN "base_fee_burned" SET DEFAULT '0';
+
+ALTER TABLE "blocks"
+    ALTER COLUMN "blob_fee_burned" DROP DEFAULT,
+    ALTER COLUMN "blob_fee_burned" TYPE varchar(78) USING "blob_fee_burned"::text,
+    ALTER COLUMN "blob_fee_burned" SET DEFAULT '0',
+    ALTER COLUMN "base_fee_burned" DROP DEFAULT,
+    ALTER COLUMN "base_fee_burned" TYPE varchar(78) USING "base_fee_burned"::text,
+    ALTER COLUMN "base_fee_burned" SET DEFAULT '0';
@@ -0,0 +1,26 @@
+-- Store the fee burn as numeric so the burn totals are summed in SQL, see
+-- pkg/db/reward.go. Empty amounts, left by blocks published before the burn
+-- was computed, become 0. Rewrites the blocks table.
+ALTER TABLE "blocks"
+    ALTER COLUMN "base_fee_burned" DROP DEFAULT,
+    ALTER COLUMN "base_fee_burned" TYPE numeric(78) USING COALESCE(NULLIF("base_fee_burned", ''), '0')::numeric,
+    ALTER COLUMN "base_fee_burned" SET DEFAULT 0,
+    ALTER COLUMN "blob_fee_burned" DROP DEFAULT,
+    ALTER COLUMN "blob_fee_burned" TYPE numeric(78) USING COALESCE(NULLIF("blob_fee_burned", ''), '0')::numeric,
+    ALTER COLUMN "blob_fee_burned" SET DEFAULT 0;
+
+ALTER TABLE "stats_hourly"
+    ALTER COLUMN "base_fee_burned" DROP DEFAULT,
+    ALTER COLUMN "base_fee_burned" TYPE numeric(78) USING COALESCE(NULLIF("base_fee_burned", ''), '0')::numeric,
+    ALTER COLUMN "base_fee_burned" SET DEFAULT 0,
+    ALTER COLUMN "blob_fee_burned" DROP DEFAULT,
+    ALTER COLUMN "blob_fee_burned" TYPE numeric(78) USING COALESCE(NULLIF("blob_fee_burned", ''), '0')::numeric,
+    ALTER COLUMN "blob_fee_burned" SET DEFAULT 0;
+
+ALTER TABLE "stats_daily"
+    ALTER COLUMN "base_fee_burned" DROP DEFAULT,
+    ALTER COLUMN "base_fee_burned" TYPE numeric(78) USING COALESCE(NULLIF("base_fee_burned", ''), '0')::numeric,
+    ALTER COLUMN "base_fee_burned" SET DEFAULT 0,
+    ALTER COLUMN "blob_fee_burned" DROP DEFAULT,
+    ALTER COLUMN "blob_fee_burned" TYPE numeric(78) USING COALESCE(NULLIF("blob_fee_burned", ''), '0')::numeric,
+    ALTER COLUMN "blob_fee_burned" SET DEFAULT 0;
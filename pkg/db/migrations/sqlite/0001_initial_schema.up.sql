@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS "blocks" (
+    "hash" text NOT NULL,
+    "number" integer NOT NULL,
+    "gas_limit" integer NOT NULL,
+    "gas_used" integer NOT NULL,
+    "difficulty" integer NOT NULL,
+    "time" integer NOT NULL,
+    "parent_hash" text NOT NULL,
+    "nonce" integer NOT NULL,
+    "miner" text NOT NULL,
+    "size" integer NOT NULL,
+    "root_hash" text NOT NULL,
+    "uncle_hash" text NOT NULL,
+    "tx_hash" text NOT NULL,
+    "receipt_hash" text NOT NULL,
+    "extra_data" blob,
+    PRIMARY KEY ("hash"),
+    CONSTRAINT "uni_blocks_number" UNIQUE ("number")
+);
+CREATE INDEX IF NOT EXISTS "idx_blocks_number" ON "blocks" ("number" asc);
+
+CREATE TABLE IF NOT EXISTS "transactions" (
+    "hash" text NOT NULL,
+    "from" text NOT NULL,
+    "to" text,
+    "contract" text NOT NULL,
+    "value" integer NOT NULL,
+    "data" blob NOT NULL,
+    "gas" integer NOT NULL,
+    "gas_price" integer NOT NULL,
+    "cost" integer NOT NULL,
+    "nonce" integer NOT NULL,
+    "status" integer NOT NULL,
+    "block_hash" text NOT NULL,
+    PRIMARY KEY ("hash")
+);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "logs";
+DROP INDEX IF EXISTS "idx_transactions_block_number";
+DROP INDEX IF EXISTS "idx_transactions_block_hash";
+DROP INDEX IF EXISTS "idx_transactions_to";
+DROP INDEX IF EXISTS "idx_transactions_from";
+DROP INDEX IF EXISTS "idx_blocks_miner";
+ALTER TABLE "transactions" DROP COLUMN "block_number";
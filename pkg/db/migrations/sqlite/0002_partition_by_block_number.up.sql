@@ -0,0 +1,28 @@
+-- SQLite has no table partitioning. Like the Postgres version, this adds the
+-- block number of transactions, backfilled from their block, and the logs
+-- table and indexes auto migrated after the schema of 0001.
+ALTER TABLE "transactions" ADD COLUMN "block_number" integer NOT NULL DEFAULT 0;
+UPDATE "transactions" SET "block_number" = (SELECT "number" FROM "blocks" WHERE "blocks"."hash" = "transactions"."block_hash")
+WHERE EXISTS (SELECT 1 FROM "blocks" WHERE "blocks"."hash" = "transactions"."block_hash");
+CREATE INDEX IF NOT EXISTS "idx_blocks_miner" ON "blocks" ("miner");
+CREATE INDEX IF NOT EXISTS "idx_transactions_from" ON "transactions" ("from");
+CREATE INDEX IF NOT EXISTS "idx_transactions_to" ON "transactions" ("to");
+CREATE INDEX IF NOT EXISTS "idx_transactions_block_hash" ON "transactions" ("block_hash");
+CREATE INDEX IF NOT EXISTS "idx_transactions_block_number" ON "transactions" ("block_number");
+
+CREATE TABLE IF NOT EXISTS "logs" (
+    "block_hash" text NOT NULL,
+    "log_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "tx_hash" text NOT NULL,
+    "address" text NOT NULL,
+    "topic0" text,
+    "topic1" text,
+    "topic2" text,
+    "topic3" text,
+    "data" blob,
+    PRIMARY KEY ("block_hash", "log_index")
+);
+CREATE INDEX IF NOT EXISTS "idx_logs_block_number" ON "logs" ("block_number");
+CREATE INDEX IF NOT EXISTS "idx_logs_tx_hash" ON "logs" ("tx_hash");
+CREATE INDEX IF NOT EXISTS "idx_logs_address" ON "logs" ("address");
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "idx_blocks_time";
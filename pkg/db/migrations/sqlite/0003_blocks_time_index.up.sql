@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS "idx_blocks_time" ON "blocks" ("time");
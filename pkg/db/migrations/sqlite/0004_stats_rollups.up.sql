@@ -0,0 +1,43 @@
+-- Hourly and daily chain statistics, see pkg/stats. Inserting a block or
+-- transaction marks the hour of its block in "stats_dirty", rolling up an hour
+-- marks its day.
+
+CREATE TABLE IF NOT EXISTS "stats_hourly" (
+    "time" integer NOT NULL,
+    "blocks" integer NOT NULL,
+    "txs" integer NOT NULL,
+    "gas_used" integer NOT NULL,
+    "avg_gas_price" integer NOT NULL,
+    "median_gas_price" integer NOT NULL,
+    "active_addresses" integer NOT NULL,
+    "contract_deployments" integer NOT NULL,
+    "failed_txs" integer NOT NULL,
+    "failed_tx_ratio" real NOT NULL,
+    "avg_block_time" real NOT NULL,
+    PRIMARY KEY ("time")
+);
+
+CREATE TABLE IF NOT EXISTS "stats_daily" (
+    "time" integer NOT NULL,
+    "blocks" integer NOT NULL,
+    "txs" integer NOT NULL,
+    "gas_used" integer NOT NULL,
+    "avg_gas_price" integer NOT NULL,
+    "median_gas_price" integer NOT NULL,
+    "active_addresses" integer NOT NULL,
+    "contract_deployments" integer NOT NULL,
+    "failed_txs" integer NOT NULL,
+    "failed_tx_ratio" real NOT NULL,
+    "avg_block_time" real NOT NULL,
+    PRIMARY KEY ("time")
+);
+
+CREATE TABLE IF NOT EXISTS "stats_dirty" (
+    "period" text NOT NULL,
+    "time" integer NOT NULL,
+    PRIMARY KEY ("period", "time")
+);
+
+-- Roll up the history indexed before this migration.
+INSERT INTO "stats_dirty" ("period", "time")
+SELECT DISTINCT 'hourly', "time" - "time" % 3600 FROM "blocks";
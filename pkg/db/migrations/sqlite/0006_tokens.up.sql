@@ -0,0 +1,49 @@
+-- ERC-20 tokens, see pkg/db/token.go. Transfers and approvals are decoded
+-- from logs. Balances are kept up to date as transfers are inserted and
+-- reorged blocks are removed. Token amounts are uint256 decimal strings.
+
+CREATE TABLE IF NOT EXISTS "tokens" (
+    "address" text NOT NULL,
+    "name" text NOT NULL,
+    "symbol" text NOT NULL,
+    "decimals" integer NOT NULL,
+    PRIMARY KEY ("address")
+);
+
+CREATE TABLE IF NOT EXISTS "token_transfers" (
+    "block_hash" text NOT NULL,
+    "log_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "tx_hash" text NOT NULL,
+    "token" text NOT NULL,
+    "from" text NOT NULL,
+    "to" text NOT NULL,
+    "value" text NOT NULL,
+    PRIMARY KEY ("block_hash", "log_index")
+);
+CREATE INDEX IF NOT EXISTS "idx_token_transfers_block_number" ON "token_transfers" ("block_number");
+CREATE INDEX IF NOT EXISTS "idx_token_transfers_token" ON "token_transfers" ("token", "block_number");
+CREATE INDEX IF NOT EXISTS "idx_token_transfers_from" ON "token_transfers" ("from");
+CREATE INDEX IF NOT EXISTS "idx_token_transfers_to" ON "token_transfers" ("to");
+
+CREATE TABLE IF NOT EXISTS "token_approvals" (
+    "block_hash" text NOT NULL,
+    "log_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "tx_hash" text NOT NULL,
+    "token" text NOT NULL,
+    "owner" text NOT NULL,
+    "spender" text NOT NULL,
+    "value" text NOT NULL,
+    PRIMARY KEY ("block_hash", "log_index")
+);
+CREATE INDEX IF NOT EXISTS "idx_token_approvals_block_number" ON "token_approvals" ("block_number");
+CREATE INDEX IF NOT EXISTS "idx_token_approvals_owner" ON "token_approvals" ("owner");
+
+CREATE TABLE IF NOT EXISTS "token_balances" (
+    "token" text NOT NULL,
+    "holder" text NOT NULL,
+    "balance" text NOT NULL,
+    PRIMARY KEY ("token", "holder")
+);
+CREATE INDEX IF NOT EXISTS "idx_token_balances_holder" ON "token_balances" ("holder");
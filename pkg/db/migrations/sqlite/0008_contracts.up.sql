@@ -0,0 +1,24 @@
+-- Deployed contracts, see pkg/db/contract.go. Interfaces is a JSON array of
+-- the standards the contract implements.
+
+CREATE TABLE IF NOT EXISTS "contracts" (
+    "address" text NOT NULL,
+    "deployer" text NOT NULL,
+    "tx_hash" text NOT NULL,
+    "block_hash" text NOT NULL,
+    "block_number" integer NOT NULL,
+    "bytecode_hash" text NOT NULL,
+    "interfaces" text NOT NULL,
+    "internal" integer NOT NULL,
+    PRIMARY KEY ("address")
+);
+CREATE INDEX IF NOT EXISTS "idx_contracts_deployer" ON "contracts" ("deployer", "block_number");
+CREATE INDEX IF NOT EXISTS "idx_contracts_block_number" ON "contracts" ("block_number");
+
+-- Register the contracts deployed by the transactions indexed before this
+-- migration. Their bytecode and interfaces are unknown.
+INSERT INTO "contracts" ("address", "deployer", "tx_hash", "block_hash", "block_number", "bytecode_hash", "interfaces", "internal")
+SELECT "contract", "from", "hash", "block_hash", "block_number", '', '[]', 0
+FROM "transactions"
+WHERE "to" = '' AND "status" = 1 AND "contract" <> '0x0000000000000000000000000000000000000000'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,14 @@
+-- Uploaded contract ABIs and known function and event signatures, see
+-- pkg/db/abi.go and pkg/decode.
+
+CREATE TABLE IF NOT EXISTS "contract_abis" (
+    "address" text NOT NULL,
+    "abi" text NOT NULL,
+    PRIMARY KEY ("address")
+);
+
+CREATE TABLE IF NOT EXISTS "signatures" (
+    "hash" text NOT NULL,
+    "signature" text NOT NULL,
+    PRIMARY KEY ("hash", "signature")
+);
@@ -0,0 +1,23 @@
+-- Internal transactions flattened from call traces when tracing is enabled,
+-- see pkg/eth/trace.go.
+
+CREATE TABLE IF NOT EXISTS "internal_txs" (
+    "block_hash" text NOT NULL,
+    "tx_hash" text NOT NULL,
+    "trace_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "depth" integer NOT NULL,
+    "type" text NOT NULL,
+    "from" text NOT NULL,
+    "to" text NOT NULL,
+    "value" text NOT NULL,
+    "gas" integer NOT NULL,
+    "gas_used" integer NOT NULL,
+    "error" text NOT NULL,
+    "reverted" integer NOT NULL,
+    PRIMARY KEY ("block_hash", "tx_hash", "trace_index")
+);
+CREATE INDEX IF NOT EXISTS "idx_internal_txs_block_number" ON "internal_txs" ("block_number");
+CREATE INDEX IF NOT EXISTS "idx_internal_txs_tx_hash" ON "internal_txs" ("tx_hash");
+CREATE INDEX IF NOT EXISTS "idx_internal_txs_from" ON "internal_txs" ("from", "block_number");
+CREATE INDEX IF NOT EXISTS "idx_internal_txs_to" ON "internal_txs" ("to", "block_number");
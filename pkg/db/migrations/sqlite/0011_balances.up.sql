@@ -0,0 +1,14 @@
+-- Balance and nonce snapshots of the addresses touched by each block, see
+-- pkg/eth/balance.go.
+
+CREATE TABLE IF NOT EXISTS "balances" (
+    "address" text NOT NULL,
+    "block_number" integer NOT NULL,
+    "block_hash" text NOT NULL,
+    "time" integer NOT NULL,
+    "balance" text NOT NULL,
+    "nonce" integer NOT NULL,
+    PRIMARY KEY ("address", "block_number")
+);
+CREATE INDEX IF NOT EXISTS "idx_balances_block_number" ON "balances" ("block_number");
+CREATE INDEX IF NOT EXISTS "idx_balances_address_time" ON "balances" ("address", "time");
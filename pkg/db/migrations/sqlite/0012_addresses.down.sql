@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "addresses";
+ALTER TABLE "transactions" DROP COLUMN "gas_used";
@@ -0,0 +1,20 @@
+-- Uncle headers included by pre-merge blocks, see pkg/eth/uncle.go.
+
+CREATE TABLE IF NOT EXISTS "uncles" (
+    "block_hash" text NOT NULL,
+    "uncle_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "hash" text NOT NULL,
+    "number" integer NOT NULL,
+    "parent_hash" text NOT NULL,
+    "miner" text NOT NULL,
+    "difficulty" integer NOT NULL,
+    "gas_limit" integer NOT NULL,
+    "gas_used" integer NOT NULL,
+    "time" integer NOT NULL,
+    "reward" text NOT NULL,
+    PRIMARY KEY ("block_hash", "uncle_index")
+);
+CREATE INDEX IF NOT EXISTS "idx_uncles_block_number" ON "uncles" ("block_number");
+CREATE INDEX IF NOT EXISTS "idx_uncles_hash" ON "uncles" ("hash");
+CREATE INDEX IF NOT EXISTS "idx_uncles_miner" ON "uncles" ("miner");
@@ -0,0 +1,39 @@
+-- Block rewards and fee burn, see pkg/eth/reward.go, with per miner totals
+-- kept by pkg/db/reward.go. Blocks indexed before this migration keep zero
+-- rewards and burn, and are only counted in the totals.
+ALTER TABLE "blocks" ADD COLUMN "priority_fees" text NOT NULL DEFAULT '0';
+ALTER TABLE "blocks" ADD COLUMN "base_fee_burned" text NOT NULL DEFAULT '0';
+ALTER TABLE "blocks" ADD COLUMN "blob_fee_burned" text NOT NULL DEFAULT '0';
+ALTER TABLE "blocks" ADD COLUMN "block_reward" text NOT NULL DEFAULT '0';
+ALTER TABLE "blocks" ADD COLUMN "uncle_inclusion_reward" text NOT NULL DEFAULT '0';
+
+ALTER TABLE "stats_hourly" ADD COLUMN "base_fee_burned" text NOT NULL DEFAULT '0';
+ALTER TABLE "stats_hourly" ADD COLUMN "blob_fee_burned" text NOT NULL DEFAULT '0';
+ALTER TABLE "stats_daily" ADD COLUMN "base_fee_burned" text NOT NULL DEFAULT '0';
+ALTER TABLE "stats_daily" ADD COLUMN "blob_fee_burned" text NOT NULL DEFAULT '0';
+
+CREATE TABLE IF NOT EXISTS "miner_rewards" (
+    "miner" text NOT NULL,
+    "blocks" integer NOT NULL,
+    "uncles" integer NOT NULL,
+    "block_rewards" text NOT NULL,
+    "uncle_inclusion_rewards" text NOT NULL,
+    "uncle_rewards" text NOT NULL,
+    "priority_fees" text NOT NULL,
+    PRIMARY KEY ("miner")
+);
+
+-- Count the blocks and uncles indexed before this migration. Their rewards
+-- and fees weren't recorded and stay 0, only the uncle rewards are summed.
+-- SQLite has no arbitrary precision sums, so they are summed as doubles.
+INSERT INTO "miner_rewards" ("miner", "blocks", "uncles", "block_rewards", "uncle_inclusion_rewards", "uncle_rewards", "priority_fees")
+SELECT "miner", SUM("blocks"), SUM("uncles"), '0', '0', printf('%.0f', TOTAL("uncle_rewards")), '0'
+FROM (
+    SELECT "miner", 1 AS "blocks", 0 AS "uncles", 0 AS "uncle_rewards"
+    FROM "blocks"
+    UNION ALL
+    SELECT "miner", 0, 1, CAST("reward" AS real)
+    FROM "uncles"
+) AS "mined"
+GROUP BY "miner"
+ON CONFLICT DO NOTHING;
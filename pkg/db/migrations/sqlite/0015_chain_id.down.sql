@@ -0,0 +1,349 @@
+-- Fails if rows of several chains share a key, delete the other chains
+-- first.
+
+CREATE TABLE "miner_rewards_old" (
+    "miner" text NOT NULL,
+    "blocks" integer NOT NULL,
+    "uncles" integer NOT NULL,
+    "block_rewards" text NOT NULL,
+    "uncle_inclusion_rewards" text NOT NULL,
+    "uncle_rewards" text NOT NULL,
+    "priority_fees" text NOT NULL,
+    PRIMARY KEY ("miner")
+);
+INSERT INTO "miner_rewards_old" ("miner", "blocks", "uncles", "block_rewards", "uncle_inclusion_rewards", "uncle_rewards", "priority_fees") SELECT "miner", "blocks", "uncles", "block_rewards", "uncle_inclusion_rewards", "uncle_rewards", "priority_fees" FROM "miner_rewards";
+DROP TABLE "miner_rewards";
+ALTER TABLE "miner_rewards_old" RENAME TO "miner_rewards";
+
+CREATE TABLE "uncles_old" (
+    "block_hash" text NOT NULL,
+    "uncle_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "hash" text NOT NULL,
+    "number" integer NOT NULL,
+    "parent_hash" text NOT NULL,
+    "miner" text NOT NULL,
+    "difficulty" integer NOT NULL,
+    "gas_limit" integer NOT NULL,
+    "gas_used" integer NOT NULL,
+    "time" integer NOT NULL,
+    "reward" text NOT NULL,
+    PRIMARY KEY ("block_hash", "uncle_index")
+);
+INSERT INTO "uncles_old" ("block_hash", "uncle_index", "block_number", "hash", "number", "parent_hash", "miner", "difficulty", "gas_limit", "gas_used", "time", "reward") SELECT "block_hash", "uncle_index", "block_number", "hash", "number", "parent_hash", "miner", "difficulty", "gas_limit", "gas_used", "time", "reward" FROM "uncles";
+DROP TABLE "uncles";
+ALTER TABLE "uncles_old" RENAME TO "uncles";
+CREATE INDEX "idx_uncles_block_number" ON "uncles" ("block_number");
+CREATE INDEX "idx_uncles_hash" ON "uncles" ("hash");
+CREATE INDEX "idx_uncles_miner" ON "uncles" ("miner");
+
+CREATE TABLE "addresses_old" (
+    "address" text NOT NULL,
+    "first_seen_block" integer NOT NULL,
+    "last_seen_block" integer NOT NULL,
+    "txs_sent" integer NOT NULL,
+    "txs_received" integer NOT NULL,
+    "value_sent" text NOT NULL,
+    "value_received" text NOT NULL,
+    "gas_spent" integer NOT NULL,
+    PRIMARY KEY ("address")
+);
+INSERT INTO "addresses_old" ("address", "first_seen_block", "last_seen_block", "txs_sent", "txs_received", "value_sent", "value_received", "gas_spent") SELECT "address", "first_seen_block", "last_seen_block", "txs_sent", "txs_received", "value_sent", "value_received", "gas_spent" FROM "addresses";
+DROP TABLE "addresses";
+ALTER TABLE "addresses_old" RENAME TO "addresses";
+
+CREATE TABLE "balances_old" (
+    "address" text NOT NULL,
+    "block_number" integer NOT NULL,
+    "block_hash" text NOT NULL,
+    "time" integer NOT NULL,
+    "balance" text NOT NULL,
+    "nonce" integer NOT NULL,
+    PRIMARY KEY ("address", "block_number")
+);
+INSERT INTO "balances_old" ("address", "block_number", "block_hash", "time", "balance", "nonce") SELECT "address", "block_number", "block_hash", "time", "balance", "nonce" FROM "balances";
+DROP TABLE "balances";
+ALTER TABLE "balances_old" RENAME TO "balances";
+CREATE INDEX "idx_balances_block_number" ON "balances" ("block_number");
+CREATE INDEX "idx_balances_address_time" ON "balances" ("address", "time");
+
+CREATE TABLE "internal_txs_old" (
+    "block_hash" text NOT NULL,
+    "tx_hash" text NOT NULL,
+    "trace_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "depth" integer NOT NULL,
+    "type" text NOT NULL,
+    "from" text NOT NULL,
+    "to" text NOT NULL,
+    "value" text NOT NULL,
+    "gas" integer NOT NULL,
+    "gas_used" integer NOT NULL,
+    "error" text NOT NULL,
+    "reverted" integer NOT NULL,
+    PRIMARY KEY ("block_hash", "tx_hash", "trace_index")
+);
+INSERT INTO "internal_txs_old" ("block_hash", "tx_hash", "trace_index", "block_number", "depth", "type", "from", "to", "value", "gas", "gas_used", "error", "reverted") SELECT "block_hash", "tx_hash", "trace_index", "block_number", "depth", "type", "from", "to", "value", "gas", "gas_used", "error", "reverted" FROM "internal_txs";
+DROP TABLE "internal_txs";
+ALTER TABLE "internal_txs_old" RENAME TO "internal_txs";
+CREATE INDEX "idx_internal_txs_block_number" ON "internal_txs" ("block_number");
+CREATE INDEX "idx_internal_txs_tx_hash" ON "internal_txs" ("tx_hash");
+CREATE INDEX "idx_internal_txs_from" ON "internal_txs" ("from", "block_number");
+CREATE INDEX "idx_internal_txs_to" ON "internal_txs" ("to", "block_number");
+
+CREATE TABLE "contract_abis_old" (
+    "address" text NOT NULL,
+    "abi" text NOT NULL,
+    PRIMARY KEY ("address")
+);
+INSERT INTO "contract_abis_old" ("address", "abi") SELECT "address", "abi" FROM "contract_abis";
+DROP TABLE "contract_abis";
+ALTER TABLE "contract_abis_old" RENAME TO "contract_abis";
+
+CREATE TABLE "contracts_old" (
+    "address" text NOT NULL,
+    "deployer" text NOT NULL,
+    "tx_hash" text NOT NULL,
+    "block_hash" text NOT NULL,
+    "block_number" integer NOT NULL,
+    "bytecode_hash" text NOT NULL,
+    "interfaces" text NOT NULL,
+    "internal" integer NOT NULL,
+    PRIMARY KEY ("address")
+);
+INSERT INTO "contracts_old" ("address", "deployer", "tx_hash", "block_hash", "block_number", "bytecode_hash", "interfaces", "internal") SELECT "address", "deployer", "tx_hash", "block_hash", "block_number", "bytecode_hash", "interfaces", "internal" FROM "contracts";
+DROP TABLE "contracts";
+ALTER TABLE "contracts_old" RENAME TO "contracts";
+CREATE INDEX "idx_contracts_deployer" ON "contracts" ("deployer", "block_number");
+CREATE INDEX "idx_contracts_block_number" ON "contracts" ("block_number");
+
+CREATE TABLE "nft_owners_old" (
+    "contract" text NOT NULL,
+    "token_id" text NOT NULL,
+    "owner" text NOT NULL,
+    "balance" text NOT NULL,
+    PRIMARY KEY ("contract", "token_id", "owner")
+);
+INSERT INTO "nft_owners_old" ("contract", "token_id", "owner", "balance") SELECT "contract", "token_id", "owner", "balance" FROM "nft_owners";
+DROP TABLE "nft_owners";
+ALTER TABLE "nft_owners_old" RENAME TO "nft_owners";
+CREATE INDEX "idx_nft_owners_owner" ON "nft_owners" ("owner", "contract");
+
+CREATE TABLE "nft_transfers_old" (
+    "block_hash" text NOT NULL,
+    "log_index" integer NOT NULL,
+    "batch_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "tx_hash" text NOT NULL,
+    "contract" text NOT NULL,
+    "token_id" text NOT NULL,
+    "standard" text NOT NULL,
+    "operator" text NOT NULL,
+    "from" text NOT NULL,
+    "to" text NOT NULL,
+    "amount" text NOT NULL,
+    PRIMARY KEY ("block_hash", "log_index", "batch_index")
+);
+INSERT INTO "nft_transfers_old" ("block_hash", "log_index", "batch_index", "block_number", "tx_hash", "contract", "token_id", "standard", "operator", "from", "to", "amount") SELECT "block_hash", "log_index", "batch_index", "block_number", "tx_hash", "contract", "token_id", "standard", "operator", "from", "to", "amount" FROM "nft_transfers";
+DROP TABLE "nft_transfers";
+ALTER TABLE "nft_transfers_old" RENAME TO "nft_transfers";
+CREATE INDEX "idx_nft_transfers_block_number" ON "nft_transfers" ("block_number");
+CREATE INDEX "idx_nft_transfers_token" ON "nft_transfers" ("contract", "token_id", "block_number");
+CREATE INDEX "idx_nft_transfers_from" ON "nft_transfers" ("from");
+CREATE INDEX "idx_nft_transfers_to" ON "nft_transfers" ("to");
+
+CREATE TABLE "nfts_old" (
+    "contract" text NOT NULL,
+    "token_id" text NOT NULL,
+    "standard" text NOT NULL,
+    "uri" text NOT NULL,
+    PRIMARY KEY ("contract", "token_id")
+);
+INSERT INTO "nfts_old" ("contract", "token_id", "standard", "uri") SELECT "contract", "token_id", "standard", "uri" FROM "nfts";
+DROP TABLE "nfts";
+ALTER TABLE "nfts_old" RENAME TO "nfts";
+
+CREATE TABLE "token_balances_old" (
+    "token" text NOT NULL,
+    "holder" text NOT NULL,
+    "balance" text NOT NULL,
+    PRIMARY KEY ("token", "holder")
+);
+INSERT INTO "token_balances_old" ("token", "holder", "balance") SELECT "token", "holder", "balance" FROM "token_balances";
+DROP TABLE "token_balances";
+ALTER TABLE "token_balances_old" RENAME TO "token_balances";
+CREATE INDEX "idx_token_balances_holder" ON "token_balances" ("holder");
+
+CREATE TABLE "token_approvals_old" (
+    "block_hash" text NOT NULL,
+    "log_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "tx_hash" text NOT NULL,
+    "token" text NOT NULL,
+    "owner" text NOT NULL,
+    "spender" text NOT NULL,
+    "value" text NOT NULL,
+    PRIMARY KEY ("block_hash", "log_index")
+);
+INSERT INTO "token_approvals_old" ("block_hash", "log_index", "block_number", "tx_hash", "token", "owner", "spender", "value") SELECT "block_hash", "log_index", "block_number", "tx_hash", "token", "owner", "spender", "value" FROM "token_approvals";
+DROP TABLE "token_approvals";
+ALTER TABLE "token_approvals_old" RENAME TO "token_approvals";
+CREATE INDEX "idx_token_approvals_block_number" ON "token_approvals" ("block_number");
+CREATE INDEX "idx_token_approvals_owner" ON "token_approvals" ("owner");
+
+CREATE TABLE "token_transfers_old" (
+    "block_hash" text NOT NULL,
+    "log_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "tx_hash" text NOT NULL,
+    "token" text NOT NULL,
+    "from" text NOT NULL,
+    "to" text NOT NULL,
+    "value" text NOT NULL,
+    PRIMARY KEY ("block_hash", "log_index")
+);
+INSERT INTO "token_transfers_old" ("block_hash", "log_index", "block_number", "tx_hash", "token", "from", "to", "value") SELECT "block_hash", "log_index", "block_number", "tx_hash", "token", "from", "to", "value" FROM "token_transfers";
+DROP TABLE "token_transfers";
+ALTER TABLE "token_transfers_old" RENAME TO "token_transfers";
+CREATE INDEX "idx_token_transfers_block_number" ON "token_transfers" ("block_number");
+CREATE INDEX "idx_token_transfers_token" ON "token_transfers" ("token", "block_number");
+CREATE INDEX "idx_token_transfers_from" ON "token_transfers" ("from");
+CREATE INDEX "idx_token_transfers_to" ON "token_transfers" ("to");
+
+CREATE TABLE "tokens_old" (
+    "address" text NOT NULL,
+    "name" text NOT NULL,
+    "symbol" text NOT NULL,
+    "decimals" integer NOT NULL,
+    PRIMARY KEY ("address")
+);
+INSERT INTO "tokens_old" ("address", "name", "symbol", "decimals") SELECT "address", "name", "symbol", "decimals" FROM "tokens";
+DROP TABLE "tokens";
+ALTER TABLE "tokens_old" RENAME TO "tokens";
+
+CREATE TABLE "stats_dirty_old" (
+    "period" text NOT NULL,
+    "time" integer NOT NULL,
+    PRIMARY KEY ("period", "time")
+);
+INSERT INTO "stats_dirty_old" ("period", "time") SELECT "period", "time" FROM "stats_dirty";
+DROP TABLE "stats_dirty";
+ALTER TABLE "stats_dirty_old" RENAME TO "stats_dirty";
+
+CREATE TABLE "stats_daily_old" (
+    "time" integer NOT NULL,
+    "blocks" integer NOT NULL,
+    "txs" integer NOT NULL,
+    "gas_used" integer NOT NULL,
+    "avg_gas_price" integer NOT NULL,
+    "median_gas_price" integer NOT NULL,
+    "active_addresses" integer NOT NULL,
+    "contract_deployments" integer NOT NULL,
+    "failed_txs" integer NOT NULL,
+    "failed_tx_ratio" real NOT NULL,
+    "avg_block_time" real NOT NULL,
+    "base_fee_burned" text NOT NULL DEFAULT '0',
+    "blob_fee_burned" text NOT NULL DEFAULT '0',
+    PRIMARY KEY ("time")
+);
+INSERT INTO "stats_daily_old" ("time", "blocks", "txs", "gas_used", "avg_gas_price", "median_gas_price", "active_addresses", "contract_deployments", "failed_txs", "failed_tx_ratio", "avg_block_time", "base_fee_burned", "blob_fee_burned") SELECT "time", "blocks", "txs", "gas_used", "avg_gas_price", "median_gas_price", "active_addresses", "contract_deployments", "failed_txs", "failed_tx_ratio", "avg_block_time", "base_fee_burned", "blob_fee_burned" FROM "stats_daily";
+DROP TABLE "stats_daily";
+ALTER TABLE "stats_daily_old" RENAME TO "stats_daily";
+
+CREATE TABLE "stats_hourly_old" (
+    "time" integer NOT NULL,
+    "blocks" integer NOT NULL,
+    "txs" integer NOT NULL,
+    "gas_used" integer NOT NULL,
+    "avg_gas_price" integer NOT NULL,
+    "median_gas_price" integer NOT NULL,
+    "active_addresses" integer NOT NULL,
+    "contract_deployments" integer NOT NULL,
+    "failed_txs" integer NOT NULL,
+    "failed_tx_ratio" real NOT NULL,
+    "avg_block_time" real NOT NULL,
+    "base_fee_burned" text NOT NULL DEFAULT '0',
+    "blob_fee_burned" text NOT NULL DEFAULT '0',
+    PRIMARY KEY ("time")
+);
+INSERT INTO "stats_hourly_old" ("time", "blocks", "txs", "gas_used", "avg_gas_price", "median_gas_price", "active_addresses", "contract_deployments", "failed_txs", "failed_tx_ratio", "avg_block_time", "base_fee_burned", "blob_fee_burned") SELECT "time", "blocks", "txs", "gas_used", "avg_gas_price", "median_gas_price", "active_addresses", "contract_deployments", "failed_txs", "failed_tx_ratio", "avg_block_time", "base_fee_burned", "blob_fee_burned" FROM "stats_hourly";
+DROP TABLE "stats_hourly";
+ALTER TABLE "stats_hourly_old" RENAME TO "stats_hourly";
+
+CREATE TABLE "logs_old" (
+    "block_hash" text NOT NULL,
+    "log_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "tx_hash" text NOT NULL,
+    "address" text NOT NULL,
+    "topic0" text,
+    "topic1" text,
+    "topic2" text,
+    "topic3" text,
+    "data" blob,
+    PRIMARY KEY ("block_hash", "log_index")
+);
+INSERT INTO "logs_old" ("block_hash", "log_index", "block_number", "tx_hash", "address", "topic0", "topic1", "topic2", "topic3", "data") SELECT "block_hash", "log_index", "block_number", "tx_hash", "address", "topic0", "topic1", "topic2", "topic3", "data" FROM "logs";
+DROP TABLE "logs";
+ALTER TABLE "logs_old" RENAME TO "logs";
+CREATE INDEX "idx_logs_block_number" ON "logs" ("block_number");
+CREATE INDEX "idx_logs_tx_hash" ON "logs" ("tx_hash");
+CREATE INDEX "idx_logs_address" ON "logs" ("address");
+
+CREATE TABLE "transactions_old" (
+    "hash" text NOT NULL,
+    "from" text NOT NULL,
+    "to" text,
+    "contract" text NOT NULL,
+    "value" integer NOT NULL,
+    "data" blob NOT NULL,
+    "gas" integer NOT NULL,
+    "gas_price" integer NOT NULL,
+    "cost" integer NOT NULL,
+    "nonce" integer NOT NULL,
+    "status" integer NOT NULL,
+    "block_hash" text NOT NULL,
+    "block_number" integer NOT NULL,
+    "priority_fee" integer NOT NULL DEFAULT 0,
+    "gas_used" integer NOT NULL DEFAULT 0,
+    PRIMARY KEY ("hash")
+);
+INSERT INTO "transactions_old" ("hash", "from", "to", "contract", "value", "data", "gas", "gas_price", "cost", "nonce", "status", "block_hash", "block_number", "priority_fee", "gas_used") SELECT "hash", "from", "to", "contract", "value", "data", "gas", "gas_price", "cost", "nonce", "status", "block_hash", "block_number", "priority_fee", "gas_used" FROM "transactions";
+DROP TABLE "transactions";
+ALTER TABLE "transactions_old" RENAME TO "transactions";
+CREATE INDEX "idx_transactions_from" ON "transactions" ("from");
+CREATE INDEX "idx_transactions_to" ON "transactions" ("to");
+CREATE INDEX "idx_transactions_block_hash" ON "transactions" ("block_hash");
+CREATE INDEX "idx_transactions_block_number" ON "transactions" ("block_number");
+
+CREATE TABLE "blocks_old" (
+    "hash" text NOT NULL,
+    "number" integer NOT NULL,
+    "gas_limit" integer NOT NULL,
+    "gas_used" integer NOT NULL,
+    "difficulty" integer NOT NULL,
+    "time" integer NOT NULL,
+    "parent_hash" text NOT NULL,
+    "nonce" integer NOT NULL,
+    "miner" text NOT NULL,
+    "size" integer NOT NULL,
+    "root_hash" text NOT NULL,
+    "uncle_hash" text NOT NULL,
+    "tx_hash" text NOT NULL,
+    "receipt_hash" text NOT NULL,
+    "extra_data" blob,
+    "base_fee" integer NOT NULL DEFAULT 0,
+    "priority_fees" text NOT NULL DEFAULT '0',
+    "base_fee_burned" text NOT NULL DEFAULT '0',
+    "blob_fee_burned" text NOT NULL DEFAULT '0',
+    "block_reward" text NOT NULL DEFAULT '0',
+    "uncle_inclusion_reward" text NOT NULL DEFAULT '0',
+    PRIMARY KEY ("hash"),
+    CONSTRAINT "uni_blocks_number" UNIQUE ("number")
+);
+INSERT INTO "blocks_old" ("hash", "number", "gas_limit", "gas_used", "difficulty", "time", "parent_hash", "nonce", "miner", "size", "root_hash", "uncle_hash", "tx_hash", "receipt_hash", "extra_data", "base_fee", "priority_fees", "base_fee_burned", "blob_fee_burned", "block_reward", "uncle_inclusion_reward") SELECT "hash", "number", "gas_limit", "gas_used", "difficulty", "time", "parent_hash", "nonce", "miner", "size", "root_hash", "uncle_hash", "tx_hash", "receipt_hash", "extra_data", "base_fee", "priority_fees", "base_fee_burned", "blob_fee_burned", "block_reward", "uncle_inclusion_reward" FROM "blocks";
+DROP TABLE "blocks";
+ALTER TABLE "blocks_old" RENAME TO "blocks";
+CREATE INDEX "idx_blocks_number" ON "blocks" ("number" asc);
+CREATE INDEX "idx_blocks_miner" ON "blocks" ("miner");
+CREATE INDEX "idx_blocks_time" ON "blocks" ("time");
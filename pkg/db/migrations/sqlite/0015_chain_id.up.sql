@@ -0,0 +1,373 @@
+-- Index several chains in one database. Every table but signatures gets a
+-- chain_id column leading its primary key, see pkg/db.GormDB.ForChain. Rows
+-- indexed before this migration belong to chain 0, run
+-- `migrate assign-chain <chainId>` to move them to their chain.
+--
+-- SQLite can't change a primary key in place, so each table is rebuilt.
+
+CREATE TABLE "blocks_new" (
+    "chain_id" integer NOT NULL,
+    "hash" text NOT NULL,
+    "number" integer NOT NULL,
+    "gas_limit" integer NOT NULL,
+    "gas_used" integer NOT NULL,
+    "difficulty" integer NOT NULL,
+    "time" integer NOT NULL,
+    "parent_hash" text NOT NULL,
+    "nonce" integer NOT NULL,
+    "miner" text NOT NULL,
+    "size" integer NOT NULL,
+    "root_hash" text NOT NULL,
+    "uncle_hash" text NOT NULL,
+    "tx_hash" text NOT NULL,
+    "receipt_hash" text NOT NULL,
+    "extra_data" blob,
+    "base_fee" integer NOT NULL DEFAULT 0,
+    "priority_fees" text NOT NULL DEFAULT '0',
+    "base_fee_burned" text NOT NULL DEFAULT '0',
+    "blob_fee_burned" text NOT NULL DEFAULT '0',
+    "block_reward" text NOT NULL DEFAULT '0',
+    "uncle_inclusion_reward" text NOT NULL DEFAULT '0',
+    PRIMARY KEY ("chain_id", "hash"),
+    CONSTRAINT "uni_blocks_number" UNIQUE ("chain_id", "number")
+);
+INSERT INTO "blocks_new" ("chain_id", "hash", "number", "gas_limit", "gas_used", "difficulty", "time", "parent_hash", "nonce", "miner", "size", "root_hash", "uncle_hash", "tx_hash", "receipt_hash", "extra_data", "base_fee", "priority_fees", "base_fee_burned", "blob_fee_burned", "block_reward", "uncle_inclusion_reward") SELECT 0, "hash", "number", "gas_limit", "gas_used", "difficulty", "time", "parent_hash", "nonce", "miner", "size", "root_hash", "uncle_hash", "tx_hash", "receipt_hash", "extra_data", "base_fee", "priority_fees", "base_fee_burned", "blob_fee_burned", "block_reward", "uncle_inclusion_reward" FROM "blocks";
+DROP TABLE "blocks";
+ALTER TABLE "blocks_new" RENAME TO "blocks";
+CREATE INDEX "idx_blocks_number" ON "blocks" ("number" asc);
+CREATE INDEX "idx_blocks_miner" ON "blocks" ("miner");
+CREATE INDEX "idx_blocks_time" ON "blocks" ("time");
+
+CREATE TABLE "transactions_new" (
+    "chain_id" integer NOT NULL,
+    "hash" text NOT NULL,
+    "from" text NOT NULL,
+    "to" text,
+    "contract" text NOT NULL,
+    "value" integer NOT NULL,
+    "data" blob NOT NULL,
+    "gas" integer NOT NULL,
+    "gas_price" integer NOT NULL,
+    "cost" integer NOT NULL,
+    "nonce" integer NOT NULL,
+    "status" integer NOT NULL,
+    "block_hash" text NOT NULL,
+    "block_number" integer NOT NULL,
+    "priority_fee" integer NOT NULL DEFAULT 0,
+    "gas_used" integer NOT NULL DEFAULT 0,
+    PRIMARY KEY ("chain_id", "hash")
+);
+INSERT INTO "transactions_new" ("chain_id", "hash", "from", "to", "contract", "value", "data", "gas", "gas_price", "cost", "nonce", "status", "block_hash", "block_number", "priority_fee", "gas_used") SELECT 0, "hash", "from", "to", "contract", "value", "data", "gas", "gas_price", "cost", "nonce", "status", "block_hash", "block_number", "priority_fee", "gas_used" FROM "transactions";
+DROP TABLE "transactions";
+ALTER TABLE "transactions_new" RENAME TO "transactions";
+CREATE INDEX "idx_transactions_from" ON "transactions" ("from");
+CREATE INDEX "idx_transactions_to" ON "transactions" ("to");
+CREATE INDEX "idx_transactions_block_hash" ON "transactions" ("block_hash");
+CREATE INDEX "idx_transactions_block_number" ON "transactions" ("block_number");
+
+CREATE TABLE "logs_new" (
+    "chain_id" integer NOT NULL,
+    "block_hash" text NOT NULL,
+    "log_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "tx_hash" text NOT NULL,
+    "address" text NOT NULL,
+    "topic0" text,
+    "topic1" text,
+    "topic2" text,
+    "topic3" text,
+    "data" blob,
+    PRIMARY KEY ("chain_id", "block_hash", "log_index")
+);
+INSERT INTO "logs_new" ("chain_id", "block_hash", "log_index", "block_number", "tx_hash", "address", "topic0", "topic1", "topic2", "topic3", "data") SELECT 0, "block_hash", "log_index", "block_number", "tx_hash", "address", "topic0", "topic1", "topic2", "topic3", "data" FROM "logs";
+DROP TABLE "logs";
+ALTER TABLE "logs_new" RENAME TO "logs";
+CREATE INDEX "idx_logs_block_number" ON "logs" ("block_number");
+CREATE INDEX "idx_logs_tx_hash" ON "logs" ("tx_hash");
+CREATE INDEX "idx_logs_address" ON "logs" ("address");
+
+CREATE TABLE "stats_hourly_new" (
+    "chain_id" integer NOT NULL,
+    "time" integer NOT NULL,
+    "blocks" integer NOT NULL,
+    "txs" integer NOT NULL,
+    "gas_used" integer NOT NULL,
+    "avg_gas_price" integer NOT NULL,
+    "median_gas_price" integer NOT NULL,
+    "active_addresses" integer NOT NULL,
+    "contract_deployments" integer NOT NULL,
+    "failed_txs" integer NOT NULL,
+    "failed_tx_ratio" real NOT NULL,
+    "avg_block_time" real NOT NULL,
+    "base_fee_burned" text NOT NULL DEFAULT '0',
+    "blob_fee_burned" text NOT NULL DEFAULT '0',
+    PRIMARY KEY ("chain_id", "time")
+);
+INSERT INTO "stats_hourly_new" ("chain_id", "time", "blocks", "txs", "gas_used", "avg_gas_price", "median_gas_price", "active_addresses", "contract_deployments", "failed_txs", "failed_tx_ratio", "avg_block_time", "base_fee_burned", "blob_fee_burned") SELECT 0, "time", "blocks", "txs", "gas_used", "avg_gas_price", "median_gas_price", "active_addresses", "contract_deployments", "failed_txs", "failed_tx_ratio", "avg_block_time", "base_fee_burned", "blob_fee_burned" FROM "stats_hourly";
+DROP TABLE "stats_hourly";
+ALTER TABLE "stats_hourly_new" RENAME TO "stats_hourly";
+
+CREATE TABLE "stats_daily_new" (
+    "chain_id" integer NOT NULL,
+    "time" integer NOT NULL,
+    "blocks" integer NOT NULL,
+    "txs" integer NOT NULL,
+    "gas_used" integer NOT NULL,
+    "avg_gas_price" integer NOT NULL,
+    "median_gas_price" integer NOT NULL,
+    "active_addresses" integer NOT NULL,
+    "contract_deployments" integer NOT NULL,
+    "failed_txs" integer NOT NULL,
+    "failed_tx_ratio" real NOT NULL,
+    "avg_block_time" real NOT NULL,
+    "base_fee_burned" text NOT NULL DEFAULT '0',
+    "blob_fee_burned" text NOT NULL DEFAULT '0',
+    PRIMARY KEY ("chain_id", "time")
+);
+INSERT INTO "stats_daily_new" ("chain_id", "time", "blocks", "txs", "gas_used", "avg_gas_price", "median_gas_price", "active_addresses", "contract_deployments", "failed_txs", "failed_tx_ratio", "avg_block_time", "base_fee_burned", "blob_fee_burned") SELECT 0, "time", "blocks", "txs", "gas_used", "avg_gas_price", "median_gas_price", "active_addresses", "contract_deployments", "failed_txs", "failed_tx_ratio", "avg_block_time", "base_fee_burned", "blob_fee_burned" FROM "stats_daily";
+DROP TABLE "stats_daily";
+ALTER TABLE "stats_daily_new" RENAME TO "stats_daily";
+
+CREATE TABLE "stats_dirty_new" (
+    "chain_id" integer NOT NULL,
+    "period" text NOT NULL,
+    "time" integer NOT NULL,
+    PRIMARY KEY ("chain_id", "period", "time")
+);
+INSERT INTO "stats_dirty_new" ("chain_id", "period", "time") SELECT 0, "period", "time" FROM "stats_dirty";
+DROP TABLE "stats_dirty";
+ALTER TABLE "stats_dirty_new" RENAME TO "stats_dirty";
+
+CREATE TABLE "tokens_new" (
+    "chain_id" integer NOT NULL,
+    "address" text NOT NULL,
+    "name" text NOT NULL,
+    "symbol" text NOT NULL,
+    "decimals" integer NOT NULL,
+    PRIMARY KEY ("chain_id", "address")
+);
+INSERT INTO "tokens_new" ("chain_id", "address", "name", "symbol", "decimals") SELECT 0, "address", "name", "symbol", "decimals" FROM "tokens";
+DROP TABLE "tokens";
+ALTER TABLE "tokens_new" RENAME TO "tokens";
+
+CREATE TABLE "token_transfers_new" (
+    "chain_id" integer NOT NULL,
+    "block_hash" text NOT NULL,
+    "log_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "tx_hash" text NOT NULL,
+    "token" text NOT NULL,
+    "from" text NOT NULL,
+    "to" text NOT NULL,
+    "value" text NOT NULL,
+    PRIMARY KEY ("chain_id", "block_hash", "log_index")
+);
+INSERT INTO "token_transfers_new" ("chain_id", "block_hash", "log_index", "block_number", "tx_hash", "token", "from", "to", "value") SELECT 0, "block_hash", "log_index", "block_number", "tx_hash", "token", "from", "to", "value" FROM "token_transfers";
+DROP TABLE "token_transfers";
+ALTER TABLE "token_transfers_new" RENAME TO "token_transfers";
+CREATE INDEX "idx_token_transfers_block_number" ON "token_transfers" ("block_number");
+CREATE INDEX "idx_token_transfers_token" ON "token_transfers" ("token", "block_number");
+CREATE INDEX "idx_token_transfers_from" ON "token_transfers" ("from");
+CREATE INDEX "idx_token_transfers_to" ON "token_transfers" ("to");
+
+CREATE TABLE "token_approvals_new" (
+    "chain_id" integer NOT NULL,
+    "block_hash" text NOT NULL,
+    "log_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "tx_hash" text NOT NULL,
+    "token" text NOT NULL,
+    "owner" text NOT NULL,
+    "spender" text NOT NULL,
+    "value" text NOT NULL,
+    PRIMARY KEY ("chain_id", "block_hash", "log_index")
+);
+INSERT INTO "token_approvals_new" ("chain_id", "block_hash", "log_index", "block_number", "tx_hash", "token", "owner", "spender", "value") SELECT 0, "block_hash", "log_index", "block_number", "tx_hash", "token", "owner", "spender", "value" FROM "token_approvals";
+DROP TABLE "token_approvals";
+ALTER TABLE "token_approvals_new" RENAME TO "token_approvals";
+CREATE INDEX "idx_token_approvals_block_number" ON "token_approvals" ("block_number");
+CREATE INDEX "idx_token_approvals_owner" ON "token_approvals" ("owner");
+
+CREATE TABLE "token_balances_new" (
+    "chain_id" integer NOT NULL,
+    "token" text NOT NULL,
+    "holder" text NOT NULL,
+    "balance" text NOT NULL,
+    PRIMARY KEY ("chain_id", "token", "holder")
+);
+INSERT INTO "token_balances_new" ("chain_id", "token", "holder", "balance") SELECT 0, "token", "holder", "balance" FROM "token_balances";
+DROP TABLE "token_balances";
+ALTER TABLE "token_balances_new" RENAME TO "token_balances";
+CREATE INDEX "idx_token_balances_holder" ON "token_balances" ("holder");
+
+CREATE TABLE "nfts_new" (
+    "chain_id" integer NOT NULL,
+    "contract" text NOT NULL,
+    "token_id" text NOT NULL,
+    "standard" text NOT NULL,
+    "uri" text NOT NULL,
+    PRIMARY KEY ("chain_id", "contract", "token_id")
+);
+INSERT INTO "nfts_new" ("chain_id", "contract", "token_id", "standard", "uri") SELECT 0, "contract", "token_id", "standard", "uri" FROM "nfts";
+DROP TABLE "nfts";
+ALTER TABLE "nfts_new" RENAME TO "nfts";
+
+CREATE TABLE "nft_transfers_new" (
+    "chain_id" integer NOT NULL,
+    "block_hash" text NOT NULL,
+    "log_index" integer NOT NULL,
+    "batch_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "tx_hash" text NOT NULL,
+    "contract" text NOT NULL,
+    "token_id" text NOT NULL,
+    "standard" text NOT NULL,
+    "operator" text NOT NULL,
+    "from" text NOT NULL,
+    "to" text NOT NULL,
+    "amount" text NOT NULL,
+    PRIMARY KEY ("chain_id", "block_hash", "log_index", "batch_index")
+);
+INSERT INTO "nft_transfers_new" ("chain_id", "block_hash", "log_index", "batch_index", "block_number", "tx_hash", "contract", "token_id", "standard", "operator", "from", "to", "amount") SELECT 0, "block_hash", "log_index", "batch_index", "block_number", "tx_hash", "contract", "token_id", "standard", "operator", "from", "to", "amount" FROM "nft_transfers";
+DROP TABLE "nft_transfers";
+ALTER TABLE "nft_transfers_new" RENAME TO "nft_transfers";
+CREATE INDEX "idx_nft_transfers_block_number" ON "nft_transfers" ("block_number");
+CREATE INDEX "idx_nft_transfers_token" ON "nft_transfers" ("contract", "token_id", "block_number");
+CREATE INDEX "idx_nft_transfers_from" ON "nft_transfers" ("from");
+CREATE INDEX "idx_nft_transfers_to" ON "nft_transfers" ("to");
+
+CREATE TABLE "nft_owners_new" (
+    "chain_id" integer NOT NULL,
+    "contract" text NOT NULL,
+    "token_id" text NOT NULL,
+    "owner" text NOT NULL,
+    "balance" text NOT NULL,
+    PRIMARY KEY ("chain_id", "contract", "token_id", "owner")
+);
+INSERT INTO "nft_owners_new" ("chain_id", "contract", "token_id", "owner", "balance") SELECT 0, "contract", "token_id", "owner", "balance" FROM "nft_owners";
+DROP TABLE "nft_owners";
+ALTER TABLE "nft_owners_new" RENAME TO "nft_owners";
+CREATE INDEX "idx_nft_owners_owner" ON "nft_owners" ("owner", "contract");
+
+CREATE TABLE "contracts_new" (
+    "chain_id" integer NOT NULL,
+    "address" text NOT NULL,
+    "deployer" text NOT NULL,
+    "tx_hash" text NOT NULL,
+    "block_hash" text NOT NULL,
+    "block_number" integer NOT NULL,
+    "bytecode_hash" text NOT NULL,
+    "interfaces" text NOT NULL,
+    "internal" integer NOT NULL,
+    PRIMARY KEY ("chain_id", "address")
+);
+INSERT INTO "contracts_new" ("chain_id", "address", "deployer", "tx_hash", "block_hash", "block_number", "bytecode_hash", "interfaces", "internal") SELECT 0, "address", "deployer", "tx_hash", "block_hash", "block_number", "bytecode_hash", "interfaces", "internal" FROM "contracts";
+DROP TABLE "contracts";
+ALTER TABLE "contracts_new" RENAME TO "contracts";
+CREATE INDEX "idx_contracts_deployer" ON "contracts" ("deployer", "block_number");
+CREATE INDEX "idx_contracts_block_number" ON "contracts" ("block_number");
+
+CREATE TABLE "contract_abis_new" (
+    "chain_id" integer NOT NULL,
+    "address" text NOT NULL,
+    "abi" text NOT NULL,
+    PRIMARY KEY ("chain_id", "address")
+);
+INSERT INTO "contract_abis_new" ("chain_id", "address", "abi") SELECT 0, "address", "abi" FROM "contract_abis";
+DROP TABLE "contract_abis";
+ALTER TABLE "contract_abis_new" RENAME TO "contract_abis";
+
+CREATE TABLE "internal_txs_new" (
+    "chain_id" integer NOT NULL,
+    "block_hash" text NOT NULL,
+    "tx_hash" text NOT NULL,
+    "trace_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "depth" integer NOT NULL,
+    "type" text NOT NULL,
+    "from" text NOT NULL,
+    "to" text NOT NULL,
+    "value" text NOT NULL,
+    "gas" integer NOT NULL,
+    "gas_used" integer NOT NULL,
+    "error" text NOT NULL,
+    "reverted" integer NOT NULL,
+    PRIMARY KEY ("chain_id", "block_hash", "tx_hash", "trace_index")
+);
+INSERT INTO "internal_txs_new" ("chain_id", "block_hash", "tx_hash", "trace_index", "block_number", "depth", "type", "from", "to", "value", "gas", "gas_used", "error", "reverted") SELECT 0, "block_hash", "tx_hash", "trace_index", "block_number", "depth", "type", "from", "to", "value", "gas", "gas_used", "error", "reverted" FROM "internal_txs";
+DROP TABLE "internal_txs";
+ALTER TABLE "internal_txs_new" RENAME TO "internal_txs";
+CREATE INDEX "idx_internal_txs_block_number" ON "internal_txs" ("block_number");
+CREATE INDEX "idx_internal_txs_tx_hash" ON "internal_txs" ("tx_hash");
+CREATE INDEX "idx_internal_txs_from" ON "internal_txs" ("from", "block_number");
+CREATE INDEX "idx_internal_txs_to" ON "internal_txs" ("to", "block_number");
+
+CREATE TABLE "balances_new" (
+    "chain_id" integer NOT NULL,
+    "address" text NOT NULL,
+    "block_number" integer NOT NULL,
+    "block_hash" text NOT NULL,
+    "time" integer NOT NULL,
+    "balance" text NOT NULL,
+    "nonce" integer NOT NULL,
+    PRIMARY KEY ("chain_id", "address", "block_number")
+);
+INSERT INTO "balances_new" ("chain_id", "address", "block_number", "block_hash", "time", "balance", "nonce") SELECT 0, "address", "block_number", "block_hash", "time", "balance", "nonce" FROM "balances";
+DROP TABLE "balances";
+ALTER TABLE "balances_new" RENAME TO "balances";
+CREATE INDEX "idx_balances_block_number" ON "balances" ("block_number");
+CREATE INDEX "idx_balances_address_time" ON "balances" ("address", "time");
+
+CREATE TABLE "addresses_new" (
+    "chain_id" integer NOT NULL,
+    "address" text NOT NULL,
+    "first_seen_block" integer NOT NULL,
+    "last_seen_block" integer NOT NULL,
+    "txs_sent" integer NOT NULL,
+    "txs_received" integer NOT NULL,
+    "value_sent" text NOT NULL,
+    "value_received" text NOT NULL,
+    "gas_spent" integer NOT NULL,
+    PRIMARY KEY ("chain_id", "address")
+);
+INSERT INTO "addresses_new" ("chain_id", "address", "first_seen_block", "last_seen_block", "txs_sent", "txs_received", "value_sent", "value_received", "gas_spent") SELECT 0, "address", "first_seen_block", "last_seen_block", "txs_sent", "txs_received", "value_sent", "value_received", "gas_spent" FROM "addresses";
+DROP TABLE "addresses";
+ALTER TABLE "addresses_new" RENAME TO "addresses";
+
+CREATE TABLE "uncles_new" (
+    "chain_id" integer NOT NULL,
+    "block_hash" text NOT NULL,
+    "uncle_index" integer NOT NULL,
+    "block_number" integer NOT NULL,
+    "hash" text NOT NULL,
+    "number" integer NOT NULL,
+    "parent_hash" text NOT NULL,
+    "miner" text NOT NULL,
+    "difficulty" integer NOT NULL,
+    "gas_limit" integer NOT NULL,
+    "gas_used" integer NOT NULL,
+    "time" integer NOT NULL,
+    "reward" text NOT NULL,
+    PRIMARY KEY ("chain_id", "block_hash", "uncle_index")
+);
+INSERT INTO "uncles_new" ("chain_id", "block_hash", "uncle_index", "block_number", "hash", "number", "parent_hash", "miner", "difficulty", "gas_limit", "gas_used", "time", "reward") SELECT 0, "block_hash", "uncle_index", "block_number", "hash", "number", "parent_hash", "miner", "difficulty", "gas_limit", "gas_used", "time", "reward" FROM "uncles";
+DROP TABLE "uncles";
+ALTER TABLE "uncles_new" RENAME TO "uncles";
+CREATE INDEX "idx_uncles_block_number" ON "uncles" ("block_number");
+CREATE INDEX "idx_uncles_hash" ON "uncles" ("hash");
+CREATE INDEX "idx_uncles_miner" ON "uncles" ("miner");
+
+CREATE TABLE "miner_rewards_new" (
+    "chain_id" integer NOT NULL,
+    "miner" text NOT NULL,
+    "blocks" integer NOT NULL,
+    "uncles" integer NOT NULL,
+    "block_rewards" text NOT NULL,
+    "uncle_inclusion_rewards" text NOT NULL,
+    "uncle_rewards" text NOT NULL,
+    "priority_fees" text NOT NULL,
+    PRIMARY KEY ("chain_id", "miner")
+);
+INSERT INTO "miner_rewards_new" ("chain_id", "miner", "blocks", "uncles", "block_rewards", "uncle_inclusion_rewards", "uncle_rewards", "priority_fees") SELECT 0, "miner", "blocks", "uncles", "block_rewards", "uncle_inclusion_rewards", "uncle_rewards", "priority_fees" FROM "miner_rewards";
+DROP TABLE "miner_rewards";
+ALTER TABLE "miner_rewards_new" RENAME TO "miner_rewards";
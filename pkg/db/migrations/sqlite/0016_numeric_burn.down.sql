@@ -0,0 +1,2 @@
+-- Zero amounts can't be told apart from the empty amounts they replaced,
+-- so they stay 0.
@@ -0,0 +1,5 @@
+-- SQLite keeps the fee burn as decimal text, its SUM would round amounts
+-- above 2^63 to a float, so the burn totals are summed in Go, see
+-- pkg/db/reward.go. Empty amounts become 0 as on Postgres.
+UPDATE "blocks" SET "base_fee_burned" = '0' WHERE "base_fee_burned" = '';
+UPDATE "blocks" SET "blob_fee_burned" = '0' WHERE "blob_fee_burned" = '';
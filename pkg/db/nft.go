@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InsertNFT stores the metadata of an NFT, replacing what was stored for it
+// before.
+func (g *GormDB) InsertNFT(ctx context.Context, nft data.NFT) error {
+	db, cancel := g.write(ctx)
+	defer cancel()
+	nft.ChainID = g.chainID
+	return db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&nft).Error
+}
+
+// InsertNFTTransfer stores a transfer and moves its amount from the sender to
+// the recipient in "nft_owners". A transfer that is already stored is
+// ignored.
+func (g *GormDB) InsertNFTTransfer(ctx context.Context, transfer data.NFTTransfer) error {
+	amount, ok := new(big.Int).SetString(transfer.Amount, 10)
+	if !ok {
+		return fmt.Errorf("invalid nft transfer amount %q", transfer.Amount)
+	}
+	db, cancel := g.write(ctx)
+	defer cancel()
+	transfer.ChainID = g.chainID
+	return db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&transfer)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return applyNFTTransfer(tx, &transfer, amount)
+	})
+}
+
+// applyNFTTransfer adds amount to the recipient's copies and subtracts it
+// from the sender's. Pass a negated amount to revert a transfer.
+func applyNFTTransfer(tx *gorm.DB, transfer *data.NFTTransfer, amount *big.Int) error {
+	if err := addNFTBalance(tx, transfer, transfer.From, new(big.Int).Neg(amount)); err != nil {
+		return err
+	}
+	return addNFTBalance(tx, transfer, transfer.To, amount)
+}
+
+func addNFTBalance(tx *gorm.DB, transfer *data.NFTTransfer, owner string, delta *big.Int) error {
+	if owner == zeroAddress {
+		return nil
+	}
+	key := map[string]any{"chain_id": transfer.ChainID, "contract": transfer.Contract, "token_id": transfer.TokenID, "owner": owner}
+	return addBalance(tx, "nft_owners", key, delta)
+}
+
+// removeNFTTransfers reverts the ownership changes of the transfers in a
+// reorged block and deletes them.
+func removeNFTTransfers(tx *gorm.DB, chainID, number uint64, hash string) error {
+	var transfers []*data.NFTTransfer
+	err := tx.Where("chain_id = ? AND block_number = ? AND block_hash = ?", chainID, number, hash).Find(&transfers).Error
+	if err != nil {
+		return err
+	}
+	for _, transfer := range transfers {
+		amount, ok := new(big.Int).SetString(transfer.Amount, 10)
+		if !ok {
+			return fmt.Errorf("invalid nft transfer amount %q", transfer.Amount)
+		}
+		if err := applyNFTTransfer(tx, transfer, amount.Neg(amount)); err != nil {
+			return err
+		}
+	}
+	return tx.Where("chain_id = ? AND block_number = ? AND block_hash = ?", chainID, number, hash).Delete(&data.NFTTransfer{}).Error
+}
+
+// GetNFT returns the metadata of an NFT with up to MaxPageSize owners holding
+// a positive balance, largest first.
+func (g *GormDB) GetNFT(ctx context.Context, contract, tokenID string) (*data.NFT, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var nft data.NFT
+	if err := db.Take(&nft, "chain_id = ? AND contract = ? AND token_id = ?", g.chainID, contract, tokenID).Error; err != nil {
+		return nil, err
+	}
+	nft.Owners = []*data.NFTOwner{}
+	err := db.Where("chain_id = ? AND contract = ? AND token_id = ? AND balance NOT LIKE '-%'", g.chainID, contract, tokenID).
+		Order("LENGTH(balance) desc, balance desc, owner asc").
+		Limit(MaxPageSize).
+		Find(&nft.Owners).Error
+	if err != nil {
+		return nil, err
+	}
+	return &nft, nil
+}
+
+func (g *GormDB) FindNFTTransfers(ctx context.Context, filter NFTTransferFilter) ([]*data.NFTTransfer, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	transfers := []*data.NFTTransfer{}
+	err := db.Where("chain_id = ? AND contract = ? AND token_id = ?", g.chainID, filter.Contract, filter.TokenID).
+		Order("block_number asc, log_index asc, batch_index asc").
+		Limit(filter.limit()).Offset(filter.offset()).
+		Find(&transfers).Error
+	if err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// GetAddressNFTs returns the NFTs an address holds with their metadata,
+// ordered by contract and token id.
+func (g *GormDB) GetAddressNFTs(ctx context.Context, filter NFTOwnerFilter) ([]*data.NFTHolding, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	query := db.Table("nft_owners AS o").
+		Select(`o.contract, o.token_id, COALESCE(n.standard, '') AS standard,
+			COALESCE(n.uri, '') AS uri, o.balance`).
+		Joins(`LEFT JOIN nfts AS n ON n.chain_id = o.chain_id AND n.contract = o.contract AND n.token_id = o.token_id`).
+		Where("o.chain_id = ? AND o.owner = ? AND o.balance NOT LIKE '-%'", g.chainID, filter.Owner)
+	if filter.Contract != "" {
+		query = query.Where("o.contract = ?", filter.Contract)
+	}
+	holdings := []*data.NFTHolding{}
+	err := query.Order("o.contract asc, LENGTH(o.token_id) asc, o.token_id asc").
+		Limit(filter.limit()).Offset(filter.offset()).
+		Scan(&holdings).Error
+	if err != nil {
+		return nil, err
+	}
+	return holdings, nil
+}
@@ -0,0 +1,157 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// partitionSizes is the number of blocks in each partition of the tables
+// range partitioned on block number by migration 0002. Changing a size only
+// affects partitions created afterwards.
+var partitionSizes = map[string]uint64{
+	"blocks":       1000000,
+	"transactions": 100000,
+}
+
+// partitionKeys is the block number column each table is partitioned on.
+var partitionKeys = map[string]string{
+	"blocks":       "number",
+	"transactions": "block_number",
+}
+
+// partitionCache remembers the partitions that have been created so inserts
+// only issue DDL the first time a range is seen.
+type partitionCache struct {
+	mu    sync.Mutex
+	known map[string]bool
+}
+
+func partitionName(table string, start uint64) string {
+	return fmt.Sprintf("%s_p%012d", table, start)
+}
+
+func partitionRange(table string, number uint64) (uint64, uint64) {
+	size := partitionSizes[table]
+	start := number / size * size
+	return start, start + size
+}
+
+// inBlocks restricts a lookup by hash to the partitions holding numbers, the
+// block numbers of the rows when the caller knows them. Without numbers the
+// index of every partition is probed.
+func inBlocks(query *gorm.DB, column string, numbers []uint64) *gorm.DB {
+	if len(numbers) == 0 {
+		return query
+	}
+	return query.Where(column+" IN ?", numbers)
+}
+
+func createPartition(db *gorm.DB, table string, start, end uint64) error {
+	return db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %q PARTITION OF %q FOR VALUES FROM (%d) TO (%d)`,
+		partitionName(table, start), table, start, end)).Error
+}
+
+// sqlStateInvalidObjectDefinition is the SQLSTATE of creating a partition
+// that overlaps another one.
+const sqlStateInvalidObjectDefinition = "42P17"
+
+// hasSQLState reports whether err is a Postgres error with the given SQLSTATE.
+func hasSQLState(err error, code string) bool {
+	var pgErr interface{ SQLState() string }
+	return errors.As(err, &pgErr) && pgErr.SQLState() == code
+}
+
+// ensurePartition creates the partition of table holding number if it does
+// not exist yet. Ranges still covered by a legacy partition can't be created,
+// the error is logged once and inserts fall through to the legacy partition.
+// Any other failure is retried by the next insert into the range.
+func (g *GormDB) ensurePartition(db *gorm.DB, table string, number uint64) {
+	if g.Dialector.Name() != dialectPostgres {
+		return
+	}
+	start, end := partitionRange(table, number)
+	name := partitionName(table, start)
+
+	g.partitions.mu.Lock()
+	defer g.partitions.mu.Unlock()
+	if g.partitions.known[name] {
+		return
+	}
+	if err := createPartition(db, table, start, end); err != nil {
+		slog.Warn("failed to create partition", "partition", name, "err", err)
+		if !hasSQLState(err, sqlStateInvalidObjectDefinition) {
+			return
+		}
+	}
+	g.partitions.known[name] = true
+}
+
+// Repartition moves the rows of table's legacy partition, left by migration
+// 0002 on databases that had data, into regular partitions one range at a
+// time. Writes to the table must be stopped while it runs. It can be rerun
+// after an interruption.
+func (g *GormDB) Repartition(table string) error {
+	key, ok := partitionKeys[table]
+	if !ok {
+		return fmt.Errorf("table %s is not partitioned", table)
+	}
+	legacy := table + "_legacy"
+
+	var exists bool
+	if err := g.Raw(`SELECT to_regclass(?) IS NOT NULL`, legacy).Scan(&exists).Error; err != nil {
+		return err
+	}
+	if !exists {
+		slog.Info("nothing to repartition", "table", table)
+		return nil
+	}
+
+	var attached bool
+	err := g.Raw(`SELECT EXISTS (SELECT 1 FROM pg_inherits WHERE inhrelid = to_regclass(?))`, legacy).Scan(&attached).Error
+	if err != nil {
+		return err
+	}
+	if attached {
+		if err := g.Exec(fmt.Sprintf(`ALTER TABLE %q DETACH PARTITION %q`, table, legacy)).Error; err != nil {
+			return fmt.Errorf("failed to detach %s: %w", legacy, err)
+		}
+	}
+
+	var bounds struct {
+		Min *uint64
+		Max *uint64
+	}
+	err = g.Raw(fmt.Sprintf(`SELECT min(%q) AS min, max(%q) AS max FROM %q`, key, key, legacy)).Scan(&bounds).Error
+	if err != nil {
+		return err
+	}
+	if bounds.Min != nil {
+		for start, _ := partitionRange(table, *bounds.Min); start <= *bounds.Max; start += partitionSizes[table] {
+			end := start + partitionSizes[table]
+			err := g.Transaction(func(tx *gorm.DB) error {
+				if err := createPartition(tx, table, start, end); err != nil {
+					return err
+				}
+				err := tx.Exec(fmt.Sprintf(`INSERT INTO %q SELECT * FROM %q WHERE %q >= ? AND %q < ? ON CONFLICT DO NOTHING`,
+					table, legacy, key, key), start, end).Error
+				if err != nil {
+					return err
+				}
+				return tx.Exec(fmt.Sprintf(`DELETE FROM %q WHERE %q >= ? AND %q < ?`, legacy, key, key), start, end).Error
+			})
+			if err != nil {
+				return fmt.Errorf("failed to move %s rows %d-%d: %w", table, start, end-1, err)
+			}
+			slog.Info("moved partition", "partition", partitionName(table, start))
+		}
+	}
+
+	if err := g.Exec(fmt.Sprintf(`DROP TABLE %q`, legacy)).Error; err != nil {
+		return fmt.Errorf("failed to drop %s: %w", legacy, err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionRange(t *testing.T) {
+	start, end := partitionRange("blocks", 19123456)
+	assert.Equal(t, uint64(19000000), start)
+	assert.Equal(t, uint64(20000000), end)
+	start, end = partitionRange("transactions", 19123456)
+	assert.Equal(t, uint64(19100000), start)
+	assert.Equal(t, uint64(19200000), end)
+	assert.Equal(t, "transactions_p000019100000", partitionName("transactions", start))
+}
+
+func TestEnsurePartitionOncePerRange(t *testing.T) {
+	s := newSuite(t)
+	g := s.dbMock.(*GormDB)
+
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(
+		`CREATE TABLE IF NOT EXISTS "blocks_p000019000000" PARTITION OF "blocks" FOR VALUES FROM (19000000) TO (20000000)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(
+		`CREATE TABLE IF NOT EXISTS "blocks_p000020000000" PARTITION OF "blocks" FOR VALUES FROM (20000000) TO (21000000)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	g.ensurePartition(g.DB, "blocks", 19000000)
+	g.ensurePartition(g.DB, "blocks", 19999999)
+	g.ensurePartition(g.DB, "blocks", 20000000)
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+// pgError is a Postgres error with a SQLSTATE, like the driver's.
+type pgError struct {
+	code string
+	msg  string
+}
+
+func (e *pgError) Error() string    { return e.msg }
+func (e *pgError) SQLState() string { return e.code }
+
+func TestEnsurePartitionRetriesFailedCreate(t *testing.T) {
+	s := newSuite(t)
+
+	s.sqlMock.ExpectExec(`^CREATE TABLE IF NOT EXISTS "blocks_p000000000000"`).
+		WillReturnError(&pgError{code: "55P03", msg: "canceling statement due to lock timeout"})
+	s.sqlMock.ExpectBegin()
+	s.sqlMock.ExpectQuery(`^SELECT \* FROM "blocks"`).WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+	s.sqlMock.ExpectExec(`^INSERT INTO "blocks"`).
+		WillReturnError(errors.New(`no partition of relation "blocks" found for row`))
+	s.sqlMock.ExpectRollback()
+	// The next insert into the range creates the partition again.
+	s.sqlMock.ExpectExec(`^CREATE TABLE IF NOT EXISTS "blocks_p000000000000"`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.sqlMock.ExpectBegin()
+	s.sqlMock.ExpectQuery(`^SELECT \* FROM "blocks"`).WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+	s.sqlMock.ExpectExec(`^INSERT INTO "blocks"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.sqlMock.ExpectExec(`^INSERT INTO "miner_rewards"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.sqlMock.ExpectQuery(`^SELECT \* FROM "miner_rewards"`).
+		WillReturnRows(sqlmock.NewRows([]string{"miner", "block_rewards", "uncle_inclusion_rewards", "uncle_rewards", "priority_fees"}).
+			AddRow(mockBlocks[0].Miner, "0", "0", "0", "0"))
+	s.sqlMock.ExpectExec(`^UPDATE "miner_rewards"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.sqlMock.ExpectExec(`^INSERT INTO "stats_dirty"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.sqlMock.ExpectCommit()
+
+	assert.Error(t, s.dbMock.InsertBlock(context.Background(), mockBlocks[0]))
+	assert.NoError(t, s.dbMock.InsertBlock(context.Background(), mockBlocks[0]))
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+func TestInsertBlockIntoLegacyPartition(t *testing.T) {
+	s := newSuite(t)
+
+	// The range overlaps the legacy partition, the insert still goes ahead.
+	s.sqlMock.ExpectExec(`^CREATE TABLE IF NOT EXISTS "blocks_p000000000000"`).
+		WillReturnError(&pgError{code: sqlStateInvalidObjectDefinition,
+			msg: `partition "blocks_p000000000000" would overlap partition "blocks_legacy"`})
+	s.sqlMock.ExpectBegin()
+	s.sqlMock.ExpectQuery(`^SELECT \* FROM "blocks"`).WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+	s.sqlMock.ExpectExec(`^INSERT INTO "blocks"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.sqlMock.ExpectExec(`^INSERT INTO "miner_rewards"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.sqlMock.ExpectQuery(`^SELECT \* FROM "miner_rewards"`).
+		WillReturnRows(sqlmock.NewRows([]string{"miner", "block_rewards", "uncle_inclusion_rewards", "uncle_rewards", "priority_fees"}).
+			AddRow(mockBlocks[0].Miner, "0", "0", "0", "0"))
+	s.sqlMock.ExpectExec(`^UPDATE "miner_rewards"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.sqlMock.ExpectExec(`^INSERT INTO "stats_dirty"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.sqlMock.ExpectCommit()
+	s.sqlMock.ExpectBegin()
+	s.sqlMock.ExpectQuery(`^SELECT \* FROM "blocks"`).WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+	s.sqlMock.ExpectExec(`^INSERT INTO "blocks"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.sqlMock.ExpectExec(`^INSERT INTO "miner_rewards"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.sqlMock.ExpectQuery(`^SELECT \* FROM "miner_rewards"`).
+		WillReturnRows(sqlmock.NewRows([]string{"miner", "block_rewards", "uncle_inclusion_rewards", "uncle_rewards", "priority_fees"}).
+			AddRow(mockBlocks[0].Miner, "0", "0", "0", "0"))
+	s.sqlMock.ExpectExec(`^UPDATE "miner_rewards"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.sqlMock.ExpectExec(`^INSERT INTO "stats_dirty"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.sqlMock.ExpectCommit()
+
+	assert.NoError(t, s.dbMock.InsertBlock(context.Background(), mockBlocks[0]))
+	assert.NoError(t, s.dbMock.InsertBlock(context.Background(), mockBlocks[1]))
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+func TestRepartition(t *testing.T) {
+	s := newSuite(t)
+	g := s.dbMock.(*GormDB)
+
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(`SELECT to_regclass($1) IS NOT NULL`)).
+		WithArgs("transactions_legacy").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM pg_inherits WHERE inhrelid = to_regclass($1))`)).
+		WithArgs("transactions_legacy").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(`ALTER TABLE "transactions" DETACH PARTITION "transactions_legacy"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(`SELECT min("block_number") AS min, max("block_number") AS max FROM "transactions_legacy"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"min", "max"}).AddRow(150000, 250000))
+	for _, start := range []uint64{100000, 200000} {
+		end := start + 100000
+		s.sqlMock.ExpectBegin()
+		s.sqlMock.ExpectExec(regexp.QuoteMeta(
+			`CREATE TABLE IF NOT EXISTS "` + partitionName("transactions", start) + `" PARTITION OF "transactions"`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		s.sqlMock.ExpectExec(regexp.QuoteMeta(
+			`INSERT INTO "transactions" SELECT * FROM "transactions_legacy" WHERE "block_number" >= $1 AND "block_number" < $2 ON CONFLICT DO NOTHING`)).
+			WithArgs(start, end).
+			WillReturnResult(sqlmock.NewResult(0, 10))
+		s.sqlMock.ExpectExec(regexp.QuoteMeta(
+			`DELETE FROM "transactions_legacy" WHERE "block_number" >= $1 AND "block_number" < $2`)).
+			WithArgs(start, end).
+			WillReturnResult(sqlmock.NewResult(0, 10))
+		s.sqlMock.ExpectCommit()
+	}
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(`DROP TABLE "transactions_legacy"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, g.Repartition("transactions"))
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+func TestRepartitionWithoutLegacy(t *testing.T) {
+	s := newSuite(t)
+	g := s.dbMock.(*GormDB)
+
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(`SELECT to_regclass($1) IS NOT NULL`)).
+		WithArgs("blocks_legacy").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	assert.NoError(t, g.Repartition("blocks"))
+	assert.EqualError(t, g.Repartition("logs"), "table logs is not partitioned")
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+func TestSQLiteSkipsPartitions(t *testing.T) {
+	db := openMigrated(t, "sqlite://:memory:", false)
+	assert.NoError(t, db.InsertTx(context.Background(), conformanceTx(1, 19000000, address(1), address(2))))
+}
+
+func TestPartitionPruning(t *testing.T) {
+	url := os.Getenv("TEST_DB_URL")
+	if url == "" {
+		t.Skip("TEST_DB_URL is not set")
+	}
+	db := openMigrated(t, url, true)
+	g := db.(*GormDB)
+	for _, n := range []uint64{1, 1000001} {
+		require.NoError(t, db.InsertBlock(context.Background(), conformanceBlock(n)))
+		require.NoError(t, db.InsertTx(context.Background(), conformanceTx(byte(n%100), n, address(1), address(2))))
+	}
+
+	var plan []string
+	require.NoError(t, g.Raw(`EXPLAIN SELECT * FROM "transactions" WHERE block_number BETWEEN ? AND ?`, 1, 2).Scan(&plan).Error)
+	assert.Contains(t, strings.Join(plan, "\n"), "transactions_p000000000000")
+	assert.NotContains(t, strings.Join(plan, "\n"), "transactions_p000001000000")
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"gorm.io/gorm"
+)
+
+// DeleteBlockRange deletes the blocks numbered in [fromNumber, toNumber) with
+// their transactions, logs, internal transactions, token transfers and
+// approvals, NFT transfers, balance snapshots and uncles, returning the
+// number of blocks deleted. Token balances, NFT owners and address summaries
+// are kept. Only the connection's chain is pruned.
+func (g *GormDB) DeleteBlockRange(ctx context.Context, fromNumber, toNumber uint64) (int64, error) {
+	db, cancel := g.write(ctx)
+	defer cancel()
+	var deleted int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("chain_id = ? AND block_number >= ? AND block_number < ?", g.chainID, fromNumber, toNumber).
+			Delete(&data.TokenTransfer{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("chain_id = ? AND block_number >= ? AND block_number < ?", g.chainID, fromNumber, toNumber).
+			Delete(&data.TokenApproval{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("chain_id = ? AND block_number >= ? AND block_number < ?", g.chainID, fromNumber, toNumber).
+			Delete(&data.NFTTransfer{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("chain_id = ? AND block_number >= ? AND block_number < ?", g.chainID, fromNumber, toNumber).
+			Delete(&data.InternalTx{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("chain_id = ? AND block_number >= ? AND block_number < ?", g.chainID, fromNumber, toNumber).
+			Delete(&data.Balance{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("chain_id = ? AND block_number >= ? AND block_number < ?", g.chainID, fromNumber, toNumber).
+			Delete(&data.Uncle{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("chain_id = ? AND block_number >= ? AND block_number < ?", g.chainID, fromNumber, toNumber).
+			Delete(&data.Log{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("chain_id = ? AND block_number >= ? AND block_number < ?", g.chainID, fromNumber, toNumber).
+			Delete(&data.Transaction{}).Error
+		if err != nil {
+			return err
+		}
+		res := tx.Where("chain_id = ? AND number >= ? AND number < ?", g.chainID, fromNumber, toNumber).Delete(&data.Block{})
+		deleted = res.RowsAffected
+		return res.Error
+	})
+	return deleted, err
+}
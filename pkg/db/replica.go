@@ -0,0 +1,70 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// useReplicas routes queries to the replicas at urls. Inserts, deletes,
+// transactions and DDL keep going to the primary.
+func (g *GormDB) useReplicas(urls []string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+	if g.Dialector.Name() != dialectPostgres {
+		return fmt.Errorf("read replicas are only supported with postgres")
+	}
+	conns := make([]*sql.DB, 0, len(urls))
+	for _, url := range urls {
+		conn, err := sql.Open("pgx", url)
+		if err != nil {
+			closeAll(conns)
+			return fmt.Errorf("failed to connect to db replica: %w", err)
+		}
+		conns = append(conns, conn)
+	}
+	if err := g.registerReplicas(conns); err != nil {
+		closeAll(conns)
+		return err
+	}
+	return nil
+}
+
+func (g *GormDB) registerReplicas(conns []*sql.DB) error {
+	replicas := make([]gorm.Dialector, len(conns))
+	for i, conn := range conns {
+		replicas[i] = postgres.New(postgres.Config{Conn: conn})
+	}
+	if err := g.Use(dbresolver.Register(dbresolver.Config{Replicas: replicas})); err != nil {
+		return fmt.Errorf("failed to register db replicas: %w", err)
+	}
+	g.replicas = conns
+	return nil
+}
+
+// Primary returns a DB that also sends queries to the primary, for callers
+// that must read their own writes despite replication lag.
+func (g *GormDB) Primary() DB {
+	if len(g.replicas) == 0 {
+		return g
+	}
+	return &GormDB{
+		DB:         g.Clauses(dbresolver.Write).Session(&gorm.Session{}),
+		partitions: g.partitions,
+		timeouts:   g.timeouts,
+		chainID:    g.chainID,
+	}
+}
+
+func closeAll(conns []*sql.DB) error {
+	var errs []error
+	for _, conn := range conns {
+		errs = append(errs, conn.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReplicaSuite(t *testing.T) (suite, sqlmock.Sqlmock) {
+	s := newSuite(t)
+	replicaDB, replicaMock, err := sqlmock.New()
+	require.NoError(t, err)
+	require.NoError(t, s.dbMock.(*GormDB).registerReplicas([]*sql.DB{replicaDB}))
+	return s, replicaMock
+}
+
+func expectBlockQuery(m sqlmock.Sqlmock) {
+	m.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "blocks" WHERE chain_id = $1 AND number = $2`)).
+		WillReturnRows(sqlmock.NewRows([]string{"hash", "number"}).AddRow(mockBlocks[0].Hash, mockBlocks[0].Number))
+}
+
+func TestReplicaRouting(t *testing.T) {
+	s, replicaMock := newReplicaSuite(t)
+
+	expectBlockQuery(replicaMock)
+	_, err := s.dbMock.GetBlockByNumber(context.Background(), mockBlocks[0].Number)
+	require.NoError(t, err)
+
+	s.sqlMock.ExpectBegin()
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "token_transfers"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "token_approvals"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "nft_transfers"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "internal_txs"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "balances"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "uncles"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "logs"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "blocks"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.sqlMock.ExpectCommit()
+	_, err = s.dbMock.DeleteBlockRange(context.Background(), 0, 1)
+	require.NoError(t, err)
+
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
+
+func TestPrimaryReadsYourWrites(t *testing.T) {
+	s, replicaMock := newReplicaSuite(t)
+	primary := s.dbMock.Primary()
+
+	expectBlockQuery(s.sqlMock)
+	expectBlockQuery(s.sqlMock)
+	_, err := primary.GetBlockByNumber(context.Background(), mockBlocks[0].Number)
+	require.NoError(t, err)
+	// The primary DB is reusable and doesn't leak conditions between queries.
+	_, err = primary.GetBlockByNumber(context.Background(), mockBlocks[0].Number)
+	require.NoError(t, err)
+
+	expectBlockQuery(replicaMock)
+	_, err = s.dbMock.GetBlockByNumber(context.Background(), mockBlocks[0].Number)
+	require.NoError(t, err)
+
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
+
+func TestPrimaryWithoutReplicas(t *testing.T) {
+	s := newSuite(t)
+	assert.Same(t, s.dbMock, s.dbMock.Primary())
+}
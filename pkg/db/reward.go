@@ -0,0 +1,219 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetMinerRewards returns the rewards earned by a fee recipient.
+func (g *GormDB) GetMinerRewards(ctx context.Context, miner string) (*data.MinerRewards, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var rewards data.MinerRewards
+	if err := db.Take(&rewards, "chain_id = ? AND miner = ?", g.chainID, miner).Error; err != nil {
+		return nil, err
+	}
+	total := new(big.Int)
+	for _, amount := range []string{rewards.BlockRewards, rewards.UncleInclusionRewards, rewards.UncleRewards, rewards.PriorityFees} {
+		n, err := parseWei(amount)
+		if err != nil {
+			return nil, err
+		}
+		total.Add(total, n)
+	}
+	rewards.Total = total.String()
+	return &rewards, nil
+}
+
+// GetBurnStats returns the ETH burned in each bucket of filter.Period,
+// oldest first, and in all the buckets between filter.FromTime and
+// filter.ToTime regardless of the page.
+func (g *GormDB) GetBurnStats(ctx context.Context, filter StatsFilter) (*data.BurnStats, error) {
+	table, ok := statsTables[filter.Period]
+	if !ok {
+		return nil, &FilterError{"period", "must be hourly or daily"}
+	}
+	db, cancel := g.read(ctx)
+	defer cancel()
+	inRange := func() *gorm.DB {
+		query := db.Table(table).Where("chain_id = ?", g.chainID)
+		if filter.FromTime != nil {
+			query = query.Where("time >= ?", filter.Period.Bucket(*filter.FromTime))
+		}
+		if filter.ToTime != nil {
+			query = query.Where("time <= ?", filter.Period.Bucket(*filter.ToTime))
+		}
+		return query
+	}
+	buckets := []*data.BurnPoint{}
+	err := inRange().Select("time, base_fee_burned, blob_fee_burned").Order("time asc").
+		Limit(filter.limit()).Offset(filter.offset()).
+		Find(&buckets).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, bucket := range buckets {
+		if bucket.Burned, err = sumWei(bucket.BaseFeeBurned, bucket.BlobFeeBurned); err != nil {
+			return nil, err
+		}
+	}
+
+	stats := &data.BurnStats{Buckets: buckets}
+	if stats.BaseFeeBurned, stats.BlobFeeBurned, err = sumBurned(inRange()); err != nil {
+		return nil, err
+	}
+	if stats.Burned, err = sumWei(stats.BaseFeeBurned, stats.BlobFeeBurned); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// sumBurned sums the base_fee_burned and blob_fee_burned columns of the rows
+// matched by query. Postgres stores them as numeric and sums them in SQL.
+// SQLite keeps the decimal strings, which its SUM would round to a float, so
+// they're summed in Go there.
+func sumBurned(query *gorm.DB) (string, string, error) {
+	if query.Dialector.Name() == dialectPostgres {
+		var sums struct{ BaseFeeBurned, BlobFeeBurned string }
+		err := query.Select(`COALESCE(SUM(base_fee_burned), 0)::text AS base_fee_burned,
+			COALESCE(SUM(blob_fee_burned), 0)::text AS blob_fee_burned`).
+			Scan(&sums).Error
+		return sums.BaseFeeBurned, sums.BlobFeeBurned, err
+	}
+	var rows []struct{ BaseFeeBurned, BlobFeeBurned string }
+	if err := query.Select("base_fee_burned, blob_fee_burned").Scan(&rows).Error; err != nil {
+		return "", "", err
+	}
+	baseFee, blobFee := "0", "0"
+	for _, row := range rows {
+		var err error
+		if baseFee, err = sumWei(baseFee, row.BaseFeeBurned); err != nil {
+			return "", "", err
+		}
+		if blobFee, err = sumWei(blobFee, row.BlobFeeBurned); err != nil {
+			return "", "", err
+		}
+	}
+	return baseFee, blobFee, nil
+}
+
+// applyMinerBlock adds the rewards of a stored block to its miner's totals,
+// or removes them when sign is negative.
+func applyMinerBlock(db *gorm.DB, block *data.Block, sign int) error {
+	return updateMinerRewards(db, block.ChainID, block.Miner, sign, func(r *data.MinerRewards) error {
+		r.Blocks = addCount(r.Blocks, sign)
+		if err := addWei(&r.BlockRewards, block.BlockReward, sign); err != nil {
+			return err
+		}
+		if err := addWei(&r.UncleInclusionRewards, block.UncleInclusionReward, sign); err != nil {
+			return err
+		}
+		return addWei(&r.PriorityFees, block.PriorityFees, sign)
+	})
+}
+
+// applyMinerUncle adds the reward of a stored uncle to its miner's totals,
+// or removes it when sign is negative.
+func applyMinerUncle(db *gorm.DB, uncle *data.Uncle, sign int) error {
+	return updateMinerRewards(db, uncle.ChainID, uncle.Miner, sign, func(r *data.MinerRewards) error {
+		r.Uncles = addCount(r.Uncles, sign)
+		return addWei(&r.UncleRewards, uncle.Reward, sign)
+	})
+}
+
+// updateMinerRewards applies update to the totals of miner, creating them
+// when a block or uncle is added and deleting them when the last one is
+// removed, like an address summary.
+func updateMinerRewards(db *gorm.DB, chainID uint64, miner string, sign int, update func(*data.MinerRewards) error) error {
+	if sign > 0 {
+		row := data.MinerRewards{
+			ChainID:               chainID,
+			Miner:                 miner,
+			BlockRewards:          "0",
+			UncleInclusionRewards: "0",
+			UncleRewards:          "0",
+			PriorityFees:          "0",
+		}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+			return err
+		}
+	}
+	var rewards data.MinerRewards
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Take(&rewards, "chain_id = ? AND miner = ?", chainID, miner).Error
+	if err != nil {
+		if sign < 0 && errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if err := update(&rewards); err != nil {
+		return err
+	}
+	if rewards.Blocks == 0 && rewards.Uncles == 0 {
+		return db.Where("chain_id = ? AND miner = ?", chainID, miner).Delete(&data.MinerRewards{}).Error
+	}
+	return db.Where("chain_id = ? AND miner = ?", chainID, miner).Select("*").Updates(&rewards).Error
+}
+
+// revertMinerUncles removes the uncles of a reorged block from their
+// miners' totals, before the uncles are deleted.
+func revertMinerUncles(db *gorm.DB, chainID, number uint64, hash string) error {
+	var uncles []*data.Uncle
+	err := db.Where("chain_id = ? AND block_number = ? AND block_hash = ?", chainID, number, hash).Find(&uncles).Error
+	if err != nil {
+		return err
+	}
+	for _, uncle := range uncles {
+		if err := applyMinerUncle(db, uncle, -1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseWei parses a uint256 decimal string, empty for blocks published
+// before rewards were computed.
+func parseWei(s string) (*big.Int, error) {
+	if s == "" {
+		return new(big.Int), nil
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal %q", s)
+	}
+	return n, nil
+}
+
+// addWei adds the decimal amount to the decimal string at sum, or subtracts
+// it when sign is negative.
+func addWei(sum *string, amount string, sign int) error {
+	delta, err := parseWei(amount)
+	if err != nil {
+		return err
+	}
+	if sign < 0 {
+		delta.Neg(delta)
+	}
+	return addDecimal(sum, delta)
+}
+
+// sumWei returns the sum of two decimal wei amounts. Amounts are summed in
+// Go with it, the decimal strings don't add up in SQL on every dialect, see
+// sumBurned.
+func sumWei(a, b string) (string, error) {
+	x, err := parseWei(a)
+	if err != nil {
+		return "", err
+	}
+	y, err := parseWei(b)
+	if err != nil {
+		return "", err
+	}
+	return x.Add(x, y).String(), nil
+}
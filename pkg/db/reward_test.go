@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetBurnStatsSumsInSQL checks the totals over the range are summed by
+// Postgres rather than loaded row by row.
+func TestGetBurnStatsSumsInSQL(t *testing.T) {
+	s := newSuite(t)
+	from := StatsHourly.Bucket(1625812800)
+
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT time, base_fee_burned, blob_fee_burned FROM "stats_hourly" WHERE chain_id = $1 AND time >= $2 ORDER BY time asc LIMIT $3`)).
+		WithArgs(mockChainID, from, DefaultPageSize).
+		WillReturnRows(sqlmock.NewRows([]string{"time", "base_fee_burned", "blob_fee_burned"}).
+			AddRow(from, "100000000000000000000", "10"))
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT COALESCE(SUM(base_fee_burned), 0)::text AS base_fee_burned,
+			COALESCE(SUM(blob_fee_burned), 0)::text AS blob_fee_burned FROM "stats_hourly" WHERE chain_id = $1 AND time >= $2`)).
+		WithArgs(mockChainID, from).
+		WillReturnRows(sqlmock.NewRows([]string{"base_fee_burned", "blob_fee_burned"}).
+			AddRow("300000000000000000000", "30"))
+
+	stats, err := s.dbMock.GetBurnStats(context.Background(), StatsFilter{Period: StatsHourly, FromTime: &from})
+	require.NoError(t, err)
+	assert.Equal(t, &data.BurnStats{
+		BaseFeeBurned: "300000000000000000000", BlobFeeBurned: "30", Burned: "300000000000000000030",
+		Buckets: []*data.BurnPoint{{
+			Time: from, BaseFeeBurned: "100000000000000000000", BlobFeeBurned: "10", Burned: "100000000000000000010",
+		}},
+	}, stats)
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
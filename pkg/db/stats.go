@@ -0,0 +1,204 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StatsPeriod is the bucket size of a statistics rollup.
+type StatsPeriod string
+
+const (
+	StatsHourly StatsPeriod = "hourly"
+	StatsDaily  StatsPeriod = "daily"
+)
+
+var statsTables = map[StatsPeriod]string{
+	StatsHourly: "stats_hourly",
+	StatsDaily:  "stats_daily",
+}
+
+var statsPeriodSeconds = map[StatsPeriod]uint64{
+	StatsHourly: 60 * 60,
+	StatsDaily:  24 * 60 * 60,
+}
+
+// Bucket returns the start of the bucket holding the unix timestamp t.
+func (p StatsPeriod) Bucket(t uint64) uint64 {
+	return t - t%statsPeriodSeconds[p]
+}
+
+// markStatsDirty queues the hour of chainID holding blockTime for the next
+// rollup.
+func markStatsDirty(db *gorm.DB, chainID, blockTime uint64) error {
+	return db.Exec(`INSERT INTO "stats_dirty" ("chain_id", "period", "time") VALUES (?, ?, ?) ON CONFLICT DO NOTHING`,
+		chainID, string(StatsHourly), StatsHourly.Bucket(blockTime)).Error
+}
+
+// markTxStatsDirty queues the hour of the block numbered blockNumber. A
+// transaction stored ahead of its block marks nothing, the block marks its
+// hour when it arrives.
+func markTxStatsDirty(db *gorm.DB, chainID, blockNumber uint64) error {
+	return db.Exec(fmt.Sprintf(`INSERT INTO "stats_dirty" ("chain_id", "period", "time") SELECT "chain_id", '%s', "time" - "time" %% %d FROM "blocks" WHERE "chain_id" = ? AND "number" = ? ON CONFLICT DO NOTHING`,
+		StatsHourly, statsPeriodSeconds[StatsHourly]), chainID, blockNumber).Error
+}
+
+// GetDirtyStats returns the start of up to limit buckets of period waiting
+// for a rollup, oldest first.
+func (g *GormDB) GetDirtyStats(ctx context.Context, period StatsPeriod, limit int) ([]uint64, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var buckets []uint64
+	err := db.Table("stats_dirty").Where("chain_id = ? AND period = ?", g.chainID, string(period)).
+		Order("time asc").Limit(limit).
+		Pluck("time", &buckets).Error
+	if err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// RollupStats recomputes the bucket of period starting at start from the
+// stored blocks and transactions and clears its dirty mark. The mark is
+// cleared first, so a transaction stored while the rollup runs marks the
+// bucket again. Rolling up an hour marks its day.
+func (g *GormDB) RollupStats(ctx context.Context, period StatsPeriod, start uint64) error {
+	db, cancel := g.write(ctx)
+	defer cancel()
+	table := statsTables[period]
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`DELETE FROM "stats_dirty" WHERE "chain_id" = ? AND "period" = ? AND "time" = ?`, g.chainID, string(period), start).Error
+		if err != nil {
+			return err
+		}
+		stats, err := computeStats(tx, g.chainID, start, start+statsPeriodSeconds[period])
+		if err != nil {
+			return err
+		}
+		if stats == nil {
+			err = tx.Table(table).Where("chain_id = ? AND time = ?", g.chainID, start).Delete(&data.Stats{}).Error
+		} else {
+			err = tx.Table(table).Clauses(clause.OnConflict{UpdateAll: true}).Create(stats).Error
+		}
+		if err != nil || period != StatsHourly {
+			return err
+		}
+		return tx.Exec(`INSERT INTO "stats_dirty" ("chain_id", "period", "time") VALUES (?, ?, ?) ON CONFLICT DO NOTHING`,
+			g.chainID, string(StatsDaily), StatsDaily.Bucket(start)).Error
+	})
+}
+
+// computeStats aggregates the blocks of chainID mined in [from, to) and
+// their transactions. It returns nil when there are no such blocks.
+func computeStats(db *gorm.DB, chainID, from, to uint64) (*data.Stats, error) {
+	var blocks struct {
+		Count      uint64
+		GasUsed    uint64
+		FromNumber uint64
+		ToNumber   uint64
+		FromTime   uint64
+		ToTime     uint64
+	}
+	err := db.Model(&data.Block{}).
+		Select(`COUNT(*) AS count, COALESCE(SUM(gas_used), 0) AS gas_used,
+			COALESCE(MIN(number), 0) AS from_number, COALESCE(MAX(number), 0) AS to_number,
+			COALESCE(MIN(time), 0) AS from_time, COALESCE(MAX(time), 0) AS to_time`).
+		Where("chain_id = ? AND time >= ? AND time < ?", chainID, from, to).
+		Scan(&blocks).Error
+	if err != nil || blocks.Count == 0 {
+		return nil, err
+	}
+	stats := &data.Stats{
+		ChainID: chainID,
+		Time:    from,
+		Blocks:  blocks.Count,
+		GasUsed: blocks.GasUsed,
+	}
+	if blocks.Count > 1 {
+		stats.AvgBlockTime = float64(blocks.ToTime-blocks.FromTime) / float64(blocks.Count-1)
+	}
+	stats.BaseFeeBurned, stats.BlobFeeBurned, err = sumBurned(db.Model(&data.Block{}).
+		Where("chain_id = ? AND time >= ? AND time < ?", chainID, from, to))
+	if err != nil {
+		return nil, err
+	}
+
+	// Transactions are matched by block number so the partitions and the
+	// block number index are used.
+	inRange := func() *gorm.DB {
+		return db.Model(&data.Transaction{}).
+			Where("chain_id = ? AND block_number >= ? AND block_number <= ?", chainID, blocks.FromNumber, blocks.ToNumber)
+	}
+	var txs struct {
+		Count       uint64
+		AvgGasPrice float64
+		Failed      uint64
+		Deployments uint64
+	}
+	err = inRange().
+		Select(`COUNT(*) AS count, COALESCE(AVG(gas_price), 0) AS avg_gas_price,
+			COALESCE(SUM(CASE WHEN status = 0 THEN 1 ELSE 0 END), 0) AS failed,
+			COALESCE(SUM(CASE WHEN "to" = '' THEN 1 ELSE 0 END), 0) AS deployments`).
+		Scan(&txs).Error
+	if err != nil || txs.Count == 0 {
+		return stats, err
+	}
+	stats.Txs = txs.Count
+	stats.AvgGasPrice = uint64(txs.AvgGasPrice)
+	stats.FailedTxs = txs.Failed
+	stats.FailedTxRatio = float64(txs.Failed) / float64(txs.Count)
+	stats.ContractDeployments = txs.Deployments
+
+	// The median is the middle price, or the mean of the two middle prices
+	// when the count is even.
+	var prices []uint64
+	err = inRange().Order("gas_price asc").
+		Offset(int((txs.Count-1)/2)).Limit(int(2-txs.Count%2)).
+		Pluck("gas_price", &prices).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(prices) > 0 {
+		stats.MedianGasPrice = prices[0] + (prices[len(prices)-1]-prices[0])/2
+	}
+
+	err = db.Raw(`SELECT COUNT(*) FROM (
+			SELECT "from" AS address FROM "transactions" WHERE chain_id = @chain AND block_number >= @from AND block_number <= @to
+			UNION
+			SELECT "to" FROM "transactions" WHERE chain_id = @chain AND block_number >= @from AND block_number <= @to AND "to" <> ''
+		) AS addresses`,
+		map[string]any{"chain": chainID, "from": blocks.FromNumber, "to": blocks.ToNumber}).
+		Scan(&stats.ActiveAddresses).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (g *GormDB) FindStats(ctx context.Context, filter StatsFilter) ([]*data.Stats, error) {
+	table, ok := statsTables[filter.Period]
+	if !ok {
+		return nil, &FilterError{"period", "must be hourly or daily"}
+	}
+	db, cancel := g.read(ctx)
+	defer cancel()
+	query := db.Table(table).Where("chain_id = ?", g.chainID)
+	if filter.FromTime != nil {
+		query = query.Where("time >= ?", filter.Period.Bucket(*filter.FromTime))
+	}
+	if filter.ToTime != nil {
+		query = query.Where("time <= ?", filter.Period.Bucket(*filter.ToTime))
+	}
+	stats := []*data.Stats{}
+	err := query.Order("time asc").
+		Limit(filter.limit()).Offset(filter.offset()).
+		Find(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
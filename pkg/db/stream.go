@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"gorm.io/gorm"
+)
+
+// stream scans the rows of query one at a time so large ranges can be
+// exported without loading them into memory. Streams are only bounded by the
+// caller's context, not by the read timeout.
+func stream[T any](g *GormDB, query *gorm.DB, fn func(*T) error) error {
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var row T
+		if err := g.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (g *GormDB) StreamBlocks(ctx context.Context, fromNumber, toNumber uint64, fn func(*data.Block) error) error {
+	query := g.WithContext(ctx).Model(&data.Block{}).
+		Where("chain_id = ? AND number BETWEEN ? AND ?", g.chainID, fromNumber, toNumber).
+		Order("number asc")
+	return stream(g, query, fn)
+}
+
+func (g *GormDB) StreamTxs(ctx context.Context, fromBlock, toBlock uint64, fn func(*data.Transaction) error) error {
+	query := g.WithContext(ctx).Model(&data.Transaction{}).
+		Where("chain_id = ? AND block_number BETWEEN ? AND ?", g.chainID, fromBlock, toBlock).
+		Order("block_number asc, hash asc")
+	return stream(g, query, fn)
+}
+
+func (g *GormDB) StreamLogs(ctx context.Context, fromBlock, toBlock uint64, fn func(*data.Log) error) error {
+	query := g.WithContext(ctx).Model(&data.Log{}).
+		Where("chain_id = ? AND block_number BETWEEN ? AND ?", g.chainID, fromBlock, toBlock).
+		Order("block_number asc, log_index asc")
+	return stream(g, query, fn)
+}
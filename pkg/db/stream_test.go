@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamBlocks(t *testing.T) {
+	s := newSuite(t)
+
+	rows := sqlmock.NewRows([]string{
+		"hash", "number", "gas_limit", "gas_used", "difficulty", "time",
+		"parent_hash", "nonce", "miner", "size", "root_hash", "uncle_hash",
+		"tx_hash", "receipt_hash", "extra_data",
+	})
+	for _, b := range mockBlocks {
+		rows.AddRow(
+			b.Hash, b.Number, b.GasLimit, b.GasUsed, b.Difficulty,
+			b.Time, b.ParentHash, b.Nonce, b.Miner, b.Size,
+			b.RootHash, b.UncleHash, b.TxHash, b.ReceiptHash, b.ExtraData,
+		)
+	}
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "blocks" WHERE chain_id = $1 AND number BETWEEN $2 AND $3 ORDER BY number asc`)).
+		WithArgs(mockChainID, 1, 2).
+		WillReturnRows(rows)
+
+	var blocks []data.Block
+	err := s.dbMock.StreamBlocks(context.Background(), 1, 2, func(b *data.Block) error {
+		blocks = append(blocks, *b)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, mockBlocks, blocks)
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+func TestStreamLogsStopsOnError(t *testing.T) {
+	s := newSuite(t)
+
+	rows := sqlmock.NewRows([]string{
+		"block_hash", "log_index", "block_number", "tx_hash", "address", "topic0", "topic1", "topic2", "topic3", "data",
+	})
+	for _, l := range mockLogs {
+		rows.AddRow(l.BlockHash, l.Index, l.BlockNumber, l.TxHash, l.Address, l.Topic0, l.Topic1, l.Topic2, l.Topic3, l.Data)
+	}
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "logs" WHERE chain_id = $1 AND block_number BETWEEN $2 AND $3 ORDER BY block_number asc, log_index asc`)).
+		WithArgs(mockChainID, 0, 10).
+		WillReturnRows(rows)
+
+	errStop := errors.New("stop")
+	calls := 0
+	err := s.dbMock.StreamLogs(context.Background(), 0, 10, func(l *data.Log) error {
+		calls++
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, 1, calls)
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,181 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var zeroAddress = common.Address{}.Hex()
+
+// InsertToken stores the metadata of a token, replacing what was stored for
+// it before.
+func (g *GormDB) InsertToken(ctx context.Context, token data.Token) error {
+	db, cancel := g.write(ctx)
+	defer cancel()
+	token.ChainID = g.chainID
+	return db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&token).Error
+}
+
+// InsertTokenTransfer stores a transfer and moves its value from the sender's
+// balance to the recipient's. A transfer that is already stored is ignored,
+// so redelivered messages don't count twice.
+func (g *GormDB) InsertTokenTransfer(ctx context.Context, transfer data.TokenTransfer) error {
+	value, ok := new(big.Int).SetString(transfer.Value, 10)
+	if !ok {
+		return fmt.Errorf("invalid token transfer value %q", transfer.Value)
+	}
+	db, cancel := g.write(ctx)
+	defer cancel()
+	transfer.ChainID = g.chainID
+	return db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&transfer)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return applyTokenTransfer(tx, &transfer, value)
+	})
+}
+
+// applyTokenTransfer adds value to the recipient's balance and subtracts it
+// from the sender's. Pass a negated value to revert a transfer.
+func applyTokenTransfer(tx *gorm.DB, transfer *data.TokenTransfer, value *big.Int) error {
+	if err := addTokenBalance(tx, transfer.ChainID, transfer.Token, transfer.From, new(big.Int).Neg(value)); err != nil {
+		return err
+	}
+	return addTokenBalance(tx, transfer.ChainID, transfer.Token, transfer.To, value)
+}
+
+// addTokenBalance adds delta to the balance of holder. The zero address
+// has no balance.
+func addTokenBalance(tx *gorm.DB, chainID uint64, token, holder string, delta *big.Int) error {
+	if holder == zeroAddress {
+		return nil
+	}
+	return addBalance(tx, "token_balances", map[string]any{"chain_id": chainID, "token": token, "holder": holder}, delta)
+}
+
+// addBalance adds delta to the decimal balance column of the row of table
+// with the key columns. The row is created first and then read for update,
+// so concurrent transfers to the same row wait for each other. Rows whose
+// balance reaches zero are deleted.
+func addBalance(tx *gorm.DB, table string, key map[string]any, delta *big.Int) error {
+	if delta.Sign() == 0 {
+		return nil
+	}
+	row := map[string]any{"balance": "0"}
+	for column, value := range key {
+		row[column] = value
+	}
+	err := tx.Table(table).Clauses(clause.OnConflict{DoNothing: true}).Create(row).Error
+	if err != nil {
+		return err
+	}
+	var balance struct{ Balance string }
+	err = tx.Table(table).Select("balance").Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(key).Take(&balance).Error
+	if err != nil {
+		return err
+	}
+	sum, ok := new(big.Int).SetString(balance.Balance, 10)
+	if !ok {
+		return fmt.Errorf("invalid balance in %s for %v: %q", table, key, balance.Balance)
+	}
+	sum.Add(sum, delta)
+	if sum.Sign() == 0 {
+		return tx.Table(table).Where(key).Delete(map[string]any{}).Error
+	}
+	return tx.Table(table).Where(key).Update("balance", sum.String()).Error
+}
+
+// removeTokenEvents reverts the balance changes of the transfers in a reorged
+// block and deletes its transfers and approvals.
+func removeTokenEvents(tx *gorm.DB, chainID, number uint64, hash string) error {
+	var transfers []*data.TokenTransfer
+	err := tx.Where("chain_id = ? AND block_number = ? AND block_hash = ?", chainID, number, hash).Find(&transfers).Error
+	if err != nil {
+		return err
+	}
+	for _, transfer := range transfers {
+		value, ok := new(big.Int).SetString(transfer.Value, 10)
+		if !ok {
+			return fmt.Errorf("invalid token transfer value %q", transfer.Value)
+		}
+		if err := applyTokenTransfer(tx, transfer, value.Neg(value)); err != nil {
+			return err
+		}
+	}
+	err = tx.Where("chain_id = ? AND block_number = ? AND block_hash = ?", chainID, number, hash).Delete(&data.TokenTransfer{}).Error
+	if err != nil {
+		return err
+	}
+	return tx.Where("chain_id = ? AND block_number = ? AND block_hash = ?", chainID, number, hash).Delete(&data.TokenApproval{}).Error
+}
+
+func (g *GormDB) InsertTokenApproval(ctx context.Context, approval data.TokenApproval) error {
+	db, cancel := g.write(ctx)
+	defer cancel()
+	approval.ChainID = g.chainID
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&approval).Error
+}
+
+func (g *GormDB) FindTokenTransfers(ctx context.Context, filter TokenTransferFilter) ([]*data.TokenTransfer, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	query := db.Where("chain_id = ? AND token = ?", g.chainID, filter.Token)
+	if filter.FromBlock != nil {
+		query = query.Where("block_number >= ?", *filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		query = query.Where("block_number <= ?", *filter.ToBlock)
+	}
+	transfers := []*data.TokenTransfer{}
+	err := query.Order("block_number asc, log_index asc").
+		Limit(filter.limit()).Offset(filter.offset()).
+		Find(&transfers).Error
+	if err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// GetTokenHolders returns the holders of a token with a positive balance,
+// largest first. Balances are decimal strings without leading zeros, so the
+// longer one is larger.
+func (g *GormDB) GetTokenHolders(ctx context.Context, token string, page Page) ([]*data.TokenBalance, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	holders := []*data.TokenBalance{}
+	err := db.Where("chain_id = ? AND token = ? AND balance NOT LIKE '-%'", g.chainID, token).
+		Order("LENGTH(balance) desc, balance desc, holder asc").
+		Limit(page.limit()).Offset(page.offset()).
+		Find(&holders).Error
+	if err != nil {
+		return nil, err
+	}
+	return holders, nil
+}
+
+// GetAddressTokens returns the token balances of an address with the
+// metadata of each token, ordered by token address.
+func (g *GormDB) GetAddressTokens(ctx context.Context, holder string) ([]*data.TokenHolding, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	holdings := []*data.TokenHolding{}
+	err := db.Table("token_balances AS b").
+		Select(`b.token, COALESCE(t.name, '') AS name, COALESCE(t.symbol, '') AS symbol,
+			COALESCE(t.decimals, 0) AS decimals, b.balance`).
+		Joins(`LEFT JOIN tokens AS t ON t.chain_id = b.chain_id AND t.address = b.token`).
+		Where("b.chain_id = ? AND b.holder = ?", g.chainID, holder).
+		Order("b.token asc").
+		Scan(&holdings).Error
+	if err != nil {
+		return nil, err
+	}
+	return holdings, nil
+}
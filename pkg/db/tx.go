@@ -1,25 +1,171 @@
 package db
 
 import (
+	"context"
+	"errors"
+
 	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"gorm.io/gorm"
 )
 
-func (g *GormDB) InsertTx(tx data.Transaction) error {
-	return g.Create(&tx).Error
+func (g *GormDB) InsertTx(ctx context.Context, tx data.Transaction) error {
+	db, cancel := g.write(ctx)
+	defer cancel()
+	tx.ChainID = g.chainID
+	g.ensurePartition(db, "transactions", tx.BlockNumber)
+	return db.Transaction(func(db *gorm.DB) error {
+		if err := db.Create(&tx).Error; err != nil {
+			return err
+		}
+		if err := applyAddressTx(db, &tx, 1); err != nil {
+			return err
+		}
+		return markTxStatsDirty(db, tx.ChainID, tx.BlockNumber)
+	})
 }
 
-func (g *GormDB) GetTxByHash(hash string) (*data.Transaction, error) {
+// GetTxByHash looks a transaction up by hash. Hashes are only unique per
+// block, the earliest match is returned. A known blockNumber narrows the
+// lookup to its partition, nil probes every partition.
+func (g *GormDB) GetTxByHash(ctx context.Context, hash string, blockNumber *uint64) (*data.Transaction, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	query := db.Where("chain_id = ? AND hash = ?", g.chainID, hash)
+	if blockNumber != nil {
+		query = query.Where("block_number = ?", *blockNumber)
+	}
 	var tx data.Transaction
-	if err := g.First(&tx, "hash = ?", hash).Error; err != nil {
+	if err := query.Order("block_number asc").Take(&tx).Error; err != nil {
 		return nil, err
 	}
 	return &tx, nil
 }
 
-func (g *GormDB) GetTxs() ([]*data.Transaction, error) {
+func (g *GormDB) GetTxs(ctx context.Context) ([]*data.Transaction, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var txs []*data.Transaction
+	if err := db.Find(&txs, "chain_id = ?", g.chainID).Error; err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+func (g *GormDB) GetTxsByHashes(ctx context.Context, hashes []string, blockNumbers []uint64) ([]*data.Transaction, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var txs []*data.Transaction
+	query := inBlocks(db.Where("chain_id = ? AND hash IN ?", g.chainID, hashes), "block_number", blockNumbers)
+	if err := query.Find(&txs).Error; err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+func (g *GormDB) GetTxsByBlockHashes(ctx context.Context, hashes []string, numbers []uint64) ([]*data.Transaction, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	var txs []*data.Transaction
+	query := inBlocks(db.Where("chain_id = ? AND block_hash IN ?", g.chainID, hashes), "block_number", numbers)
+	err := query.Order("block_number asc, hash asc").Find(&txs).Error
+	if err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+func (g *GormDB) FindTxs(ctx context.Context, filter TxFilter) ([]*data.Transaction, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	db, cancel := g.read(ctx)
+	defer cancel()
+	found, err := resolveTimeRange(db, g.chainID, &filter.FromBlock, &filter.ToBlock, filter.FromTime, filter.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return []*data.Transaction{}, nil
+	}
+
+	query := db.Model(&data.Transaction{}).Where("chain_id = ?", g.chainID)
+	if filter.From != "" {
+		query = query.Where(`"from" = ?`, filter.From)
+	}
+	if filter.To != "" {
+		query = query.Where(`"to" = ?`, filter.To)
+	}
+	if filter.BlockHash != "" {
+		query = query.Where("block_hash = ?", filter.BlockHash)
+	}
+	if filter.ContractCreation != nil {
+		if *filter.ContractCreation {
+			query = query.Where(`"to" = ''`)
+		} else {
+			query = query.Where(`"to" <> ''`)
+		}
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+	if filter.FromBlock != nil {
+		query = query.Where("block_number >= ?", *filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		query = query.Where("block_number <= ?", *filter.ToBlock)
+	}
+	if filter.MinValue != nil {
+		query = query.Where("value >= ?", *filter.MinValue)
+	}
+	if filter.MaxValue != nil {
+		query = query.Where("value <= ?", *filter.MaxValue)
+	}
+	if filter.MinGasPrice != nil {
+		query = query.Where("gas_price >= ?", *filter.MinGasPrice)
+	}
+	if filter.MaxGasPrice != nil {
+		query = query.Where("gas_price <= ?", *filter.MaxGasPrice)
+	}
+	if filter.MethodSelector != nil {
+		query = query.Where("substr(data, 1, 4) = ?", filter.MethodSelector)
+	}
 	var txs []*data.Transaction
-	if err := g.Find(&txs).Error; err != nil {
+	err = query.Order(txSortOrders[filter.Sort]).
+		Limit(filter.limit()).Offset(filter.offset()).
+		Find(&txs).Error
+	if err != nil {
 		return nil, err
 	}
 	return txs, nil
 }
+
+// resolveTimeRange narrows the block range [from, to] to the blocks mined in
+// [fromTime, toTime], using the index on block time. It returns false when no
+// stored block falls in the time range.
+func resolveTimeRange(db *gorm.DB, chainID uint64, from, to **uint64, fromTime, toTime *uint64) (bool, error) {
+	if fromTime != nil {
+		block, err := blockAtOrAfter(db, chainID, *fromTime)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if *from == nil || **from < block.Number {
+			*from = &block.Number
+		}
+	}
+	if toTime != nil {
+		block, err := blockAtOrBefore(db, chainID, *toTime)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if *to == nil || **to > block.Number {
+			*to = &block.Number
+		}
+	}
+	return true, nil
+}
@@ -1,59 +1,81 @@
 package db
 
 import (
+	"context"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/CaelRowley/geth-indexer-service/pkg/data"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var mockTxs = []data.Transaction{
 	{
-		Hash:      "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
-		From:      "0x0000000000000000000000000000000000000001",
-		To:        "0x0000000000000000000000000000000000000002",
-		Contract:  "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
-		Value:     200,
-		Data:      []byte("some data"),
-		Gas:       500000,
-		GasPrice:  1000000,
-		Cost:      1000000000,
-		Nonce:     0,
-		Status:    0,
-		BlockHash: "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+		Hash:        "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+		From:        "0x0000000000000000000000000000000000000001",
+		To:          "0x0000000000000000000000000000000000000002",
+		Contract:    "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+		Value:       200,
+		Data:        []byte("some data"),
+		Gas:         500000,
+		GasPrice:    1000000,
+		Cost:        1000000000,
+		Nonce:       0,
+		Status:      0,
+		BlockHash:   "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+		BlockNumber: 1,
 	},
 	{
 		Hash: "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
 		From: "0x0000000000000000000000000000000000000003",
 		// To:        ,
-		Contract:  "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
-		Value:     200,
-		Data:      []byte("some other data"),
-		Gas:       500000,
-		GasPrice:  1000000,
-		Cost:      1000000000,
-		Nonce:     0,
-		Status:    0,
-		BlockHash: "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+		Contract:    "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+		Value:       200,
+		Data:        []byte("some other data"),
+		Gas:         500000,
+		GasPrice:    1000000,
+		Cost:        1000000000,
+		Nonce:       0,
+		Status:      0,
+		BlockHash:   "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+		BlockNumber: 1,
 	},
 }
 
 func TestInsertTx(t *testing.T) {
 	s := newSuite(t)
 
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(
+		`CREATE TABLE IF NOT EXISTS "transactions_p000000000000" PARTITION OF "transactions" FOR VALUES FROM (0) TO (100000)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
 	s.sqlMock.ExpectBegin()
 	s.sqlMock.ExpectExec(regexp.QuoteMeta(
-		`INSERT INTO "transactions" ("hash","from","to","contract","value","data","gas","gas_price","cost","nonce","status","block_hash") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)`)).
+		`INSERT INTO "transactions" ("chain_id","hash","from","to","contract","value","data","gas","gas_price","cost","nonce","status","block_hash","block_number","priority_fee","gas_used") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16)`)).
 		WithArgs(
-			mockTxs[0].Hash, mockTxs[0].From, mockTxs[0].To, mockTxs[0].Contract, mockTxs[0].Value, mockTxs[0].Data,
-			mockTxs[0].Gas, mockTxs[0].GasPrice, mockTxs[0].Cost, mockTxs[0].Nonce, mockTxs[0].Status, mockTxs[0].BlockHash,
+			mockChainID, mockTxs[0].Hash, mockTxs[0].From, mockTxs[0].To, mockTxs[0].Contract, mockTxs[0].Value, mockTxs[0].Data,
+			mockTxs[0].Gas, mockTxs[0].GasPrice, mockTxs[0].Cost, mockTxs[0].Nonce, mockTxs[0].Status, mockTxs[0].BlockHash, mockTxs[0].BlockNumber, mockTxs[0].PriorityFee,
+			mockTxs[0].GasUsed,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	// The sender's and recipient's summaries are created and updated.
+	for _, address := range []string{mockTxs[0].From, mockTxs[0].To} {
+		s.sqlMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "addresses"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		s.sqlMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "addresses" WHERE chain_id = $1 AND address = $2 LIMIT $3 FOR UPDATE`)).
+			WithArgs(mockChainID, address, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"address", "first_seen_block", "last_seen_block", "value_sent", "value_received"}).
+				AddRow(address, mockTxs[0].BlockNumber, mockTxs[0].BlockNumber, "0", "0"))
+		s.sqlMock.ExpectExec(regexp.QuoteMeta(`UPDATE "addresses" SET`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	s.sqlMock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO "stats_dirty" ("chain_id", "period", "time") SELECT "chain_id", 'hourly', "time" - "time" % 3600 FROM "blocks" WHERE "chain_id" = $1 AND "number" = $2 ON CONFLICT DO NOTHING`)).
+		WithArgs(mockChainID, mockTxs[0].BlockNumber).
+		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	s.sqlMock.ExpectCommit()
-	err := s.dbMock.InsertTx(mockTxs[0])
+	err := s.dbMock.InsertTx(context.Background(), mockTxs[0])
 	assert.NoError(t, err)
 	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
 }
@@ -62,16 +84,16 @@ func TestGetTxByHash(t *testing.T) {
 	s := newSuite(t)
 
 	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT * FROM "transactions" WHERE hash = $1 ORDER BY "transactions"."hash" LIMIT $2`)).
-		WithArgs(mockTxs[0].Hash, 1).
+		`SELECT * FROM "transactions" WHERE (chain_id = $1 AND hash = $2) AND block_number = $3 ORDER BY block_number asc LIMIT $4`)).
+		WithArgs(mockChainID, mockTxs[0].Hash, mockTxs[0].BlockNumber, 1).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"hash", "from", "to", "contract", "value", "data", "gas", "gas_price", "cost", "nonce", "status", "block_hash",
+			"hash", "from", "to", "contract", "value", "data", "gas", "gas_price", "cost", "nonce", "status", "block_hash", "block_number",
 		}).AddRow(
 			mockTxs[0].Hash, mockTxs[0].From, mockTxs[0].To, mockTxs[0].Contract, mockTxs[0].Value, mockTxs[0].Data,
-			mockTxs[0].Gas, mockTxs[0].GasPrice, mockTxs[0].Cost, mockTxs[0].Nonce, mockTxs[0].Status, mockTxs[0].BlockHash,
+			mockTxs[0].Gas, mockTxs[0].GasPrice, mockTxs[0].Cost, mockTxs[0].Nonce, mockTxs[0].Status, mockTxs[0].BlockHash, mockTxs[0].BlockNumber,
 		))
 
-	retrievedBlock, err := s.dbMock.GetTxByHash(mockTxs[0].Hash)
+	retrievedBlock, err := s.dbMock.GetTxByHash(context.Background(), mockTxs[0].Hash, &mockTxs[0].BlockNumber)
 	assert.NoError(t, err)
 	assert.Equal(t, &mockTxs[0], retrievedBlock)
 	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
@@ -83,16 +105,16 @@ func TestGetTxs(t *testing.T) {
 	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
 		`SELECT * FROM "transactions"`)).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"hash", "from", "to", "contract", "value", "data", "gas", "gas_price", "cost", "nonce", "status", "block_hash",
+			"hash", "from", "to", "contract", "value", "data", "gas", "gas_price", "cost", "nonce", "status", "block_hash", "block_number",
 		}).AddRow(
 			mockTxs[0].Hash, mockTxs[0].From, mockTxs[0].To, mockTxs[0].Contract, mockTxs[0].Value, mockTxs[0].Data,
-			mockTxs[0].Gas, mockTxs[0].GasPrice, mockTxs[0].Cost, mockTxs[0].Nonce, mockTxs[0].Status, mockTxs[0].BlockHash,
+			mockTxs[0].Gas, mockTxs[0].GasPrice, mockTxs[0].Cost, mockTxs[0].Nonce, mockTxs[0].Status, mockTxs[0].BlockHash, mockTxs[0].BlockNumber,
 		).AddRow(
 			mockTxs[1].Hash, mockTxs[1].From, mockTxs[1].To, mockTxs[1].Contract, mockTxs[1].Value, mockTxs[1].Data,
-			mockTxs[1].Gas, mockTxs[1].GasPrice, mockTxs[1].Cost, mockTxs[1].Nonce, mockTxs[1].Status, mockTxs[1].BlockHash,
+			mockTxs[1].Gas, mockTxs[1].GasPrice, mockTxs[1].Cost, mockTxs[1].Nonce, mockTxs[1].Status, mockTxs[1].BlockHash, mockTxs[1].BlockNumber,
 		))
 
-	retrievedBlocks, err := s.dbMock.GetTxs()
+	retrievedBlocks, err := s.dbMock.GetTxs(context.Background())
 	assert.NoError(t, err)
 	assert.Len(t, retrievedBlocks, len(mockTxs))
 	for i, retrievedBlock := range retrievedBlocks {
@@ -100,3 +122,139 @@ func TestGetTxs(t *testing.T) {
 	}
 	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
 }
+
+func TestGetTxsByHashes(t *testing.T) {
+	s := newSuite(t)
+
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "transactions" WHERE chain_id = $1 AND hash IN ($2)`)).
+		WithArgs(mockChainID, mockTxs[0].Hash).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"hash", "from", "to", "contract", "value", "data", "gas", "gas_price", "cost", "nonce", "status", "block_hash", "block_number",
+		}).AddRow(
+			mockTxs[0].Hash, mockTxs[0].From, mockTxs[0].To, mockTxs[0].Contract, mockTxs[0].Value, mockTxs[0].Data,
+			mockTxs[0].Gas, mockTxs[0].GasPrice, mockTxs[0].Cost, mockTxs[0].Nonce, mockTxs[0].Status, mockTxs[0].BlockHash, mockTxs[0].BlockNumber,
+		))
+
+	retrievedTxs, err := s.dbMock.GetTxsByHashes(context.Background(), []string{mockTxs[0].Hash}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []*data.Transaction{&mockTxs[0]}, retrievedTxs)
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+func TestGetTxsByBlockHashes(t *testing.T) {
+	s := newSuite(t)
+
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "transactions" WHERE (chain_id = $1 AND block_hash IN ($2)) AND block_number IN ($3) ORDER BY block_number asc, hash asc`)).
+		WithArgs(mockChainID, mockTxs[0].BlockHash, mockTxs[0].BlockNumber).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"hash", "from", "to", "contract", "value", "data", "gas", "gas_price", "cost", "nonce", "status", "block_hash", "block_number",
+		}).AddRow(
+			mockTxs[0].Hash, mockTxs[0].From, mockTxs[0].To, mockTxs[0].Contract, mockTxs[0].Value, mockTxs[0].Data,
+			mockTxs[0].Gas, mockTxs[0].GasPrice, mockTxs[0].Cost, mockTxs[0].Nonce, mockTxs[0].Status, mockTxs[0].BlockHash, mockTxs[0].BlockNumber,
+		).AddRow(
+			mockTxs[1].Hash, mockTxs[1].From, mockTxs[1].To, mockTxs[1].Contract, mockTxs[1].Value, mockTxs[1].Data,
+			mockTxs[1].Gas, mockTxs[1].GasPrice, mockTxs[1].Cost, mockTxs[1].Nonce, mockTxs[1].Status, mockTxs[1].BlockHash, mockTxs[1].BlockNumber,
+		))
+
+	retrievedTxs, err := s.dbMock.GetTxsByBlockHashes(context.Background(), []string{mockTxs[0].BlockHash}, []uint64{mockTxs[0].BlockNumber})
+	assert.NoError(t, err)
+	assert.Len(t, retrievedTxs, len(mockTxs))
+	for i, retrievedTx := range retrievedTxs {
+		assert.Equal(t, &mockTxs[i], retrievedTx)
+	}
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+func TestFindTxs(t *testing.T) {
+	s := newSuite(t)
+
+	fromBlock := uint64(1)
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "transactions" WHERE chain_id = $1 AND "from" = $2 AND "to" = $3 AND block_number >= $4 ORDER BY block_number asc, hash asc LIMIT $5`)).
+		WithArgs(mockChainID, mockTxs[0].From, mockTxs[0].To, fromBlock, DefaultPageSize).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"hash", "from", "to", "contract", "value", "data", "gas", "gas_price", "cost", "nonce", "status", "block_hash", "block_number",
+		}).AddRow(
+			mockTxs[0].Hash, mockTxs[0].From, mockTxs[0].To, mockTxs[0].Contract, mockTxs[0].Value, mockTxs[0].Data,
+			mockTxs[0].Gas, mockTxs[0].GasPrice, mockTxs[0].Cost, mockTxs[0].Nonce, mockTxs[0].Status, mockTxs[0].BlockHash, mockTxs[0].BlockNumber,
+		))
+
+	retrievedTxs, err := s.dbMock.FindTxs(context.Background(), TxFilter{
+		From:      mockTxs[0].From,
+		To:        mockTxs[0].To,
+		FromBlock: &fromBlock,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*data.Transaction{&mockTxs[0]}, retrievedTxs)
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+func TestFindTxsTimeRange(t *testing.T) {
+	s := newSuite(t)
+
+	fromTime := uint64(1700000000)
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "blocks" WHERE chain_id = $1 AND time >= $2 ORDER BY time asc, number asc LIMIT $3`)).
+		WithArgs(mockChainID, fromTime, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"number"}).AddRow(19000000))
+	s.sqlMock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "transactions" WHERE chain_id = $1 AND "to" = $2 AND status = $3 AND block_number >= $4 ORDER BY block_number desc, hash desc LIMIT $5`)).
+		WithArgs(mockChainID, mockTxs[0].To, 0, 19000000, DefaultPageSize).
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+
+	status := uint64(0)
+	_, err := s.dbMock.FindTxs(context.Background(), TxFilter{
+		To:       mockTxs[0].To,
+		Status:   &status,
+		FromTime: &fromTime,
+		Sort:     TxSortBlockNumberDesc,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, s.sqlMock.ExpectationsWereMet())
+}
+
+func TestTxFilterValidate(t *testing.T) {
+	status := uint64(1)
+	yes, no := true, false
+	low, high := uint64(1), uint64(2)
+	wide := uint64(maxUnindexedBlocks + 1)
+	dayAgo, weekAgo := uint64(time.Now().Add(-23*time.Hour).Unix()), uint64(time.Now().Add(-7*24*time.Hour).Unix())
+	tests := []struct {
+		name   string
+		filter TxFilter
+		field  string
+	}{
+		{"empty", TxFilter{}, ""},
+		{"indexed", TxFilter{To: mockTxs[0].To, Status: &status}, ""},
+		{"block range", TxFilter{FromBlock: &low, ToBlock: &high, MethodSelector: []byte{1, 2, 3, 4}}, ""},
+		{"open block range", TxFilter{FromBlock: &low, MethodSelector: []byte{1, 2, 3, 4}}, "filter"},
+		{"wide block range", TxFilter{FromBlock: &low, ToBlock: &wide, Status: &status}, "filter"},
+		{"time range", TxFilter{FromTime: &dayAgo, Status: &status}, ""},
+		{"wide time range", TxFilter{FromTime: &weekAgo, Status: &status}, "filter"},
+		{"contract creation", TxFilter{ContractCreation: &yes, Status: &status}, ""},
+		{"unindexed", TxFilter{Status: &status}, "filter"},
+		{"contract calls unindexed", TxFilter{ContractCreation: &no}, "filter"},
+		{"selector length", TxFilter{From: mockTxs[0].From, MethodSelector: []byte{1}}, "method"},
+		{"empty range", TxFilter{From: mockTxs[0].From, MinValue: &high, MaxValue: &low}, "value"},
+		{"creation with to", TxFilter{To: mockTxs[0].To, ContractCreation: &yes}, "contractCreation"},
+		{"sort", TxFilter{Sort: "nonce"}, "sort"},
+		{"block sort", TxFilter{Sort: TxSortBlockNumberDesc}, ""},
+		{"indexed value sort", TxFilter{From: mockTxs[0].From, Sort: TxSortValueDesc}, ""},
+		{"unindexed value sort", TxFilter{FromBlock: &low, ToBlock: &high, Sort: TxSortValue}, "sort"},
+		{"unindexed gas price sort", TxFilter{Sort: TxSortGasPriceDesc}, "sort"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.field == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var filterErr *FilterError
+			require.ErrorAs(t, err, &filterErr)
+			assert.Equal(t, tt.field, filterErr.Field)
+		})
+	}
+}
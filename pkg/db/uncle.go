@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InsertUncle stores an uncle and adds its reward to its miner's totals,
+// unless it was already stored.
+func (g *GormDB) InsertUncle(ctx context.Context, uncle data.Uncle) error {
+	db, cancel := g.write(ctx)
+	defer cancel()
+	uncle.ChainID = g.chainID
+	return db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&uncle)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return applyMinerUncle(tx, &uncle, 1)
+	})
+}
+
+// GetUncles returns the uncles included by a block, in inclusion order.
+func (g *GormDB) GetUncles(ctx context.Context, blockHash string) ([]*data.Uncle, error) {
+	db, cancel := g.read(ctx)
+	defer cancel()
+	uncles := []*data.Uncle{}
+	err := db.Where("chain_id = ? AND block_hash = ?", g.chainID, blockHash).Order("uncle_index asc").Find(&uncles).Error
+	if err != nil {
+		return nil, err
+	}
+	return uncles, nil
+}
@@ -0,0 +1,296 @@
+// Package decode decodes transaction calldata and logs with the contract ABIs
+// and signatures stored in the database.
+package decode
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Sources of a decoded call or event.
+const (
+	SourceABI       = "abi"
+	SourceSignature = "signature"
+)
+
+// ParseABI parses a JSON contract ABI.
+func ParseABI(s string) (*abi.ABI, error) {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// Signatures returns the signatures of the functions and non-anonymous
+// events declared by an ABI, ordered by signature.
+func Signatures(contractABI *abi.ABI) []data.Signature {
+	signatures := []data.Signature{}
+	for _, method := range contractABI.Methods {
+		signatures = append(signatures, data.Signature{Hash: hexutil.Encode(method.ID), Signature: method.Sig})
+	}
+	for _, event := range contractABI.Events {
+		if !event.Anonymous {
+			signatures = append(signatures, data.Signature{Hash: event.ID.Hex(), Signature: event.Sig})
+		}
+	}
+	sort.Slice(signatures, func(i, j int) bool {
+		return signatures[i].Signature < signatures[j].Signature
+	})
+	return signatures
+}
+
+// FunctionSignature parses a text function signature such as
+// transfer(address,uint256) into its canonical form and selector.
+func FunctionSignature(s string) (data.Signature, error) {
+	parsed, err := parseSignature(s, "function")
+	if err != nil {
+		return data.Signature{}, err
+	}
+	for _, method := range parsed.Methods {
+		return data.Signature{Hash: hexutil.Encode(method.ID), Signature: method.Sig}, nil
+	}
+	return data.Signature{}, fmt.Errorf("invalid function signature %q", s)
+}
+
+// EventSignature parses a text event signature such as
+// Transfer(address,address,uint256) into its canonical form and topic.
+func EventSignature(s string) (data.Signature, error) {
+	parsed, err := parseSignature(s, "event")
+	if err != nil {
+		return data.Signature{}, err
+	}
+	for _, event := range parsed.Events {
+		return data.Signature{Hash: event.ID.Hex(), Signature: event.Sig}, nil
+	}
+	return data.Signature{}, fmt.Errorf("invalid event signature %q", s)
+}
+
+// parseSignature parses a text signature into an ABI with a single function
+// or event whose arguments have placeholder names.
+func parseSignature(s, kind string) (*abi.ABI, error) {
+	selector, err := abi.ParseSelector(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		return nil, err
+	}
+	selector.Type = kind
+	canonicalTypes(selector.Inputs)
+	abiJSON, err := json.Marshal([]abi.SelectorMarshaling{selector})
+	if err != nil {
+		return nil, err
+	}
+	return ParseABI(string(abiJSON))
+}
+
+// canonicalTypes replaces the uint and int aliases with uint256 and int256,
+// which go-ethereum doesn't accept.
+func canonicalTypes(args []abi.ArgumentMarshaling) {
+	for i := range args {
+		for _, alias := range []string{"uint", "int"} {
+			if rest, ok := strings.CutPrefix(args[i].Type, alias); ok && (rest == "" || rest[0] == '[') {
+				args[i].Type = alias + "256" + rest
+			}
+		}
+		canonicalTypes(args[i].Components)
+	}
+}
+
+// CallSelector returns the function selector of calldata, or "" if it is
+// too short to have one.
+func CallSelector(input []byte) string {
+	if len(input) < 4 {
+		return ""
+	}
+	return hexutil.Encode(input[:4])
+}
+
+// Call decodes calldata with the ABI of the called contract, which may be
+// nil, and otherwise with the first of the selector's signatures that
+// encodes the arguments exactly. It returns nil if neither decodes it.
+func Call(contractABI *abi.ABI, signatures []string, input []byte) *data.Decoded {
+	if len(input) < 4 {
+		return nil
+	}
+	if contractABI != nil {
+		if method, err := contractABI.MethodById(input[:4]); err == nil {
+			if values, err := method.Inputs.Unpack(input[4:]); err == nil {
+				return &data.Decoded{
+					Name:      method.RawName,
+					Signature: method.Sig,
+					Source:    SourceABI,
+					Args:      decodedArgs(method.Inputs, values, true),
+				}
+			}
+		}
+	}
+	for _, signature := range signatures {
+		parsed, err := parseSignature(signature, "function")
+		if err != nil {
+			continue
+		}
+		method, err := parsed.MethodById(input[:4])
+		if err != nil {
+			continue
+		}
+		values, err := method.Inputs.Unpack(input[4:])
+		if err != nil {
+			continue
+		}
+		if packed, err := method.Inputs.Pack(values...); err != nil || !bytes.Equal(packed, input[4:]) {
+			continue
+		}
+		return &data.Decoded{
+			Name:      method.RawName,
+			Signature: method.Sig,
+			Source:    SourceSignature,
+			Args:      decodedArgs(method.Inputs, values, false),
+		}
+	}
+	return nil
+}
+
+// Event decodes a log with the ABI of the emitting contract, which may be
+// nil, and otherwise with the signatures of its first topic. Text signatures
+// don't say which arguments are indexed, so the leading arguments are
+// assumed to be, one per remaining topic. It returns nil if neither decodes
+// the log.
+func Event(contractABI *abi.ABI, signatures []string, log *data.Log) *data.Decoded {
+	topics := log.Topics()
+	if len(topics) == 0 {
+		return nil
+	}
+	if contractABI != nil {
+		if event, err := contractABI.EventByID(common.HexToHash(topics[0])); err == nil {
+			if args, ok := decodeEvent(event.Inputs, topics[1:], log.Data, true); ok {
+				return &data.Decoded{Name: event.RawName, Signature: event.Sig, Source: SourceABI, Args: args}
+			}
+		}
+	}
+	for _, signature := range signatures {
+		parsed, err := parseSignature(signature, "event")
+		if err != nil {
+			continue
+		}
+		event, err := parsed.EventByID(common.HexToHash(topics[0]))
+		if err != nil || len(topics)-1 > len(event.Inputs) {
+			continue
+		}
+		inputs := make(abi.Arguments, len(event.Inputs))
+		copy(inputs, event.Inputs)
+		for i := range inputs {
+			inputs[i].Indexed = i < len(topics)-1
+		}
+		if args, ok := decodeEvent(inputs, topics[1:], log.Data, false); ok {
+			return &data.Decoded{Name: event.RawName, Signature: event.Sig, Source: SourceSignature, Args: args}
+		}
+	}
+	return nil
+}
+
+// decodeEvent decodes the indexed inputs from topics and the others from
+// logData, which must encode them exactly.
+func decodeEvent(inputs abi.Arguments, topics []string, logData []byte, named bool) ([]data.DecodedArg, bool) {
+	nonIndexed := inputs.NonIndexed()
+	if len(topics) != len(inputs)-len(nonIndexed) {
+		return nil, false
+	}
+	values, err := nonIndexed.Unpack(logData)
+	if err != nil {
+		return nil, false
+	}
+	if packed, err := nonIndexed.Pack(values...); err != nil || !bytes.Equal(packed, logData) {
+		return nil, false
+	}
+	args := make([]data.DecodedArg, 0, len(inputs))
+	for _, input := range inputs {
+		arg := data.DecodedArg{Type: input.Type.String(), Indexed: input.Indexed}
+		if named {
+			arg.Name = input.Name
+		}
+		if input.Indexed {
+			topic := common.HexToHash(topics[0])
+			topics = topics[1:]
+			arg.Value = topic.Hex()
+			if isWord(input.Type) {
+				value, err := abi.Arguments{{Type: input.Type}}.Unpack(topic.Bytes())
+				if err != nil {
+					return nil, false
+				}
+				arg.Value = formatValue(input.Type, value[0], named)
+			}
+		} else {
+			arg.Value = formatValue(input.Type, values[0], named)
+			values = values[1:]
+		}
+		args = append(args, arg)
+	}
+	return args, true
+}
+
+// isWord reports whether values of t are stored in indexed topics, instead
+// of their hash.
+func isWord(t abi.Type) bool {
+	switch t.T {
+	case abi.IntTy, abi.UintTy, abi.BoolTy, abi.AddressTy, abi.FixedBytesTy, abi.HashTy:
+		return true
+	}
+	return false
+}
+
+func decodedArgs(inputs abi.Arguments, values []any, named bool) []data.DecodedArg {
+	args := make([]data.DecodedArg, len(inputs))
+	for i, input := range inputs {
+		args[i] = data.DecodedArg{Type: input.Type.String(), Value: formatValue(input.Type, values[i], named)}
+		if named {
+			args[i].Name = input.Name
+		}
+	}
+	return args
+}
+
+// formatValue converts a value unpacked by go-ethereum into its JSON form,
+// described on data.DecodedArg.
+func formatValue(t abi.Type, v any, named bool) any {
+	rv := reflect.ValueOf(v)
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		if n, ok := v.(*big.Int); ok {
+			return n.String()
+		}
+		return fmt.Sprint(v)
+	case abi.AddressTy:
+		return v.(common.Address).Hex()
+	case abi.BytesTy:
+		return hexutil.Encode(v.([]byte))
+	case abi.FixedBytesTy, abi.HashTy, abi.FunctionTy:
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return hexutil.Encode(b)
+	case abi.SliceTy, abi.ArrayTy:
+		values := make([]any, rv.Len())
+		for i := range values {
+			values[i] = formatValue(*t.Elem, rv.Index(i).Interface(), named)
+		}
+		return values
+	case abi.TupleTy:
+		args := make([]data.DecodedArg, len(t.TupleElems))
+		for i, elem := range t.TupleElems {
+			args[i] = data.DecodedArg{Type: elem.String(), Value: formatValue(*elem, rv.Field(i).Interface(), named)}
+			if named {
+				args[i].Name = t.TupleRawNames[i]
+			}
+		}
+		return args
+	}
+	return v
+}
@@ -0,0 +1,154 @@
+package decode
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const erc20ABI = `[
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"type":"bool"}]},
+	{"type":"function","name":"swap","inputs":[{"name":"path","type":"address[]"},{"name":"order","type":"tuple","components":[{"name":"id","type":"uint64"},{"name":"salt","type":"bytes32"}]}],"outputs":[]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256"}]}
+]`
+
+var (
+	from = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	to   = common.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+func transferInput(t *testing.T) []byte {
+	parsed, err := ParseABI(erc20ABI)
+	require.NoError(t, err)
+	input, err := parsed.Pack("transfer", to, big.NewInt(1000))
+	require.NoError(t, err)
+	return input
+}
+
+func TestSignatures(t *testing.T) {
+	sig, err := FunctionSignature("transfer(address, uint)")
+	require.NoError(t, err)
+	assert.Equal(t, data.Signature{Hash: "0xa9059cbb", Signature: "transfer(address,uint256)"}, sig)
+
+	sig, err = EventSignature("Transfer(address,address,uint256)")
+	require.NoError(t, err)
+	assert.Equal(t, "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", sig.Hash)
+
+	_, err = FunctionSignature("transfer(address")
+	assert.Error(t, err)
+
+	parsed, err := ParseABI(erc20ABI)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []data.Signature{
+		{Hash: "0xa9059cbb", Signature: "transfer(address,uint256)"},
+		{Hash: "0xb3086a6c", Signature: "swap(address[],(uint64,bytes32))"},
+		{Hash: "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", Signature: "Transfer(address,address,uint256)"},
+	}, Signatures(parsed))
+}
+
+func TestCall(t *testing.T) {
+	parsed, err := ParseABI(erc20ABI)
+	require.NoError(t, err)
+	input := transferInput(t)
+
+	assert.Equal(t, &data.Decoded{
+		Name: "transfer", Signature: "transfer(address,uint256)", Source: SourceABI,
+		Args: []data.DecodedArg{
+			{Name: "to", Type: "address", Value: to.Hex()},
+			{Name: "amount", Type: "uint256", Value: "1000"},
+		},
+	}, Call(parsed, nil, input))
+
+	// Without the ABI the first signature that encodes the arguments exactly
+	// wins, the colliding one doesn't fit the calldata.
+	assert.Equal(t, &data.Decoded{
+		Name: "transfer", Signature: "transfer(address,uint256)", Source: SourceSignature,
+		Args: []data.DecodedArg{
+			{Type: "address", Value: to.Hex()},
+			{Type: "uint256", Value: "1000"},
+		},
+	}, Call(nil, []string{"many_msg_babbage(bytes1)", "transfer(address,uint256)"}, input))
+
+	assert.Nil(t, Call(nil, []string{"many_msg_babbage(bytes1)"}, input))
+	assert.Nil(t, Call(parsed, nil, input[:3]))
+	assert.Nil(t, Call(nil, nil, input))
+}
+
+func TestCallNestedTypes(t *testing.T) {
+	parsed, err := ParseABI(erc20ABI)
+	require.NoError(t, err)
+	order := struct {
+		Id   uint64
+		Salt [32]byte
+	}{7, [32]byte{1}}
+	input, err := parsed.Pack("swap", []common.Address{from, to}, order)
+	require.NoError(t, err)
+
+	decoded := Call(parsed, nil, input)
+	require.NotNil(t, decoded)
+	assert.Equal(t, []data.DecodedArg{
+		{Name: "path", Type: "address[]", Value: []any{from.Hex(), to.Hex()}},
+		{Name: "order", Type: "(uint64,bytes32)", Value: []data.DecodedArg{
+			{Name: "id", Type: "uint64", Value: "7"},
+			{Name: "salt", Type: "bytes32", Value: hexutil.Encode(order.Salt[:])},
+		}},
+	}, decoded.Args)
+}
+
+func TestEvent(t *testing.T) {
+	parsed, err := ParseABI(erc20ABI)
+	require.NoError(t, err)
+	transfer := parsed.Events["Transfer"].ID.Hex()
+	fromTopic := common.BytesToHash(from.Bytes()).Hex()
+	toTopic := common.BytesToHash(to.Bytes()).Hex()
+	erc20Log := &data.Log{
+		Topic0: transfer, Topic1: fromTopic, Topic2: toTopic,
+		Data: common.BigToHash(big.NewInt(5)).Bytes(),
+	}
+	signatures := []string{"Transfer(address,address,uint256)"}
+
+	assert.Equal(t, &data.Decoded{
+		Name: "Transfer", Signature: "Transfer(address,address,uint256)", Source: SourceABI,
+		Args: []data.DecodedArg{
+			{Name: "from", Type: "address", Indexed: true, Value: from.Hex()},
+			{Name: "to", Type: "address", Indexed: true, Value: to.Hex()},
+			{Name: "value", Type: "uint256", Value: "5"},
+		},
+	}, Event(parsed, signatures, erc20Log))
+
+	// An ERC-721 Transfer has the same topic but indexes the token id, so
+	// only the signature decodes it.
+	erc721Log := &data.Log{
+		Topic0: transfer, Topic1: fromTopic, Topic2: toTopic,
+		Topic3: common.BigToHash(big.NewInt(42)).Hex(),
+	}
+	assert.Equal(t, &data.Decoded{
+		Name: "Transfer", Signature: "Transfer(address,address,uint256)", Source: SourceSignature,
+		Args: []data.DecodedArg{
+			{Type: "address", Indexed: true, Value: from.Hex()},
+			{Type: "address", Indexed: true, Value: to.Hex()},
+			{Type: "uint256", Indexed: true, Value: "42"},
+		},
+	}, Event(parsed, signatures, erc721Log))
+
+	// Indexed strings are only logged as their hash.
+	named, err := EventSignature("Named(string,uint256)")
+	require.NoError(t, err)
+	nameTopic := crypto.Keccak256Hash([]byte("alice")).Hex()
+	namedLog := &data.Log{Topic0: named.Hash, Topic1: nameTopic, Data: common.BigToHash(big.NewInt(1)).Bytes()}
+	assert.Equal(t, []data.DecodedArg{
+		{Type: "string", Indexed: true, Value: nameTopic},
+		{Type: "uint256", Value: "1"},
+	}, Event(nil, []string{named.Signature}, namedLog).Args)
+
+	namedLog.Data = nil
+	assert.Nil(t, Event(nil, []string{named.Signature}, namedLog), "data doesn't encode the other arguments")
+
+	assert.Nil(t, Event(parsed, nil, &data.Log{}))
+}
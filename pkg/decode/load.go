@@ -0,0 +1,125 @@
+package decode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Txs sets the decoded method of the transactions that call a contract. The
+// ABIs of the called contracts are fetched with one query, and the
+// signatures of the selectors they don't declare with another.
+func Txs(ctx context.Context, dbConn db.DB, txs []*data.Transaction) error {
+	var addresses []string
+	for _, tx := range txs {
+		if tx.To != "" && CallSelector(tx.Data) != "" {
+			addresses = append(addresses, tx.To)
+		}
+	}
+	if len(addresses) == 0 {
+		return nil
+	}
+	abis, err := LoadABIs(ctx, dbConn, addresses)
+	if err != nil {
+		return err
+	}
+	var selectors []string
+	for _, tx := range txs {
+		selector := CallSelector(tx.Data)
+		if tx.To == "" || selector == "" {
+			continue
+		}
+		if contractABI := abis[tx.To]; contractABI != nil {
+			if _, err := contractABI.MethodById(tx.Data[:4]); err == nil {
+				continue
+			}
+		}
+		selectors = append(selectors, selector)
+	}
+	signatures, err := LoadSignatures(ctx, dbConn, selectors)
+	if err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		if tx.To != "" {
+			tx.Method = Call(abis[tx.To], signatures[CallSelector(tx.Data)], tx.Data)
+		}
+	}
+	return nil
+}
+
+// Logs sets the decoded event of logs, fetching ABIs and signatures like
+// Txs.
+func Logs(ctx context.Context, dbConn db.DB, logs []*data.Log) error {
+	var addresses []string
+	for _, log := range logs {
+		if log.Topic0 != "" {
+			addresses = append(addresses, log.Address)
+		}
+	}
+	if len(addresses) == 0 {
+		return nil
+	}
+	abis, err := LoadABIs(ctx, dbConn, addresses)
+	if err != nil {
+		return err
+	}
+	var topics []string
+	for _, log := range logs {
+		if log.Topic0 == "" {
+			continue
+		}
+		if contractABI := abis[log.Address]; contractABI != nil {
+			if _, err := contractABI.EventByID(common.HexToHash(log.Topic0)); err == nil {
+				continue
+			}
+		}
+		topics = append(topics, log.Topic0)
+	}
+	signatures, err := LoadSignatures(ctx, dbConn, topics)
+	if err != nil {
+		return err
+	}
+	for _, log := range logs {
+		log.Event = Event(abis[log.Address], signatures[log.Topic0], log)
+	}
+	return nil
+}
+
+// LoadABIs returns the parsed ABIs of the contracts at addresses that have
+// one.
+func LoadABIs(ctx context.Context, dbConn db.DB, addresses []string) (map[string]*abi.ABI, error) {
+	abis, err := dbConn.GetContractABIs(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*abi.ABI, len(abis))
+	for _, contractABI := range abis {
+		parsed, err := ParseABI(contractABI.ABI)
+		if err != nil {
+			return nil, fmt.Errorf("invalid abi stored for %s: %w", contractABI.Address, err)
+		}
+		result[contractABI.Address] = parsed
+	}
+	return result, nil
+}
+
+// LoadSignatures returns the text signatures of the selectors in hashes.
+func LoadSignatures(ctx context.Context, dbConn db.DB, hashes []string) (map[string][]string, error) {
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+	signatures, err := dbConn.GetSignatures(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]string, len(hashes))
+	for _, signature := range signatures {
+		result[signature.Hash] = append(result[signature.Hash], signature.Signature)
+	}
+	return result, nil
+}
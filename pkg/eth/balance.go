@@ -0,0 +1,93 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// balanceBatchSize is the number of addresses whose balance and nonce are
+// fetched per batch request.
+const balanceBatchSize = 100
+
+// publishBalances publishes the balance and nonce after block of the miner,
+// the withdrawal recipients and the other touched addresses.
+func (c EthClient) publishBalances(ctx context.Context, block *types.Block, touched []common.Address) error {
+	touched = append(touched, block.Coinbase())
+	for _, withdrawal := range block.Withdrawals() {
+		touched = append(touched, withdrawal.Address)
+	}
+	balances, err := c.batchBalances(ctx, block, uniqueAddresses(touched))
+	if err != nil {
+		return err
+	}
+	for _, balance := range balances {
+		balanceData, err := json.Marshal(balance)
+		if err != nil {
+			return err
+		}
+		if err := c.PubSub.GetPublisher().PublishBalance(balanceData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchBalances fetches the balances and nonces of addresses at block with
+// batched eth_getBalance and eth_getTransactionCount calls.
+func (c EthClient) batchBalances(ctx context.Context, block *types.Block, addresses []common.Address) ([]data.Balance, error) {
+	at := rpc.BlockNumberOrHashWithHash(block.Hash(), true)
+	balances := make([]data.Balance, 0, len(addresses))
+	for start := 0; start < len(addresses); start += balanceBatchSize {
+		batch := addresses[start:min(start+balanceBatchSize, len(addresses))]
+		reqs := make([]rpc.BatchElem, 0, 2*len(batch))
+		for _, address := range batch {
+			reqs = append(reqs,
+				rpc.BatchElem{Method: "eth_getBalance", Args: []interface{}{address, at}, Result: new(hexutil.Big)},
+				rpc.BatchElem{Method: "eth_getTransactionCount", Args: []interface{}{address, at}, Result: new(hexutil.Uint64)},
+			)
+		}
+		if err := c.Client.Client().BatchCallContext(ctx, reqs); err != nil {
+			return nil, err
+		}
+		for i, address := range batch {
+			for _, req := range reqs[2*i : 2*i+2] {
+				if req.Error != nil {
+					return nil, fmt.Errorf("failed to get %s of %s at block %s: %w", req.Method, address.Hex(), block.Hash().Hex(), req.Error)
+				}
+			}
+			balances = append(balances, data.Balance{
+				Address:     address.Hex(),
+				BlockNumber: block.NumberU64(),
+				BlockHash:   block.Hash().Hex(),
+				Time:        block.Time(),
+				Balance:     (*big.Int)(reqs[2*i].Result.(*hexutil.Big)).String(),
+				Nonce:       uint64(*reqs[2*i+1].Result.(*hexutil.Uint64)),
+			})
+		}
+	}
+	return balances, nil
+}
+
+// uniqueAddresses returns the sorted addresses without duplicates.
+func uniqueAddresses(addresses []common.Address) []common.Address {
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
+	})
+	unique := addresses[:0]
+	for i, address := range addresses {
+		if i == 0 || address != addresses[i-1] {
+			unique = append(unique, address)
+		}
+	}
+	return unique
+}
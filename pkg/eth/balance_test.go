@@ -0,0 +1,73 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// balanceService serves eth_getBalance and eth_getTransactionCount for a
+// single block, the balance of an address is its last byte in ether.
+type balanceService struct {
+	block common.Hash
+}
+
+func (s balanceService) GetBalance(address common.Address, at rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	if hash, ok := at.Hash(); !ok || hash != s.block || !at.RequireCanonical {
+		return nil, errors.New("unknown block")
+	}
+	wei := new(big.Int).Mul(big.NewInt(int64(address[19])), big.NewInt(1e18))
+	return (*hexutil.Big)(wei), nil
+}
+
+func (s balanceService) GetTransactionCount(address common.Address, at rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	if hash, ok := at.Hash(); !ok || hash != s.block {
+		return 0, errors.New("unknown block")
+	}
+	return hexutil.Uint64(address[18]), nil
+}
+
+func TestBatchBalances(t *testing.T) {
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(7), Time: 1700000000})
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", balanceService{block.Hash()}))
+	defer server.Stop()
+	c := EthClient{Client: ethclient.NewClient(rpc.DialInProc(server))}
+
+	// More addresses than fit in a batch.
+	addresses := make([]common.Address, balanceBatchSize+20)
+	for i := range addresses {
+		addresses[i] = common.BytesToAddress([]byte{1, byte(i / 10), byte(i)})
+	}
+	balances, err := c.batchBalances(context.Background(), block, addresses)
+	require.NoError(t, err)
+	require.Len(t, balances, len(addresses))
+	assert.Equal(t, data.Balance{
+		Address:     addresses[119].Hex(),
+		BlockNumber: 7,
+		BlockHash:   block.Hash().Hex(),
+		Time:        1700000000,
+		Balance:     "119000000000000000000",
+		Nonce:       11,
+	}, balances[119])
+
+	other := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(8)})
+	_, err = c.batchBalances(context.Background(), other, addresses[:1])
+	assert.ErrorContains(t, err, "unknown block")
+}
+
+func TestUniqueAddresses(t *testing.T) {
+	a, b, c := common.HexToAddress("0x0a"), common.HexToAddress("0x0b"), common.HexToAddress("0x0c")
+	assert.Equal(t, []common.Address{a, b, c}, uniqueAddresses([]common.Address{c, a, b, a, c}))
+	assert.Empty(t, uniqueAddresses(nil))
+}
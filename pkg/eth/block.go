@@ -7,7 +7,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-func (c EthClient) publishBlock(block *types.Block) error {
+func (c EthClient) publishBlock(block *types.Block, receipts []*types.Receipt) error {
 	newBlock := data.Block{
 		Hash:        block.Hash().Hex(),
 		Number:      block.Number().Uint64(),
@@ -25,6 +25,10 @@ func (c EthClient) publishBlock(block *types.Block) error {
 		ReceiptHash: block.ReceiptHash().Hex(),
 		ExtraData:   block.Extra(),
 	}
+	if baseFee := block.BaseFee(); baseFee != nil {
+		newBlock.BaseFee = baseFee.Uint64()
+	}
+	c.rewards.setBlockRewards(&newBlock, block, receipts)
 	blockData, err := json.Marshal(newBlock)
 	if err != nil {
 		return err
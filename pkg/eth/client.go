@@ -3,9 +3,11 @@ package eth
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/CaelRowley/geth-indexer-service/pkg/db"
 	"github.com/CaelRowley/geth-indexer-service/pkg/pubsub"
+	"github.com/CaelRowley/geth-indexer-service/pkg/retention"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
@@ -18,14 +20,51 @@ type Client interface {
 type EthClient struct {
 	*ethclient.Client
 	pubsub.PubSub
+	retention retention.Policy
+	// tracer is the tracing API used to index internal transactions, see
+	// TracerDebug and TracerTrace.
+	tracer string
+	// rewards are the chain's proof of work block rewards.
+	rewards rewardRules
+	// tokens holds the addresses of the tokens whose metadata was published.
+	tokens *sync.Map
 }
 
-func NewClient(url string, pubsub pubsub.PubSub) (Client, error) {
+// NodeChainID returns the chain id of the node at url.
+func NodeChainID(url string) (uint64, error) {
+	client, err := ethclient.Dial(url)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to eth client: %w", err)
+	}
+	defer client.Close()
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve the chain id from eth client: %w", err)
+	}
+	return chainID.Uint64(), nil
+}
+
+// NewClient connects to the node at url, which must serve chainID.
+func NewClient(url string, chainID uint64, pubsub pubsub.PubSub, retention retention.Policy, tracer string) (Client, error) {
+	switch tracer {
+	case TracerNone, TracerDebug, TracerTrace:
+	default:
+		return nil, fmt.Errorf("unknown tracer %q, expected %q or %q", tracer, TracerDebug, TracerTrace)
+	}
 	client, err := ethclient.Dial(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to eth client: %w", err)
 	}
-	return &EthClient{client, pubsub}, nil
+	nodeChainID, err := client.ChainID(context.Background())
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to retrieve the chain id from eth client: %w", err)
+	}
+	if !nodeChainID.IsUint64() || nodeChainID.Uint64() != chainID {
+		client.Close()
+		return nil, fmt.Errorf("eth client serves chain %s, expected %d", nodeChainID, chainID)
+	}
+	return &EthClient{client, pubsub, retention, tracer, rewardRulesFor(nodeChainID), &sync.Map{}}, nil
 }
 
 func (c EthClient) Close() {
@@ -0,0 +1,144 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	erc165ABI = mustParseABI(`[
+		{"type":"function","name":"supportsInterface","inputs":[{"type":"bytes4"}],"outputs":[{"type":"bool"}],"stateMutability":"view"}
+	]`)
+
+	// ERC-165 interface ids.
+	erc165ID  = [4]byte{0x01, 0xff, 0xc9, 0xa7}
+	invalidID = [4]byte{0xff, 0xff, 0xff, 0xff}
+	erc165IDs = []struct {
+		standard string
+		id       [4]byte
+	}{
+		{data.StandardERC721, [4]byte{0x80, 0xac, 0x58, 0xcd}},
+		{data.StandardERC1155, [4]byte{0xd9, 0xb6, 0x7a, 0x26}},
+	}
+
+	// Function selectors that all appear in the runtime code of a contract
+	// implementing a standard, checked when it doesn't implement ERC-165.
+	selectorHeuristics = []struct {
+		standard  string
+		selectors []string
+	}{
+		// totalSupply, balanceOf, transfer, transferFrom, approve, allowance
+		{data.StandardERC20, []string{"0x18160ddd", "0x70a08231", "0xa9059cbb", "0x23b872dd", "0x095ea7b3", "0xdd62ed3e"}},
+		// ownerOf, safeTransferFrom(address,address,uint256), setApprovalForAll
+		{data.StandardERC721, []string{"0x6352211e", "0x42842e0e", "0xa22cb465"}},
+		// balanceOfBatch, safeBatchTransferFrom, setApprovalForAll
+		{data.StandardERC1155, []string{"0x4e1273f4", "0x2eb2c2d6", "0xa22cb465"}},
+	}
+)
+
+// publishContract publishes the contract deployed by a transaction.
+func (c EthClient) publishContract(ctx context.Context, receipt *types.Receipt, deployer common.Address) error {
+	contract := data.Contract{
+		Address:     receipt.ContractAddress.Hex(),
+		Deployer:    deployer.Hex(),
+		TxHash:      receipt.TxHash.Hex(),
+		BlockHash:   receipt.BlockHash.Hex(),
+		BlockNumber: receipt.BlockNumber.Uint64(),
+	}
+	if err := c.inspectContract(ctx, receipt.ContractAddress, &contract); err != nil {
+		return err
+	}
+	contractData, err := json.Marshal(contract)
+	if err != nil {
+		return err
+	}
+	return c.PubSub.GetPublisher().PublishContract(contractData)
+}
+
+// inspectContract sets the bytecode hash and detected interfaces of contract
+// from its runtime code at the latest block.
+func (c EthClient) inspectContract(ctx context.Context, address common.Address, contract *data.Contract) error {
+	code, err := c.CodeAt(ctx, address, nil)
+	if err != nil {
+		return err
+	}
+	contract.Interfaces = []string{}
+	if len(code) == 0 {
+		return nil
+	}
+	contract.BytecodeHash = crypto.Keccak256Hash(code).Hex()
+
+	supported, err := c.supportsInterface(ctx, address, erc165ID)
+	if err != nil {
+		return err
+	}
+	if supported {
+		// A contract implementing ERC-165 must not support the invalid id.
+		supported, err = c.supportsInterface(ctx, address, invalidID)
+		if err != nil {
+			return err
+		}
+		supported = !supported
+	}
+	if supported {
+		contract.Interfaces = append(contract.Interfaces, data.StandardERC165)
+		for _, iface := range erc165IDs {
+			ok, err := c.supportsInterface(ctx, address, iface.id)
+			if err != nil {
+				return err
+			}
+			if ok {
+				contract.Interfaces = append(contract.Interfaces, iface.standard)
+			}
+		}
+	}
+	for _, h := range selectorHeuristics {
+		// ERC-165 contracts report their NFT standards themselves.
+		if supported && h.standard != data.StandardERC20 {
+			continue
+		}
+		if hasSelectors(code, h.selectors) {
+			contract.Interfaces = append(contract.Interfaces, h.standard)
+		}
+	}
+	return nil
+}
+
+// supportsInterface calls the ERC-165 supportsInterface method. Calls that
+// fail or don't return a bool count as unsupported, only a done ctx is an
+// error.
+func (c EthClient) supportsInterface(ctx context.Context, address common.Address, id [4]byte) (bool, error) {
+	input, err := erc165ABI.Pack("supportsInterface", id)
+	if err != nil {
+		return false, err
+	}
+	out, err := c.CallContract(ctx, ethereum.CallMsg{To: &address, Data: input}, nil)
+	if err != nil {
+		return false, ctx.Err()
+	}
+	values, err := erc165ABI.Unpack("supportsInterface", out)
+	if err != nil {
+		return false, nil
+	}
+	ok, _ := values[0].(bool)
+	return ok, nil
+}
+
+// hasSelectors reports whether every selector appears in code, where the
+// function dispatcher pushes them.
+func hasSelectors(code []byte, selectors []string) bool {
+	for _, selector := range selectors {
+		if !bytes.Contains(code, hexutil.MustDecode(selector)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,22 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectorHeuristics(t *testing.T) {
+	// Dispatcher of a minimal ERC-20: PUSH4 <selector> EQ for each method.
+	var code []byte
+	for _, selector := range selectorHeuristics[0].selectors {
+		code = append(code, 0x80, 0x63)
+		code = append(code, hexutil.MustDecode(selector)...)
+		code = append(code, 0x14)
+	}
+	assert.True(t, hasSelectors(code, selectorHeuristics[0].selectors))
+	assert.False(t, hasSelectors(code, selectorHeuristics[1].selectors))
+	assert.False(t, hasSelectors(code[:len(code)-7], selectorHeuristics[0].selectors))
+	assert.True(t, hasSelectors(nil, nil))
+}
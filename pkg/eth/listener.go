@@ -38,11 +38,5 @@ func (c EthClient) handleHeader(ctx context.Context, header *types.Header) error
 	if err != nil {
 		return err
 	}
-	if err = c.publishBlock(block); err != nil {
-		return err
-	}
-	if err := c.publishTxs(ctx, block.Transactions(), block.Hash()); err != nil {
-		return err
-	}
-	return nil
+	return c.handleBlock(ctx, block)
 }
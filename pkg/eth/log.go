@@ -0,0 +1,35 @@
+package eth
+
+import (
+	"encoding/json"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func (c EthClient) publishLogs(logs []*types.Log) error {
+	for _, log := range logs {
+		newLog := data.Log{
+			BlockHash:   log.BlockHash.Hex(),
+			Index:       log.Index,
+			BlockNumber: log.BlockNumber,
+			TxHash:      log.TxHash.Hex(),
+			Address:     log.Address.Hex(),
+			Data:        log.Data,
+		}
+		topics := []*string{&newLog.Topic0, &newLog.Topic1, &newLog.Topic2, &newLog.Topic3}
+		for i, topic := range log.Topics {
+			if i < len(topics) {
+				*topics[i] = topic.Hex()
+			}
+		}
+		logData, err := json.Marshal(newLog)
+		if err != nil {
+			return err
+		}
+		if err := c.PubSub.GetPublisher().PublishLog(logData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	transferSingleTopic = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+	transferBatchTopic  = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+
+	nftABI = mustParseABI(`[
+		{"type":"event","name":"TransferBatch","inputs":[
+			{"name":"operator","type":"address","indexed":true},
+			{"name":"from","type":"address","indexed":true},
+			{"name":"to","type":"address","indexed":true},
+			{"name":"ids","type":"uint256[]"},
+			{"name":"values","type":"uint256[]"}
+		]},
+		{"type":"function","name":"tokenURI","inputs":[{"type":"uint256"}],"outputs":[{"type":"string"}],"stateMutability":"view"},
+		{"type":"function","name":"uri","inputs":[{"type":"uint256"}],"outputs":[{"type":"string"}],"stateMutability":"view"}
+	]`)
+)
+
+// decodeNFTTransfers decodes an ERC-721 Transfer log or an ERC-1155
+// TransferSingle or TransferBatch log. It returns nil for other logs and for
+// malformed ones, which any contract can emit.
+func decodeNFTTransfers(log *types.Log) []data.NFTTransfer {
+	if len(log.Topics) != 4 {
+		return nil
+	}
+	transfer := data.NFTTransfer{
+		BlockHash:   log.BlockHash.Hex(),
+		LogIndex:    log.Index,
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash.Hex(),
+		Contract:    log.Address.Hex(),
+	}
+	switch log.Topics[0] {
+	case transferTopic:
+		if len(log.Data) != 0 {
+			return nil
+		}
+		transfer.Standard = data.StandardERC721
+		transfer.From = topicAddress(log.Topics[1])
+		transfer.To = topicAddress(log.Topics[2])
+		transfer.Operator = transfer.From
+		transfer.TokenID = log.Topics[3].Big().String()
+		transfer.Amount = "1"
+		return []data.NFTTransfer{transfer}
+	case transferSingleTopic:
+		if len(log.Data) != 2*common.HashLength {
+			return nil
+		}
+		transfer.Standard = data.StandardERC1155
+		transfer.Operator = topicAddress(log.Topics[1])
+		transfer.From = topicAddress(log.Topics[2])
+		transfer.To = topicAddress(log.Topics[3])
+		transfer.TokenID = new(big.Int).SetBytes(log.Data[:common.HashLength]).String()
+		transfer.Amount = new(big.Int).SetBytes(log.Data[common.HashLength:]).String()
+		return []data.NFTTransfer{transfer}
+	case transferBatchTopic:
+		values, err := nftABI.Unpack("TransferBatch", log.Data)
+		if err != nil {
+			slog.Warn("skipping undecodable TransferBatch log", "tx", log.TxHash.Hex(), "index", log.Index, "err", err)
+			return nil
+		}
+		ids, amounts := values[0].([]*big.Int), values[1].([]*big.Int)
+		if len(ids) != len(amounts) {
+			slog.Warn("skipping TransferBatch log with mismatched ids and values", "tx", log.TxHash.Hex(), "index", log.Index, "ids", len(ids), "values", len(amounts))
+			return nil
+		}
+		transfer.Standard = data.StandardERC1155
+		transfer.Operator = topicAddress(log.Topics[1])
+		transfer.From = topicAddress(log.Topics[2])
+		transfer.To = topicAddress(log.Topics[3])
+		transfers := make([]data.NFTTransfer, len(ids))
+		for i := range ids {
+			transfers[i] = transfer
+			transfers[i].BatchIndex = uint(i)
+			transfers[i].TokenID = ids[i].String()
+			transfers[i].Amount = amounts[i].String()
+		}
+		return transfers
+	}
+	return nil
+}
+
+// nftURIBatchSize is the number of tokens whose URI is read per batch
+// request.
+const nftURIBatchSize = 100
+
+// publishNFTEvents publishes the NFT transfers in logs. The first transfer of
+// each token is preceded by the token's metadata so its URI follows changes.
+// The URIs are read with batched calls, as a TransferBatch log can move
+// thousands of tokens.
+func (c EthClient) publishNFTEvents(ctx context.Context, logs []*types.Log) error {
+	type token struct{ contract, tokenID, standard string }
+	var (
+		transfers []data.NFTTransfer
+		nfts      []data.NFT
+	)
+	// first maps each token to its index in nfts, or -1 once published.
+	first := map[token]int{}
+	for _, log := range logs {
+		for _, transfer := range decodeNFTTransfers(log) {
+			transfers = append(transfers, transfer)
+			key := token{transfer.Contract, transfer.TokenID, transfer.Standard}
+			if _, ok := first[key]; !ok {
+				first[key] = len(nfts)
+				nfts = append(nfts, data.NFT{Contract: transfer.Contract, TokenID: transfer.TokenID, Standard: transfer.Standard})
+			}
+		}
+	}
+	if err := c.batchNFTURIs(ctx, nfts); err != nil {
+		return err
+	}
+
+	publisher := c.PubSub.GetPublisher()
+	for _, transfer := range transfers {
+		key := token{transfer.Contract, transfer.TokenID, transfer.Standard}
+		if i := first[key]; i >= 0 {
+			nftData, err := json.Marshal(nfts[i])
+			if err != nil {
+				return err
+			}
+			if err := publisher.PublishNFT(nftData); err != nil {
+				return err
+			}
+			first[key] = -1
+		}
+		transferData, err := json.Marshal(transfer)
+		if err != nil {
+			return err
+		}
+		if err := publisher.PublishNFTTransfer(transferData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchNFTURIs sets the metadata URI of nfts, read with batched tokenURI calls
+// for ERC-721 or uri calls for ERC-1155 at the latest block, substituting
+// the {id} placeholder of ERC-1155. The metadata extensions are optional, so
+// a failing call leaves the URI empty. It only fails when ctx is done.
+func (c EthClient) batchNFTURIs(ctx context.Context, nfts []data.NFT) error {
+	for start := 0; start < len(nfts); start += nftURIBatchSize {
+		batch := nfts[start:min(start+nftURIBatchSize, len(nfts))]
+		ids := make([]*big.Int, len(batch))
+		reqs := make([]rpc.BatchElem, len(batch))
+		for i, nft := range batch {
+			id, ok := new(big.Int).SetString(nft.TokenID, 10)
+			if !ok {
+				return fmt.Errorf("invalid token id %q", nft.TokenID)
+			}
+			input, err := nftABI.Pack(nftURIMethod(nft.Standard), id)
+			if err != nil {
+				return err
+			}
+			ids[i] = id
+			reqs[i] = rpc.BatchElem{
+				Method: "eth_call",
+				Args:   []interface{}{map[string]interface{}{"to": common.HexToAddress(nft.Contract), "data": hexutil.Bytes(input)}, "latest"},
+				Result: new(hexutil.Bytes),
+			}
+		}
+		if err := c.Client.Client().BatchCallContext(ctx, reqs); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+		for i, req := range reqs {
+			if req.Error != nil {
+				continue
+			}
+			nft := &batch[i]
+			values, err := nftABI.Unpack(nftURIMethod(nft.Standard), *req.Result.(*hexutil.Bytes))
+			if err != nil {
+				continue
+			}
+			uri, _ := values[0].(string)
+			if nft.Standard == data.StandardERC1155 {
+				uri = strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", ids[i]))
+			}
+			nft.URI = sanitizeText(uri)
+		}
+	}
+	return nil
+}
+
+// nftURIMethod returns the metadata URI method of a token standard.
+func nftURIMethod(standard string) string {
+	if standard == data.StandardERC1155 {
+		return "uri"
+	}
+	return "tokenURI"
+}
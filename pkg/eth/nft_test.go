@@ -0,0 +1,120 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeNFTTransfers(t *testing.T) {
+	operator := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	from := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	to := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	contract := common.HexToAddress("0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D")
+	newLog := func(topics []common.Hash, logData []byte) *types.Log {
+		return &types.Log{Address: contract, Topics: topics, Data: logData, BlockNumber: 12, Index: 3}
+	}
+	expected := data.NFTTransfer{
+		BlockHash:   common.Hash{}.Hex(),
+		LogIndex:    3,
+		BlockNumber: 12,
+		TxHash:      common.Hash{}.Hex(),
+		Contract:    contract.Hex(),
+		From:        from.Hex(),
+		To:          to.Hex(),
+	}
+
+	erc721 := expected
+	erc721.Standard, erc721.Operator, erc721.TokenID, erc721.Amount = data.StandardERC721, from.Hex(), "42", "1"
+	transfers := decodeNFTTransfers(newLog(
+		[]common.Hash{transferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes()), common.BigToHash(big.NewInt(42))},
+		nil,
+	))
+	assert.Equal(t, []data.NFTTransfer{erc721}, transfers)
+
+	addresses := []common.Hash{common.BytesToHash(operator.Bytes()), common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())}
+	single := expected
+	single.Standard, single.Operator, single.TokenID, single.Amount = data.StandardERC1155, operator.Hex(), "7", "100"
+	transfers = decodeNFTTransfers(newLog(
+		append([]common.Hash{transferSingleTopic}, addresses...),
+		append(common.BigToHash(big.NewInt(7)).Bytes(), common.BigToHash(big.NewInt(100)).Bytes()...),
+	))
+	assert.Equal(t, []data.NFTTransfer{single}, transfers)
+
+	batchData, err := nftABI.Events["TransferBatch"].Inputs.NonIndexed().Pack(
+		[]*big.Int{big.NewInt(7), big.NewInt(8)}, []*big.Int{big.NewInt(1), big.NewInt(2)})
+	require.NoError(t, err)
+	transfers = decodeNFTTransfers(newLog(append([]common.Hash{transferBatchTopic}, addresses...), batchData))
+	require.Len(t, transfers, 2)
+	assert.Equal(t, uint(1), transfers[1].BatchIndex)
+	assert.Equal(t, "8", transfers[1].TokenID)
+	assert.Equal(t, "2", transfers[1].Amount)
+
+	// ERC-20 transfers have three topics.
+	transfers = decodeNFTTransfers(newLog(append([]common.Hash{transferTopic}, addresses[1:]...), common.BigToHash(big.NewInt(1)).Bytes()))
+	assert.Empty(t, transfers)
+
+	// Malformed TransferBatch logs are skipped instead of failing the block.
+	batchLog := append([]common.Hash{transferBatchTopic}, addresses...)
+	assert.Empty(t, decodeNFTTransfers(newLog(batchLog, []byte{0xde, 0xad, 0xbe, 0xef})))
+	mismatched, err := nftABI.Events["TransferBatch"].Inputs.NonIndexed().Pack(
+		[]*big.Int{big.NewInt(7), big.NewInt(8)}, []*big.Int{big.NewInt(1)})
+	require.NoError(t, err)
+	assert.Empty(t, decodeNFTTransfers(newLog(batchLog, mismatched)))
+}
+
+// uriService serves eth_call for tokenURI and uri. Token 0 has no URI.
+type uriService struct{}
+
+func (uriService) Call(args struct {
+	To   common.Address
+	Data hexutil.Bytes
+}, _ string) (hexutil.Bytes, error) {
+	method, err := nftABI.MethodById(args.Data)
+	if err != nil {
+		return nil, err
+	}
+	values, err := method.Inputs.Unpack(args.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	id := values[0].(*big.Int)
+	if id.Sign() == 0 {
+		return nil, errors.New("execution reverted")
+	}
+	uri := fmt.Sprintf("ipfs://%s/%s", method.Name, id)
+	if method.Name == "uri" {
+		uri = "ipfs://uri/{id}"
+	}
+	return method.Outputs.Pack(uri)
+}
+
+func TestBatchNFTURIs(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", uriService{}))
+	defer server.Stop()
+	c := EthClient{Client: ethclient.NewClient(rpc.DialInProc(server))}
+
+	// More tokens than fit in a batch.
+	contract := common.HexToAddress("0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D").Hex()
+	nfts := make([]data.NFT, nftURIBatchSize+20)
+	for i := range nfts {
+		nfts[i] = data.NFT{Contract: contract, TokenID: fmt.Sprint(i), Standard: data.StandardERC721}
+	}
+	nfts = append(nfts, data.NFT{Contract: contract, TokenID: "255", Standard: data.StandardERC1155})
+	require.NoError(t, c.batchNFTURIs(context.Background(), nfts))
+	assert.Empty(t, nfts[0].URI)
+	assert.Equal(t, "ipfs://tokenURI/119", nfts[119].URI)
+	assert.Equal(t, "ipfs://uri/00000000000000000000000000000000000000000000000000000000000000ff", nfts[len(nfts)-1].URI)
+}
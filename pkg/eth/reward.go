@@ -0,0 +1,136 @@
+package eth
+
+import (
+	"math/big"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Chain ids of the Ethereum Classic networks, whose block rewards follow
+// ECIP-1017 instead of the Byzantium and Constantinople reductions.
+const (
+	classicChainID = 61
+	mordorChainID  = 63
+)
+
+// Static block rewards of ethash chains in wei, 5 ETH at Frontier, 3 ETH
+// from Byzantium and 2 ETH from Constantinople.
+var (
+	frontierBlockReward       = new(big.Int).Mul(big.NewInt(5), big.NewInt(params.Ether))
+	byzantiumBlockReward      = new(big.Int).Mul(big.NewInt(3), big.NewInt(params.Ether))
+	constantinopleBlockReward = new(big.Int).Mul(big.NewInt(2), big.NewInt(params.Ether))
+)
+
+// rewardRules are the proof of work block rewards of a chain.
+type rewardRules struct {
+	// config holds the fork blocks of an ethash chain. It is nil for chains
+	// without static rewards.
+	config *params.ChainConfig
+	// eraLength is the number of blocks of an ECIP-1017 era, after which
+	// the block reward drops by 20%. It is 0 for ethash chains.
+	eraLength uint64
+}
+
+// ethashConfigs are the known chains mined with ethash before the merge.
+var ethashConfigs = []*params.ChainConfig{
+	params.MainnetChainConfig,
+	params.SepoliaChainConfig,
+	params.HoleskyChainConfig,
+}
+
+// rewardRulesFor returns the reward rules of a chain. Only the known ethash
+// and ECIP-1017 chains have static rewards, other chains such as Clique,
+// Parlia or Bor networks seal blocks with a difficulty but pay no block
+// reward.
+func rewardRulesFor(chainID *big.Int) rewardRules {
+	switch {
+	case chainID.Cmp(big.NewInt(classicChainID)) == 0:
+		return rewardRules{eraLength: 5_000_000}
+	case chainID.Cmp(big.NewInt(mordorChainID)) == 0:
+		return rewardRules{eraLength: 2_000_000}
+	}
+	for _, config := range ethashConfigs {
+		if config.Ethash != nil && chainID.Cmp(config.ChainID) == 0 {
+			return rewardRules{config: config}
+		}
+	}
+	return rewardRules{}
+}
+
+// blockReward returns the static reward of the block at number, without
+// fees and rewards for including uncles.
+func (r rewardRules) blockReward(number uint64) *big.Int {
+	if r.eraLength > 0 {
+		// 5 ether * (4/5)^era
+		era := big.NewInt(int64(r.era(number)))
+		reward := new(big.Int).Set(frontierBlockReward)
+		reward.Mul(reward, new(big.Int).Exp(big.NewInt(4), era, nil))
+		return reward.Div(reward, new(big.Int).Exp(big.NewInt(5), era, nil))
+	}
+	if r.config == nil {
+		return new(big.Int)
+	}
+	n := new(big.Int).SetUint64(number)
+	switch {
+	case r.config.IsConstantinople(n):
+		return new(big.Int).Set(constantinopleBlockReward)
+	case r.config.IsByzantium(n):
+		return new(big.Int).Set(byzantiumBlockReward)
+	}
+	return new(big.Int).Set(frontierBlockReward)
+}
+
+// uncleReward returns the reward of the miner of an uncle at uncleNumber
+// included by the block at number: 1/8 of the block reward less for each
+// block the uncle is behind, or a flat 1/32 of it after the first
+// ECIP-1017 era.
+func (r rewardRules) uncleReward(uncleNumber, number uint64) *big.Int {
+	reward := r.blockReward(number)
+	if r.eraLength > 0 && r.era(number) > 0 {
+		return reward.Div(reward, big.NewInt(32))
+	}
+	reward.Mul(reward, new(big.Int).SetUint64(uncleNumber+8-number))
+	return reward.Div(reward, big.NewInt(8))
+}
+
+// setBlockRewards sets the fees, burn and static rewards of newBlock from
+// block and the receipts of its transactions. Every transaction pays its
+// tip per gas to the miner for the gas it used, and burns the base fee and
+// its blob fees. Blocks after the merge have no static rewards.
+func (r rewardRules) setBlockRewards(newBlock *data.Block, block *types.Block, receipts []*types.Receipt) {
+	priorityFees, blobFees := new(big.Int), new(big.Int)
+	for i, tx := range block.Transactions() {
+		tip := tx.EffectiveGasTipValue(block.BaseFee())
+		priorityFees.Add(priorityFees, tip.Mul(tip, new(big.Int).SetUint64(receipts[i].GasUsed)))
+		if receipts[i].BlobGasPrice != nil {
+			blobFee := new(big.Int).SetUint64(receipts[i].BlobGasUsed)
+			blobFees.Add(blobFees, blobFee.Mul(blobFee, receipts[i].BlobGasPrice))
+		}
+	}
+	baseFees := new(big.Int)
+	if baseFee := block.BaseFee(); baseFee != nil {
+		baseFees.Mul(baseFee, new(big.Int).SetUint64(block.GasUsed()))
+	}
+	blockReward, inclusionReward := new(big.Int), new(big.Int)
+	if block.NumberU64() > 0 && block.Difficulty().Sign() > 0 {
+		blockReward = r.blockReward(block.NumberU64())
+		// The miner earns 1/32 of the block reward for each uncle.
+		inclusionReward.Mul(blockReward, big.NewInt(int64(len(block.Uncles()))))
+		inclusionReward.Div(inclusionReward, big.NewInt(32))
+	}
+	newBlock.PriorityFees = priorityFees.String()
+	newBlock.BaseFeeBurned = baseFees.String()
+	newBlock.BlobFeeBurned = blobFees.String()
+	newBlock.BlockReward = blockReward.String()
+	newBlock.UncleInclusionReward = inclusionReward.String()
+}
+
+// era returns the zero based ECIP-1017 era of the block at number.
+func (r rewardRules) era(number uint64) uint64 {
+	if number == 0 {
+		return 0
+	}
+	return (number - 1) / r.eraLength
+}
@@ -0,0 +1,69 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetBlockRewards(t *testing.T) {
+	mainnet := rewardRulesFor(big.NewInt(1))
+
+	// After the merge the miner only earns the tips above the base fee.
+	txs := []*types.Transaction{
+		types.NewTx(&types.DynamicFeeTx{GasTipCap: big.NewInt(2 * params.GWei), GasFeeCap: big.NewInt(100 * params.GWei)}),
+		types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(11 * params.GWei)}),
+	}
+	receipts := []*types.Receipt{
+		{GasUsed: 21000, BlobGasUsed: 131072, BlobGasPrice: big.NewInt(3)},
+		{GasUsed: 50000},
+	}
+	block := types.NewBlockWithHeader(&types.Header{
+		Number: big.NewInt(19000000), Difficulty: big.NewInt(0), GasUsed: 71000, BaseFee: big.NewInt(10 * params.GWei),
+	}).WithBody(types.Body{Transactions: txs})
+	var newBlock data.Block
+	mainnet.setBlockRewards(&newBlock, block, receipts)
+	assert.Equal(t, data.Block{
+		PriorityFees:         "92000000000000",
+		BaseFeeBurned:        "710000000000000",
+		BlobFeeBurned:        "393216",
+		BlockReward:          "0",
+		UncleInclusionReward: "0",
+	}, newBlock)
+
+	// Before London nothing is burned and the whole gas price is a tip.
+	uncles := []*types.Header{{Number: big.NewInt(7279999)}, {Number: big.NewInt(7279998)}}
+	block = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(7280000), Difficulty: big.NewInt(1), GasUsed: 21000}).
+		WithBody(types.Body{Transactions: txs[1:], Uncles: uncles})
+	newBlock = data.Block{}
+	mainnet.setBlockRewards(&newBlock, block, []*types.Receipt{{GasUsed: 21000}})
+	assert.Equal(t, data.Block{
+		PriorityFees:         "231000000000000",
+		BaseFeeBurned:        "0",
+		BlobFeeBurned:        "0",
+		BlockReward:          "2000000000000000000",
+		UncleInclusionReward: "125000000000000000",
+	}, newBlock)
+}
+
+func TestSetBlockRewardsWithoutEthash(t *testing.T) {
+	// Bor blocks have a difficulty but no static reward.
+	polygon := rewardRulesFor(big.NewInt(137))
+	txs := []*types.Transaction{types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(40 * params.GWei)})}
+	block := types.NewBlockWithHeader(&types.Header{
+		Number: big.NewInt(50000000), Difficulty: big.NewInt(22), GasUsed: 21000, BaseFee: big.NewInt(30 * params.GWei),
+	}).WithBody(types.Body{Transactions: txs})
+	var newBlock data.Block
+	polygon.setBlockRewards(&newBlock, block, []*types.Receipt{{GasUsed: 21000}})
+	assert.Equal(t, data.Block{
+		PriorityFees:         "210000000000000",
+		BaseFeeBurned:        "630000000000000",
+		BlobFeeBurned:        "0",
+		BlockReward:          "0",
+		UncleInclusionReward: "0",
+	}, newBlock)
+}
@@ -8,12 +8,13 @@ import (
 	"time"
 
 	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"github.com/ethereum/go-ethereum/core/types"
 	"gorm.io/gorm"
 )
 
 func (c EthClient) StartSyncer(ctx context.Context, dbConn db.DB) error {
 	var nextBlockNumber uint64
-	firstBlock, err := dbConn.GetFirstBlock()
+	firstBlock, err := dbConn.GetFirstBlock(ctx)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			block, err := c.BlockByNumber(context.Background(), nil)
@@ -28,13 +29,31 @@ func (c EthClient) StartSyncer(ctx context.Context, dbConn db.DB) error {
 		nextBlockNumber = firstBlock.Number - 1
 	}
 
+	var head uint64
+	if c.retention.Enabled() {
+		head, err = c.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve the head block number from eth client: %w", err)
+		}
+	}
+
 	for nextBlockNumber > 0 {
 		select {
 		case <-ctx.Done():
 			slog.Info("eth syncer stopped")
 			return nil
 		default:
-			if err := c.handleBlock(ctx, nextBlockNumber); err != nil {
+			block, err := c.BlockByNumber(ctx, new(big.Int).SetUint64(nextBlockNumber))
+			if err != nil {
+				slog.Error("syncer failed to retrieve block", "err", err)
+				time.Sleep(time.Millisecond * 100)
+				continue
+			}
+			if !c.retention.Keeps(head, block.NumberU64(), block.Time(), time.Now()) {
+				slog.Info("eth syncer reached retention horizon", "number", nextBlockNumber)
+				return nil
+			}
+			if err := c.handleBlock(ctx, block); err != nil {
 				slog.Error("syncer failed to publish block", "err", err)
 				time.Sleep(time.Millisecond * 100)
 				continue
@@ -45,16 +64,29 @@ func (c EthClient) StartSyncer(ctx context.Context, dbConn db.DB) error {
 	return nil
 }
 
-func (c EthClient) handleBlock(ctx context.Context, number uint64) error {
-	bigNumber := new(big.Int).SetUint64(number)
-	block, err := c.BlockByNumber(ctx, bigNumber)
+func (c EthClient) handleBlock(ctx context.Context, block *types.Block) error {
+	receipts, err := c.batchTransactionReceipts(ctx, block.Transactions())
 	if err != nil {
 		return err
 	}
-	if err = c.publishBlock(block); err != nil {
+	if len(receipts) != len(block.Transactions()) {
+		return fmt.Errorf("len of receipts: %d doesnt match len of txs: %d", len(receipts), len(block.Transactions()))
+	}
+	if err := c.publishBlock(block, receipts); err != nil {
+		return err
+	}
+	if err := c.publishUncles(block); err != nil {
+		return err
+	}
+	touched, err := c.publishTxs(ctx, block.Transactions(), receipts, block.Hash(), block.BaseFee())
+	if err != nil {
+		return err
+	}
+	internal, err := c.publishInternalTxs(ctx, block)
+	if err != nil {
 		return err
 	}
-	if err := c.publishTxs(ctx, block.Transactions(), block.Hash()); err != nil {
+	if err := c.publishBalances(ctx, block, append(touched, internal...)); err != nil {
 		return err
 	}
 	return nil
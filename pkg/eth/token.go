@@ -0,0 +1,172 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	approvalTopic = crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+
+	erc20ABI = mustParseABI(`[
+		{"type":"function","name":"name","inputs":[],"outputs":[{"type":"string"}],"stateMutability":"view"},
+		{"type":"function","name":"symbol","inputs":[],"outputs":[{"type":"string"}],"stateMutability":"view"},
+		{"type":"function","name":"decimals","inputs":[],"outputs":[{"type":"uint8"}],"stateMutability":"view"}
+	]`)
+)
+
+func mustParseABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// isERC20Event reports whether log is the ERC-20 event with the signature
+// topic. ERC-721 emits events with the same signatures but indexes the token
+// id as a fourth topic instead of logging an amount.
+func isERC20Event(log *types.Log, topic common.Hash) bool {
+	return len(log.Topics) == 3 && log.Topics[0] == topic && len(log.Data) == common.HashLength
+}
+
+func topicAddress(topic common.Hash) string {
+	return common.BytesToAddress(topic.Bytes()).Hex()
+}
+
+func decodeTransfer(log *types.Log) data.TokenTransfer {
+	return data.TokenTransfer{
+		BlockHash:   log.BlockHash.Hex(),
+		LogIndex:    log.Index,
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash.Hex(),
+		Token:       log.Address.Hex(),
+		From:        topicAddress(log.Topics[1]),
+		To:          topicAddress(log.Topics[2]),
+		Value:       common.BytesToHash(log.Data).Big().String(),
+	}
+}
+
+func decodeApproval(log *types.Log) data.TokenApproval {
+	return data.TokenApproval{
+		BlockHash:   log.BlockHash.Hex(),
+		LogIndex:    log.Index,
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash.Hex(),
+		Token:       log.Address.Hex(),
+		Owner:       topicAddress(log.Topics[1]),
+		Spender:     topicAddress(log.Topics[2]),
+		Value:       common.BytesToHash(log.Data).Big().String(),
+	}
+}
+
+// publishTokenEvents publishes the ERC-20 transfers and approvals in logs,
+// preceded by the metadata of tokens this client hasn't published yet.
+func (c EthClient) publishTokenEvents(ctx context.Context, logs []*types.Log) error {
+	publisher := c.PubSub.GetPublisher()
+	for _, log := range logs {
+		var (
+			msg     []byte
+			publish func([]byte) error
+			err     error
+		)
+		switch {
+		case isERC20Event(log, transferTopic):
+			msg, err = json.Marshal(decodeTransfer(log))
+			publish = publisher.PublishTokenTransfer
+		case isERC20Event(log, approvalTopic):
+			msg, err = json.Marshal(decodeApproval(log))
+			publish = publisher.PublishTokenApproval
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := c.publishToken(ctx, log.Address); err != nil {
+			return err
+		}
+		if err := publish(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c EthClient) publishToken(ctx context.Context, address common.Address) error {
+	if _, ok := c.tokens.Load(address); ok {
+		return nil
+	}
+	token, err := c.tokenMetadata(ctx, address)
+	if err != nil {
+		return err
+	}
+	tokenData, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	if err := c.PubSub.GetPublisher().PublishToken(tokenData); err != nil {
+		return err
+	}
+	c.tokens.Store(address, struct{}{})
+	return nil
+}
+
+// tokenMetadata reads the name, symbol and decimals of a token at the latest
+// block. The metadata methods are optional in ERC-20, so a failing call
+// leaves its field empty. It only fails when ctx is done.
+func (c EthClient) tokenMetadata(ctx context.Context, address common.Address) (data.Token, error) {
+	token := data.Token{Address: address.Hex()}
+	for _, method := range []string{"name", "symbol", "decimals"} {
+		input, err := erc20ABI.Pack(method)
+		if err != nil {
+			return token, err
+		}
+		out, err := c.CallContract(ctx, ethereum.CallMsg{To: &address, Data: input}, nil)
+		if err != nil {
+			if ctx.Err() != nil {
+				return token, ctx.Err()
+			}
+			continue
+		}
+		switch method {
+		case "name":
+			token.Name = unpackTokenString(method, out)
+		case "symbol":
+			token.Symbol = unpackTokenString(method, out)
+		case "decimals":
+			if values, err := erc20ABI.Unpack(method, out); err == nil {
+				token.Decimals, _ = values[0].(uint8)
+			}
+		}
+	}
+	return token, nil
+}
+
+// unpackTokenString decodes the result of name or symbol. Some early tokens
+// return a bytes32 instead of a string.
+func unpackTokenString(method string, out []byte) string {
+	var s string
+	if values, err := erc20ABI.Unpack(method, out); err == nil {
+		s, _ = values[0].(string)
+	} else if len(out) == common.HashLength {
+		s = string(bytes.TrimRight(out, "\x00"))
+	}
+	return sanitizeText(s)
+}
+
+// sanitizeText drops what Postgres rejects in text: invalid UTF-8 and NUL
+// characters.
+func sanitizeText(s string) string {
+	return strings.ReplaceAll(strings.ToValidUTF8(s, ""), "\x00", "")
+}
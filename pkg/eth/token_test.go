@@ -0,0 +1,57 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTransfer(t *testing.T) {
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	value, _ := new(big.Int).SetString("5000000000000000000000", 10)
+	log := &types.Log{
+		Address:     common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+		Topics:      []common.Hash{transferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:        common.BigToHash(value).Bytes(),
+		BlockNumber: 19000000,
+		TxHash:      common.HexToHash("0x01"),
+		BlockHash:   common.HexToHash("0x02"),
+		Index:       7,
+	}
+	require.True(t, isERC20Event(log, transferTopic))
+	assert.False(t, isERC20Event(log, approvalTopic))
+	assert.Equal(t, data.TokenTransfer{
+		BlockHash:   log.BlockHash.Hex(),
+		LogIndex:    7,
+		BlockNumber: 19000000,
+		TxHash:      log.TxHash.Hex(),
+		Token:       "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		From:        from.Hex(),
+		To:          to.Hex(),
+		Value:       "5000000000000000000000",
+	}, decodeTransfer(log))
+
+	// ERC-721 transfers index the token id and log no data.
+	nft := *log
+	nft.Topics = append(nft.Topics, common.BigToHash(big.NewInt(1)))
+	nft.Data = nil
+	assert.False(t, isERC20Event(&nft, transferTopic))
+}
+
+func TestUnpackTokenString(t *testing.T) {
+	out, err := erc20ABI.Methods["name"].Outputs.Pack("USD Coin")
+	require.NoError(t, err)
+	assert.Equal(t, "USD Coin", unpackTokenString("name", out))
+
+	var bytes32 common.Hash
+	copy(bytes32[:], "MKR")
+	assert.Equal(t, "MKR", unpackTokenString("symbol", bytes32.Bytes()))
+
+	assert.Empty(t, unpackTokenString("symbol", nil))
+}
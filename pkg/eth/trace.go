@@ -0,0 +1,277 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tracers that index internal transactions. TracerDebug calls
+// debug_traceBlockByHash with the callTracer, supported by geth and most
+// clients. TracerTrace calls trace_block, supported by Erigon and
+// Nethermind. Tracing is disabled by default since not every node exposes
+// these APIs.
+const (
+	TracerNone  = ""
+	TracerDebug = "debug"
+	TracerTrace = "trace"
+)
+
+// callFrame is a call frame returned by the callTracer.
+type callFrame struct {
+	Type    string         `json:"type"`
+	From    common.Address `json:"from"`
+	To      common.Address `json:"to"`
+	Value   *hexutil.Big   `json:"value"`
+	Gas     hexutil.Uint64 `json:"gas"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	Error   string         `json:"error"`
+	Calls   []callFrame    `json:"calls"`
+}
+
+// callTrace is the callTracer result of a transaction.
+type callTrace struct {
+	TxHash *common.Hash `json:"txHash"`
+	Result *callFrame   `json:"result"`
+	Error  string       `json:"error"`
+}
+
+// parityTrace is a trace returned by trace_block. Its frames are listed in
+// depth-first order, TraceAddress is the path of child indexes from the
+// transaction's top-level call.
+type parityTrace struct {
+	Type   string `json:"type"`
+	Action struct {
+		CallType       string         `json:"callType"`
+		CreationMethod string         `json:"creationMethod"`
+		From           common.Address `json:"from"`
+		To             common.Address `json:"to"`
+		Value          *hexutil.Big   `json:"value"`
+		Gas            hexutil.Uint64 `json:"gas"`
+		Address        common.Address `json:"address"`
+		RefundAddress  common.Address `json:"refundAddress"`
+		Balance        *hexutil.Big   `json:"balance"`
+	} `json:"action"`
+	Result *struct {
+		GasUsed hexutil.Uint64 `json:"gasUsed"`
+		Address common.Address `json:"address"`
+	} `json:"result"`
+	Error           string       `json:"error"`
+	BlockHash       common.Hash  `json:"blockHash"`
+	TraceAddress    []uint       `json:"traceAddress"`
+	TransactionHash *common.Hash `json:"transactionHash"`
+}
+
+// publishInternalTxs traces the transactions of a block and publishes their
+// internal transactions, and the contracts they created. It returns the
+// participants of the internal transactions that weren't reverted.
+func (c EthClient) publishInternalTxs(ctx context.Context, block *types.Block) ([]common.Address, error) {
+	var internalTxs []data.InternalTx
+	switch c.tracer {
+	case TracerDebug:
+		var traces []callTrace
+		if err := c.Client.Client().CallContext(ctx, &traces, "debug_traceBlockByHash", block.Hash(), map[string]string{"tracer": "callTracer"}); err != nil {
+			return nil, fmt.Errorf("failed to trace block %s: %w", block.Hash().Hex(), err)
+		}
+		txHashes := make([]common.Hash, len(block.Transactions()))
+		for i, tx := range block.Transactions() {
+			txHashes[i] = tx.Hash()
+		}
+		var err error
+		if internalTxs, err = flattenCallTraces(block.Hash(), block.NumberU64(), txHashes, traces); err != nil {
+			return nil, err
+		}
+	case TracerTrace:
+		var traces []parityTrace
+		if err := c.Client.Client().CallContext(ctx, &traces, "trace_block", hexutil.EncodeUint64(block.NumberU64())); err != nil {
+			return nil, fmt.Errorf("failed to trace block %s: %w", block.Hash().Hex(), err)
+		}
+		var err error
+		if internalTxs, err = flattenParityTraces(block.Hash(), block.NumberU64(), traces); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+
+	var touched []common.Address
+	for _, internalTx := range internalTxs {
+		if !internalTx.Reverted {
+			touched = append(touched, common.HexToAddress(internalTx.From), common.HexToAddress(internalTx.To))
+		}
+		txData, err := json.Marshal(internalTx)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.PubSub.GetPublisher().PublishInternalTx(txData); err != nil {
+			return nil, err
+		}
+		if isCreate(internalTx.Type) && !internalTx.Reverted && internalTx.To != (common.Address{}).Hex() {
+			if err := c.publishInternalContract(ctx, internalTx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return touched, nil
+}
+
+// publishInternalContract publishes the contract created by an internal
+// transaction.
+func (c EthClient) publishInternalContract(ctx context.Context, internalTx data.InternalTx) error {
+	contract := data.Contract{
+		Address:     internalTx.To,
+		Deployer:    internalTx.From,
+		TxHash:      internalTx.TxHash,
+		BlockHash:   internalTx.BlockHash,
+		BlockNumber: internalTx.BlockNumber,
+		Internal:    true,
+	}
+	if err := c.inspectContract(ctx, common.HexToAddress(internalTx.To), &contract); err != nil {
+		return err
+	}
+	contractData, err := json.Marshal(contract)
+	if err != nil {
+		return err
+	}
+	return c.PubSub.GetPublisher().PublishContract(contractData)
+}
+
+// flattenCallTraces flattens the callTracer results of the transactions
+// with txHashes, in block order, into their internal transactions.
+func flattenCallTraces(blockHash common.Hash, number uint64, txHashes []common.Hash, traces []callTrace) ([]data.InternalTx, error) {
+	if len(traces) != len(txHashes) {
+		return nil, fmt.Errorf("len of traces: %d doesnt match len of txs: %d", len(traces), len(txHashes))
+	}
+	var internalTxs []data.InternalTx
+	for i, trace := range traces {
+		if trace.TxHash != nil && *trace.TxHash != txHashes[i] {
+			return nil, fmt.Errorf("trace %d is of tx %s, expected %s", i, trace.TxHash.Hex(), txHashes[i].Hex())
+		}
+		if trace.Result == nil {
+			return nil, fmt.Errorf("failed to trace tx %s: %s", txHashes[i].Hex(), trace.Error)
+		}
+		tx := data.InternalTx{BlockHash: blockHash.Hex(), TxHash: txHashes[i].Hex(), BlockNumber: number}
+		reverted := trace.Result.Error != ""
+		for _, frame := range trace.Result.Calls {
+			internalTxs = flattenCallFrame(internalTxs, tx, frame, 1, reverted)
+		}
+	}
+	return internalTxs, nil
+}
+
+// flattenCallFrame appends frame and its children, in depth-first order, to
+// internalTxs.
+func flattenCallFrame(internalTxs []data.InternalTx, tx data.InternalTx, frame callFrame, depth uint, reverted bool) []data.InternalTx {
+	var traceIndex uint
+	if n := len(internalTxs); n > 0 && internalTxs[n-1].TxHash == tx.TxHash {
+		traceIndex = internalTxs[n-1].TraceIndex + 1
+	}
+	reverted = reverted || frame.Error != ""
+	tx.TraceIndex = traceIndex
+	tx.Depth = depth
+	tx.Type = strings.ToUpper(frame.Type)
+	tx.From = frame.From.Hex()
+	tx.To = frame.To.Hex()
+	tx.Value = bigString(frame.Value)
+	tx.Gas = uint64(frame.Gas)
+	tx.GasUsed = uint64(frame.GasUsed)
+	tx.Error = frame.Error
+	tx.Reverted = reverted
+	internalTxs = append(internalTxs, tx)
+	for _, child := range frame.Calls {
+		internalTxs = flattenCallFrame(internalTxs, tx, child, depth+1, reverted)
+	}
+	return internalTxs
+}
+
+// flattenParityTraces converts the traces of a block returned by trace_block
+// into internal transactions. Top-level calls and block rewards are skipped.
+func flattenParityTraces(blockHash common.Hash, number uint64, traces []parityTrace) ([]data.InternalTx, error) {
+	var internalTxs []data.InternalTx
+	// failed holds the trace addresses of the current transaction's frames
+	// that reverted themselves or through a parent.
+	failed := map[string]bool{}
+	var traceIndex uint
+	for _, trace := range traces {
+		if trace.TransactionHash == nil {
+			continue
+		}
+		if trace.BlockHash != blockHash {
+			return nil, fmt.Errorf("trace of tx %s is in block %s, expected %s", trace.TransactionHash.Hex(), trace.BlockHash.Hex(), blockHash.Hex())
+		}
+		depth := len(trace.TraceAddress)
+		if depth == 0 {
+			clear(failed)
+			traceIndex = 0
+		}
+		key := fmt.Sprint(trace.TraceAddress)
+		reverted := trace.Error != ""
+		if depth > 0 {
+			reverted = reverted || failed[fmt.Sprint(trace.TraceAddress[:depth-1])]
+		}
+		failed[key] = reverted
+		if depth == 0 {
+			continue
+		}
+
+		tx := data.InternalTx{
+			BlockHash:   blockHash.Hex(),
+			TxHash:      trace.TransactionHash.Hex(),
+			TraceIndex:  traceIndex,
+			BlockNumber: number,
+			Depth:       uint(depth),
+			Gas:         uint64(trace.Action.Gas),
+			Error:       trace.Error,
+			Reverted:    reverted,
+		}
+		if trace.Result != nil {
+			tx.GasUsed = uint64(trace.Result.GasUsed)
+		}
+		switch trace.Type {
+		case "call":
+			tx.Type = strings.ToUpper(trace.Action.CallType)
+			tx.From = trace.Action.From.Hex()
+			tx.To = trace.Action.To.Hex()
+			tx.Value = bigString(trace.Action.Value)
+		case "create":
+			tx.Type = "CREATE"
+			if trace.Action.CreationMethod == "create2" {
+				tx.Type = "CREATE2"
+			}
+			tx.From = trace.Action.From.Hex()
+			tx.To = common.Address{}.Hex()
+			if trace.Result != nil {
+				tx.To = trace.Result.Address.Hex()
+			}
+			tx.Value = bigString(trace.Action.Value)
+		case "suicide":
+			tx.Type = "SELFDESTRUCT"
+			tx.From = trace.Action.Address.Hex()
+			tx.To = trace.Action.RefundAddress.Hex()
+			tx.Value = bigString(trace.Action.Balance)
+		default:
+			return nil, fmt.Errorf("unknown trace type %q in tx %s", trace.Type, trace.TransactionHash.Hex())
+		}
+		internalTxs = append(internalTxs, tx)
+		traceIndex++
+	}
+	return internalTxs, nil
+}
+
+func isCreate(frameType string) bool {
+	return frameType == "CREATE" || frameType == "CREATE2"
+}
+
+func bigString(n *hexutil.Big) string {
+	if n == nil {
+		return "0"
+	}
+	return (*big.Int)(n).String()
+}
@@ -0,0 +1,110 @@
+package eth
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	traceBlock = common.HexToHash("0xb1")
+	traceTx1   = common.HexToHash("0x01")
+	traceTx2   = common.HexToHash("0x02")
+	eoa        = common.HexToAddress("0x10")
+	router     = common.HexToAddress("0x11")
+	pool       = common.HexToAddress("0x12")
+	created    = common.HexToAddress("0x13")
+)
+
+// expectedTraces are the internal transactions of both trace fixtures: tx1
+// calls the router, which calls the pool with a nested call that fails and
+// creates a contract, tx2 has no internal transactions.
+func expectedTraces() []data.InternalTx {
+	internalTx := func(index, depth uint, typ string, from, to common.Address, value string, gas, gasUsed uint64) data.InternalTx {
+		return data.InternalTx{
+			BlockHash: traceBlock.Hex(), TxHash: traceTx1.Hex(), TraceIndex: index, BlockNumber: 7, Depth: depth,
+			Type: typ, From: from.Hex(), To: to.Hex(), Value: value, Gas: gas, GasUsed: gasUsed,
+		}
+	}
+	call := internalTx(0, 1, "CALL", router, pool, "1000000000000000000", 90000, 60000)
+	failed := internalTx(1, 2, "STATICCALL", pool, router, "0", 30000, 30000)
+	failed.Error = "out of gas"
+	failed.Reverted = true
+	create := internalTx(2, 2, "CREATE2", pool, created, "0", 20000, 10000)
+	return []data.InternalTx{call, failed, create}
+}
+
+func TestFlattenCallTraces(t *testing.T) {
+	var traces []callTrace
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"txHash": "`+traceTx1.Hex()+`", "result": {
+			"type": "CALL", "from": "`+eoa.Hex()+`", "to": "`+router.Hex()+`", "value": "0xde0b6b3a7640000", "gas": "0x186a0", "gasUsed": "0x101d0",
+			"calls": [{
+				"type": "CALL", "from": "`+router.Hex()+`", "to": "`+pool.Hex()+`", "value": "0xde0b6b3a7640000", "gas": "0x15f90", "gasUsed": "0xea60",
+				"calls": [
+					{"type": "STATICCALL", "from": "`+pool.Hex()+`", "to": "`+router.Hex()+`", "gas": "0x7530", "gasUsed": "0x7530", "error": "out of gas"},
+					{"type": "CREATE2", "from": "`+pool.Hex()+`", "to": "`+created.Hex()+`", "value": "0x0", "gas": "0x4e20", "gasUsed": "0x2710"}
+				]
+			}]
+		}},
+		{"txHash": "`+traceTx2.Hex()+`", "result": {"type": "CALL", "from": "`+eoa.Hex()+`", "to": "`+eoa.Hex()+`", "value": "0x1", "gas": "0x5208", "gasUsed": "0x5208"}}
+	]`), &traces))
+
+	internalTxs, err := flattenCallTraces(traceBlock, 7, []common.Hash{traceTx1, traceTx2}, traces)
+	require.NoError(t, err)
+	assert.Equal(t, expectedTraces(), internalTxs)
+
+	// A reverted transaction reverts all of its internal transactions.
+	traces[0].Result.Error = "execution reverted"
+	internalTxs, err = flattenCallTraces(traceBlock, 7, []common.Hash{traceTx1, traceTx2}, traces)
+	require.NoError(t, err)
+	for _, internalTx := range internalTxs {
+		assert.True(t, internalTx.Reverted)
+	}
+
+	_, err = flattenCallTraces(traceBlock, 7, []common.Hash{traceTx2, traceTx1}, traces)
+	assert.Error(t, err, "traces out of order")
+	_, err = flattenCallTraces(traceBlock, 7, []common.Hash{traceTx1}, traces)
+	assert.Error(t, err, "missing traces")
+}
+
+func TestFlattenParityTraces(t *testing.T) {
+	var traces []parityTrace
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"type": "call", "action": {"callType": "call", "from": "`+eoa.Hex()+`", "to": "`+router.Hex()+`", "value": "0xde0b6b3a7640000", "gas": "0x186a0"},
+			"result": {"gasUsed": "0x101d0"}, "traceAddress": [], "blockHash": "`+traceBlock.Hex()+`", "transactionHash": "`+traceTx1.Hex()+`"},
+		{"type": "call", "action": {"callType": "call", "from": "`+router.Hex()+`", "to": "`+pool.Hex()+`", "value": "0xde0b6b3a7640000", "gas": "0x15f90"},
+			"result": {"gasUsed": "0xea60"}, "traceAddress": [0], "blockHash": "`+traceBlock.Hex()+`", "transactionHash": "`+traceTx1.Hex()+`"},
+		{"type": "call", "action": {"callType": "staticcall", "from": "`+pool.Hex()+`", "to": "`+router.Hex()+`", "value": "0x0", "gas": "0x7530"},
+			"result": null, "error": "out of gas", "traceAddress": [0, 0], "blockHash": "`+traceBlock.Hex()+`", "transactionHash": "`+traceTx1.Hex()+`"},
+		{"type": "create", "action": {"creationMethod": "create2", "from": "`+pool.Hex()+`", "value": "0x0", "gas": "0x4e20"},
+			"result": {"gasUsed": "0x2710", "address": "`+created.Hex()+`"}, "traceAddress": [0, 1], "blockHash": "`+traceBlock.Hex()+`", "transactionHash": "`+traceTx1.Hex()+`"},
+		{"type": "call", "action": {"callType": "call", "from": "`+eoa.Hex()+`", "to": "`+eoa.Hex()+`", "value": "0x1", "gas": "0x5208"},
+			"result": {"gasUsed": "0x5208"}, "traceAddress": [], "blockHash": "`+traceBlock.Hex()+`", "transactionHash": "`+traceTx2.Hex()+`"},
+		{"type": "reward", "action": {"author": "`+eoa.Hex()+`", "rewardType": "block", "value": "0x1bc16d674ec80000"},
+			"result": null, "traceAddress": [], "blockHash": "`+traceBlock.Hex()+`", "transactionHash": null}
+	]`), &traces))
+
+	internalTxs, err := flattenParityTraces(traceBlock, 7, traces)
+	require.NoError(t, err)
+	expected := expectedTraces()
+	// trace_block omits the result, and so the gas used, of failed frames.
+	expected[1].GasUsed = 0
+	assert.Equal(t, expected, internalTxs)
+
+	// Children of a failed frame are reverted too.
+	traces[1].Error = "execution reverted"
+	internalTxs, err = flattenParityTraces(traceBlock, 7, traces)
+	require.NoError(t, err)
+	for _, internalTx := range internalTxs {
+		assert.True(t, internalTx.Reverted)
+	}
+
+	traces[0].BlockHash = common.HexToHash("0xb2")
+	_, err = flattenParityTraces(traceBlock, 7, traces)
+	assert.Error(t, err, "trace of another block")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
 
 	"github.com/CaelRowley/geth-indexer-service/pkg/data"
 	"github.com/ethereum/go-ethereum/common"
@@ -11,19 +12,22 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
-func (c EthClient) publishTx(tx *types.Transaction, sender common.Address, receipt *types.Receipt) error {
+func (c EthClient) publishTx(tx *types.Transaction, sender common.Address, receipt *types.Receipt, baseFee *big.Int) error {
 	newTx := data.Transaction{
-		Hash:      tx.Hash().Hex(),
-		From:      sender.Hex(),
-		Contract:  receipt.ContractAddress.Hex(),
-		Value:     tx.Value().Uint64(),
-		Data:      tx.Data(),
-		Gas:       tx.Gas(),
-		GasPrice:  tx.GasPrice().Uint64(),
-		Cost:      tx.Cost().Uint64(),
-		Nonce:     tx.Nonce(),
-		Status:    receipt.Status,
-		BlockHash: receipt.BlockHash.Hex(),
+		Hash:        tx.Hash().Hex(),
+		From:        sender.Hex(),
+		Contract:    receipt.ContractAddress.Hex(),
+		Value:       tx.Value().Uint64(),
+		Data:        tx.Data(),
+		Gas:         tx.Gas(),
+		GasPrice:    tx.GasPrice().Uint64(),
+		Cost:        tx.Cost().Uint64(),
+		Nonce:       tx.Nonce(),
+		Status:      receipt.Status,
+		BlockHash:   receipt.BlockHash.Hex(),
+		BlockNumber: receipt.BlockNumber.Uint64(),
+		PriorityFee: tx.EffectiveGasTipValue(baseFee).Uint64(),
+		GasUsed:     receipt.GasUsed,
 	}
 	if tx.To() != nil {
 		newTx.To = tx.To().Hex()
@@ -38,30 +42,45 @@ func (c EthClient) publishTx(tx *types.Transaction, sender common.Address, recei
 	return nil
 }
 
-func (c EthClient) publishTxs(ctx context.Context, txs types.Transactions, blockHash common.Hash) error {
-	receipts, err := c.batchTransactionReceipts(ctx, txs)
-	if err != nil {
-		return err
-	}
-	if len(receipts) != len(txs) {
-		return fmt.Errorf("len of receipts: %d doesnt match len of txs: %d", len(receipts), len(txs))
-	}
-
+// publishTxs publishes the transactions of a block with their receipts'
+// logs, token events and deployed contracts. It returns the senders,
+// recipients and deployed contracts.
+func (c EthClient) publishTxs(ctx context.Context, txs types.Transactions, receipts []*types.Receipt, blockHash common.Hash, baseFee *big.Int) ([]common.Address, error) {
 	senders, err := c.batchTransactionSenders(ctx, txs, blockHash, receipts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if len(senders) != len(txs) {
-		return fmt.Errorf("len of senders: %d doesnt match len of txs: %d", len(senders), len(txs))
+		return nil, fmt.Errorf("len of senders: %d doesnt match len of txs: %d", len(senders), len(txs))
 	}
 
+	var touched []common.Address
 	for i, tx := range txs {
-		if err := c.publishTx(tx, senders[i], receipts[i]); err != nil {
-			return err
+		touched = append(touched, senders[i])
+		if tx.To() != nil {
+			touched = append(touched, *tx.To())
+		}
+		if err := c.publishTx(tx, senders[i], receipts[i], baseFee); err != nil {
+			return nil, err
+		}
+		if receipts[i].Status == types.ReceiptStatusSuccessful && receipts[i].ContractAddress != (common.Address{}) {
+			touched = append(touched, receipts[i].ContractAddress)
+			if err := c.publishContract(ctx, receipts[i], senders[i]); err != nil {
+				return nil, err
+			}
+		}
+		if err := c.publishLogs(receipts[i].Logs); err != nil {
+			return nil, err
+		}
+		if err := c.publishTokenEvents(ctx, receipts[i].Logs); err != nil {
+			return nil, err
+		}
+		if err := c.publishNFTEvents(ctx, receipts[i].Logs); err != nil {
+			return nil, err
 		}
 	}
 
-	return nil
+	return touched, nil
 }
 
 func (c *EthClient) batchTransactionReceipts(ctx context.Context, txs []*types.Transaction) ([]*types.Receipt, error) {
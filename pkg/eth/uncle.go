@@ -0,0 +1,45 @@
+package eth
+
+import (
+	"encoding/json"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// publishUncles publishes the uncles of a block. ethclient fetches their
+// headers with eth_getUncleByBlockHashAndIndex along with the block.
+func (c EthClient) publishUncles(block *types.Block) error {
+	for _, uncle := range blockUncles(block, c.rewards) {
+		uncleData, err := json.Marshal(uncle)
+		if err != nil {
+			return err
+		}
+		if err := c.PubSub.GetPublisher().PublishUncle(uncleData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blockUncles returns the uncles of a block with their miners' rewards.
+func blockUncles(block *types.Block, rewards rewardRules) []data.Uncle {
+	uncles := make([]data.Uncle, len(block.Uncles()))
+	for i, header := range block.Uncles() {
+		uncles[i] = data.Uncle{
+			BlockHash:   block.Hash().Hex(),
+			UncleIndex:  uint(i),
+			BlockNumber: block.NumberU64(),
+			Hash:        header.Hash().Hex(),
+			Number:      header.Number.Uint64(),
+			ParentHash:  header.ParentHash.Hex(),
+			Miner:       header.Coinbase.Hex(),
+			Difficulty:  header.Difficulty.Uint64(),
+			GasLimit:    header.GasLimit,
+			GasUsed:     header.GasUsed,
+			Time:        header.Time,
+			Reward:      rewards.uncleReward(header.Number.Uint64(), block.NumberU64()).String(),
+		}
+	}
+	return uncles
+}
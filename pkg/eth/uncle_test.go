@@ -0,0 +1,68 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUncleReward(t *testing.T) {
+	mainnet := rewardRulesFor(big.NewInt(1))
+	classic := rewardRulesFor(big.NewInt(classicChainID))
+	tests := []struct {
+		name          string
+		rules         rewardRules
+		uncle, number uint64
+		block, reward string
+	}{
+		{"frontier", mainnet, 99, 100, "5000000000000000000", "4375000000000000000"},
+		{"byzantium", mainnet, 4369994, 4370000, "3000000000000000000", "750000000000000000"},
+		{"constantinople", mainnet, 7279998, 7280000, "2000000000000000000", "1500000000000000000"},
+		{"sepolia", rewardRulesFor(big.NewInt(11155111)), 9, 10, "2000000000000000000", "1750000000000000000"},
+		{"other chain", rewardRulesFor(big.NewInt(1337)), 9, 10, "0", "0"},
+		{"classic era 1", classic, 4999999, 5000000, "5000000000000000000", "4375000000000000000"},
+		{"classic era 2", classic, 5000000, 5000001, "4000000000000000000", "125000000000000000"},
+		{"classic era 3", classic, 10000000, 10000001, "3200000000000000000", "100000000000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.block, tt.rules.blockReward(tt.number).String())
+			assert.Equal(t, tt.reward, tt.rules.uncleReward(tt.uncle, tt.number).String())
+		})
+	}
+}
+
+func TestBlockUncles(t *testing.T) {
+	uncle := &types.Header{
+		Number:     big.NewInt(4369999),
+		ParentHash: common.HexToHash("0x01"),
+		Coinbase:   common.HexToAddress("0xea674fdde714fd979de3edf0f56aa9716b898ec8"),
+		Difficulty: big.NewInt(2500000000000000),
+		GasLimit:   8000000,
+		GasUsed:    7990000,
+		Time:       1508131300,
+	}
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(4370000), Difficulty: big.NewInt(1)}).
+		WithBody(types.Body{Uncles: []*types.Header{uncle}})
+
+	assert.Equal(t, []data.Uncle{{
+		BlockHash:   block.Hash().Hex(),
+		UncleIndex:  0,
+		BlockNumber: 4370000,
+		Hash:        uncle.Hash().Hex(),
+		Number:      4369999,
+		ParentHash:  common.HexToHash("0x01").Hex(),
+		Miner:       "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+		Difficulty:  2500000000000000,
+		GasLimit:    8000000,
+		GasUsed:     7990000,
+		Time:        1508131300,
+		Reward:      "2625000000000000000",
+	}}, blockUncles(block, rewardRulesFor(big.NewInt(1))))
+
+	assert.Empty(t, blockUncles(types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}), rewardRulesFor(big.NewInt(1))))
+}
@@ -0,0 +1,160 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatNDJSON  Format = "ndjson"
+	FormatParquet Format = "parquet"
+)
+
+type Table string
+
+const (
+	TableBlocks       Table = "blocks"
+	TableTransactions Table = "transactions"
+	TableLogs         Table = "logs"
+)
+
+// DefaultPartitionSize is the number of blocks written to each output file.
+const DefaultPartitionSize = 10000
+
+var Tables = []Table{TableBlocks, TableTransactions, TableLogs}
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatCSV, FormatNDJSON, FormatParquet:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported export format: %s", s)
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+func ParseTable(s string) (Table, error) {
+	switch t := Table(s); t {
+	case TableBlocks, TableTransactions, TableLogs:
+		return t, nil
+	default:
+		return "", fmt.Errorf("unsupported export table: %s", s)
+	}
+}
+
+// Range is an inclusive range of block numbers.
+type Range struct {
+	From uint64
+	To   uint64
+}
+
+// Partitions splits [from, to] into ranges of size blocks aligned to multiples
+// of size, so repeated exports produce the same files.
+func Partitions(from, to, size uint64) []Range {
+	if size == 0 {
+		size = DefaultPartitionSize
+	}
+	var ranges []Range
+	for start := from; start <= to; {
+		end := (start/size+1)*size - 1
+		if end > to {
+			end = to
+		}
+		ranges = append(ranges, Range{From: start, To: end})
+		if end == to {
+			break
+		}
+		start = end + 1
+	}
+	return ranges
+}
+
+// Filename is the path of the partition file for table relative to the
+// export directory. Block numbers are zero padded so files sort in order.
+func (r Range) Filename(table Table, format Format) string {
+	return filepath.Join(string(table), fmt.Sprintf("%s_%012d_%012d.%s", table, r.From, r.To, format))
+}
+
+// Write streams the rows of table in r to w.
+func Write(ctx context.Context, dbConn db.DB, w io.Writer, table Table, format Format, r Range) error {
+	switch table {
+	case TableBlocks:
+		return writeRows(w, format, func(fn func(blockRow) error) error {
+			return dbConn.StreamBlocks(ctx, r.From, r.To, func(b *data.Block) error {
+				return fn(newBlockRow(b))
+			})
+		})
+	case TableTransactions:
+		return writeRows(w, format, func(fn func(txRow) error) error {
+			return dbConn.StreamTxs(ctx, r.From, r.To, func(tx *data.Transaction) error {
+				return fn(newTxRow(tx))
+			})
+		})
+	case TableLogs:
+		return writeRows(w, format, func(fn func(logRow) error) error {
+			return dbConn.StreamLogs(ctx, r.From, r.To, func(l *data.Log) error {
+				return fn(newLogRow(l))
+			})
+		})
+	default:
+		return fmt.Errorf("unsupported export table: %s", table)
+	}
+}
+
+// WriteDir exports tables for [from, to] into dir, one file per table and
+// partition of partitionSize blocks.
+func WriteDir(ctx context.Context, dbConn db.DB, dir string, tables []Table, format Format, from, to, partitionSize uint64) ([]string, error) {
+	var files []string
+	for _, r := range Partitions(from, to, partitionSize) {
+		for _, table := range tables {
+			name := filepath.Join(dir, r.Filename(table, format))
+			if err := writeFile(ctx, dbConn, name, table, format, r); err != nil {
+				return files, err
+			}
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+func writeFile(ctx context.Context, dbConn db.DB, name string, table Table, format Format, r Range) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+	// Write to a temporary file first so an interrupted export never leaves a
+	// partial partition behind.
+	tmp := name + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	if err := Write(ctx, dbConn, f, table, format, r); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to export %s %d-%d: %w", table, r.From, r.To, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to close export file: %w", err)
+	}
+	return os.Rename(tmp, name)
+}
@@ -0,0 +1,126 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDB struct {
+	db.DB
+	blocks []data.Block
+	txs    []data.Transaction
+	logs   []data.Log
+}
+
+func (f *fakeDB) StreamBlocks(_ context.Context, from, to uint64, fn func(*data.Block) error) error {
+	for i := range f.blocks {
+		if b := &f.blocks[i]; b.Number >= from && b.Number <= to {
+			if err := fn(b); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeDB) StreamTxs(_ context.Context, from, to uint64, fn func(*data.Transaction) error) error {
+	for i := range f.txs {
+		if tx := &f.txs[i]; tx.BlockNumber >= from && tx.BlockNumber <= to {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeDB) StreamLogs(_ context.Context, from, to uint64, fn func(*data.Log) error) error {
+	for i := range f.logs {
+		if l := &f.logs[i]; l.BlockNumber >= from && l.BlockNumber <= to {
+			if err := fn(l); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{
+		blocks: []data.Block{
+			{Number: 9, Hash: "0x09", Miner: "0xaa", GasUsed: 21000, ExtraData: []byte{0x01}},
+			{Number: 10, Hash: "0x0a", Miner: "0xaa", ExtraData: []byte{}},
+		},
+		txs: []data.Transaction{
+			{Hash: "0x01", BlockNumber: 9, BlockHash: "0x09", From: "0xbb", To: "0xcc", Value: 5, Data: []byte{0xa9, 0x05}},
+		},
+		logs: []data.Log{
+			{BlockNumber: 10, BlockHash: "0x0a", Index: 3, TxHash: "0x02", Address: "0xcc", Topic0: "0xdd", Data: []byte{}},
+		},
+	}
+}
+
+func TestPartitions(t *testing.T) {
+	assert.Equal(t, []Range{{5, 9}, {10, 19}, {20, 21}}, Partitions(5, 21, 10))
+	assert.Equal(t, []Range{{10, 19}}, Partitions(10, 19, 10))
+	assert.Equal(t, []Range{{7, 7}}, Partitions(7, 7, 10))
+	assert.Equal(t, "logs/logs_000000000010_000000000019.parquet", Range{10, 19}.Filename(TableLogs, FormatParquet))
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(context.Background(), newFakeDB(), &buf, TableTransactions, FormatCSV, Range{0, 100})
+	require.NoError(t, err)
+	assert.Equal(t,
+		"block_number,block_hash,hash,from,to,contract,value,gas,gas_price,cost,nonce,status,priority_fee,gas_used,data\n"+
+			"9,0x09,0x01,0xbb,0xcc,,5,0,0,0,0,0,0,0,0xa905\n",
+		buf.String())
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(context.Background(), newFakeDB(), &buf, TableBlocks, FormatNDJSON, Range{10, 10})
+	require.NoError(t, err)
+	assert.Equal(t,
+		`{"number":10,"hash":"0x0a","parent_hash":"","time":0,"miner":"0xaa","gas_limit":0,"gas_used":0,"difficulty":0,"nonce":0,"size":0,"root_hash":"","uncle_hash":"","tx_hash":"","receipt_hash":"","extra_data":"0x","base_fee":0,"priority_fees":"","base_fee_burned":"","blob_fee_burned":"","block_reward":"","uncle_inclusion_reward":""}`+"\n",
+		buf.String())
+}
+
+func TestWriteParquet(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(context.Background(), newFakeDB(), &buf, TableLogs, FormatParquet, Range{0, 100})
+	require.NoError(t, err)
+
+	rows, err := parquet.Read[logRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, []logRow{{BlockNumber: 10, BlockHash: "0x0a", LogIndex: 3, TxHash: "0x02", Address: "0xcc", Topic0: "0xdd", Data: "0x"}}, rows)
+}
+
+func TestWriteDir(t *testing.T) {
+	dir := t.TempDir()
+	files, err := WriteDir(context.Background(), newFakeDB(), dir, []Table{TableBlocks, TableTransactions}, FormatNDJSON, 5, 14, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "blocks/blocks_000000000005_000000000009.ndjson"),
+		filepath.Join(dir, "transactions/transactions_000000000005_000000000009.ndjson"),
+		filepath.Join(dir, "blocks/blocks_000000000010_000000000014.ndjson"),
+		filepath.Join(dir, "transactions/transactions_000000000010_000000000014.ndjson"),
+	}, files)
+
+	b, err := os.ReadFile(files[2])
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(b), "\n"))
+	b, err = os.ReadFile(files[3])
+	require.NoError(t, err)
+	assert.Empty(t, b)
+}
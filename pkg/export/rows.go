@@ -0,0 +1,127 @@
+package export
+
+import (
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// The row types define the flat schema shared by every format, byte columns
+// are hex encoded so CSV, NDJSON and Parquet files load identically.
+
+type blockRow struct {
+	Number      uint64 `json:"number" parquet:"number"`
+	Hash        string `json:"hash" parquet:"hash"`
+	ParentHash  string `json:"parent_hash" parquet:"parent_hash"`
+	Time        uint64 `json:"time" parquet:"time"`
+	Miner       string `json:"miner" parquet:"miner"`
+	GasLimit    uint64 `json:"gas_limit" parquet:"gas_limit"`
+	GasUsed     uint64 `json:"gas_used" parquet:"gas_used"`
+	Difficulty  uint64 `json:"difficulty" parquet:"difficulty"`
+	Nonce       uint64 `json:"nonce" parquet:"nonce"`
+	Size        uint64 `json:"size" parquet:"size"`
+	RootHash    string `json:"root_hash" parquet:"root_hash"`
+	UncleHash   string `json:"uncle_hash" parquet:"uncle_hash"`
+	TxHash      string `json:"tx_hash" parquet:"tx_hash"`
+	ReceiptHash string `json:"receipt_hash" parquet:"receipt_hash"`
+	ExtraData   string `json:"extra_data" parquet:"extra_data"`
+	BaseFee     uint64 `json:"base_fee" parquet:"base_fee"`
+
+	PriorityFees         string `json:"priority_fees" parquet:"priority_fees"`
+	BaseFeeBurned        string `json:"base_fee_burned" parquet:"base_fee_burned"`
+	BlobFeeBurned        string `json:"blob_fee_burned" parquet:"blob_fee_burned"`
+	BlockReward          string `json:"block_reward" parquet:"block_reward"`
+	UncleInclusionReward string `json:"uncle_inclusion_reward" parquet:"uncle_inclusion_reward"`
+}
+
+func newBlockRow(b *data.Block) blockRow {
+	return blockRow{
+		Number:      b.Number,
+		Hash:        b.Hash,
+		ParentHash:  b.ParentHash,
+		Time:        b.Time,
+		Miner:       b.Miner,
+		GasLimit:    b.GasLimit,
+		GasUsed:     b.GasUsed,
+		Difficulty:  b.Difficulty,
+		Nonce:       b.Nonce,
+		Size:        b.Size,
+		RootHash:    b.RootHash,
+		UncleHash:   b.UncleHash,
+		TxHash:      b.TxHash,
+		ReceiptHash: b.ReceiptHash,
+		ExtraData:   hexutil.Encode(b.ExtraData),
+		BaseFee:     b.BaseFee,
+
+		PriorityFees:         b.PriorityFees,
+		BaseFeeBurned:        b.BaseFeeBurned,
+		BlobFeeBurned:        b.BlobFeeBurned,
+		BlockReward:          b.BlockReward,
+		UncleInclusionReward: b.UncleInclusionReward,
+	}
+}
+
+type txRow struct {
+	BlockNumber uint64 `json:"block_number" parquet:"block_number"`
+	BlockHash   string `json:"block_hash" parquet:"block_hash"`
+	Hash        string `json:"hash" parquet:"hash"`
+	From        string `json:"from" parquet:"from"`
+	To          string `json:"to" parquet:"to"`
+	Contract    string `json:"contract" parquet:"contract"`
+	Value       uint64 `json:"value" parquet:"value"`
+	Gas         uint64 `json:"gas" parquet:"gas"`
+	GasPrice    uint64 `json:"gas_price" parquet:"gas_price"`
+	Cost        uint64 `json:"cost" parquet:"cost"`
+	Nonce       uint64 `json:"nonce" parquet:"nonce"`
+	Status      uint64 `json:"status" parquet:"status"`
+	PriorityFee uint64 `json:"priority_fee" parquet:"priority_fee"`
+	GasUsed     uint64 `json:"gas_used" parquet:"gas_used"`
+	Data        string `json:"data" parquet:"data"`
+}
+
+func newTxRow(tx *data.Transaction) txRow {
+	return txRow{
+		BlockNumber: tx.BlockNumber,
+		BlockHash:   tx.BlockHash,
+		Hash:        tx.Hash,
+		From:        tx.From,
+		To:          tx.To,
+		Contract:    tx.Contract,
+		Value:       tx.Value,
+		Gas:         tx.Gas,
+		GasPrice:    tx.GasPrice,
+		Cost:        tx.Cost,
+		Nonce:       tx.Nonce,
+		Status:      tx.Status,
+		PriorityFee: tx.PriorityFee,
+		GasUsed:     tx.GasUsed,
+		Data:        hexutil.Encode(tx.Data),
+	}
+}
+
+type logRow struct {
+	BlockNumber uint64 `json:"block_number" parquet:"block_number"`
+	BlockHash   string `json:"block_hash" parquet:"block_hash"`
+	LogIndex    uint64 `json:"log_index" parquet:"log_index"`
+	TxHash      string `json:"tx_hash" parquet:"tx_hash"`
+	Address     string `json:"address" parquet:"address"`
+	Topic0      string `json:"topic0" parquet:"topic0"`
+	Topic1      string `json:"topic1" parquet:"topic1"`
+	Topic2      string `json:"topic2" parquet:"topic2"`
+	Topic3      string `json:"topic3" parquet:"topic3"`
+	Data        string `json:"data" parquet:"data"`
+}
+
+func newLogRow(l *data.Log) logRow {
+	return logRow{
+		BlockNumber: l.BlockNumber,
+		BlockHash:   l.BlockHash,
+		LogIndex:    uint64(l.Index),
+		TxHash:      l.TxHash,
+		Address:     l.Address,
+		Topic0:      l.Topic0,
+		Topic1:      l.Topic1,
+		Topic2:      l.Topic2,
+		Topic3:      l.Topic3,
+		Data:        hexutil.Encode(l.Data),
+	}
+}
@@ -0,0 +1,132 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	// parquetRowGroupSize bounds how many rows the parquet writer buffers
+	// before flushing a row group, keeping memory constant for large ranges.
+	parquetRowGroupSize = 10000
+	parquetBatchSize    = 1000
+)
+
+type rowWriter[T any] interface {
+	Write(T) error
+	Close() error
+}
+
+// writeRows writes every row produced by stream to w in format.
+func writeRows[T any](w io.Writer, format Format, stream func(func(T) error) error) error {
+	rw, err := newRowWriter[T](w, format)
+	if err != nil {
+		return err
+	}
+	if err := stream(rw.Write); err != nil {
+		return err
+	}
+	return rw.Close()
+}
+
+func newRowWriter[T any](w io.Writer, format Format) (rowWriter[T], error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter[T](w)
+	case FormatNDJSON:
+		return &ndjsonWriter[T]{json.NewEncoder(w)}, nil
+	case FormatParquet:
+		return &parquetWriter[T]{w: parquet.NewGenericWriter[T](w, parquet.MaxRowsPerRowGroup(parquetRowGroupSize))}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+type ndjsonWriter[T any] struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonWriter[T]) Write(row T) error {
+	return n.enc.Encode(row)
+}
+
+func (n *ndjsonWriter[T]) Close() error {
+	return nil
+}
+
+// parquetWriter buffers rows so they are written to the parquet writer in
+// batches rather than one call per row.
+type parquetWriter[T any] struct {
+	w   *parquet.GenericWriter[T]
+	buf []T
+}
+
+func (p *parquetWriter[T]) Write(row T) error {
+	p.buf = append(p.buf, row)
+	if len(p.buf) < parquetBatchSize {
+		return nil
+	}
+	return p.flush()
+}
+
+func (p *parquetWriter[T]) flush() error {
+	if _, err := p.w.Write(p.buf); err != nil {
+		return err
+	}
+	p.buf = p.buf[:0]
+	return nil
+}
+
+func (p *parquetWriter[T]) Close() error {
+	if err := p.flush(); err != nil {
+		return err
+	}
+	return p.w.Close()
+}
+
+// csvWriter writes a header from the json tags of T followed by one record
+// per row. Only string and unsigned integer fields are supported.
+type csvWriter[T any] struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVWriter[T any](w io.Writer) (*csvWriter[T], error) {
+	t := reflect.TypeFor[T]()
+	header := make([]string, t.NumField())
+	for i := range header {
+		header[i] = t.Field(i).Tag.Get("json")
+	}
+	c := &csvWriter[T]{w: csv.NewWriter(w), record: make([]string, len(header))}
+	if err := c.w.Write(header); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *csvWriter[T]) Write(row T) error {
+	v := reflect.ValueOf(row)
+	for i := range c.record {
+		f := v.Field(i)
+		switch f.Kind() {
+		case reflect.String:
+			c.record[i] = f.String()
+		case reflect.Uint, reflect.Uint64:
+			c.record[i] = strconv.FormatUint(f.Uint(), 10)
+		default:
+			return fmt.Errorf("unsupported csv column type: %s", f.Kind())
+		}
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter[T]) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,115 @@
+package gas
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+// DefaultCacheTTL is about half a block on mainnet, so suggestions follow the
+// chain head without recomputing them for every request.
+const DefaultCacheTTL = 5 * time.Second
+
+// Oracle caches the fee suggestions and history computed by the database,
+// keyed by the number of blocks they look at.
+type Oracle struct {
+	dbConn      db.DB
+	suggestions *cache[*data.GasOracle]
+	history     *cache[[]*data.GasHistory]
+}
+
+func NewOracle(dbConn db.DB, ttl time.Duration) *Oracle {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &Oracle{
+		dbConn:      dbConn,
+		suggestions: newCache[*data.GasOracle](ttl),
+		history:     newCache[[]*data.GasHistory](ttl),
+	}
+}
+
+func (o *Oracle) Suggest(ctx context.Context, blocks int) (*data.GasOracle, error) {
+	return o.suggestions.get(ctx, blocks, func(ctx context.Context) (*data.GasOracle, error) {
+		return o.dbConn.GetGasOracle(ctx, blocks)
+	})
+}
+
+func (o *Oracle) History(ctx context.Context, blocks int) ([]*data.GasHistory, error) {
+	return o.history.get(ctx, blocks, func(ctx context.Context) ([]*data.GasHistory, error) {
+		return o.dbConn.GetGasHistory(ctx, blocks)
+	})
+}
+
+type entry[T any] struct {
+	value   T
+	expires time.Time
+}
+
+// call is a load in flight, shared by the requests that missed the same key.
+type call[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// cache loads each missing key once, however many requests miss it at the
+// same time, and doesn't hold its lock while loading so other keys are
+// served meanwhile.
+type cache[T any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[int]entry[T]
+	calls   map[int]*call[T]
+}
+
+func newCache[T any](ttl time.Duration) *cache[T] {
+	return &cache[T]{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[int]entry[T]{},
+		calls:   map[int]*call[T]{},
+	}
+}
+
+// get returns the cached value of key or waits for it to load. The load
+// isn't canceled with the request that started it, so the requests waiting
+// on it don't fail when that one goes away; each only stops waiting when its
+// own ctx is done.
+func (c *cache[T]) get(ctx context.Context, key int, load func(context.Context) (T, error)) (T, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok && c.now().Before(e.expires) {
+		c.mu.Unlock()
+		return e.value, nil
+	}
+	cl, ok := c.calls[key]
+	if !ok {
+		cl = &call[T]{done: make(chan struct{})}
+		c.calls[key] = cl
+		go c.load(context.WithoutCancel(ctx), key, cl, load)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-cl.done:
+		return cl.value, cl.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+func (c *cache[T]) load(ctx context.Context, key int, cl *call[T], load func(context.Context) (T, error)) {
+	cl.value, cl.err = load(ctx)
+	c.mu.Lock()
+	delete(c.calls, key)
+	if cl.err == nil {
+		c.entries[key] = entry[T]{value: cl.value, expires: c.now().Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	close(cl.done)
+}
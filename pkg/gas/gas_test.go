@@ -0,0 +1,109 @@
+package gas
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+type MockDB struct {
+	db.DB
+	mock.Mock
+}
+
+func (m *MockDB) GetGasOracle(_ context.Context, blocks int) (*data.GasOracle, error) {
+	args := m.Called(blocks)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*data.GasOracle), args.Error(1)
+}
+
+func (m *MockDB) GetGasHistory(_ context.Context, blocks int) ([]*data.GasHistory, error) {
+	args := m.Called(blocks)
+	return args.Get(0).([]*data.GasHistory), args.Error(1)
+}
+
+func TestSuggestCaches(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	dbMock := new(MockDB)
+	dbMock.On("GetGasOracle", 20).Return(&data.GasOracle{ToBlock: 100}, nil).Once()
+	dbMock.On("GetGasOracle", 20).Return(&data.GasOracle{ToBlock: 101}, nil).Once()
+	dbMock.On("GetGasOracle", 50).Return(&data.GasOracle{ToBlock: 100}, nil).Once()
+
+	o := NewOracle(dbMock, time.Second)
+	o.suggestions.now = func() time.Time { return now }
+
+	for range 3 {
+		oracle, err := o.Suggest(context.Background(), 20)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(100), oracle.ToBlock)
+	}
+	_, err := o.Suggest(context.Background(), 50)
+	require.NoError(t, err)
+
+	now = now.Add(time.Second)
+	oracle, err := o.Suggest(context.Background(), 20)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(101), oracle.ToBlock)
+	dbMock.AssertExpectations(t)
+}
+
+func TestSuggestDoesNotCacheErrors(t *testing.T) {
+	dbMock := new(MockDB)
+	dbMock.On("GetGasOracle", 20).Return(nil, errors.New("timeout")).Once()
+	dbMock.On("GetGasOracle", 20).Return(&data.GasOracle{ToBlock: 100}, nil).Once()
+
+	o := NewOracle(dbMock, time.Minute)
+	_, err := o.Suggest(context.Background(), 20)
+	assert.Error(t, err)
+	oracle, err := o.Suggest(context.Background(), 20)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), oracle.ToBlock)
+}
+
+func TestSuggestSharesLoads(t *testing.T) {
+	release := make(chan time.Time)
+	dbMock := new(MockDB)
+	dbMock.On("GetGasOracle", 20).WaitUntil(release).Return(&data.GasOracle{ToBlock: 100}, nil).Once()
+	dbMock.On("GetGasOracle", 50).Return(&data.GasOracle{ToBlock: 100}, nil).Once()
+	o := NewOracle(dbMock, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := o.Suggest(ctx, 20)
+		first <- err
+	}()
+	require.Eventually(t, func() bool {
+		o.suggestions.mu.Lock()
+		defer o.suggestions.mu.Unlock()
+		return len(o.suggestions.calls) == 1
+	}, time.Second, time.Millisecond)
+	second := make(chan *data.GasOracle)
+	go func() {
+		oracle, err := o.Suggest(context.Background(), 20)
+		assert.NoError(t, err)
+		second <- oracle
+	}()
+
+	// Other windows are served while 20 blocks load.
+	_, err := o.Suggest(context.Background(), 50)
+	require.NoError(t, err)
+
+	// The request that started the load goes away, the other one still gets
+	// its result.
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+	close(release)
+	assert.Equal(t, uint64(100), (<-second).ToBlock)
+	dbMock.AssertExpectations(t)
+}
@@ -0,0 +1,58 @@
+package graphql
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaString string
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type Handler struct {
+	schema *graphqlgo.Schema
+	dbConn db.DB
+}
+
+// maxDepth bounds the nesting of queries, the schema is cyclic. The deepest
+// path without a cycle is blocks → transactions → logs → event → args → value.
+const maxDepth = 6
+
+func NewHandler(dbConn db.DB) *Handler {
+	schema := graphqlgo.MustParseSchema(schemaString, &queryResolver{}, graphqlgo.MaxDepth(maxDepth))
+	return &Handler{schema, dbConn}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.Serve(w, r, h.dbConn)
+}
+
+// Serve executes the request against dbConn instead of the handler's
+// connection.
+func (h *Handler) Serve(w http.ResponseWriter, r *http.Request, dbConn db.DB) {
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := withLoaders(r.Context(), newLoaders(r.Context(), dbConn))
+	resp := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	respData, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(respData)
+}
@@ -0,0 +1,345 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDB only implements the methods used by the resolvers, calling any
+// other db.DB method panics on the nil embedded interface.
+type MockDB struct {
+	db.DB
+	mock.Mock
+}
+
+func (m *MockDB) FindBlocks(_ context.Context, filter db.BlockFilter) ([]*data.Block, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*data.Block), args.Error(1)
+}
+
+func (m *MockDB) GetBlocksByHashes(_ context.Context, hashes []string, numbers []uint64) ([]*data.Block, error) {
+	sort.Strings(hashes)
+	slices.Sort(numbers)
+	args := m.Called(hashes, numbers)
+	return args.Get(0).([]*data.Block), args.Error(1)
+}
+
+func (m *MockDB) GetTxsByHashes(_ context.Context, hashes []string, blockNumbers []uint64) ([]*data.Transaction, error) {
+	sort.Strings(hashes)
+	slices.Sort(blockNumbers)
+	args := m.Called(hashes, blockNumbers)
+	return args.Get(0).([]*data.Transaction), args.Error(1)
+}
+
+func (m *MockDB) GetTxsByBlockHashes(_ context.Context, hashes []string, numbers []uint64) ([]*data.Transaction, error) {
+	sort.Strings(hashes)
+	slices.Sort(numbers)
+	args := m.Called(hashes, numbers)
+	return args.Get(0).([]*data.Transaction), args.Error(1)
+}
+
+func (m *MockDB) FindLogs(_ context.Context, filter db.LogFilter) ([]*data.Log, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*data.Log), args.Error(1)
+}
+
+func (m *MockDB) GetLogsByTxHashes(_ context.Context, hashes []string) ([]*data.Log, error) {
+	sort.Strings(hashes)
+	args := m.Called(hashes)
+	return args.Get(0).([]*data.Log), args.Error(1)
+}
+
+func (m *MockDB) FindTxs(_ context.Context, filter db.TxFilter) ([]*data.Transaction, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*data.Transaction), args.Error(1)
+}
+
+func (m *MockDB) GetContractABIs(_ context.Context, addresses []string) ([]*data.ContractABI, error) {
+	sort.Strings(addresses)
+	args := m.Called(addresses)
+	return args.Get(0).([]*data.ContractABI), args.Error(1)
+}
+
+func (m *MockDB) GetSignatures(_ context.Context, hashes []string) ([]*data.Signature, error) {
+	sort.Strings(hashes)
+	args := m.Called(hashes)
+	return args.Get(0).([]*data.Signature), args.Error(1)
+}
+
+var mockBlocks = []*data.Block{
+	{Hash: "0xb1", Number: 1, Miner: "0x01"},
+	{Hash: "0xb2", Number: 2, Miner: "0x02"},
+}
+
+var mockTxs = []*data.Transaction{
+	{Hash: "0xt1", From: "0x01", To: "0x02", BlockHash: "0xb1", BlockNumber: 1},
+	{Hash: "0xt2", From: "0x02", BlockHash: "0xb2", BlockNumber: 2, Value: 5000000000},
+}
+
+var mockLogs = []*data.Log{
+	{BlockHash: "0xb1", Index: 0, BlockNumber: 1, TxHash: "0xt1", Address: "0x03", Topic0: "0xaa", Topic1: "0xbb", Data: []byte{1, 2}},
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func execute(t *testing.T, h http.Handler, query string, variables map[string]any) response {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	assert.NoError(t, err)
+	req, err := http.NewRequest("POST", "/graphql", bytes.NewReader(body))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var resp response
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	return resp
+}
+
+func TestNestedQueryIsBatched(t *testing.T) {
+	mockDB := new(MockDB)
+	mockDB.On("FindBlocks", db.BlockFilter{FromNumber: ptr(uint64(1)), Page: db.Page{Limit: 2}}).Return(mockBlocks, nil)
+	mockDB.On("GetTxsByBlockHashes", []string{"0xb1", "0xb2"}, []uint64{1, 2}).Return(mockTxs, nil).Once()
+	mockDB.On("GetLogsByTxHashes", []string{"0xt1", "0xt2"}).Return(mockLogs, nil).Once()
+
+	resp := execute(t, NewHandler(mockDB), `query($from: Long) {
+		blocks(filter: {fromNumber: $from}, first: 2) {
+			number
+			transactions {
+				hash
+				to
+				value
+				logs { index topics data }
+			}
+		}
+	}`, map[string]any{"from": "1"})
+
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"blocks": [
+		{"number": 1, "transactions": [
+			{"hash": "0xt1", "to": "0x02", "value": 0, "logs": [{"index": 0, "topics": ["0xaa", "0xbb"], "data": "0x0102"}]}
+		]},
+		{"number": 2, "transactions": [
+			{"hash": "0xt2", "to": null, "value": 5000000000, "logs": []}
+		]}
+	]}`, string(resp.Data))
+	mockDB.AssertExpectations(t)
+}
+
+func TestDecodedQueryIsBatched(t *testing.T) {
+	transferTopic := "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	calldata := append([]byte{0xa9, 0x05, 0x9c, 0xbb}, make([]byte, 64)...)
+	calldata[len(calldata)-1] = 9
+	txs := []*data.Transaction{
+		{Hash: "0xt1", To: "0x02", BlockHash: "0xb1", Data: calldata},
+		{Hash: "0xt2", To: "0x03", BlockHash: "0xb1", Data: []byte{0x01}},
+	}
+	logs := []*data.Log{
+		{TxHash: "0xt1", Address: "0x02", Topic0: transferTopic, Topic1: "0x01", Topic2: "0x02", Topic3: "0x03"},
+	}
+	mockDB := new(MockDB)
+	mockDB.On("FindBlocks", db.BlockFilter{}).Return(mockBlocks[:1], nil)
+	mockDB.On("GetTxsByBlockHashes", []string{"0xb1"}, []uint64{1}).Return(txs, nil).Once()
+	mockDB.On("GetLogsByTxHashes", []string{"0xt1", "0xt2"}).Return(logs, nil).Once()
+	mockDB.On("GetContractABIs", []string{"0x02"}).Return([]*data.ContractABI{}, nil).Once()
+	mockDB.On("GetSignatures", []string{"0xa9059cbb", transferTopic}).Return([]*data.Signature{
+		{Hash: "0xa9059cbb", Signature: "transfer(address,uint256)"},
+		{Hash: transferTopic, Signature: "Transfer(address,address,uint256)"},
+	}, nil).Once()
+
+	resp := execute(t, NewHandler(mockDB), `{
+		blocks {
+			transactions {
+				method { name source args { type value } }
+				logs { event { signature args { indexed value } } }
+			}
+		}
+	}`, nil)
+
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"blocks": [{"transactions": [
+		{
+			"method": {"name": "transfer", "source": "signature", "args": [
+				{"type": "address", "value": "0x0000000000000000000000000000000000000000"},
+				{"type": "uint256", "value": "9"}
+			]},
+			"logs": [{"event": {"signature": "Transfer(address,address,uint256)", "args": [
+				{"indexed": true, "value": "0x0000000000000000000000000000000000000001"},
+				{"indexed": true, "value": "0x0000000000000000000000000000000000000002"},
+				{"indexed": true, "value": "3"}
+			]}}]
+		},
+		{"method": null, "logs": []}
+	]}]}`, string(resp.Data))
+	mockDB.AssertExpectations(t)
+}
+
+func TestLookupsByHashUseBlockNumbers(t *testing.T) {
+	mockDB := new(MockDB)
+	mockDB.On("FindLogs", db.LogFilter{}).Return(mockLogs, nil)
+	mockDB.On("GetTxsByHashes", []string{"0xt1"}, []uint64{1}).Return(mockTxs[:1], nil).Once()
+	mockDB.On("GetBlocksByHashes", []string{"0xb1"}, []uint64{1}).Return(mockBlocks[:1], nil).Once()
+
+	resp := execute(t, NewHandler(mockDB), `{ logs { transaction { block { number } } } }`, nil)
+
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"logs": [{"transaction": {"block": {"number": 1}}}]}`, string(resp.Data))
+	mockDB.AssertExpectations(t)
+}
+
+func TestLookupByHashWithoutBlockNumber(t *testing.T) {
+	hash := "0x" + strings.Repeat("ab", 32)
+	mockDB := new(MockDB)
+	mockDB.On("GetTxsByHashes", []string{hash}, []uint64(nil)).Return([]*data.Transaction{{Hash: hash}}, nil).Once()
+
+	resp := execute(t, NewHandler(mockDB), `{ transaction(hash: "`+strings.ToUpper(hash[2:])+`") { hash } }`, nil)
+	assert.Len(t, resp.Errors, 1)
+	assert.Equal(t, "hash: must be a 0x prefixed 32 byte hash", resp.Errors[0].Message)
+
+	resp = execute(t, NewHandler(mockDB), `{ transaction(hash: "0x`+strings.ToUpper(hash[2:])+`") { hash } }`, nil)
+
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"transaction": {"hash": "`+hash+`"}}`, string(resp.Data))
+	mockDB.AssertExpectations(t)
+}
+
+func TestAddressQuery(t *testing.T) {
+	address := "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	mockDB := new(MockDB)
+	mockDB.On("FindTxs", db.TxFilter{From: address, Page: db.Page{Offset: 1}}).Return([]*data.Transaction{mockTxs[1]}, nil)
+
+	resp := execute(t, NewHandler(mockDB), `{
+		address(address: "`+strings.ToLower(address)+`") {
+			address
+			transactionsFrom(skip: 1) { hash blockNumber }
+		}
+	}`, nil)
+
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"address": {"address": "`+address+`", "transactionsFrom": [{"hash": "0xt2", "blockNumber": 2}]}}`, string(resp.Data))
+	mockDB.AssertExpectations(t)
+}
+
+func TestFilterArgsAreNormalised(t *testing.T) {
+	address := "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	hash := "0x" + strings.Repeat("cd", 32)
+	mockDB := new(MockDB)
+	mockDB.On("FindTxs", db.TxFilter{From: address, BlockHash: hash}).Return([]*data.Transaction{}, nil).Once()
+	mockDB.On("FindLogs", db.LogFilter{Address: address, Topic0: hash, TxHash: hash}).Return([]*data.Log{}, nil).Once()
+
+	resp := execute(t, NewHandler(mockDB), `query($address: String, $hash: String) {
+		transactions(filter: {from: $address, blockHash: $hash}) { hash }
+		logs(filter: {address: $address, topic0: $hash, txHash: $hash}) { index }
+	}`, map[string]any{"address": strings.ToLower(address), "hash": "0x" + strings.ToUpper(hash[2:])})
+
+	assert.Empty(t, resp.Errors)
+	mockDB.AssertExpectations(t)
+}
+
+func TestInvalidFilterArgs(t *testing.T) {
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{`{ transactions(filter: {from: "0x02"}) { hash } }`, "from: must be a 0x prefixed 20 byte address"},
+		{`{ transactions(filter: {blockHash: "0xb1"}) { hash } }`, "blockHash: must be a 0x prefixed 32 byte hash"},
+		{`{ transactions(filter: {fromBlock: 2, toBlock: 1}) { hash } }`, "invalid filter block: "},
+		{`{ logs(filter: {topic0: "0xaa"}) { index } }`, "topic0: must be a 0x prefixed 32 byte hash"},
+		{`{ block(hash: "0xb1") { number } }`, "hash: must be a 0x prefixed 32 byte hash"},
+	}
+	for _, tt := range tests {
+		mockDB := new(MockDB)
+		resp := execute(t, NewHandler(mockDB), tt.query, nil)
+		if assert.Len(t, resp.Errors, 1, tt.query) {
+			assert.Contains(t, resp.Errors[0].Message, tt.err)
+		}
+		mockDB.AssertExpectations(t)
+	}
+}
+
+func TestBlockTransactionsArePaged(t *testing.T) {
+	txs := []*data.Transaction{
+		{Hash: "0xt1", BlockHash: "0xb1"},
+		{Hash: "0xt2", BlockHash: "0xb1"},
+		{Hash: "0xt3", BlockHash: "0xb1"},
+	}
+	mockDB := new(MockDB)
+	mockDB.On("FindBlocks", db.BlockFilter{}).Return(mockBlocks[:1], nil)
+	mockDB.On("GetTxsByBlockHashes", []string{"0xb1"}, []uint64{1}).Return(txs, nil).Once()
+
+	resp := execute(t, NewHandler(mockDB), `{
+		blocks {
+			first: transactions(first: 2) { hash }
+			rest: transactions(skip: 2) { hash }
+			none: transactions(skip: 5) { hash }
+		}
+	}`, nil)
+
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"blocks": [{
+		"first": [{"hash": "0xt1"}, {"hash": "0xt2"}],
+		"rest": [{"hash": "0xt3"}],
+		"none": []
+	}]}`, string(resp.Data))
+	mockDB.AssertExpectations(t)
+}
+
+func TestMaxDepth(t *testing.T) {
+	resp := execute(t, NewHandler(new(MockDB)), `{
+		blocks {
+			transactions {
+				block {
+					transactions {
+						logs {
+							transaction { hash }
+						}
+					}
+				}
+			}
+		}
+	}`, nil)
+
+	assert.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "exceeds max depth 6")
+}
+
+func TestBlockRequiresArgument(t *testing.T) {
+	resp := execute(t, NewHandler(new(MockDB)), `{ block { hash } }`, nil)
+
+	assert.Len(t, resp.Errors, 1)
+	assert.Equal(t, "either number or hash is required", resp.Errors[0].Message)
+}
+
+func TestLongScalar(t *testing.T) {
+	var l Long
+	assert.NoError(t, l.UnmarshalGraphQL("0x10"))
+	assert.Equal(t, Long(16), l)
+	assert.NoError(t, l.UnmarshalGraphQL(int32(7)))
+	assert.Equal(t, Long(7), l)
+	assert.Error(t, l.UnmarshalGraphQL(int32(-1)))
+	assert.Error(t, l.UnmarshalGraphQL(1.5))
+	assert.Error(t, l.UnmarshalGraphQL(true))
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,198 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"github.com/CaelRowley/geth-indexer-service/pkg/decode"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+type ctxKey struct{}
+
+// batch collects keys from sibling resolvers and fetches them with a single
+// query the first time any of them is loaded. fetchMu serializes fetches while
+// mu only guards the maps, so a fetch can queue keys on other batches.
+type batch[T any] struct {
+	fetchMu sync.Mutex
+	mu      sync.Mutex
+	fetch   func([]string) (map[string]T, error)
+	pending map[string]struct{}
+	loaded  map[string]T
+}
+
+func newBatch[T any](fetch func([]string) (map[string]T, error)) *batch[T] {
+	return &batch[T]{
+		fetch:   fetch,
+		pending: make(map[string]struct{}),
+		loaded:  make(map[string]T),
+	}
+}
+
+func (b *batch[T]) add(keys ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, key := range keys {
+		if _, ok := b.loaded[key]; !ok {
+			b.pending[key] = struct{}{}
+		}
+	}
+}
+
+func (b *batch[T]) load(key string) (T, error) {
+	b.fetchMu.Lock()
+	defer b.fetchMu.Unlock()
+
+	b.mu.Lock()
+	if v, ok := b.loaded[key]; ok {
+		b.mu.Unlock()
+		return v, nil
+	}
+	b.pending[key] = struct{}{}
+	keys := make([]string, 0, len(b.pending))
+	for k := range b.pending {
+		keys = append(keys, k)
+	}
+	b.pending = make(map[string]struct{})
+	b.mu.Unlock()
+
+	result, err := b.fetch(keys)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, k := range keys {
+		b.loaded[k] = result[k]
+	}
+	return b.loaded[key], nil
+}
+
+// blockNumbers remembers the block numbers of the block and transaction
+// hashes the resolvers have seen, so lookups by hash only probe the
+// partitions holding them.
+type blockNumbers struct {
+	mu      sync.Mutex
+	numbers map[string]uint64
+}
+
+func (n *blockNumbers) set(hash string, number uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.numbers[hash] = number
+}
+
+// of returns the block numbers of hashes, nil if any of them is unknown.
+func (n *blockNumbers) of(hashes []string) []uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	numbers := make([]uint64, 0, len(hashes))
+	seen := make(map[uint64]bool, len(hashes))
+	for _, hash := range hashes {
+		number, ok := n.numbers[hash]
+		if !ok {
+			return nil
+		}
+		if !seen[number] {
+			seen[number] = true
+			numbers = append(numbers, number)
+		}
+	}
+	return numbers
+}
+
+// loaders holds the database connection and batches for a single request.
+type loaders struct {
+	dbConn     db.DB
+	numbers    *blockNumbers
+	blocks     *batch[*data.Block]
+	txsByBlock *batch[[]*data.Transaction]
+	txs        *batch[*data.Transaction]
+	logsByTx   *batch[[]*data.Log]
+	abis       *batch[*abi.ABI]
+	signatures *batch[[]string]
+}
+
+// newLoaders wires the batches so that every fetch also queues the keys of the
+// children it returned, letting sibling resolvers share one query per level.
+func newLoaders(ctx context.Context, dbConn db.DB) *loaders {
+	l := &loaders{}
+	*l = loaders{
+		dbConn:  dbConn,
+		numbers: &blockNumbers{numbers: make(map[string]uint64)},
+		blocks: newBatch(func(hashes []string) (map[string]*data.Block, error) {
+			blocks, err := dbConn.GetBlocksByHashes(ctx, hashes, l.numbers.of(hashes))
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[string]*data.Block, len(blocks))
+			for _, block := range blocks {
+				result[block.Hash] = block
+				l.addBlock(block)
+			}
+			return result, nil
+		}),
+		txsByBlock: newBatch(func(hashes []string) (map[string][]*data.Transaction, error) {
+			txs, err := dbConn.GetTxsByBlockHashes(ctx, hashes, l.numbers.of(hashes))
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[string][]*data.Transaction, len(hashes))
+			for _, tx := range txs {
+				result[tx.BlockHash] = append(result[tx.BlockHash], tx)
+				l.logsByTx.add(tx.Hash)
+			}
+			return result, nil
+		}),
+		txs: newBatch(func(hashes []string) (map[string]*data.Transaction, error) {
+			txs, err := dbConn.GetTxsByHashes(ctx, hashes, l.numbers.of(hashes))
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[string]*data.Transaction, len(txs))
+			for _, tx := range txs {
+				result[tx.Hash] = tx
+				l.logsByTx.add(tx.Hash)
+			}
+			return result, nil
+		}),
+		logsByTx: newBatch(func(hashes []string) (map[string][]*data.Log, error) {
+			logs, err := dbConn.GetLogsByTxHashes(ctx, hashes)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[string][]*data.Log, len(hashes))
+			for _, log := range logs {
+				result[log.TxHash] = append(result[log.TxHash], log)
+				l.numbers.set(log.TxHash, log.BlockNumber)
+				l.txs.add(log.TxHash)
+			}
+			return result, nil
+		}),
+		abis: newBatch(func(addresses []string) (map[string]*abi.ABI, error) {
+			return decode.LoadABIs(ctx, dbConn, addresses)
+		}),
+		signatures: newBatch(func(hashes []string) (map[string][]string, error) {
+			return decode.LoadSignatures(ctx, dbConn, hashes)
+		}),
+	}
+	return l
+}
+
+// addBlock queues the transactions of block.
+func (l *loaders) addBlock(block *data.Block) {
+	l.numbers.set(block.Hash, block.Number)
+	l.txsByBlock.add(block.Hash)
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(ctxKey{}).(*loaders)
+}
@@ -0,0 +1,449 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"github.com/CaelRowley/geth-indexer-service/pkg/decode"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"gorm.io/gorm"
+)
+
+var (
+	hashPattern    = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+	addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+)
+
+// hashArg returns the stored, lowercase form of a hash argument, as the
+// REST handlers do, so a checksummed or uppercase hash still matches.
+func hashArg(name string, v *string) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	if !hashPattern.MatchString(*v) {
+		return "", fmt.Errorf("%s: must be a 0x prefixed 32 byte hash", name)
+	}
+	return strings.ToLower(*v), nil
+}
+
+// addressArg returns the checksummed form of an address argument.
+func addressArg(name string, v *string) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	if !addressPattern.MatchString(*v) {
+		return "", fmt.Errorf("%s: must be a 0x prefixed 20 byte address", name)
+	}
+	return common.HexToAddress(*v).Hex(), nil
+}
+
+type pageArgs struct {
+	First *int32
+	Skip  *int32
+}
+
+func (a pageArgs) page() db.Page {
+	var p db.Page
+	if a.First != nil {
+		p.Limit = int(*a.First)
+	}
+	if a.Skip != nil {
+		p.Offset = int(*a.Skip)
+	}
+	return p
+}
+
+// slice pages items loaded in full, with the defaults and maximum of the
+// db package's pages.
+func (a pageArgs) slice(n int) (int, int) {
+	limit, offset := db.DefaultPageSize, 0
+	if a.First != nil && *a.First > 0 {
+		limit = min(int(*a.First), db.MaxPageSize)
+	}
+	if a.Skip != nil && *a.Skip > 0 {
+		offset = min(int(*a.Skip), n)
+	}
+	return offset, min(offset+limit, n)
+}
+
+type queryResolver struct{}
+
+func (q *queryResolver) Block(ctx context.Context, args struct {
+	Number *Long
+	Hash   *string
+}) (*blockResolver, error) {
+	l := loadersFrom(ctx)
+	if args.Hash != nil {
+		hash, err := hashArg("hash", args.Hash)
+		if err != nil {
+			return nil, err
+		}
+		block, err := l.blocks.load(hash)
+		if err != nil || block == nil {
+			return nil, err
+		}
+		return newBlockResolver(block, l), nil
+	}
+	if args.Number != nil {
+		block, err := l.dbConn.GetBlockByNumber(ctx, uint64(*args.Number))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return newBlockResolver(block, l), nil
+	}
+	return nil, errors.New("either number or hash is required")
+}
+
+func (q *queryResolver) Blocks(ctx context.Context, args struct {
+	Filter *struct {
+		FromNumber *Long
+		ToNumber   *Long
+	}
+	pageArgs
+}) ([]*blockResolver, error) {
+	filter := db.BlockFilter{Page: args.page()}
+	if args.Filter != nil {
+		filter.FromNumber = toUint64(args.Filter.FromNumber)
+		filter.ToNumber = toUint64(args.Filter.ToNumber)
+	}
+	blocks, err := loadersFrom(ctx).dbConn.FindBlocks(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return newBlockResolvers(blocks, loadersFrom(ctx)), nil
+}
+
+func (q *queryResolver) Transaction(ctx context.Context, args struct{ Hash string }) (*txResolver, error) {
+	hash, err := hashArg("hash", &args.Hash)
+	if err != nil {
+		return nil, err
+	}
+	l := loadersFrom(ctx)
+	tx, err := l.txs.load(hash)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	return newTxResolver(tx, l), nil
+}
+
+func (q *queryResolver) Transactions(ctx context.Context, args struct {
+	Filter *struct {
+		From      *string
+		To        *string
+		BlockHash *string
+		FromBlock *Long
+		ToBlock   *Long
+	}
+	pageArgs
+}) ([]*txResolver, error) {
+	filter := db.TxFilter{Page: args.page()}
+	if args.Filter != nil {
+		var err error
+		if filter.From, err = addressArg("from", args.Filter.From); err != nil {
+			return nil, err
+		}
+		if filter.To, err = addressArg("to", args.Filter.To); err != nil {
+			return nil, err
+		}
+		if filter.BlockHash, err = hashArg("blockHash", args.Filter.BlockHash); err != nil {
+			return nil, err
+		}
+		filter.FromBlock = toUint64(args.Filter.FromBlock)
+		filter.ToBlock = toUint64(args.Filter.ToBlock)
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	txs, err := loadersFrom(ctx).dbConn.FindTxs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return newTxResolvers(txs, loadersFrom(ctx)), nil
+}
+
+func (q *queryResolver) Logs(ctx context.Context, args struct {
+	Filter *struct {
+		Address   *string
+		Topic0    *string
+		TxHash    *string
+		FromBlock *Long
+		ToBlock   *Long
+	}
+	pageArgs
+}) ([]*logResolver, error) {
+	filter := db.LogFilter{Page: args.page()}
+	if args.Filter != nil {
+		var err error
+		if filter.Address, err = addressArg("address", args.Filter.Address); err != nil {
+			return nil, err
+		}
+		if filter.Topic0, err = hashArg("topic0", args.Filter.Topic0); err != nil {
+			return nil, err
+		}
+		if filter.TxHash, err = hashArg("txHash", args.Filter.TxHash); err != nil {
+			return nil, err
+		}
+		filter.FromBlock = toUint64(args.Filter.FromBlock)
+		filter.ToBlock = toUint64(args.Filter.ToBlock)
+	}
+	logs, err := loadersFrom(ctx).dbConn.FindLogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return newLogResolvers(logs, loadersFrom(ctx)), nil
+}
+
+func (q *queryResolver) Address(ctx context.Context, args struct{ Address string }) (*addressResolver, error) {
+	address, err := addressArg("address", &args.Address)
+	if err != nil {
+		return nil, err
+	}
+	return &addressResolver{address: address, l: loadersFrom(ctx)}, nil
+}
+
+type blockResolver struct {
+	b *data.Block
+	l *loaders
+}
+
+func newBlockResolver(block *data.Block, l *loaders) *blockResolver {
+	l.addBlock(block)
+	return &blockResolver{block, l}
+}
+
+func newBlockResolvers(blocks []*data.Block, l *loaders) []*blockResolver {
+	resolvers := make([]*blockResolver, len(blocks))
+	for i, block := range blocks {
+		resolvers[i] = newBlockResolver(block, l)
+	}
+	return resolvers
+}
+
+func (r *blockResolver) Hash() string        { return r.b.Hash }
+func (r *blockResolver) Number() Long        { return Long(r.b.Number) }
+func (r *blockResolver) GasLimit() Long      { return Long(r.b.GasLimit) }
+func (r *blockResolver) GasUsed() Long       { return Long(r.b.GasUsed) }
+func (r *blockResolver) Difficulty() Long    { return Long(r.b.Difficulty) }
+func (r *blockResolver) Time() Long          { return Long(r.b.Time) }
+func (r *blockResolver) ParentHash() string  { return r.b.ParentHash }
+func (r *blockResolver) Nonce() Long         { return Long(r.b.Nonce) }
+func (r *blockResolver) Miner() string       { return r.b.Miner }
+func (r *blockResolver) Size() Long          { return Long(r.b.Size) }
+func (r *blockResolver) RootHash() string    { return r.b.RootHash }
+func (r *blockResolver) UncleHash() string   { return r.b.UncleHash }
+func (r *blockResolver) TxHash() string      { return r.b.TxHash }
+func (r *blockResolver) ReceiptHash() string { return r.b.ReceiptHash }
+func (r *blockResolver) ExtraData() string   { return hexutil.Encode(r.b.ExtraData) }
+func (r *blockResolver) BaseFee() Long       { return Long(r.b.BaseFee) }
+
+func (r *blockResolver) Transactions(args pageArgs) ([]*txResolver, error) {
+	txs, err := r.l.txsByBlock.load(r.b.Hash)
+	if err != nil {
+		return nil, err
+	}
+	lo, hi := args.slice(len(txs))
+	return newTxResolvers(txs[lo:hi], r.l), nil
+}
+
+type txResolver struct {
+	tx *data.Transaction
+	l  *loaders
+}
+
+func newTxResolver(tx *data.Transaction, l *loaders) *txResolver {
+	l.numbers.set(tx.BlockHash, tx.BlockNumber)
+	l.numbers.set(tx.Hash, tx.BlockNumber)
+	l.blocks.add(tx.BlockHash)
+	l.logsByTx.add(tx.Hash)
+	if selector := decode.CallSelector(tx.Data); tx.To != "" && selector != "" {
+		l.abis.add(tx.To)
+		l.signatures.add(selector)
+	}
+	return &txResolver{tx, l}
+}
+
+func newTxResolvers(txs []*data.Transaction, l *loaders) []*txResolver {
+	resolvers := make([]*txResolver, len(txs))
+	for i, tx := range txs {
+		resolvers[i] = newTxResolver(tx, l)
+	}
+	return resolvers
+}
+
+func (r *txResolver) Hash() string      { return r.tx.Hash }
+func (r *txResolver) From() string      { return r.tx.From }
+func (r *txResolver) Contract() string  { return r.tx.Contract }
+func (r *txResolver) Value() Long       { return Long(r.tx.Value) }
+func (r *txResolver) Data() string      { return hexutil.Encode(r.tx.Data) }
+func (r *txResolver) Gas() Long         { return Long(r.tx.Gas) }
+func (r *txResolver) GasPrice() Long    { return Long(r.tx.GasPrice) }
+func (r *txResolver) Cost() Long        { return Long(r.tx.Cost) }
+func (r *txResolver) Nonce() Long       { return Long(r.tx.Nonce) }
+func (r *txResolver) Status() Long      { return Long(r.tx.Status) }
+func (r *txResolver) BlockHash() string { return r.tx.BlockHash }
+func (r *txResolver) BlockNumber() Long { return Long(r.tx.BlockNumber) }
+func (r *txResolver) PriorityFee() Long { return Long(r.tx.PriorityFee) }
+func (r *txResolver) GasUsed() Long     { return Long(r.tx.GasUsed) }
+
+func (r *txResolver) To() *string {
+	if r.tx.To == "" {
+		return nil
+	}
+	return &r.tx.To
+}
+
+// Method decodes the calldata of a contract call, with the contract's ABI
+// and the selector's signatures loaded for all transactions at once.
+func (r *txResolver) Method() (*decodedResolver, error) {
+	selector := decode.CallSelector(r.tx.Data)
+	if r.tx.To == "" || selector == "" {
+		return nil, nil
+	}
+	contractABI, err := r.l.abis.load(r.tx.To)
+	if err != nil {
+		return nil, err
+	}
+	signatures, err := r.l.signatures.load(selector)
+	if err != nil {
+		return nil, err
+	}
+	return newDecodedResolver(decode.Call(contractABI, signatures, r.tx.Data)), nil
+}
+
+func (r *txResolver) Block() (*blockResolver, error) {
+	block, err := r.l.blocks.load(r.tx.BlockHash)
+	if err != nil || block == nil {
+		return nil, err
+	}
+	return newBlockResolver(block, r.l), nil
+}
+
+func (r *txResolver) Logs() ([]*logResolver, error) {
+	logs, err := r.l.logsByTx.load(r.tx.Hash)
+	if err != nil {
+		return nil, err
+	}
+	return newLogResolvers(logs, r.l), nil
+}
+
+type logResolver struct {
+	log *data.Log
+	l   *loaders
+}
+
+func newLogResolvers(logs []*data.Log, l *loaders) []*logResolver {
+	resolvers := make([]*logResolver, len(logs))
+	for i, log := range logs {
+		l.numbers.set(log.TxHash, log.BlockNumber)
+		l.txs.add(log.TxHash)
+		if log.Topic0 != "" {
+			l.abis.add(log.Address)
+			l.signatures.add(log.Topic0)
+		}
+		resolvers[i] = &logResolver{log, l}
+	}
+	return resolvers
+}
+
+func (r *logResolver) BlockHash() string { return r.log.BlockHash }
+func (r *logResolver) BlockNumber() Long { return Long(r.log.BlockNumber) }
+func (r *logResolver) Index() int32      { return int32(r.log.Index) }
+func (r *logResolver) TxHash() string    { return r.log.TxHash }
+func (r *logResolver) Address() string   { return r.log.Address }
+func (r *logResolver) Topics() []string  { return r.log.Topics() }
+func (r *logResolver) Data() string      { return hexutil.Encode(r.log.Data) }
+
+func (r *logResolver) Event() (*decodedResolver, error) {
+	if r.log.Topic0 == "" {
+		return nil, nil
+	}
+	contractABI, err := r.l.abis.load(r.log.Address)
+	if err != nil {
+		return nil, err
+	}
+	signatures, err := r.l.signatures.load(r.log.Topic0)
+	if err != nil {
+		return nil, err
+	}
+	return newDecodedResolver(decode.Event(contractABI, signatures, r.log)), nil
+}
+
+func (r *logResolver) Transaction() (*txResolver, error) {
+	tx, err := r.l.txs.load(r.log.TxHash)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	return newTxResolver(tx, r.l), nil
+}
+
+type decodedResolver struct {
+	d *data.Decoded
+}
+
+func newDecodedResolver(d *data.Decoded) *decodedResolver {
+	if d == nil {
+		return nil
+	}
+	return &decodedResolver{d}
+}
+
+func (r *decodedResolver) Name() string      { return r.d.Name }
+func (r *decodedResolver) Signature() string { return r.d.Signature }
+func (r *decodedResolver) Source() string    { return r.d.Source }
+
+func (r *decodedResolver) Args() []*decodedArgResolver {
+	args := make([]*decodedArgResolver, len(r.d.Args))
+	for i := range r.d.Args {
+		args[i] = &decodedArgResolver{&r.d.Args[i]}
+	}
+	return args
+}
+
+type decodedArgResolver struct {
+	arg *data.DecodedArg
+}
+
+func (r *decodedArgResolver) Name() string  { return r.arg.Name }
+func (r *decodedArgResolver) Type() string  { return r.arg.Type }
+func (r *decodedArgResolver) Indexed() bool { return r.arg.Indexed }
+func (r *decodedArgResolver) Value() JSON   { return JSON{r.arg.Value} }
+
+type addressResolver struct {
+	address string
+	l       *loaders
+}
+
+func (r *addressResolver) Address() string { return r.address }
+
+func (r *addressResolver) TransactionsFrom(ctx context.Context, args pageArgs) ([]*txResolver, error) {
+	txs, err := r.l.dbConn.FindTxs(ctx, db.TxFilter{From: r.address, Page: args.page()})
+	if err != nil {
+		return nil, err
+	}
+	return newTxResolvers(txs, r.l), nil
+}
+
+func (r *addressResolver) TransactionsTo(ctx context.Context, args pageArgs) ([]*txResolver, error) {
+	txs, err := r.l.dbConn.FindTxs(ctx, db.TxFilter{To: r.address, Page: args.page()})
+	if err != nil {
+		return nil, err
+	}
+	return newTxResolvers(txs, r.l), nil
+}
+
+func (r *addressResolver) Logs(ctx context.Context, args pageArgs) ([]*logResolver, error) {
+	logs, err := r.l.dbConn.FindLogs(ctx, db.LogFilter{Address: r.address, Page: args.page()})
+	if err != nil {
+		return nil, err
+	}
+	return newLogResolvers(logs, r.l), nil
+}
@@ -0,0 +1,68 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Long is a uint64 scalar, GraphQL's Int is limited to 32 bits.
+type Long uint64
+
+func (Long) ImplementsGraphQLType(name string) bool {
+	return name == "Long"
+}
+
+func (l *Long) UnmarshalGraphQL(input interface{}) error {
+	switch v := input.(type) {
+	case string:
+		n, err := strconv.ParseUint(v, 0, 64)
+		if err != nil {
+			return fmt.Errorf("invalid Long %q: %w", v, err)
+		}
+		*l = Long(n)
+	case int32:
+		if v < 0 {
+			return fmt.Errorf("invalid Long %d: must not be negative", v)
+		}
+		*l = Long(v)
+	case float64:
+		if v < 0 || v != float64(uint64(v)) {
+			return fmt.Errorf("invalid Long %v: must be a non-negative integer", v)
+		}
+		*l = Long(v)
+	default:
+		return fmt.Errorf("unexpected type %T for Long", input)
+	}
+	return nil
+}
+
+func (l Long) MarshalJSON() ([]byte, error) {
+	return json.Marshal(uint64(l))
+}
+
+// JSON is a scalar holding any JSON value, used for decoded arguments.
+type JSON struct {
+	value any
+}
+
+func (JSON) ImplementsGraphQLType(name string) bool {
+	return name == "JSON"
+}
+
+func (j *JSON) UnmarshalGraphQL(input interface{}) error {
+	j.value = input
+	return nil
+}
+
+func (j JSON) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.value)
+}
+
+func toUint64(l *Long) *uint64 {
+	if l == nil {
+		return nil
+	}
+	n := uint64(*l)
+	return &n
+}
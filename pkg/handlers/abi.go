@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/decode"
+)
+
+// maxAdminBodySize bounds uploaded ABIs and signature lists.
+const maxAdminBodySize = 1 << 20
+
+// requireAdmin only passes requests with the admin token as their bearer
+// token. Admin endpoints are unavailable when no token is configured.
+func (h *Handlers) requireAdmin(next http.Handler) http.Handler {
+	return makeHandler(func(w http.ResponseWriter, r *http.Request) error {
+		if h.adminToken == "" {
+			return NewAPIError(http.StatusServiceUnavailable, errors.New("admin endpoints are not configured"))
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+			return NewAPIError(http.StatusUnauthorized, errors.New("invalid admin token"))
+		}
+		next.ServeHTTP(w, r)
+		return nil
+	})
+}
+
+// PutContractABI stores the JSON ABI in the request body for a contract and
+// adds its function and event signatures to the signature database.
+func (h *Handlers) PutContractABI(w http.ResponseWriter, r *http.Request) error {
+	address, err := addressParam(r)
+	if err != nil {
+		return err
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAdminBodySize))
+	if err != nil {
+		return InvalidJson(err)
+	}
+	parsed, err := decode.ParseABI(string(body))
+	if err != nil {
+		return InvalidJson(err)
+	}
+
+	signatures := decode.Signatures(parsed)
+	if err := h.dbConn.InsertContractABI(r.Context(), data.ContractABI{Address: address, ABI: string(body)}); err != nil {
+		return fmt.Errorf("failed to insert contract abi: %w", err)
+	}
+	if err := h.dbConn.InsertSignatures(r.Context(), signatures); err != nil {
+		return fmt.Errorf("failed to insert signatures: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, signatures)
+}
+
+type signaturesRequest struct {
+	Functions []string `json:"functions"`
+	Events    []string `json:"events"`
+}
+
+// PostSignatures adds text function and event signatures to the signature
+// database, used to decode calls to and logs of contracts without an ABI.
+func (h *Handlers) PostSignatures(w http.ResponseWriter, r *http.Request) error {
+	var req signaturesRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAdminBodySize)).Decode(&req); err != nil {
+		return InvalidJson(err)
+	}
+
+	signatures := []data.Signature{}
+	errs := map[string]string{}
+	for _, group := range []struct {
+		field string
+		texts []string
+		parse func(string) (data.Signature, error)
+	}{
+		{"functions", req.Functions, decode.FunctionSignature},
+		{"events", req.Events, decode.EventSignature},
+	} {
+		for i, text := range group.texts {
+			signature, err := group.parse(text)
+			if err != nil {
+				errs[fmt.Sprintf("%s[%d]", group.field, i)] = err.Error()
+				continue
+			}
+			signatures = append(signatures, signature)
+		}
+	}
+	if len(errs) > 0 {
+		return InvalidRequestData(errs)
+	}
+
+	if err := h.dbConn.InsertSignatures(r.Context(), signatures); err != nil {
+		return fmt.Errorf("failed to insert signatures: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, signatures)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+)
+
+const transferABI = `[{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"type":"bool"}]}]`
+
+var transferSignature = data.Signature{Hash: "0xa9059cbb", Signature: "transfer(address,uint256)"}
+
+func TestAdmin(t *testing.T) {
+	tests := []struct {
+		name       string
+		adminToken string
+		method     string
+		path       string
+		auth       string
+		body       string
+		setup      func(m *MockDB)
+		code       int
+		expected   string
+	}{
+		{
+			name:       "upload abi",
+			adminToken: "secret",
+			method:     "PUT",
+			path:       "/admin/abi/" + strings.ToLower(usdc),
+			auth:       "Bearer secret",
+			body:       transferABI,
+			setup: func(m *MockDB) {
+				m.On("InsertContractABI", data.ContractABI{Address: usdc, ABI: transferABI}).Return(nil)
+				m.On("InsertSignatures", []data.Signature{transferSignature}).Return(nil)
+			},
+			code:     http.StatusOK,
+			expected: `[{"hash":"0xa9059cbb","signature":"transfer(address,uint256)"}]`,
+		},
+		{
+			name:       "invalid abi",
+			adminToken: "secret",
+			method:     "PUT",
+			path:       "/admin/abi/" + usdc,
+			auth:       "Bearer secret",
+			body:       `not json`,
+			setup:      func(m *MockDB) {},
+			code:       http.StatusBadRequest,
+			expected:   `{"statusCode":400,"msg":"invalid JSON request data invalid character 'o' in literal null (expecting 'u')"}`,
+		},
+		{
+			name:       "signatures",
+			adminToken: "secret",
+			method:     "POST",
+			path:       "/admin/signatures",
+			auth:       "Bearer secret",
+			body:       `{"functions":["transfer(address, uint)"],"events":["Transfer(address,address,uint256)"]}`,
+			setup: func(m *MockDB) {
+				m.On("InsertSignatures", []data.Signature{
+					transferSignature,
+					{Hash: "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", Signature: "Transfer(address,address,uint256)"},
+				}).Return(nil)
+			},
+			code:     http.StatusOK,
+			expected: `[{"hash":"0xa9059cbb","signature":"transfer(address,uint256)"},{"hash":"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef","signature":"Transfer(address,address,uint256)"}]`,
+		},
+		{
+			name:       "invalid signatures",
+			adminToken: "secret",
+			method:     "POST",
+			path:       "/admin/signatures",
+			auth:       "Bearer secret",
+			body:       `{"functions":["transfer(address,uint256)","transfer(address"]}`,
+			setup:      func(m *MockDB) {},
+			code:       http.StatusUnprocessableEntity,
+			expected:   `{"statusCode":422,"msg":{"functions[1]":"failed to parse selector 'transfer(address': expected ')', got ''"}}`,
+		},
+		{
+			name:       "wrong token",
+			adminToken: "secret",
+			method:     "POST",
+			path:       "/admin/signatures",
+			auth:       "Bearer guess",
+			body:       `{}`,
+			setup:      func(m *MockDB) {},
+			code:       http.StatusUnauthorized,
+			expected:   `{"statusCode":401,"msg":"invalid admin token"}`,
+		},
+		{
+			name:     "not configured",
+			method:   "POST",
+			path:     "/admin/signatures",
+			auth:     "Bearer ",
+			body:     `{}`,
+			setup:    func(m *MockDB) {},
+			code:     http.StatusServiceUnavailable,
+			expected: `{"statusCode":503,"msg":"admin endpoints are not configured"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(MockDB)
+			tt.setup(mockDB)
+			handlers := &Handlers{dbConn: mockDB, adminToken: tt.adminToken}
+
+			r := chi.NewRouter()
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(handlers.requireAdmin)
+				r.Put("/abi/{address}", makeHandler(handlers.PutContractABI))
+				r.Post("/signatures", makeHandler(handlers.PostSignatures))
+			})
+
+			req, err := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", tt.auth)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDecodedTxAndLogs(t *testing.T) {
+	calldata := append([]byte{0xa9, 0x05, 0x9c, 0xbb}, make([]byte, 64)...)
+	calldata[4+63] = 7
+	mockDB := new(MockDB)
+	mockDB.On("GetTxByHash", "0xabc", (*uint64)(nil)).Return(data.Transaction{Hash: "0xabc", To: usdc, Data: calldata}, nil)
+	mockDB.On("GetContractABIs", []string{usdc}).Return([]*data.ContractABI{{Address: usdc, ABI: transferABI}}, nil)
+	mockDB.On("GetLogsByTxHashes", []string{"0xabc"}).Return([]*data.Log{
+		{TxHash: "0xabc", Address: bayc, Topic0: "0x01"},
+	}, nil)
+	mockDB.On("GetContractABIs", []string{bayc}).Return([]*data.ContractABI{}, nil)
+	mockDB.On("GetSignatures", []string{"0x01"}).Return([]*data.Signature{}, nil)
+	handlers := &Handlers{dbConn: mockDB}
+
+	r := chi.NewRouter()
+	r.Get("/tx/get-tx/{hash}", makeHandler(handlers.GetTx))
+	r.Get("/tx/get-tx/{hash}/logs", makeHandler(handlers.GetTxLogs))
+
+	req, err := http.NewRequest("GET", "/tx/get-tx/0xabc", nil)
+	assert.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"method":{"name":"transfer","signature":"transfer(address,uint256)","source":"abi","args":[{"name":"to","type":"address","value":"0x0000000000000000000000000000000000000000"},{"name":"amount","type":"uint256","value":"7"}]}`)
+
+	req, err = http.NewRequest("GET", "/tx/get-tx/0xabc/logs", nil)
+	assert.NoError(t, err)
+	recorder = httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), `"event"`)
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// GetAddress returns the summary of an address: its transaction counts and
+// totals, contract registration, latest balance and token balances.
+func (h *Handlers) GetAddress(w http.ResponseWriter, r *http.Request) error {
+	address, err := addressParam(r)
+	if err != nil {
+		return err
+	}
+
+	summary, err := h.reader(r).GetAddressSummary(r.Context(), address)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NotFound(errors.New("address not found"))
+		}
+		return fmt.Errorf("failed to get address summary: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, summary)
+}
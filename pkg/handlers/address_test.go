@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+)
+
+func TestGetAddress(t *testing.T) {
+	summary := &data.AddressSummary{
+		Address: data.Address{
+			Address: usdc, FirstSeenBlock: 6082465, LastSeenBlock: 19000000, TxsSent: 0, TxsReceived: 120,
+			ValueSent: "0", ValueReceived: "0",
+		},
+		Contract: &data.Contract{
+			Address: usdc, Deployer: bayc, TxHash: "0xe7e0fe390354509cd08c9a0168536938600ddc552b3f7cb96030ebef62e75895",
+			BlockHash: "0x4e3a3754410177e6937ef1f84bba68ea139e8d1a2258c5f85db9f1cd715a1bdd", BlockNumber: 6082465,
+			Interfaces: []string{data.StandardERC20},
+		},
+		Tokens: []*data.TokenHolding{},
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		setup    func(m *MockDB)
+		code     int
+		expected string
+	}{
+		{
+			name: "contract",
+			path: "/address/0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+			setup: func(m *MockDB) {
+				m.On("GetAddressSummary", usdc).Return(summary, nil)
+			},
+			code: http.StatusOK,
+			expected: `{"address":"` + usdc + `","firstSeenBlock":6082465,"lastSeenBlock":19000000,"txsSent":0,"txsReceived":120,` +
+				`"valueSent":"0","valueReceived":"0","gasSpent":0,` +
+				`"contract":{"address":"` + usdc + `","deployer":"` + bayc + `",` +
+				`"txHash":"0xe7e0fe390354509cd08c9a0168536938600ddc552b3f7cb96030ebef62e75895",` +
+				`"blockHash":"0x4e3a3754410177e6937ef1f84bba68ea139e8d1a2258c5f85db9f1cd715a1bdd","blockNumber":6082465,` +
+				`"bytecodeHash":"","interfaces":["ERC20"],"internal":false},"balance":null,"tokens":[]}`,
+		},
+		{
+			name: "unknown",
+			path: "/address/" + bayc,
+			setup: func(m *MockDB) {
+				m.On("GetAddressSummary", bayc).Return(nil, gorm.ErrRecordNotFound)
+			},
+			code:     http.StatusNotFound,
+			expected: `{"statusCode":404,"msg":"address not found"}`,
+		},
+		{
+			name:     "invalid address",
+			path:     "/address/0x1234",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusBadRequest,
+			expected: `{"statusCode":400,"msg":"invalid URLParam address: must be a 0x prefixed 20 byte address"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(MockDB)
+			tt.setup(mockDB)
+			handlers := &Handlers{dbConn: mockDB}
+
+			r := chi.NewRouter()
+			r.Get("/address/{address}", makeHandler(handlers.GetAddress))
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"gorm.io/gorm"
+)
+
+// GetAddressBalance returns the balance and nonce of an address after the
+// last block at or before block that touched it.
+func (h *Handlers) GetAddressBalance(w http.ResponseWriter, r *http.Request) error {
+	address, err := addressParam(r)
+	if err != nil {
+		return err
+	}
+	params := newQueryParams(r)
+	block := params.uint64("block")
+	if err := params.err(); err != nil {
+		return err
+	}
+
+	balance, err := h.reader(r).GetBalance(r.Context(), address, block)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NotFound(errors.New("balance not found"))
+		}
+		return fmt.Errorf("failed to get balance: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, balance)
+}
+
+// GetAddressBalanceChart returns the last balance of an address in each hour
+// or day.
+func (h *Handlers) GetAddressBalanceChart(w http.ResponseWriter, r *http.Request) error {
+	address, err := addressParam(r)
+	if err != nil {
+		return err
+	}
+	params := newQueryParams(r)
+	filter := db.BalanceChartFilter{
+		Address:  address,
+		Period:   db.StatsDaily,
+		FromTime: params.fromTime(),
+		ToTime:   params.uint64("toTime"),
+		Page:     db.Page{Limit: params.int("limit"), Offset: params.int("offset")},
+	}
+	if period := params.string("period"); period != "" {
+		filter.Period = db.StatsPeriod(period)
+	}
+	if err := params.err(); err != nil {
+		return err
+	}
+
+	points, err := h.reader(r).GetBalanceChart(r.Context(), filter)
+	if err != nil {
+		var filterErr *db.FilterError
+		if errors.As(err, &filterErr) {
+			return InvalidRequestData(map[string]string{filterErr.Field: filterErr.Msg})
+		}
+		return fmt.Errorf("failed to get balance chart: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, points)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+func TestBalances(t *testing.T) {
+	block := uint64(19000000)
+	from := uint64(1700000000)
+	balance := &data.Balance{
+		Address: usdc, BlockNumber: 18999990, BlockHash: "0x4e3a3754410177e6937ef1f84bba68ea139e8d1a2258c5f85db9f1cd715a1bdd",
+		Time: 1705000000, Balance: "1500000000000000000", Nonce: 3,
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		setup    func(m *MockDB)
+		code     int
+		expected string
+	}{
+		{
+			name: "at block",
+			path: "/address/0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48/balance?block=19000000",
+			setup: func(m *MockDB) {
+				m.On("GetBalance", usdc, &block).Return(balance, nil)
+			},
+			code: http.StatusOK,
+			expected: `{"address":"` + usdc + `","blockNumber":18999990,"blockHash":"0x4e3a3754410177e6937ef1f84bba68ea139e8d1a2258c5f85db9f1cd715a1bdd",` +
+				`"time":1705000000,"balance":"1500000000000000000","nonce":3}`,
+		},
+		{
+			name: "never touched",
+			path: "/address/" + bayc + "/balance",
+			setup: func(m *MockDB) {
+				m.On("GetBalance", bayc, (*uint64)(nil)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			code:     http.StatusNotFound,
+			expected: `{"statusCode":404,"msg":"balance not found"}`,
+		},
+		{
+			name:     "invalid block",
+			path:     "/address/" + bayc + "/balance?block=latest",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusUnprocessableEntity,
+			expected: `{"statusCode":422,"msg":{"block":"must be a non-negative integer"}}`,
+		},
+		{
+			name: "daily chart",
+			path: "/address/" + usdc + "/balance/chart?fromTime=1700000000&limit=30",
+			setup: func(m *MockDB) {
+				m.On("GetBalanceChart", db.BalanceChartFilter{Address: usdc, Period: db.StatsDaily, FromTime: &from, Page: db.Page{Limit: 30}}).
+					Return([]*data.BalancePoint{{Time: 1699920000, BlockNumber: 18600000, Balance: "42", Nonce: 1}}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `[{"time":1699920000,"blockNumber":18600000,"balance":"42","nonce":1}]`,
+		},
+		{
+			name: "invalid period",
+			path: "/address/" + usdc + "/balance/chart?period=weekly",
+			setup: func(m *MockDB) {
+				m.On("GetBalanceChart", db.BalanceChartFilter{Address: usdc, Period: "weekly"}).
+					Return([]*data.BalancePoint(nil), &db.FilterError{Field: "period", Msg: "must be hourly or daily"})
+			},
+			code:     http.StatusUnprocessableEntity,
+			expected: `{"statusCode":422,"msg":{"period":"must be hourly or daily"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(MockDB)
+			tt.setup(mockDB)
+			handlers := &Handlers{dbConn: mockDB}
+
+			r := chi.NewRouter()
+			r.Get("/address/{address}/balance", makeHandler(handlers.GetAddressBalance))
+			r.Get("/address/{address}/balance/chart", makeHandler(handlers.GetAddressBalanceChart))
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
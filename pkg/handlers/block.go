@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
 	"github.com/go-chi/chi"
+	"gorm.io/gorm"
 )
 
 func (h *Handlers) GetBlock(w http.ResponseWriter, r *http.Request) error {
@@ -13,17 +18,74 @@ func (h *Handlers) GetBlock(w http.ResponseWriter, r *http.Request) error {
 	if err != nil {
 		return InvalidURLParam(fmt.Errorf("number: %w", err))
 	}
-	block, err := h.dbConn.GetBlockByNumber(number)
+	dbConn := h.reader(r)
+	block, err := dbConn.GetBlockByNumber(r.Context(), number)
+	return writeBlock(w, r, dbConn, block, err)
+}
+
+func (h *Handlers) GetBlockByHash(w http.ResponseWriter, r *http.Request) error {
+	hash := chi.URLParam(r, "hash")
+	if !hashPattern.MatchString(hash) {
+		return InvalidURLParam(errors.New("hash: must be a 0x prefixed 32 byte hash"))
+	}
+	dbConn := h.reader(r)
+	block, err := dbConn.GetBlockByHash(r.Context(), strings.ToLower(hash))
+	return writeBlock(w, r, dbConn, block, err)
+}
+
+// GetBlockByTime returns the latest block mined at or before ts.
+func (h *Handlers) GetBlockByTime(w http.ResponseWriter, r *http.Request) error {
+	params := newQueryParams(r)
+	ts := params.uint64("ts")
+	if ts == nil && params.errors["ts"] == "" {
+		params.errors["ts"] = "is required"
+	}
+	if err := params.err(); err != nil {
+		return err
+	}
+	dbConn := h.reader(r)
+	block, err := dbConn.GetBlockAtTime(r.Context(), *ts)
+	return writeBlock(w, r, dbConn, block, err)
+}
+
+func (h *Handlers) GetLatestBlock(w http.ResponseWriter, r *http.Request) error {
+	dbConn := h.reader(r)
+	block, err := dbConn.GetLatestBlock(r.Context())
+	return writeBlock(w, r, dbConn, block, err)
+}
+
+// writeBlock responds with a block and its uncles.
+func writeBlock(w http.ResponseWriter, r *http.Request, dbConn db.DB, block *data.Block, err error) error {
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NotFound(errors.New("block not found"))
+		}
 		return fmt.Errorf("failed to get block: %w", err)
 	}
+	if block.Uncles, err = dbConn.GetUncles(r.Context(), block.Hash); err != nil {
+		return fmt.Errorf("failed to get uncles: %w", err)
+	}
 	return setJSONResponse(w, http.StatusOK, block)
 }
 
 func (h *Handlers) GetBlocks(w http.ResponseWriter, r *http.Request) error {
-	blocks, err := h.dbConn.GetBlocks()
+	params := newQueryParams(r)
+	filter := db.BlockFilter{
+		FromNumber: params.uint64("fromBlock"),
+		ToNumber:   params.uint64("toBlock"),
+		Miner:      params.address("miner"),
+		FromTime:   params.fromTime(),
+		ToTime:     params.uint64("toTime"),
+		Page:       db.Page{Limit: params.int("limit"), Offset: params.int("offset")},
+	}
+	if err := params.err(); err != nil {
+		return err
+	}
+
+	blocks, err := h.reader(r).FindBlocks(r.Context(), filter)
 	if err != nil {
 		return fmt.Errorf("failed to get blocks: %w", err)
 	}
+	setNextLink(w, r, filter.Page, len(blocks))
 	return setJSONResponse(w, http.StatusOK, blocks)
 }
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -9,20 +10,22 @@ import (
 	"github.com/go-chi/chi"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 
 	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
 )
 
 type MockDB struct {
 	mock.Mock
 }
 
-func (m *MockDB) InsertBlock(block data.Block) error {
+func (m *MockDB) InsertBlock(_ context.Context, block data.Block) error {
 	args := m.Called(block)
 	return args.Error(0)
 }
 
-func (m *MockDB) GetBlockByNumber(number uint64) (*data.Block, error) {
+func (m *MockDB) GetBlockByNumber(_ context.Context, number uint64) (*data.Block, error) {
 	args := m.Called(number)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -31,7 +34,25 @@ func (m *MockDB) GetBlockByNumber(number uint64) (*data.Block, error) {
 	return &block, args.Error(1)
 }
 
-func (m *MockDB) GetFirstBlock() (*data.Block, error) {
+func (m *MockDB) GetBlockByHash(_ context.Context, hash string) (*data.Block, error) {
+	args := m.Called(hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	block := args.Get(0).(data.Block)
+	return &block, args.Error(1)
+}
+
+func (m *MockDB) GetBlockAtTime(_ context.Context, t uint64) (*data.Block, error) {
+	args := m.Called(t)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	block := args.Get(0).(data.Block)
+	return &block, args.Error(1)
+}
+
+func (m *MockDB) GetFirstBlock(_ context.Context) (*data.Block, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -40,18 +61,46 @@ func (m *MockDB) GetFirstBlock() (*data.Block, error) {
 	return &block, args.Error(1)
 }
 
-func (m *MockDB) GetBlocks() ([]*data.Block, error) {
+func (m *MockDB) GetLatestBlock(_ context.Context) (*data.Block, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	block := args.Get(0).(data.Block)
+	return &block, args.Error(1)
+}
+
+func (m *MockDB) GetFirstBlockSince(_ context.Context, t uint64) (*data.Block, error) {
+	args := m.Called(t)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	block := args.Get(0).(data.Block)
+	return &block, args.Error(1)
+}
+
+func (m *MockDB) GetBlocks(_ context.Context) ([]*data.Block, error) {
 	args := m.Called()
 	return args.Get(0).([]*data.Block), args.Error(1)
 }
 
-func (m *MockDB) InsertTx(tx data.Transaction) error {
+func (m *MockDB) GetBlocksByHashes(_ context.Context, hashes []string, numbers []uint64) ([]*data.Block, error) {
+	args := m.Called(hashes, numbers)
+	return args.Get(0).([]*data.Block), args.Error(1)
+}
+
+func (m *MockDB) FindBlocks(_ context.Context, filter db.BlockFilter) ([]*data.Block, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*data.Block), args.Error(1)
+}
+
+func (m *MockDB) InsertTx(_ context.Context, tx data.Transaction) error {
 	args := m.Called(tx)
 	return args.Error(0)
 }
 
-func (m *MockDB) GetTxByHash(hash string) (*data.Transaction, error) {
-	args := m.Called(hash)
+func (m *MockDB) GetTxByHash(_ context.Context, hash string, blockNumber *uint64) (*data.Transaction, error) {
+	args := m.Called(hash, blockNumber)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -59,11 +108,287 @@ func (m *MockDB) GetTxByHash(hash string) (*data.Transaction, error) {
 	return &block, args.Error(1)
 }
 
-func (m *MockDB) GetTxs() ([]*data.Transaction, error) {
+func (m *MockDB) GetTxs(_ context.Context) ([]*data.Transaction, error) {
 	args := m.Called()
 	return args.Get(0).([]*data.Transaction), args.Error(1)
 }
 
+func (m *MockDB) GetTxsByHashes(_ context.Context, hashes []string, blockNumbers []uint64) ([]*data.Transaction, error) {
+	args := m.Called(hashes, blockNumbers)
+	return args.Get(0).([]*data.Transaction), args.Error(1)
+}
+
+func (m *MockDB) GetTxsByBlockHashes(_ context.Context, hashes []string, numbers []uint64) ([]*data.Transaction, error) {
+	args := m.Called(hashes, numbers)
+	return args.Get(0).([]*data.Transaction), args.Error(1)
+}
+
+func (m *MockDB) FindTxs(_ context.Context, filter db.TxFilter) ([]*data.Transaction, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*data.Transaction), args.Error(1)
+}
+
+func (m *MockDB) InsertLog(_ context.Context, log data.Log) error {
+	args := m.Called(log)
+	return args.Error(0)
+}
+
+func (m *MockDB) GetLogsByTxHashes(_ context.Context, hashes []string) ([]*data.Log, error) {
+	args := m.Called(hashes)
+	return args.Get(0).([]*data.Log), args.Error(1)
+}
+
+func (m *MockDB) FindLogs(_ context.Context, filter db.LogFilter) ([]*data.Log, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*data.Log), args.Error(1)
+}
+
+func (m *MockDB) HasAddress(_ context.Context, address string) (bool, error) {
+	args := m.Called(address)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDB) GetAddressSummary(_ context.Context, address string) (*data.AddressSummary, error) {
+	args := m.Called(address)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*data.AddressSummary), args.Error(1)
+}
+
+func (m *MockDB) StreamBlocks(_ context.Context, fromNumber, toNumber uint64, fn func(*data.Block) error) error {
+	args := m.Called(fromNumber, toNumber)
+	for _, block := range args.Get(0).([]*data.Block) {
+		if err := fn(block); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockDB) StreamTxs(_ context.Context, fromBlock, toBlock uint64, fn func(*data.Transaction) error) error {
+	args := m.Called(fromBlock, toBlock)
+	for _, tx := range args.Get(0).([]*data.Transaction) {
+		if err := fn(tx); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockDB) StreamLogs(_ context.Context, fromBlock, toBlock uint64, fn func(*data.Log) error) error {
+	args := m.Called(fromBlock, toBlock)
+	for _, log := range args.Get(0).([]*data.Log) {
+		if err := fn(log); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockDB) DeleteBlockRange(_ context.Context, fromNumber, toNumber uint64) (int64, error) {
+	args := m.Called(fromNumber, toNumber)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDB) GetDirtyStats(_ context.Context, period db.StatsPeriod, limit int) ([]uint64, error) {
+	args := m.Called(period, limit)
+	return args.Get(0).([]uint64), args.Error(1)
+}
+
+func (m *MockDB) RollupStats(_ context.Context, period db.StatsPeriod, start uint64) error {
+	args := m.Called(period, start)
+	return args.Error(0)
+}
+
+func (m *MockDB) FindStats(_ context.Context, filter db.StatsFilter) ([]*data.Stats, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*data.Stats), args.Error(1)
+}
+
+func (m *MockDB) GetGasOracle(_ context.Context, blocks int) (*data.GasOracle, error) {
+	args := m.Called(blocks)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*data.GasOracle), args.Error(1)
+}
+
+func (m *MockDB) GetGasHistory(_ context.Context, blocks int) ([]*data.GasHistory, error) {
+	args := m.Called(blocks)
+	return args.Get(0).([]*data.GasHistory), args.Error(1)
+}
+
+func (m *MockDB) InsertToken(_ context.Context, token data.Token) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockDB) InsertTokenTransfer(_ context.Context, transfer data.TokenTransfer) error {
+	args := m.Called(transfer)
+	return args.Error(0)
+}
+
+func (m *MockDB) InsertTokenApproval(_ context.Context, approval data.TokenApproval) error {
+	args := m.Called(approval)
+	return args.Error(0)
+}
+
+func (m *MockDB) FindTokenTransfers(_ context.Context, filter db.TokenTransferFilter) ([]*data.TokenTransfer, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*data.TokenTransfer), args.Error(1)
+}
+
+func (m *MockDB) GetTokenHolders(_ context.Context, token string, page db.Page) ([]*data.TokenBalance, error) {
+	args := m.Called(token, page)
+	return args.Get(0).([]*data.TokenBalance), args.Error(1)
+}
+
+func (m *MockDB) GetAddressTokens(_ context.Context, holder string) ([]*data.TokenHolding, error) {
+	args := m.Called(holder)
+	return args.Get(0).([]*data.TokenHolding), args.Error(1)
+}
+
+func (m *MockDB) InsertNFT(_ context.Context, nft data.NFT) error {
+	args := m.Called(nft)
+	return args.Error(0)
+}
+
+func (m *MockDB) InsertNFTTransfer(_ context.Context, transfer data.NFTTransfer) error {
+	args := m.Called(transfer)
+	return args.Error(0)
+}
+
+func (m *MockDB) GetNFT(_ context.Context, contract, tokenID string) (*data.NFT, error) {
+	args := m.Called(contract, tokenID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*data.NFT), args.Error(1)
+}
+
+func (m *MockDB) FindNFTTransfers(_ context.Context, filter db.NFTTransferFilter) ([]*data.NFTTransfer, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*data.NFTTransfer), args.Error(1)
+}
+
+func (m *MockDB) GetAddressNFTs(_ context.Context, filter db.NFTOwnerFilter) ([]*data.NFTHolding, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*data.NFTHolding), args.Error(1)
+}
+
+func (m *MockDB) InsertContract(_ context.Context, contract data.Contract) error {
+	args := m.Called(contract)
+	return args.Error(0)
+}
+
+func (m *MockDB) GetContract(_ context.Context, address string) (*data.Contract, error) {
+	args := m.Called(address)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*data.Contract), args.Error(1)
+}
+
+func (m *MockDB) FindContracts(_ context.Context, filter db.ContractFilter) ([]*data.Contract, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*data.Contract), args.Error(1)
+}
+
+func (m *MockDB) InsertContractABI(_ context.Context, contractABI data.ContractABI) error {
+	args := m.Called(contractABI)
+	return args.Error(0)
+}
+
+func (m *MockDB) InsertSignatures(_ context.Context, signatures []data.Signature) error {
+	args := m.Called(signatures)
+	return args.Error(0)
+}
+
+func (m *MockDB) GetContractABIs(_ context.Context, addresses []string) ([]*data.ContractABI, error) {
+	args := m.Called(addresses)
+	return args.Get(0).([]*data.ContractABI), args.Error(1)
+}
+
+func (m *MockDB) GetSignatures(_ context.Context, hashes []string) ([]*data.Signature, error) {
+	args := m.Called(hashes)
+	return args.Get(0).([]*data.Signature), args.Error(1)
+}
+
+func (m *MockDB) InsertInternalTx(_ context.Context, internalTx data.InternalTx) error {
+	args := m.Called(internalTx)
+	return args.Error(0)
+}
+
+func (m *MockDB) FindInternalTxs(_ context.Context, filter db.InternalTxFilter) ([]*data.InternalTx, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*data.InternalTx), args.Error(1)
+}
+
+func (m *MockDB) InsertBalance(_ context.Context, balance data.Balance) error {
+	args := m.Called(balance)
+	return args.Error(0)
+}
+
+func (m *MockDB) GetBalance(_ context.Context, address string, block *uint64) (*data.Balance, error) {
+	args := m.Called(address, block)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*data.Balance), args.Error(1)
+}
+
+func (m *MockDB) GetBalanceChart(_ context.Context, filter db.BalanceChartFilter) ([]*data.BalancePoint, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*data.BalancePoint), args.Error(1)
+}
+
+func (m *MockDB) InsertUncle(_ context.Context, uncle data.Uncle) error {
+	args := m.Called(uncle)
+	return args.Error(0)
+}
+
+func (m *MockDB) GetUncles(_ context.Context, blockHash string) ([]*data.Uncle, error) {
+	args := m.Called(blockHash)
+	return args.Get(0).([]*data.Uncle), args.Error(1)
+}
+
+func (m *MockDB) GetMinerRewards(_ context.Context, miner string) (*data.MinerRewards, error) {
+	args := m.Called(miner)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*data.MinerRewards), args.Error(1)
+}
+
+func (m *MockDB) GetBurnStats(_ context.Context, filter db.StatsFilter) (*data.BurnStats, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*data.BurnStats), args.Error(1)
+}
+
+func (m *MockDB) Primary() db.DB {
+	args := m.Called()
+	return args.Get(0).(db.DB)
+}
+
+func (m *MockDB) ForChain(chainID uint64) db.DB {
+	args := m.Called(chainID)
+	return args.Get(0).(db.DB)
+}
+
+func (m *MockDB) UnassignedTables(_ context.Context) ([]string, error) {
+	args := m.Called()
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockDB) Ping(_ context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
 func (m *MockDB) Close() error {
 	args := m.Called()
 	return args.Error(0)
@@ -106,10 +431,27 @@ var mockBlocks = []data.Block{
 	},
 }
 
+var mockUncles = []*data.Uncle{
+	{
+		BlockHash:   "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+		UncleIndex:  0,
+		BlockNumber: 1,
+		Hash:        "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
+		Number:      0,
+		ParentHash:  "0xabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd",
+		Miner:       "0x0000000000000000000000000000000000000003",
+		Difficulty:  1000000000,
+		GasLimit:    1000000,
+		Time:        1625812790,
+		Reward:      "4375000000000000000",
+	},
+}
+
 func TestGetBlock(t *testing.T) {
 	recorder := httptest.NewRecorder()
 	mockDB := new(MockDB)
 	mockDB.On("GetBlockByNumber", uint64(1)).Return(mockBlocks[0], nil)
+	mockDB.On("GetUncles", mockBlocks[0].Hash).Return(mockUncles, nil)
 
 	handlers := &Handlers{
 		dbConn: mockDB,
@@ -128,15 +470,43 @@ func TestGetBlock(t *testing.T) {
 	var block data.Block
 	err = json.NewDecoder(recorder.Body).Decode(&block)
 	assert.NoError(t, err)
-	assert.Equal(t, mockBlocks[0], block)
+	expected := mockBlocks[0]
+	expected.Uncles = mockUncles
+	assert.Equal(t, expected, block)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetBlockReadYourWrites(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	primaryDB := new(MockDB)
+	primaryDB.On("GetBlockByNumber", uint64(1)).Return(mockBlocks[0], nil)
+	primaryDB.On("GetUncles", mockBlocks[0].Hash).Return([]*data.Uncle{}, nil)
+	mockDB := new(MockDB)
+	mockDB.On("Primary").Return(primaryDB)
+
+	handlers := &Handlers{
+		dbConn: mockDB,
+	}
+
+	r := chi.NewRouter()
+	r.Get("/get-block/{number}", makeHandler(handlers.GetBlock))
+
+	req, err := http.NewRequest("GET", "/get-block/1", nil)
+	assert.NoError(t, err)
+	req.Header.Set(ReadYourWritesHeader, "true")
+
+	r.ServeHTTP(recorder, req)
 
+	assert.Equal(t, http.StatusOK, recorder.Code)
 	mockDB.AssertExpectations(t)
+	primaryDB.AssertExpectations(t)
 }
 
 func TestGetBlocks(t *testing.T) {
 	recorder := httptest.NewRecorder()
 	mockDB := new(MockDB)
-	mockDB.On("GetBlocks").Return([]*data.Block{&mockBlocks[0], &mockBlocks[1]}, nil)
+	mockDB.On("FindBlocks", db.BlockFilter{}).Return([]*data.Block{&mockBlocks[0], &mockBlocks[1]}, nil)
 
 	handlers := &Handlers{
 		dbConn: mockDB,
@@ -157,6 +527,147 @@ func TestGetBlocks(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, blocks, len(mockBlocks))
 	assert.Equal(t, mockBlocks, blocks)
+	assert.Empty(t, recorder.Header().Get("Link"))
 
 	mockDB.AssertExpectations(t)
 }
+
+func TestGetBlocksFiltered(t *testing.T) {
+	miner := "0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D"
+	fromBlock := uint64(10)
+	toTime := uint64(1625812900)
+
+	mockDB := new(MockDB)
+	mockDB.On("FindBlocks", db.BlockFilter{
+		FromNumber: &fromBlock,
+		Miner:      miner,
+		ToTime:     &toTime,
+		Page:       db.Page{Limit: 5},
+	}).Return([]*data.Block{&mockBlocks[1]}, nil)
+	handlers := &Handlers{dbConn: mockDB}
+
+	r := chi.NewRouter()
+	r.Get("/get-blocks", makeHandler(handlers.GetBlocks))
+
+	req, err := http.NewRequest("GET", "/get-blocks?fromBlock=10&miner=0x7a250d5630b4cf539739df2c5dacb4c659f2488d&toTime=1625812900&limit=5", nil)
+	assert.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetBlocksNextLink(t *testing.T) {
+	mockDB := new(MockDB)
+	mockDB.On("FindBlocks", db.BlockFilter{Page: db.Page{Limit: 2}}).Return([]*data.Block{&mockBlocks[0], &mockBlocks[1]}, nil)
+	handlers := &Handlers{dbConn: mockDB}
+
+	r := chi.NewRouter()
+	r.Get("/get-blocks", makeHandler(handlers.GetBlocks))
+
+	req, err := http.NewRequest("GET", "/get-blocks?limit=2", nil)
+	assert.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `</get-blocks?limit=2&offset=2>; rel="next"`, recorder.Header().Get("Link"))
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetBlockLookups(t *testing.T) {
+	hash := "0xABCDEFabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd"
+
+	tests := []struct {
+		name  string
+		path  string
+		setup func(m *MockDB)
+		code  int
+	}{
+		{
+			name: "unknown number",
+			path: "/block/99",
+			setup: func(m *MockDB) {
+				m.On("GetBlockByNumber", uint64(99)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name: "by hash",
+			path: "/block/hash/" + hash,
+			setup: func(m *MockDB) {
+				m.On("GetBlockByHash", mockBlocks[0].Hash).Return(mockBlocks[0], nil)
+				m.On("GetUncles", mockBlocks[0].Hash).Return([]*data.Uncle{}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:  "invalid hash",
+			path:  "/block/hash/0x123",
+			setup: func(m *MockDB) {},
+			code:  http.StatusBadRequest,
+		},
+		{
+			name: "unknown hash",
+			path: "/block/hash/" + hash,
+			setup: func(m *MockDB) {
+				m.On("GetBlockByHash", mockBlocks[0].Hash).Return(nil, gorm.ErrRecordNotFound)
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name: "by time",
+			path: "/block/by-time?ts=1625812850",
+			setup: func(m *MockDB) {
+				m.On("GetBlockAtTime", uint64(1625812850)).Return(mockBlocks[0], nil)
+				m.On("GetUncles", mockBlocks[0].Hash).Return([]*data.Uncle{}, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:  "missing ts",
+			path:  "/block/by-time",
+			setup: func(m *MockDB) {},
+			code:  http.StatusUnprocessableEntity,
+		},
+		{
+			name: "before first block",
+			path: "/block/by-time?ts=1",
+			setup: func(m *MockDB) {
+				m.On("GetBlockAtTime", uint64(1)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name: "latest",
+			path: "/block/latest",
+			setup: func(m *MockDB) {
+				m.On("GetLatestBlock").Return(mockBlocks[1], nil)
+				m.On("GetUncles", mockBlocks[1].Hash).Return([]*data.Uncle{}, nil)
+			},
+			code: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(MockDB)
+			tt.setup(mockDB)
+			handlers := &Handlers{dbConn: mockDB}
+
+			r := chi.NewRouter()
+			r.Get("/block/{number}", makeHandler(handlers.GetBlock))
+			r.Get("/block/hash/{hash}", makeHandler(handlers.GetBlockByHash))
+			r.Get("/block/by-time", makeHandler(handlers.GetBlockByTime))
+			r.Get("/block/latest", makeHandler(handlers.GetLatestBlock))
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+)
+
+type ChainResponse struct {
+	ChainID uint64 `json:"chainId"`
+	// Default is set on the chain also served at the root.
+	Default bool `json:"default"`
+}
+
+// GetChains lists the indexed chains, whose routes are served under
+// /chains/{chainId}.
+func (h *Handlers) GetChains(w http.ResponseWriter, _ *http.Request) error {
+	chains := make([]ChainResponse, len(h.chains))
+	for i, id := range h.chains {
+		chains[i] = ChainResponse{ChainID: id, Default: i == 0}
+	}
+	return setJSONResponse(w, http.StatusOK, chains)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/search"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetChains(t *testing.T) {
+	r := chi.NewRouter()
+	Init([]Chain{{ID: 1, DB: new(MockDB)}, {ID: 137, DB: new(MockDB)}}, nil, "", r)
+
+	req, err := http.NewRequest("GET", "/chains", nil)
+	assert.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `[{"chainId":1,"default":true},{"chainId":137,"default":false}]`, recorder.Body.String())
+}
+
+func TestChainRoutes(t *testing.T) {
+	mainnetDB := new(MockDB)
+	mainnetDB.On("GetBlockByNumber", uint64(1)).Return(mockBlocks[0], nil)
+	polygonDB := new(MockDB)
+	polygonDB.On("GetBlockByNumber", uint64(1)).Return(mockBlocks[0], nil)
+	searcher := new(MockSearcher)
+	searcher.On("Search", search.Query{ChainID: 137, LastBlocks: 10}).Return(&search.Result{Hits: []search.Hit{}}, nil)
+
+	r := chi.NewRouter()
+	Init([]Chain{{ID: 1, DB: mainnetDB}, {ID: 137, DB: polygonDB}}, searcher, "", r)
+
+	tests := []struct {
+		name     string
+		path     string
+		code     int
+		expected string
+	}{
+		{
+			name:     "default chain at the root",
+			path:     "/search?q=1",
+			code:     http.StatusOK,
+			expected: `{"query":"1","results":[{"type":"block","value":"1","redirect":"/block/get-block/1"}]}`,
+		},
+		{
+			name:     "default chain under its id",
+			path:     "/chains/1/search?q=1",
+			code:     http.StatusOK,
+			expected: `{"query":"1","results":[{"type":"block","value":"1","redirect":"/chains/1/block/get-block/1"}]}`,
+		},
+		{
+			name:     "other chain",
+			path:     "/chains/137/search?q=1",
+			code:     http.StatusOK,
+			expected: `{"query":"1","results":[{"type":"block","value":"1","redirect":"/chains/137/block/get-block/1"}]}`,
+		},
+		{
+			name:     "other chain advanced search",
+			path:     "/chains/137/search/advanced?lastBlocks=10",
+			code:     http.StatusOK,
+			expected: `{"total":0,"hits":[]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+		})
+	}
+	mainnetDB.AssertNumberOfCalls(t, "GetBlockByNumber", 2)
+	polygonDB.AssertNumberOfCalls(t, "GetBlockByNumber", 1)
+	searcher.AssertExpectations(t)
+
+	req, err := http.NewRequest("GET", "/chains/5/block/latest", nil)
+	assert.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"gorm.io/gorm"
+)
+
+func (h *Handlers) GetContract(w http.ResponseWriter, r *http.Request) error {
+	address, err := addressParam(r)
+	if err != nil {
+		return err
+	}
+	contract, err := h.reader(r).GetContract(r.Context(), address)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NotFound(errors.New("contract not found"))
+		}
+		return fmt.Errorf("failed to get contract: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, contract)
+}
+
+func (h *Handlers) GetContracts(w http.ResponseWriter, r *http.Request) error {
+	params := newQueryParams(r)
+	filter := db.ContractFilter{
+		Deployer: params.address("deployer"),
+		Page:     db.Page{Limit: params.int("limit"), Offset: params.int("offset")},
+	}
+	if err := params.err(); err != nil {
+		return err
+	}
+
+	contracts, err := h.reader(r).FindContracts(r.Context(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to get contracts: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, contracts)
+}
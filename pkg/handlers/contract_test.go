@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+func TestContracts(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		setup    func(m *MockDB)
+		code     int
+		expected string
+	}{
+		{
+			name: "contract",
+			path: "/contract/0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d",
+			setup: func(m *MockDB) {
+				m.On("GetContract", bayc).Return(&data.Contract{
+					Address: bayc, Deployer: usdc, BlockNumber: 12287507,
+					Interfaces: []string{data.StandardERC165, data.StandardERC721},
+				}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `{"address":"` + bayc + `","deployer":"` + usdc + `","txHash":"","blockHash":"","blockNumber":12287507,"bytecodeHash":"","interfaces":["ERC165","ERC721"],"internal":false}`,
+		},
+		{
+			name: "unknown contract",
+			path: "/contract/" + usdc,
+			setup: func(m *MockDB) {
+				m.On("GetContract", usdc).Return(nil, gorm.ErrRecordNotFound)
+			},
+			code:     http.StatusNotFound,
+			expected: `{"statusCode":404,"msg":"contract not found"}`,
+		},
+		{
+			name: "by deployer",
+			path: "/contracts?deployer=" + usdc + "&limit=2",
+			setup: func(m *MockDB) {
+				m.On("FindContracts", db.ContractFilter{Deployer: usdc, Page: db.Page{Limit: 2}}).
+					Return([]*data.Contract{{Address: bayc, Deployer: usdc, Interfaces: []string{}}}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `[{"address":"` + bayc + `","deployer":"` + usdc + `","txHash":"","blockHash":"","blockNumber":0,"bytecodeHash":"","interfaces":[],"internal":false}]`,
+		},
+		{
+			name:     "invalid deployer",
+			path:     "/contracts?deployer=0x12",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusUnprocessableEntity,
+			expected: `{"statusCode":422,"msg":{"deployer":"must be a 0x prefixed 20 byte address"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(MockDB)
+			tt.setup(mockDB)
+			handlers := &Handlers{dbConn: mockDB}
+
+			r := chi.NewRouter()
+			r.Get("/contract/{address}", makeHandler(handlers.GetContract))
+			r.Get("/contracts", makeHandler(handlers.GetContracts))
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/export"
+	"github.com/go-chi/chi"
+)
+
+func (h *Handlers) Export(w http.ResponseWriter, r *http.Request) error {
+	table, err := export.ParseTable(chi.URLParam(r, "table"))
+	if err != nil {
+		return InvalidURLParam(err)
+	}
+
+	params := newQueryParams(r)
+	format := export.FormatCSV
+	if f := params.string("format"); f != "" {
+		if format, err = export.ParseFormat(f); err != nil {
+			params.errors["format"] = "must be csv, ndjson or parquet"
+		}
+	}
+	from, to := params.uint64("fromBlock"), params.uint64("toBlock")
+	if from == nil && params.errors["fromBlock"] == "" {
+		params.errors["fromBlock"] = "is required"
+	}
+	if to == nil && params.errors["toBlock"] == "" {
+		params.errors["toBlock"] = "is required"
+	}
+	if from != nil && to != nil {
+		if *to < *from {
+			params.errors["toBlock"] = "must not be less than fromBlock"
+		} else if *to-*from >= export.DefaultPartitionSize {
+			params.errors["toBlock"] = fmt.Sprintf("range must not exceed %d blocks", export.DefaultPartitionSize)
+		}
+	}
+	if err := params.err(); err != nil {
+		return err
+	}
+
+	rng := export.Range{From: *from, To: *to}
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(rng.Filename(table, format))))
+	if err := export.Write(r.Context(), h.reader(r), w, table, format, rng); err != nil {
+		// The status line has been sent so the error can't be reported in the
+		// body, abort the response so the client sees a truncated transfer.
+		slog.Error("export failed", "err", err, "table", table, "from", rng.From, "to", rng.To)
+		panic(http.ErrAbortHandler)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		setup       func(m *MockDB)
+		code        int
+		contentType string
+		expected    string
+	}{
+		{
+			name: "logs as ndjson",
+			path: "/export/logs?fromBlock=1&toBlock=2&format=ndjson",
+			setup: func(m *MockDB) {
+				m.On("StreamLogs", uint64(1), uint64(2)).Return([]*data.Log{{BlockNumber: 1, BlockHash: "0x01", Index: 2, Address: "0xaa"}}, nil)
+			},
+			code:        http.StatusOK,
+			contentType: "application/x-ndjson",
+			expected:    `{"block_number":1,"block_hash":"0x01","log_index":2,"tx_hash":"","address":"0xaa","topic0":"","topic1":"","topic2":"","topic3":"","data":"0x"}` + "\n",
+		},
+		{
+			name: "transactions as csv by default",
+			path: "/export/transactions?fromBlock=5&toBlock=5",
+			setup: func(m *MockDB) {
+				m.On("StreamTxs", uint64(5), uint64(5)).Return([]*data.Transaction{}, nil)
+			},
+			code:        http.StatusOK,
+			contentType: "text/csv",
+			expected:    "block_number,block_hash,hash,from,to,contract,value,gas,gas_price,cost,nonce,status,priority_fee,gas_used,data\n",
+		},
+		{
+			name:        "unknown table",
+			path:        "/export/receipts?fromBlock=1&toBlock=2",
+			setup:       func(m *MockDB) {},
+			code:        http.StatusBadRequest,
+			contentType: "application/json",
+			expected:    `{"statusCode":400,"msg":"invalid URLParam unsupported export table: receipts"}`,
+		},
+		{
+			name:        "invalid parameters",
+			path:        "/export/blocks?toBlock=20000&format=xml",
+			setup:       func(m *MockDB) {},
+			code:        http.StatusUnprocessableEntity,
+			contentType: "application/json",
+			expected:    `{"statusCode":422,"msg":{"format":"must be csv, ndjson or parquet","fromBlock":"is required"}}`,
+		},
+		{
+			name:        "range too large",
+			path:        "/export/blocks?fromBlock=0&toBlock=10000",
+			setup:       func(m *MockDB) {},
+			code:        http.StatusUnprocessableEntity,
+			contentType: "application/json",
+			expected:    `{"statusCode":422,"msg":{"toBlock":"range must not exceed 10000 blocks"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(MockDB)
+			tt.setup(mockDB)
+			handlers := &Handlers{dbConn: mockDB}
+
+			r := chi.NewRouter()
+			r.Get("/export/{table}", makeHandler(handlers.Export))
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.Equal(t, tt.contentType, recorder.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expected, recorder.Body.String())
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultOracleBlocks  = 20
+	defaultHistoryBlocks = 100
+)
+
+func (h *Handlers) GetGasOracle(w http.ResponseWriter, r *http.Request) error {
+	blocks, err := gasBlocks(r, defaultOracleBlocks)
+	if err != nil {
+		return err
+	}
+	oracle, err := h.gas.Suggest(r.Context(), blocks)
+	if err != nil {
+		return gasError(err)
+	}
+	return setJSONResponse(w, http.StatusOK, oracle)
+}
+
+func (h *Handlers) GetGasHistory(w http.ResponseWriter, r *http.Request) error {
+	blocks, err := gasBlocks(r, defaultHistoryBlocks)
+	if err != nil {
+		return err
+	}
+	history, err := h.gas.History(r.Context(), blocks)
+	if err != nil {
+		return gasError(err)
+	}
+	return setJSONResponse(w, http.StatusOK, history)
+}
+
+// gasBlocks reads the number of recent blocks to look at, fallback when
+// unset.
+func gasBlocks(r *http.Request, fallback int) (int, error) {
+	params := newQueryParams(r)
+	blocks := params.int("blocks")
+	if blocks > db.MaxGasBlocks {
+		params.errors["blocks"] = fmt.Sprintf("must be at most %d", db.MaxGasBlocks)
+	}
+	if err := params.err(); err != nil {
+		return 0, err
+	}
+	if blocks == 0 {
+		return fallback, nil
+	}
+	return blocks, nil
+}
+
+func gasError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return NotFound(errors.New("no blocks indexed"))
+	}
+	return fmt.Errorf("failed to get gas fees: %w", err)
+}
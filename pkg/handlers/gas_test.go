@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/gas"
+)
+
+func TestGas(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		setup    func(m *MockDB)
+		code     int
+		expected string
+	}{
+		{
+			name: "oracle",
+			path: "/gas/oracle",
+			setup: func(m *MockDB) {
+				m.On("GetGasOracle", 20).Return(&data.GasOracle{
+					FromBlock: 81, ToBlock: 100, BaseFee: 10, NextBaseFee: 11,
+					Slow:     data.FeeSuggestion{MaxPriorityFee: 1, MaxFee: 12},
+					Standard: data.FeeSuggestion{MaxPriorityFee: 2, MaxFee: 13},
+					Fast:     data.FeeSuggestion{MaxPriorityFee: 5, MaxFee: 17},
+				}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `{"fromBlock":81,"toBlock":100,"baseFee":10,"nextBaseFee":11,"slow":{"maxPriorityFeePerGas":1,"maxFeePerGas":12},"standard":{"maxPriorityFeePerGas":2,"maxFeePerGas":13},"fast":{"maxPriorityFeePerGas":5,"maxFeePerGas":17}}`,
+		},
+		{
+			name: "oracle without blocks",
+			path: "/gas/oracle?blocks=5",
+			setup: func(m *MockDB) {
+				m.On("GetGasOracle", 5).Return(nil, gorm.ErrRecordNotFound)
+			},
+			code:     http.StatusNotFound,
+			expected: `{"statusCode":404,"msg":"no blocks indexed"}`,
+		},
+		{
+			name: "history",
+			path: "/gas/history?blocks=1",
+			setup: func(m *MockDB) {
+				m.On("GetGasHistory", 1).Return([]*data.GasHistory{{Number: 100, BaseFee: 10, Txs: 2, AvgPriorityFee: 3}}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `[{"number":100,"time":0,"baseFee":10,"gasUsed":0,"gasLimit":0,"txs":2,"minPriorityFee":0,"avgPriorityFee":3,"maxPriorityFee":0}]`,
+		},
+		{
+			name:     "too many blocks",
+			path:     "/gas/history?blocks=5000",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusUnprocessableEntity,
+			expected: `{"statusCode":422,"msg":{"blocks":"must be at most 1024"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(MockDB)
+			tt.setup(mockDB)
+			handlers := &Handlers{dbConn: mockDB, gas: gas.NewOracle(mockDB, 0)}
+
+			r := chi.NewRouter()
+			r.Get("/gas/oracle", makeHandler(handlers.GetGasOracle))
+			r.Get("/gas/history", makeHandler(handlers.GetGasHistory))
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
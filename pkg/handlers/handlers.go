@@ -7,13 +7,37 @@ import (
 	"net/http"
 
 	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"github.com/CaelRowley/geth-indexer-service/pkg/gas"
+	"github.com/CaelRowley/geth-indexer-service/pkg/graphql"
+	"github.com/CaelRowley/geth-indexer-service/pkg/retention"
+	"github.com/CaelRowley/geth-indexer-service/pkg/search"
 	"github.com/go-chi/chi"
 )
 
 type Handlers struct {
-	dbConn db.DB
+	chainID    uint64
+	dbConn     db.DB
+	searcher   search.Searcher
+	pruner     *retention.Pruner
+	gas        *gas.Oracle
+	adminToken string
+	// chains lists the served chains, the first one is the default.
+	chains []uint64
+	// prefix is the path the routes are served under, empty at the root.
+	prefix string
 }
 
+// Chain is an indexed chain served by the API, DB is scoped to it.
+type Chain struct {
+	ID     uint64
+	DB     db.DB
+	Pruner *retention.Pruner
+}
+
+// ReadYourWritesHeader set to true makes a request read from the primary
+// database, so it sees writes the replicas may not have applied yet.
+const ReadYourWritesHeader = "X-Read-Your-Writes"
+
 type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
 
 type APIError struct {
@@ -21,22 +45,100 @@ type APIError struct {
 	Msg        any `json:"msg"`
 }
 
-func Init(dbConn db.DB, r *chi.Mux) {
-	h := Handlers{
-		dbConn: dbConn,
+// Init registers the routes of every chain under /chains/{chainId}. The
+// routes of the first chain are also served at the root.
+func Init(chains []Chain, searcher search.Searcher, adminToken string, r *chi.Mux) {
+	ids := make([]uint64, len(chains))
+	for i, chain := range chains {
+		ids[i] = chain.ID
+	}
+	for i, chain := range chains {
+		h := Handlers{
+			chainID:    chain.ID,
+			dbConn:     chain.DB,
+			searcher:   searcher,
+			pruner:     chain.Pruner,
+			gas:        gas.NewOracle(chain.DB, gas.DefaultCacheTTL),
+			adminToken: adminToken,
+			chains:     ids,
+		}
+		if i == 0 {
+			r.Get("/", h.healthCheckHandler)
+			r.Get("/openapi.json", h.openAPIHandler)
+			r.Get("/chains", makeHandler(h.GetChains))
+			h.routes(r)
+		}
+		h.prefix = fmt.Sprintf("/chains/%d", chain.ID)
+		r.Route(h.prefix, h.routes)
 	}
+}
 
-	r.Get("/", h.healthCheckHandler)
+// routes registers the routes served for h's chain.
+func (h Handlers) routes(r chi.Router) {
+	r.Get("/ready", makeHandler(h.Ready))
 	r.Route("/block", func(r chi.Router) {
 		r.Get("/get-block/{number}", makeHandler(h.GetBlock))
 		r.Get("/get-blocks", makeHandler(h.GetBlocks))
+		r.Get("/hash/{hash}", makeHandler(h.GetBlockByHash))
+		r.Get("/by-time", makeHandler(h.GetBlockByTime))
+		r.Get("/latest", makeHandler(h.GetLatestBlock))
 	})
 	r.Route("/tx", func(r chi.Router) {
 		r.Get("/get-tx/{hash}", makeHandler(h.GetTx))
 		r.Get("/get-txs", makeHandler(h.GetTxs))
+		r.Get("/get-tx/{hash}/logs", makeHandler(h.GetTxLogs))
+		r.Get("/get-tx/{hash}/internal-txs", makeHandler(h.GetTxInternalTxs))
+	})
+	r.Route("/token/{address}", func(r chi.Router) {
+		r.Get("/transfers", makeHandler(h.GetTokenTransfers))
+		r.Get("/holders", makeHandler(h.GetTokenHolders))
+	})
+	r.Route("/nft/{address}/{tokenId}", func(r chi.Router) {
+		r.Get("/", makeHandler(h.GetNFT))
+		r.Get("/transfers", makeHandler(h.GetNFTTransfers))
+	})
+	r.Get("/contract/{address}", makeHandler(h.GetContract))
+	r.Get("/contracts", makeHandler(h.GetContracts))
+	r.Route("/address/{address}", func(r chi.Router) {
+		r.Get("/", makeHandler(h.GetAddress))
+		r.Get("/tokens", makeHandler(h.GetAddressTokens))
+		r.Get("/nfts", makeHandler(h.GetAddressNFTs))
+		r.Get("/internal-txs", makeHandler(h.GetAddressInternalTxs))
+		r.Get("/balance", makeHandler(h.GetAddressBalance))
+		r.Get("/balance/chart", makeHandler(h.GetAddressBalanceChart))
+	})
+	r.Get("/miner/{address}/rewards", makeHandler(h.GetMinerRewards))
+	r.Route("/gas", func(r chi.Router) {
+		r.Get("/oracle", makeHandler(h.GetGasOracle))
+		r.Get("/history", makeHandler(h.GetGasHistory))
+	})
+	r.Route("/stats", func(r chi.Router) {
+		r.Get("/daily", makeHandler(h.GetDailyStats))
+		r.Get("/hourly", makeHandler(h.GetHourlyStats))
+		r.Get("/burn", makeHandler(h.GetBurnStats))
+	})
+	r.Get("/search", makeHandler(h.Search))
+	r.Get("/search/advanced", makeHandler(h.AdvancedSearch))
+	r.Get("/export/{table}", makeHandler(h.Export))
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(h.requireAdmin)
+		r.Put("/abi/{address}", makeHandler(h.PutContractABI))
+		r.Post("/signatures", makeHandler(h.PostSignatures))
+	})
+	gql := graphql.NewHandler(h.dbConn)
+	r.Post("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		gql.Serve(w, r, h.reader(r))
 	})
 }
 
+// reader returns the connection queries for r should use.
+func (h Handlers) reader(r *http.Request) db.DB {
+	if r.Header.Get(ReadYourWritesHeader) == "true" {
+		return h.dbConn.Primary()
+	}
+	return h.dbConn
+}
+
 func makeHandler(h HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if err := h(w, r); err != nil {
@@ -84,6 +186,10 @@ func InvalidURLParam(err error) APIError {
 	return NewAPIError(http.StatusBadRequest, fmt.Errorf("invalid URLParam %w", err))
 }
 
+func NotFound(err error) APIError {
+	return NewAPIError(http.StatusNotFound, err)
+}
+
 func InvalidRequestData(errors map[string]string) APIError {
 	return APIError{
 		StatusCode: http.StatusUnprocessableEntity,
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"github.com/go-chi/chi"
+)
+
+// GetTxInternalTxs returns the internal transactions of a transaction, which
+// are only indexed when tracing is enabled.
+func (h *Handlers) GetTxInternalTxs(w http.ResponseWriter, r *http.Request) error {
+	params := newQueryParams(r)
+	filter := db.InternalTxFilter{
+		TxHash: chi.URLParam(r, "hash"),
+		Page:   db.Page{Limit: params.int("limit"), Offset: params.int("offset")},
+	}
+	if err := params.err(); err != nil {
+		return err
+	}
+	return h.findInternalTxs(w, r, filter)
+}
+
+// GetAddressInternalTxs returns the internal transactions sent from or to an
+// address.
+func (h *Handlers) GetAddressInternalTxs(w http.ResponseWriter, r *http.Request) error {
+	address, err := addressParam(r)
+	if err != nil {
+		return err
+	}
+	params := newQueryParams(r)
+	filter := db.InternalTxFilter{
+		Address: address,
+		Page:    db.Page{Limit: params.int("limit"), Offset: params.int("offset")},
+	}
+	if err := params.err(); err != nil {
+		return err
+	}
+	return h.findInternalTxs(w, r, filter)
+}
+
+func (h *Handlers) findInternalTxs(w http.ResponseWriter, r *http.Request, filter db.InternalTxFilter) error {
+	internalTxs, err := h.reader(r).FindInternalTxs(r.Context(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to get internal txs: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, internalTxs)
+}
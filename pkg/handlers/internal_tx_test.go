@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+func TestInternalTxs(t *testing.T) {
+	txHash := "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060"
+	internalTx := &data.InternalTx{
+		BlockHash: "0x4e3a3754410177e6937ef1f84bba68ea139e8d1a2258c5f85db9f1cd715a1bdd", TxHash: txHash,
+		TraceIndex: 1, BlockNumber: 46147, Depth: 2, Type: "CALL", From: bayc, To: usdc,
+		Value: "31337", Gas: 2300, Error: "out of gas", Reverted: true,
+	}
+	internalTxJSON := `{"blockHash":"0x4e3a3754410177e6937ef1f84bba68ea139e8d1a2258c5f85db9f1cd715a1bdd","txHash":"` + txHash + `",` +
+		`"traceIndex":1,"blockNumber":46147,"depth":2,"type":"CALL","from":"` + bayc + `","to":"` + usdc + `",` +
+		`"value":"31337","gas":2300,"gasUsed":0,"error":"out of gas","reverted":true}`
+
+	tests := []struct {
+		name     string
+		path     string
+		setup    func(m *MockDB)
+		code     int
+		expected string
+	}{
+		{
+			name: "by tx",
+			path: "/tx/get-tx/" + txHash + "/internal-txs",
+			setup: func(m *MockDB) {
+				m.On("FindInternalTxs", db.InternalTxFilter{TxHash: txHash}).Return([]*data.InternalTx{internalTx}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `[` + internalTxJSON + `]`,
+		},
+		{
+			name: "by address",
+			path: "/address/0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d/internal-txs?limit=10&offset=20",
+			setup: func(m *MockDB) {
+				m.On("FindInternalTxs", db.InternalTxFilter{Address: bayc, Page: db.Page{Limit: 10, Offset: 20}}).
+					Return([]*data.InternalTx{}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `[]`,
+		},
+		{
+			name:     "invalid address",
+			path:     "/address/0x12/internal-txs",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusBadRequest,
+			expected: `{"statusCode":400,"msg":"invalid URLParam address: must be a 0x prefixed 20 byte address"}`,
+		},
+		{
+			name:     "invalid limit",
+			path:     "/tx/get-tx/" + txHash + "/internal-txs?limit=ten",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusUnprocessableEntity,
+			expected: `{"statusCode":422,"msg":{"limit":"must be a non-negative integer"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(MockDB)
+			tt.setup(mockDB)
+			handlers := &Handlers{dbConn: mockDB}
+
+			r := chi.NewRouter()
+			r.Get("/tx/get-tx/{hash}/internal-txs", makeHandler(handlers.GetTxInternalTxs))
+			r.Get("/address/{address}/internal-txs", makeHandler(handlers.GetAddressInternalTxs))
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
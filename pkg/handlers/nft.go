@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// GetNFT returns the metadata and owners of an NFT.
+func (h *Handlers) GetNFT(w http.ResponseWriter, r *http.Request) error {
+	contract, tokenID, err := nftParams(r)
+	if err != nil {
+		return err
+	}
+	nft, err := h.reader(r).GetNFT(r.Context(), contract, tokenID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NotFound(errors.New("nft not found"))
+		}
+		return fmt.Errorf("failed to get nft: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, nft)
+}
+
+func (h *Handlers) GetNFTTransfers(w http.ResponseWriter, r *http.Request) error {
+	contract, tokenID, err := nftParams(r)
+	if err != nil {
+		return err
+	}
+	params := newQueryParams(r)
+	filter := db.NFTTransferFilter{
+		Contract: contract,
+		TokenID:  tokenID,
+		Page:     db.Page{Limit: params.int("limit"), Offset: params.int("offset")},
+	}
+	if err := params.err(); err != nil {
+		return err
+	}
+
+	transfers, err := h.reader(r).FindNFTTransfers(r.Context(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to get nft transfers: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, transfers)
+}
+
+func (h *Handlers) GetAddressNFTs(w http.ResponseWriter, r *http.Request) error {
+	owner, err := addressParam(r)
+	if err != nil {
+		return err
+	}
+	params := newQueryParams(r)
+	filter := db.NFTOwnerFilter{
+		Owner:    owner,
+		Contract: params.address("contract"),
+		Page:     db.Page{Limit: params.int("limit"), Offset: params.int("offset")},
+	}
+	if err := params.err(); err != nil {
+		return err
+	}
+
+	holdings, err := h.reader(r).GetAddressNFTs(r.Context(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to get address nfts: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, holdings)
+}
+
+// nftParams returns the contract address and the token id, a decimal
+// uint256, from the path.
+func nftParams(r *http.Request) (string, string, error) {
+	contract, err := addressParam(r)
+	if err != nil {
+		return "", "", err
+	}
+	id, ok := new(big.Int).SetString(chi.URLParam(r, "tokenId"), 10)
+	if !ok || id.Sign() < 0 || id.Cmp(maxUint256) > 0 {
+		return "", "", InvalidURLParam(errors.New("tokenId: must be a decimal uint256"))
+	}
+	return contract, id.String(), nil
+}
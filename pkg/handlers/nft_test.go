@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+const bayc = "0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D"
+
+func TestNFTs(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		setup    func(m *MockDB)
+		code     int
+		expected string
+	}{
+		{
+			name: "owner",
+			path: "/nft/0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d/0042",
+			setup: func(m *MockDB) {
+				m.On("GetNFT", bayc, "42").Return(&data.NFT{
+					Contract: bayc, TokenID: "42", Standard: data.StandardERC721, URI: "ipfs://42",
+					Owners: []*data.NFTOwner{{Contract: bayc, TokenID: "42", Owner: "0x1", Balance: "1"}},
+				}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `{"contract":"` + bayc + `","tokenId":"42","standard":"ERC721","uri":"ipfs://42","owners":[{"owner":"0x1","balance":"1"}]}`,
+		},
+		{
+			name: "unknown nft",
+			path: "/nft/" + bayc + "/43",
+			setup: func(m *MockDB) {
+				m.On("GetNFT", bayc, "43").Return(nil, gorm.ErrRecordNotFound)
+			},
+			code:     http.StatusNotFound,
+			expected: `{"statusCode":404,"msg":"nft not found"}`,
+		},
+		{
+			name: "history",
+			path: "/nft/" + bayc + "/42/transfers?limit=5",
+			setup: func(m *MockDB) {
+				m.On("FindNFTTransfers", db.NFTTransferFilter{Contract: bayc, TokenID: "42", Page: db.Page{Limit: 5}}).
+					Return([]*data.NFTTransfer{{BlockNumber: 12, Contract: bayc, TokenID: "42", Amount: "1"}}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `[{"blockHash":"","logIndex":0,"batchIndex":0,"blockNumber":12,"txHash":"","contract":"` + bayc + `","tokenId":"42","standard":"","operator":"","from":"","to":"","amount":"1"}]`,
+		},
+		{
+			name: "tokens of owner",
+			path: "/address/" + usdc + "/nfts?contract=" + bayc,
+			setup: func(m *MockDB) {
+				m.On("GetAddressNFTs", db.NFTOwnerFilter{Owner: usdc, Contract: bayc}).
+					Return([]*data.NFTHolding{{Contract: bayc, TokenID: "42", Standard: data.StandardERC721, Balance: "1"}}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `[{"contract":"` + bayc + `","tokenId":"42","standard":"ERC721","uri":"","balance":"1"}]`,
+		},
+		{
+			name:     "invalid token id",
+			path:     "/nft/" + bayc + "/0x2a",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusBadRequest,
+			expected: `{"statusCode":400,"msg":"invalid URLParam tokenId: must be a decimal uint256"}`,
+		},
+		{
+			name:     "token id overflows uint256",
+			path:     "/nft/" + bayc + "/115792089237316195423570985008687907853269984665640564039457584007913129639936",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusBadRequest,
+			expected: `{"statusCode":400,"msg":"invalid URLParam tokenId: must be a decimal uint256"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(MockDB)
+			tt.setup(mockDB)
+			handlers := &Handlers{dbConn: mockDB}
+
+			r := chi.NewRouter()
+			r.Get("/nft/{address}/{tokenId}", makeHandler(handlers.GetNFT))
+			r.Get("/nft/{address}/{tokenId}/transfers", makeHandler(handlers.GetNFTTransfers))
+			r.Get("/address/{address}/nfts", makeHandler(handlers.GetAddressNFTs))
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+var chainPrefix = regexp.MustCompile(`^/chains/\d+`)
+
+type openAPIDoc struct {
+	Paths map[string]map[string]struct {
+		OperationID string `json:"operationId"`
+	} `json:"paths"`
+}
+
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	var doc openAPIDoc
+	assert.NoError(t, json.Unmarshal(openAPISpec, &doc))
+
+	r := chi.NewRouter()
+	Init([]Chain{{ID: 1}}, nil, "", r)
+
+	routes := 0
+	err := chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routes++
+		// Chain routes are documented once, relative to the servers entry.
+		route = chainPrefix.ReplaceAllString(route, "")
+		route = strings.TrimSuffix(route, "/")
+		if route == "" {
+			route = "/"
+		}
+		ops, ok := doc.Paths[route]
+		if assert.True(t, ok, "route %s is missing from openapi.json", route) {
+			_, ok = ops[strings.ToLower(method)]
+			assert.True(t, ok, "%s %s is missing from openapi.json", method, route)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, routes)
+}
+
+func TestOpenAPIOperationIDsAreUnique(t *testing.T) {
+	var doc openAPIDoc
+	assert.NoError(t, json.Unmarshal(openAPISpec, &doc))
+
+	seen := map[string]string{}
+	for path, ops := range doc.Paths {
+		for method, op := range ops {
+			assert.NotEmpty(t, op.OperationID, "%s %s has no operationId", method, path)
+			prev, ok := seen[op.OperationID]
+			assert.False(t, ok, "operationId %s is used by %s and %s %s", op.OperationID, prev, method, path)
+			seen[op.OperationID] = method + " " + path
+		}
+	}
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	r := chi.NewRouter()
+	Init([]Chain{{ID: 1}}, nil, "", r)
+
+	req, err := http.NewRequest("GET", "/openapi.json", nil)
+	assert.NoError(t, err)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.Equal(t, openAPISpec, rr.Body.Bytes())
+}
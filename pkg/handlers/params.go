@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/go-chi/chi"
+)
+
+// queryParams reads optional query parameters and collects parse errors per
+// parameter so they can be returned together with InvalidRequestData.
+type queryParams struct {
+	r      *http.Request
+	errors map[string]string
+}
+
+func newQueryParams(r *http.Request) *queryParams {
+	return &queryParams{r: r, errors: map[string]string{}}
+}
+
+func (p *queryParams) string(name string) string {
+	return p.r.URL.Query().Get(name)
+}
+
+func (p *queryParams) uint64(name string) *uint64 {
+	v := p.r.URL.Query().Get(name)
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		p.errors[name] = "must be a non-negative integer"
+		return nil
+	}
+	return &n
+}
+
+func (p *queryParams) int(name string) int {
+	v := p.r.URL.Query().Get(name)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		p.errors[name] = "must be a non-negative integer"
+		return 0
+	}
+	return n
+}
+
+func (p *queryParams) bool(name string) *bool {
+	v := p.r.URL.Query().Get(name)
+	if v == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		p.errors[name] = "must be true or false"
+		return nil
+	}
+	return &b
+}
+
+func (p *queryParams) duration(name string) *time.Duration {
+	v := p.r.URL.Query().Get(name)
+	if v == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		p.errors[name] = "must be a positive duration such as 90s or 1h"
+		return nil
+	}
+	return &d
+}
+
+// fromTime reads a unix timestamp from fromTime, or from since as a
+// duration before now.
+func (p *queryParams) fromTime() *uint64 {
+	fromTime := p.uint64("fromTime")
+	since := p.duration("since")
+	if since == nil {
+		return fromTime
+	}
+	if fromTime != nil {
+		p.errors["since"] = "can't be combined with fromTime"
+		return nil
+	}
+	t := uint64(time.Now().Add(-*since).Unix())
+	return &t
+}
+
+// address returns the checksummed form of an address parameter.
+func (p *queryParams) address(name string) string {
+	v := p.r.URL.Query().Get(name)
+	if v == "" {
+		return ""
+	}
+	if !addressPattern.MatchString(v) {
+		p.errors[name] = "must be a 0x prefixed 20 byte address"
+		return ""
+	}
+	return common.HexToAddress(v).Hex()
+}
+
+func (p *queryParams) bytes(name string, size int) []byte {
+	v := p.r.URL.Query().Get(name)
+	if v == "" {
+		return nil
+	}
+	b, err := hexutil.Decode(v)
+	if err != nil || len(b) != size {
+		p.errors[name] = fmt.Sprintf("must be 0x prefixed hex of %d bytes", size)
+		return nil
+	}
+	return b
+}
+
+func (p *queryParams) err() error {
+	if len(p.errors) == 0 {
+		return nil
+	}
+	return InvalidRequestData(p.errors)
+}
+
+// setNextLink sets a Link header to the next page of a list that returned n
+// rows for page, with the request's other query parameters unchanged.
+func setNextLink(w http.ResponseWriter, r *http.Request, page db.Page, n int) {
+	next, ok := page.Next(n)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	query.Set("limit", strconv.Itoa(next.Limit))
+	query.Set("offset", strconv.Itoa(next.Offset))
+	u := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, u.String()))
+}
+
+// addressParam returns the checksummed form of the address path parameter.
+func addressParam(r *http.Request) (string, error) {
+	v := chi.URLParam(r, "address")
+	if !addressPattern.MatchString(v) {
+		return "", InvalidURLParam(errors.New("address: must be a 0x prefixed 20 byte address"))
+	}
+	return common.HexToAddress(v).Hex(), nil
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/retention"
+)
+
+type ReadyResponse struct {
+	Status    string           `json:"status"`
+	Retention retention.Status `json:"retention"`
+}
+
+func (h Handlers) Ready(w http.ResponseWriter, r *http.Request) error {
+	if err := h.dbConn.Ping(r.Context()); err != nil {
+		return NewAPIError(http.StatusServiceUnavailable, fmt.Errorf("database unavailable: %w", err))
+	}
+	resp := ReadyResponse{Status: "ready"}
+	if h.pruner != nil {
+		status, err := h.pruner.Status(r.Context())
+		if err != nil {
+			return NewAPIError(http.StatusServiceUnavailable, fmt.Errorf("failed to get retention horizon: %w", err))
+		}
+		resp.Retention = status
+	}
+	return setJSONResponse(w, http.StatusOK, resp)
+}
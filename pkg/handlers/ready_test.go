@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/retention"
+)
+
+func TestReady(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   *retention.Policy
+		setup    func(m *MockDB)
+		code     int
+		expected string
+	}{
+		{
+			name:     "retention disabled",
+			setup:    func(m *MockDB) { m.On("Ping").Return(nil) },
+			code:     http.StatusOK,
+			expected: `{"status":"ready","retention":{"enabled":false,"horizon":0}}`,
+		},
+		{
+			name:   "retention horizon",
+			policy: &retention.Policy{Blocks: 100},
+			setup: func(m *MockDB) {
+				m.On("Ping").Return(nil)
+				m.On("GetLatestBlock").Return(data.Block{Number: 1000}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `{"status":"ready","retention":{"enabled":true,"blocks":100,"horizon":901}}`,
+		},
+		{
+			name:     "database unavailable",
+			setup:    func(m *MockDB) { m.On("Ping").Return(errors.New("connection refused")) },
+			code:     http.StatusServiceUnavailable,
+			expected: `{"statusCode":503,"msg":"database unavailable: connection refused"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(MockDB)
+			tt.setup(mockDB)
+			handlers := &Handlers{dbConn: mockDB}
+			if tt.policy != nil {
+				handlers.pruner = retention.NewPruner(mockDB, *tt.policy, 0)
+			}
+
+			r := chi.NewRouter()
+			r.Get("/ready", makeHandler(handlers.Ready))
+
+			req, err := http.NewRequest("GET", "/ready", nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"gorm.io/gorm"
+)
+
+// GetMinerRewards returns the block rewards, uncle rewards and priority fees
+// earned by a fee recipient.
+func (h *Handlers) GetMinerRewards(w http.ResponseWriter, r *http.Request) error {
+	address, err := addressParam(r)
+	if err != nil {
+		return err
+	}
+
+	rewards, err := h.reader(r).GetMinerRewards(r.Context(), address)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NotFound(errors.New("miner not found"))
+		}
+		return fmt.Errorf("failed to get miner rewards: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, rewards)
+}
+
+// GetBurnStats returns the ETH burned by base and blob fees in each hour or
+// day of a time range, and over the whole range.
+func (h *Handlers) GetBurnStats(w http.ResponseWriter, r *http.Request) error {
+	params := newQueryParams(r)
+	filter := db.StatsFilter{
+		Period:   db.StatsDaily,
+		FromTime: params.fromTime(),
+		ToTime:   params.uint64("toTime"),
+		Page:     db.Page{Limit: params.int("limit"), Offset: params.int("offset")},
+	}
+	if period := params.string("period"); period != "" {
+		filter.Period = db.StatsPeriod(period)
+	}
+	if err := params.err(); err != nil {
+		return err
+	}
+
+	stats, err := h.reader(r).GetBurnStats(r.Context(), filter)
+	if err != nil {
+		var filterErr *db.FilterError
+		if errors.As(err, &filterErr) {
+			return InvalidRequestData(map[string]string{filterErr.Field: filterErr.Msg})
+		}
+		return fmt.Errorf("failed to get burn stats: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, stats)
+}
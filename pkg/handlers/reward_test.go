@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+func TestGetMinerRewards(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		setup    func(m *MockDB)
+		code     int
+		expected string
+	}{
+		{
+			name: "miner",
+			path: "/miner/" + usdc + "/rewards",
+			setup: func(m *MockDB) {
+				m.On("GetMinerRewards", usdc).Return(&data.MinerRewards{
+					Miner: usdc, Blocks: 2, Uncles: 1, BlockRewards: "4000000000000000000",
+					UncleInclusionRewards: "62500000000000000", UncleRewards: "1750000000000000000",
+					PriorityFees: "1000", Total: "5812500000000001000",
+				}, nil)
+			},
+			code: http.StatusOK,
+			expected: `{"miner":"` + usdc + `","blocks":2,"uncles":1,"blockRewards":"4000000000000000000",` +
+				`"uncleInclusionRewards":"62500000000000000","uncleRewards":"1750000000000000000",` +
+				`"priorityFees":"1000","total":"5812500000000001000"}`,
+		},
+		{
+			name: "unknown",
+			path: "/miner/" + bayc + "/rewards",
+			setup: func(m *MockDB) {
+				m.On("GetMinerRewards", bayc).Return(nil, gorm.ErrRecordNotFound)
+			},
+			code:     http.StatusNotFound,
+			expected: `{"statusCode":404,"msg":"miner not found"}`,
+		},
+		{
+			name:     "invalid address",
+			path:     "/miner/0x1234/rewards",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusBadRequest,
+			expected: `{"statusCode":400,"msg":"invalid URLParam address: must be a 0x prefixed 20 byte address"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(MockDB)
+			tt.setup(mockDB)
+			handlers := &Handlers{dbConn: mockDB}
+
+			r := chi.NewRouter()
+			r.Get("/miner/{address}/rewards", makeHandler(handlers.GetMinerRewards))
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetBurnStats(t *testing.T) {
+	fromTime := uint64(1700000000)
+
+	tests := []struct {
+		name     string
+		path     string
+		setup    func(m *MockDB)
+		code     int
+		expected string
+	}{
+		{
+			name: "daily",
+			path: "/stats/burn?fromTime=1700000000&limit=1",
+			setup: func(m *MockDB) {
+				m.On("GetBurnStats", db.StatsFilter{Period: db.StatsDaily, FromTime: &fromTime, Page: db.Page{Limit: 1}}).
+					Return(&data.BurnStats{
+						BaseFeeBurned: "3000000000000000000", BlobFeeBurned: "5", Burned: "3000000000000000005",
+						Buckets: []*data.BurnPoint{{
+							Time: 1699920000, BaseFeeBurned: "1000000000000000000", BlobFeeBurned: "0", Burned: "1000000000000000000",
+						}},
+					}, nil)
+			},
+			code: http.StatusOK,
+			expected: `{"baseFeeBurned":"3000000000000000000","blobFeeBurned":"5","burned":"3000000000000000005",` +
+				`"buckets":[{"time":1699920000,"baseFeeBurned":"1000000000000000000","blobFeeBurned":"0","burned":"1000000000000000000"}]}`,
+		},
+		{
+			name: "invalid period",
+			path: "/stats/burn?period=weekly",
+			setup: func(m *MockDB) {
+				m.On("GetBurnStats", db.StatsFilter{Period: "weekly"}).
+					Return(nil, &db.FilterError{Field: "period", Msg: "must be hourly or daily"})
+			},
+			code:     http.StatusUnprocessableEntity,
+			expected: `{"statusCode":422,"msg":{"period":"must be hourly or daily"}}`,
+		},
+		{
+			name:     "invalid params",
+			path:     "/stats/burn?limit=ten",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusUnprocessableEntity,
+			expected: `{"statusCode":422,"msg":{"limit":"must be a non-negative integer"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(MockDB)
+			tt.setup(mockDB)
+			handlers := &Handlers{dbConn: mockDB}
+
+			r := chi.NewRouter()
+			r.Get("/stats/burn", makeHandler(handlers.GetBurnStats))
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/search"
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+)
+
+type SearchResultType string
+
+const (
+	SearchResultBlock   SearchResultType = "block"
+	SearchResultTx      SearchResultType = "transaction"
+	SearchResultAddress SearchResultType = "address"
+)
+
+type SearchResult struct {
+	Type     SearchResultType `json:"type"`
+	Value    string           `json:"value"`
+	Redirect string           `json:"redirect,omitempty"`
+}
+
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
+
+type queryKind int
+
+const (
+	queryUnknown queryKind = iota
+	queryNumber
+	queryHash
+	queryAddress
+)
+
+var (
+	hashPattern    = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+	addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	numberPattern  = regexp.MustCompile(`^[0-9]+$`)
+
+	inputPrefixPattern = regexp.MustCompile(fmt.Sprintf(`^0x[0-9a-fA-F]{0,%d}$`, search.InputPrefixBytes*2))
+)
+
+func classifyQuery(q string) queryKind {
+	switch {
+	case numberPattern.MatchString(q):
+		return queryNumber
+	case hashPattern.MatchString(q):
+		return queryHash
+	case addressPattern.MatchString(q):
+		return queryAddress
+	default:
+		return queryUnknown
+	}
+}
+
+func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) error {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		return InvalidRequestData(map[string]string{"q": "is required"})
+	}
+
+	dbConn := h.reader(r)
+	resp := SearchResponse{Query: q, Results: []SearchResult{}}
+	switch classifyQuery(q) {
+	case queryNumber:
+		number, err := strconv.ParseUint(q, 10, 64)
+		if err != nil {
+			return InvalidRequestData(map[string]string{"q": err.Error()})
+		}
+		block, err := dbConn.GetBlockByNumber(r.Context(), number)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to search blocks: %w", err)
+		}
+		if block != nil {
+			resp.Results = append(resp.Results, blockResult(h.prefix, block.Number))
+		}
+	case queryHash:
+		hash := strings.ToLower(q)
+		blocks, err := dbConn.GetBlocksByHashes(r.Context(), []string{hash}, nil)
+		if err != nil {
+			return fmt.Errorf("failed to search blocks: %w", err)
+		}
+		for _, block := range blocks {
+			resp.Results = append(resp.Results, blockResult(h.prefix, block.Number))
+		}
+		txs, err := dbConn.GetTxsByHashes(r.Context(), []string{hash}, nil)
+		if err != nil {
+			return fmt.Errorf("failed to search txs: %w", err)
+		}
+		for _, tx := range txs {
+			resp.Results = append(resp.Results, SearchResult{
+				Type:     SearchResultTx,
+				Value:    tx.Hash,
+				Redirect: fmt.Sprintf("%s/tx/get-tx/%s?blockNumber=%d", h.prefix, tx.Hash, tx.BlockNumber),
+			})
+		}
+	case queryAddress:
+		address := common.HexToAddress(q).Hex()
+		found, err := dbConn.HasAddress(r.Context(), address)
+		if err != nil {
+			return fmt.Errorf("failed to search addresses: %w", err)
+		}
+		if found {
+			resp.Results = append(resp.Results, SearchResult{
+				Type:  SearchResultAddress,
+				Value: address,
+			})
+		}
+	}
+	return setJSONResponse(w, http.StatusOK, resp)
+}
+
+func blockResult(prefix string, number uint64) SearchResult {
+	n := strconv.FormatUint(number, 10)
+	return SearchResult{
+		Type:     SearchResultBlock,
+		Value:    n,
+		Redirect: prefix + "/block/get-block/" + n,
+	}
+}
+
+func (h *Handlers) AdvancedSearch(w http.ResponseWriter, r *http.Request) error {
+	if h.searcher == nil {
+		return NewAPIError(http.StatusServiceUnavailable, errors.New("search index is not configured"))
+	}
+	params := newQueryParams(r)
+	q := search.Query{
+		ChainID:     h.chainID,
+		Type:        params.string("type"),
+		Text:        params.string("text"),
+		From:        params.string("from"),
+		To:          params.string("to"),
+		Miner:       params.string("miner"),
+		InputPrefix: params.string("input"),
+		FromBlock:   params.uint64("fromBlock"),
+		ToBlock:     params.uint64("toBlock"),
+		Limit:       params.int("limit"),
+		Offset:      params.int("offset"),
+	}
+	if lastBlocks := params.uint64("lastBlocks"); lastBlocks != nil {
+		q.LastBlocks = *lastBlocks
+	}
+	if q.Type != "" && q.Type != search.TypeBlock && q.Type != search.TypeTx {
+		params.errors["type"] = "must be block or transaction"
+	}
+	if q.InputPrefix != "" && !inputPrefixPattern.MatchString(q.InputPrefix) {
+		params.errors["input"] = fmt.Sprintf("must be 0x prefixed hex of at most %d bytes", search.InputPrefixBytes)
+	}
+	if err := params.err(); err != nil {
+		return err
+	}
+
+	result, err := h.searcher.Search(r.Context(), q)
+	if err != nil {
+		return fmt.Errorf("failed to search index: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, result)
+}
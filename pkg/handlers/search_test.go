@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/search"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestClassifyQuery(t *testing.T) {
+	tests := []struct {
+		q    string
+		kind queryKind
+	}{
+		{"0", queryNumber},
+		{"19000000", queryNumber},
+		{mockBlocks[0].Hash, queryHash},
+		{"0xABCDEFabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcdefabcd", queryHash},
+		{mockBlocks[0].Miner, queryAddress},
+		{"0x12", queryUnknown},
+		{"-1", queryUnknown},
+		{"vitalik.eth", queryUnknown},
+		{"0xzzcdefabcdefabcdefabcdefabcdefabcdefabcd", queryUnknown},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.kind, classifyQuery(tt.q), tt.q)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	txHash := "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"
+	tests := []struct {
+		name     string
+		q        string
+		setup    func(m *MockDB)
+		code     int
+		expected string
+	}{
+		{
+			name: "block number",
+			q:    "1",
+			setup: func(m *MockDB) {
+				m.On("GetBlockByNumber", uint64(1)).Return(mockBlocks[0], nil)
+			},
+			code:     http.StatusOK,
+			expected: `{"query":"1","results":[{"type":"block","value":"1","redirect":"/block/get-block/1"}]}`,
+		},
+		{
+			name: "unknown block number",
+			q:    "5",
+			setup: func(m *MockDB) {
+				m.On("GetBlockByNumber", uint64(5)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			code:     http.StatusOK,
+			expected: `{"query":"5","results":[]}`,
+		},
+		{
+			name: "tx hash",
+			q:    "0x1234567890ABCDEF1234567890abcdef1234567890abcdef1234567890abcdef",
+			setup: func(m *MockDB) {
+				m.On("GetBlocksByHashes", []string{txHash}, []uint64(nil)).Return([]*data.Block{}, nil)
+				m.On("GetTxsByHashes", []string{txHash}, []uint64(nil)).Return([]*data.Transaction{{Hash: txHash, BlockNumber: 7}}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `{"query":"0x1234567890ABCDEF1234567890abcdef1234567890abcdef1234567890abcdef","results":[{"type":"transaction","value":"` + txHash + `","redirect":"/tx/get-tx/` + txHash + `?blockNumber=7"}]}`,
+		},
+		{
+			name: "block hash",
+			q:    mockBlocks[1].ParentHash,
+			setup: func(m *MockDB) {
+				m.On("GetBlocksByHashes", []string{mockBlocks[1].ParentHash}, []uint64(nil)).Return([]*data.Block{&mockBlocks[1]}, nil)
+				m.On("GetTxsByHashes", []string{mockBlocks[1].ParentHash}, []uint64(nil)).Return([]*data.Transaction{}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `{"query":"` + mockBlocks[1].ParentHash + `","results":[{"type":"block","value":"2","redirect":"/block/get-block/2"}]}`,
+		},
+		{
+			name: "address is checksummed",
+			q:    "0xd8da6bf26964af9d7eed9e03e53415d37aa96045",
+			setup: func(m *MockDB) {
+				m.On("HasAddress", "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045").Return(true, nil)
+			},
+			code:     http.StatusOK,
+			expected: `{"query":"0xd8da6bf26964af9d7eed9e03e53415d37aa96045","results":[{"type":"address","value":"0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045"}]}`,
+		},
+		{
+			name:     "unclassified",
+			q:        "hello",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusOK,
+			expected: `{"query":"hello","results":[]}`,
+		},
+		{
+			name:     "missing query",
+			q:        "",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusUnprocessableEntity,
+			expected: `{"statusCode":422,"msg":{"q":"is required"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(MockDB)
+			tt.setup(mockDB)
+			handlers := &Handlers{dbConn: mockDB}
+
+			r := chi.NewRouter()
+			r.Get("/search", makeHandler(handlers.Search))
+
+			req, err := http.NewRequest("GET", "/search", nil)
+			assert.NoError(t, err)
+			req.URL.RawQuery = "q=" + tt.q
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+type MockSearcher struct {
+	mock.Mock
+}
+
+func (m *MockSearcher) Search(ctx context.Context, q search.Query) (*search.Result, error) {
+	args := m.Called(q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*search.Result), args.Error(1)
+}
+
+func TestAdvancedSearch(t *testing.T) {
+	to := "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	tests := []struct {
+		name     string
+		query    string
+		setup    func(m *MockSearcher)
+		code     int
+		expected string
+	}{
+		{
+			name:  "transfers in last blocks",
+			query: "type=transaction&to=" + to + "&input=0xa9059cbb&lastBlocks=1000&limit=10",
+			setup: func(m *MockSearcher) {
+				q := search.Query{Type: search.TypeTx, To: to, InputPrefix: "0xa9059cbb", LastBlocks: 1000, Limit: 10}
+				m.On("Search", q).Return(&search.Result{Total: 1, Hits: []search.Hit{{Type: search.TypeTx, Hash: "0x05", BlockNumber: 1500, Score: 1}}}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `{"total":1,"hits":[{"type":"transaction","hash":"0x05","blockNumber":1500,"score":1}]}`,
+		},
+		{
+			name:  "block range",
+			query: "fromBlock=1&toBlock=2",
+			setup: func(m *MockSearcher) {
+				from, to := uint64(1), uint64(2)
+				m.On("Search", search.Query{FromBlock: &from, ToBlock: &to}).Return(&search.Result{Hits: []search.Hit{}}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `{"total":0,"hits":[]}`,
+		},
+		{
+			name:     "invalid parameters",
+			query:    "type=log&input=0xzz&fromBlock=-1&limit=a",
+			setup:    func(m *MockSearcher) {},
+			code:     http.StatusUnprocessableEntity,
+			expected: `{"statusCode":422,"msg":{"type":"must be block or transaction","input":"must be 0x prefixed hex of at most 68 bytes","fromBlock":"must be a non-negative integer","limit":"must be a non-negative integer"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searcher := new(MockSearcher)
+			tt.setup(searcher)
+			handlers := &Handlers{searcher: searcher}
+
+			r := chi.NewRouter()
+			r.Get("/search/advanced", makeHandler(handlers.AdvancedSearch))
+
+			req, err := http.NewRequest("GET", "/search/advanced?"+tt.query, nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+			searcher.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdvancedSearchNotConfigured(t *testing.T) {
+	handlers := &Handlers{}
+	r := chi.NewRouter()
+	r.Get("/search/advanced", makeHandler(handlers.AdvancedSearch))
+
+	req, err := http.NewRequest("GET", "/search/advanced", nil)
+	assert.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.JSONEq(t, `{"statusCode":503,"msg":"search index is not configured"}`, recorder.Body.String())
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+func (h *Handlers) GetDailyStats(w http.ResponseWriter, r *http.Request) error {
+	return h.getStats(w, r, db.StatsDaily)
+}
+
+func (h *Handlers) GetHourlyStats(w http.ResponseWriter, r *http.Request) error {
+	return h.getStats(w, r, db.StatsHourly)
+}
+
+func (h *Handlers) getStats(w http.ResponseWriter, r *http.Request, period db.StatsPeriod) error {
+	params := newQueryParams(r)
+	filter := db.StatsFilter{
+		Period:   period,
+		FromTime: params.fromTime(),
+		ToTime:   params.uint64("toTime"),
+		Page:     db.Page{Limit: params.int("limit"), Offset: params.int("offset")},
+	}
+	if err := params.err(); err != nil {
+		return err
+	}
+
+	stats, err := h.reader(r).FindStats(r.Context(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to get %s stats: %w", period, err)
+	}
+	return setJSONResponse(w, http.StatusOK, stats)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+func TestGetStats(t *testing.T) {
+	fromTime := uint64(1700000000)
+
+	tests := []struct {
+		name     string
+		path     string
+		setup    func(m *MockDB)
+		code     int
+		expected string
+	}{
+		{
+			name: "daily",
+			path: "/stats/daily",
+			setup: func(m *MockDB) {
+				m.On("FindStats", db.StatsFilter{Period: db.StatsDaily}).Return([]*data.Stats{{
+					Time: 1699920000, Blocks: 7200, Txs: 1200000, GasUsed: 108000000000,
+					AvgGasPrice: 30000000000, MedianGasPrice: 25000000000, ActiveAddresses: 450000,
+					ContractDeployments: 2000, FailedTxs: 24000, FailedTxRatio: 0.02, AvgBlockTime: 12,
+					BaseFeeBurned: "2000000000000000000000", BlobFeeBurned: "0",
+				}}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `[{"time":1699920000,"blocks":7200,"txs":1200000,"gasUsed":108000000000,"avgGasPrice":30000000000,"medianGasPrice":25000000000,"activeAddresses":450000,"contractDeployments":2000,"failedTxs":24000,"failedTxRatio":0.02,"avgBlockTime":12,"baseFeeBurned":"2000000000000000000000","blobFeeBurned":"0"}]`,
+		},
+		{
+			name: "hourly range",
+			path: "/stats/hourly?fromTime=1700000000&limit=24",
+			setup: func(m *MockDB) {
+				m.On("FindStats", db.StatsFilter{
+					Period:   db.StatsHourly,
+					FromTime: &fromTime,
+					Page:     db.Page{Limit: 24},
+				}).Return([]*data.Stats{}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `[]`,
+		},
+		{
+			name:     "invalid params",
+			path:     "/stats/hourly?toTime=yesterday",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusUnprocessableEntity,
+			expected: `{"statusCode":422,"msg":{"toTime":"must be a non-negative integer"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(MockDB)
+			tt.setup(mockDB)
+			handlers := &Handlers{dbConn: mockDB}
+
+			r := chi.NewRouter()
+			r.Get("/stats/daily", makeHandler(handlers.GetDailyStats))
+			r.Get("/stats/hourly", makeHandler(handlers.GetHourlyStats))
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+func (h *Handlers) GetTokenTransfers(w http.ResponseWriter, r *http.Request) error {
+	token, err := addressParam(r)
+	if err != nil {
+		return err
+	}
+	params := newQueryParams(r)
+	filter := db.TokenTransferFilter{
+		Token:     token,
+		FromBlock: params.uint64("fromBlock"),
+		ToBlock:   params.uint64("toBlock"),
+		Page:      db.Page{Limit: params.int("limit"), Offset: params.int("offset")},
+	}
+	if err := params.err(); err != nil {
+		return err
+	}
+
+	transfers, err := h.reader(r).FindTokenTransfers(r.Context(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to get token transfers: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, transfers)
+}
+
+func (h *Handlers) GetTokenHolders(w http.ResponseWriter, r *http.Request) error {
+	token, err := addressParam(r)
+	if err != nil {
+		return err
+	}
+	params := newQueryParams(r)
+	page := db.Page{Limit: params.int("limit"), Offset: params.int("offset")}
+	if err := params.err(); err != nil {
+		return err
+	}
+
+	holders, err := h.reader(r).GetTokenHolders(r.Context(), token, page)
+	if err != nil {
+		return fmt.Errorf("failed to get token holders: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, holders)
+}
+
+func (h *Handlers) GetAddressTokens(w http.ResponseWriter, r *http.Request) error {
+	address, err := addressParam(r)
+	if err != nil {
+		return err
+	}
+	holdings, err := h.reader(r).GetAddressTokens(r.Context(), address)
+	if err != nil {
+		return fmt.Errorf("failed to get address tokens: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, holdings)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+const usdc = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+
+func TestTokens(t *testing.T) {
+	fromBlock := uint64(19000000)
+	tests := []struct {
+		name     string
+		path     string
+		setup    func(m *MockDB)
+		code     int
+		expected string
+	}{
+		{
+			name: "transfers",
+			path: "/token/0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48/transfers?fromBlock=19000000&limit=1",
+			setup: func(m *MockDB) {
+				m.On("FindTokenTransfers", db.TokenTransferFilter{Token: usdc, FromBlock: &fromBlock, Page: db.Page{Limit: 1}}).
+					Return([]*data.TokenTransfer{{BlockNumber: 19000001, Token: usdc, Value: "5000000000000000000000"}}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `[{"blockHash":"","logIndex":0,"blockNumber":19000001,"txHash":"","token":"` + usdc + `","from":"","to":"","value":"5000000000000000000000"}]`,
+		},
+		{
+			name: "holders",
+			path: "/token/" + usdc + "/holders?offset=10",
+			setup: func(m *MockDB) {
+				m.On("GetTokenHolders", usdc, db.Page{Offset: 10}).
+					Return([]*data.TokenBalance{{Token: usdc, Holder: "0x1", Balance: "10"}}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `[{"token":"` + usdc + `","holder":"0x1","balance":"10"}]`,
+		},
+		{
+			name: "address tokens",
+			path: "/address/" + usdc + "/tokens",
+			setup: func(m *MockDB) {
+				m.On("GetAddressTokens", usdc).
+					Return([]*data.TokenHolding{{Token: "0x2", Name: "Token", Symbol: "TKN", Decimals: 18, Balance: "1"}}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `[{"token":"0x2","name":"Token","symbol":"TKN","decimals":18,"balance":"1"}]`,
+		},
+		{
+			name:     "invalid address",
+			path:     "/token/0x123/holders",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusBadRequest,
+			expected: `{"statusCode":400,"msg":"invalid URLParam address: must be a 0x prefixed 20 byte address"}`,
+		},
+		{
+			name:     "invalid block",
+			path:     "/token/" + usdc + "/transfers?toBlock=latest",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusUnprocessableEntity,
+			expected: `{"statusCode":422,"msg":{"toBlock":"must be a non-negative integer"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(MockDB)
+			tt.setup(mockDB)
+			handlers := &Handlers{dbConn: mockDB}
+
+			r := chi.NewRouter()
+			r.Get("/token/{address}/transfers", makeHandler(handlers.GetTokenTransfers))
+			r.Get("/token/{address}/holders", makeHandler(handlers.GetTokenHolders))
+			r.Get("/address/{address}/tokens", makeHandler(handlers.GetAddressTokens))
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
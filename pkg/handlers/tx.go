@@ -1,25 +1,87 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"github.com/CaelRowley/geth-indexer-service/pkg/decode"
 	"github.com/go-chi/chi"
 )
 
 func (h *Handlers) GetTx(w http.ResponseWriter, r *http.Request) error {
 	hash := chi.URLParam(r, "hash")
-	tx, err := h.dbConn.GetTxByHash(hash)
+	params := newQueryParams(r)
+	// The block number, when known, spares probing every partition.
+	blockNumber := params.uint64("blockNumber")
+	if err := params.err(); err != nil {
+		return err
+	}
+	dbConn := h.reader(r)
+	tx, err := dbConn.GetTxByHash(r.Context(), hash, blockNumber)
 	if err != nil {
 		return fmt.Errorf("failed to get tx: %w", err)
 	}
+	if err := decode.Txs(r.Context(), dbConn, []*data.Transaction{tx}); err != nil {
+		return fmt.Errorf("failed to decode tx: %w", err)
+	}
 	return setJSONResponse(w, http.StatusOK, tx)
 }
 
 func (h *Handlers) GetTxs(w http.ResponseWriter, r *http.Request) error {
-	txs, err := h.dbConn.GetTxs()
+	params := newQueryParams(r)
+	filter := db.TxFilter{
+		From:             params.address("from"),
+		To:               params.address("to"),
+		ContractCreation: params.bool("contractCreation"),
+		Status:           params.uint64("status"),
+		FromBlock:        params.uint64("fromBlock"),
+		ToBlock:          params.uint64("toBlock"),
+		FromTime:         params.fromTime(),
+		ToTime:           params.uint64("toTime"),
+		MinValue:         params.uint64("minValue"),
+		MaxValue:         params.uint64("maxValue"),
+		MinGasPrice:      params.uint64("minGasPrice"),
+		MaxGasPrice:      params.uint64("maxGasPrice"),
+		MethodSelector:   params.bytes("method", 4),
+		Sort:             db.TxSort(params.string("sort")),
+		Page:             db.Page{Limit: params.int("limit"), Offset: params.int("offset")},
+	}
+	if err := params.err(); err != nil {
+		return err
+	}
+	if err := filter.Validate(); err != nil {
+		var filterErr *db.FilterError
+		if errors.As(err, &filterErr) {
+			return InvalidRequestData(map[string]string{filterErr.Field: filterErr.Msg})
+		}
+		return err
+	}
+
+	dbConn := h.reader(r)
+	txs, err := dbConn.FindTxs(r.Context(), filter)
 	if err != nil {
-		return fmt.Errorf("failed to get blocks: %w", err)
+		return fmt.Errorf("failed to get txs: %w", err)
+	}
+	if err := decode.Txs(r.Context(), dbConn, txs); err != nil {
+		return fmt.Errorf("failed to decode txs: %w", err)
 	}
+	setNextLink(w, r, filter.Page, len(txs))
 	return setJSONResponse(w, http.StatusOK, txs)
 }
+
+// GetTxLogs returns the logs of a transaction with their decoded events.
+func (h *Handlers) GetTxLogs(w http.ResponseWriter, r *http.Request) error {
+	hash := chi.URLParam(r, "hash")
+	dbConn := h.reader(r)
+	logs, err := dbConn.GetLogsByTxHashes(r.Context(), []string{hash})
+	if err != nil {
+		return fmt.Errorf("failed to get tx logs: %w", err)
+	}
+	if err := decode.Logs(r.Context(), dbConn, logs); err != nil {
+		return fmt.Errorf("failed to decode tx logs: %w", err)
+	}
+	return setJSONResponse(w, http.StatusOK, logs)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+func TestGetTxs(t *testing.T) {
+	router := "0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D"
+	status := uint64(0)
+	fromBlock := uint64(100)
+
+	tests := []struct {
+		name     string
+		query    string
+		setup    func(m *MockDB)
+		code     int
+		expected string
+		link     string
+	}{
+		{
+			name:  "no filter",
+			query: "",
+			setup: func(m *MockDB) {
+				m.On("FindTxs", db.TxFilter{}).Return([]*data.Transaction{}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `[]`,
+		},
+		{
+			name:  "failed txs to a contract",
+			query: "to=0x7a250d5630b4cf539739df2c5dacb4c659f2488d&status=0&fromBlock=100&method=0x38ed1739&sort=-blockNumber&limit=10",
+			setup: func(m *MockDB) {
+				m.On("FindTxs", db.TxFilter{
+					To:             router,
+					Status:         &status,
+					FromBlock:      &fromBlock,
+					MethodSelector: []byte{0x38, 0xed, 0x17, 0x39},
+					Sort:           db.TxSortBlockNumberDesc,
+					Page:           db.Page{Limit: 10},
+				}).Return([]*data.Transaction{{Hash: "0xabc"}}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `[{"hash":"0xabc","from":"","to":"","contract":"","value":0,"data":null,"gas":0,"gasPrice":0,"cost":0,"nonce":0,"status":0,"blockHash":"","blockNumber":0,"priorityFee":0,"gasUsed":0}]`,
+		},
+		{
+			name:  "full page",
+			query: "to=" + router + "&limit=1&offset=2",
+			setup: func(m *MockDB) {
+				m.On("FindTxs", db.TxFilter{To: router, Page: db.Page{Limit: 1, Offset: 2}}).Return([]*data.Transaction{{Hash: "0xabc"}}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `[{"hash":"0xabc","from":"","to":"","contract":"","value":0,"data":null,"gas":0,"gasPrice":0,"cost":0,"nonce":0,"status":0,"blockHash":"","blockNumber":0,"priorityFee":0,"gasUsed":0}]`,
+			link:     `</get-txs?limit=1&offset=3&to=` + router + `>; rel="next"`,
+		},
+		{
+			name:  "since",
+			query: "to=" + router + "&since=1h",
+			setup: func(m *MockDB) {
+				m.On("FindTxs", mock.MatchedBy(func(f db.TxFilter) bool {
+					hourAgo := uint64(time.Now().Add(-time.Hour).Unix())
+					return f.To == router && f.FromTime != nil && *f.FromTime >= hourAgo-5 && *f.FromTime <= hourAgo
+				})).Return([]*data.Transaction{}, nil)
+			},
+			code:     http.StatusOK,
+			expected: `[]`,
+		},
+		{
+			name:     "invalid params",
+			query:    "to=0x123&contractCreation=maybe&method=0x12&since=soon",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusUnprocessableEntity,
+			expected: `{"statusCode":422,"msg":{"to":"must be a 0x prefixed 20 byte address","contractCreation":"must be true or false","method":"must be 0x prefixed hex of 4 bytes","since":"must be a positive duration such as 90s or 1h"}}`,
+		},
+		{
+			name:     "unindexed filter",
+			query:    "status=0",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusUnprocessableEntity,
+			expected: `{"statusCode":422,"msg":{"filter":"status, value, gas price, method and contractCreation=false filters need from, to, blockHash, contractCreation=true, a block range of at most 10000 blocks or a time range of at most 24 hours"}}`,
+		},
+		{
+			name:     "unindexed sort",
+			query:    "fromBlock=100&toBlock=200&sort=-value",
+			setup:    func(m *MockDB) {},
+			code:     http.StatusUnprocessableEntity,
+			expected: `{"statusCode":422,"msg":{"sort":"value and gasPrice sorts need from, to or blockHash"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := new(MockDB)
+			tt.setup(mockDB)
+			handlers := &Handlers{dbConn: mockDB}
+
+			r := chi.NewRouter()
+			r.Get("/get-txs", makeHandler(handlers.GetTxs))
+
+			req, err := http.NewRequest("GET", "/get-txs?"+tt.query, nil)
+			assert.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+			assert.Equal(t, tt.link, recorder.Header().Get("Link"))
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
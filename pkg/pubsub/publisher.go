@@ -10,20 +10,31 @@ import (
 type Publisher interface {
 	PublishBlock([]byte) error
 	PublishTx([]byte) error
+	PublishLog([]byte) error
+	PublishToken([]byte) error
+	PublishTokenTransfer([]byte) error
+	PublishTokenApproval([]byte) error
+	PublishNFT([]byte) error
+	PublishNFTTransfer([]byte) error
+	PublishContract([]byte) error
+	PublishInternalTx([]byte) error
+	PublishBalance([]byte) error
+	PublishUncle([]byte) error
 	StartEventHandler()
 	Close()
 }
 
 type KafkaProducer struct {
 	*kafka.Producer
+	chain Chain
 }
 
-func NewPublisher(url string) (Publisher, error) {
+func NewPublisher(url string, chain Chain) (Publisher, error) {
 	p, err := kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": url})
 	if err != nil {
 		return nil, err
 	}
-	return &KafkaProducer{p}, nil
+	return &KafkaProducer{p, chain}, nil
 }
 
 func (p *KafkaProducer) StartEventHandler() {
@@ -41,20 +52,60 @@ func (p *KafkaProducer) StartEventHandler() {
 }
 
 func (p *KafkaProducer) PublishBlock(blockData []byte) error {
-	err := p.Producer.Produce(&kafka.Message{
-		TopicPartition: kafka.TopicPartition{Topic: &blocksTopic, Partition: kafka.PartitionAny},
-		Value:          blockData,
-	}, nil)
-	return err
+	return p.produce(blocksTopic, blockData)
 }
 
 func (p *KafkaProducer) PublishTx(txData []byte) error {
 	fmt.Println("publishing transaction")
-	err := p.Producer.Produce(&kafka.Message{
-		TopicPartition: kafka.TopicPartition{Topic: &txsTopic, Partition: kafka.PartitionAny},
-		Value:          txData,
+	return p.produce(txsTopic, txData)
+}
+
+func (p *KafkaProducer) PublishLog(logData []byte) error {
+	return p.produce(logsTopic, logData)
+}
+
+func (p *KafkaProducer) PublishToken(tokenData []byte) error {
+	return p.produce(tokensTopic, tokenData)
+}
+
+func (p *KafkaProducer) PublishTokenTransfer(transferData []byte) error {
+	return p.produce(tokenTransfersTopic, transferData)
+}
+
+func (p *KafkaProducer) PublishTokenApproval(approvalData []byte) error {
+	return p.produce(tokenApprovalsTopic, approvalData)
+}
+
+func (p *KafkaProducer) PublishNFT(nftData []byte) error {
+	return p.produce(nftsTopic, nftData)
+}
+
+func (p *KafkaProducer) PublishNFTTransfer(transferData []byte) error {
+	return p.produce(nftTransfersTopic, transferData)
+}
+
+func (p *KafkaProducer) PublishContract(contractData []byte) error {
+	return p.produce(contractsTopic, contractData)
+}
+
+func (p *KafkaProducer) PublishInternalTx(txData []byte) error {
+	return p.produce(internalTxsTopic, txData)
+}
+
+func (p *KafkaProducer) PublishBalance(balanceData []byte) error {
+	return p.produce(balancesTopic, balanceData)
+}
+
+func (p *KafkaProducer) PublishUncle(uncleData []byte) error {
+	return p.produce(unclesTopic, uncleData)
+}
+
+func (p *KafkaProducer) produce(topic string, value []byte) error {
+	topic = p.chain.topic(topic)
+	return p.Producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Value:          value,
 	}, nil)
-	return err
 }
 
 func (k *KafkaProducer) Close() {
@@ -2,15 +2,67 @@ package pubsub
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 )
 
 var (
 	blocksTopic = "blocks"
 	txsTopic    = "transactions"
+	logsTopic   = "logs"
+
+	tokensTopic         = "tokens"
+	tokenTransfersTopic = "token_transfers"
+	tokenApprovalsTopic = "token_approvals"
+	nftsTopic           = "nfts"
+	nftTransfersTopic   = "nft_transfers"
+	contractsTopic      = "contracts"
+	internalTxsTopic    = "internal_txs"
+	balancesTopic       = "balances"
+	unclesTopic         = "uncles"
 )
 
+// Chain identifies the chain whose messages are published and consumed.
+type Chain struct {
+	ID uint64
+	// Unprefixed keeps the topic and consumer group names from before each
+	// chain had its own, so a deployment indexing a single chain picks up
+	// the messages and offsets it left in them when it's upgraded.
+	Unprefixed bool
+}
+
+// topic is the name of topic on the chain. Every chain has its own topics
+// so its messages reach the consumer writing to its chain.
+func (c Chain) topic(topic string) string {
+	if c.Unprefixed {
+		return topic
+	}
+	return fmt.Sprintf("chain-%d.%s", c.ID, topic)
+}
+
+func (c Chain) topics(topics ...string) []string {
+	names := make([]string, len(topics))
+	for i, topic := range topics {
+		names[i] = c.topic(topic)
+	}
+	return names
+}
+
+// group is the name of the consumer group consuming the chain's topics.
+func (c Chain) group(group string) string {
+	if c.Unprefixed {
+		return group
+	}
+	return fmt.Sprintf("%s-%d", group, c.ID)
+}
+
+// messageTopic returns the topic of m without the chain prefix.
+func (c Chain) messageTopic(m *kafka.Message) string {
+	return strings.TrimPrefix(*m.TopicPartition.Topic, c.topic(""))
+}
+
 type PubSub interface {
 	GetPublisher() Publisher
 	GetSubscriber() Subscriber
@@ -22,12 +74,14 @@ type Broker struct {
 	Subscriber
 }
 
-func NewPubSub(url string, dbConn db.DB) (PubSub, error) {
-	p, err := NewPublisher(url)
+// NewPubSub connects the publisher and subscriber of chain, dbConn must be
+// scoped to the same chain.
+func NewPubSub(url string, chain Chain, dbConn db.DB) (PubSub, error) {
+	p, err := NewPublisher(url, chain)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kafka producer: %w", err)
 	}
-	s, err := NewSubscriber(url, dbConn)
+	s, err := NewSubscriber(url, chain, dbConn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kafka consumer: %w", err)
 	}
@@ -0,0 +1,93 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/search"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// SearchConsumer feeds the search index from the blocks and transactions
+// topics. It uses its own consumer group so it keeps its own offsets and can
+// be rebuilt without affecting the db consumer.
+type SearchConsumer struct {
+	*kafka.Consumer
+	indexer search.Indexer
+	chain   Chain
+}
+
+// NewSearchSubscriber indexes the blocks and transactions of chain, their
+// messages don't carry the chain. Like the database consumer, a new group
+// reads the topics from their first message.
+func NewSearchSubscriber(url string, chain Chain, indexer search.Indexer) (Subscriber, error) {
+	c, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":        url,
+		"group.id":                 chain.group("evmIndexerSearch"),
+		"session.timeout.ms":       6000,
+		"auto.offset.reset":        "earliest",
+		"enable.auto.offset.store": false,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.SubscribeTopics(chain.topics(blocksTopic, txsTopic), nil); err != nil {
+		return nil, fmt.Errorf("failed to subscribe to kafka topics: %w", err)
+	}
+
+	return &SearchConsumer{c, indexer, chain}, nil
+}
+
+func (c *SearchConsumer) StartPoll(ctx context.Context) error {
+	return poll(ctx, c.Consumer, func(m *kafka.Message) {
+		topic := c.chain.messageTopic(m)
+		if topic == blocksTopic {
+			if err := c.handleBlock(ctx, m); err != nil {
+				slog.Error("failed to index block message", "err", err)
+			}
+		}
+		if topic == txsTopic {
+			if err := c.handleTx(ctx, m); err != nil {
+				slog.Error("failed to index tx message", "err", err)
+			}
+		}
+	})
+}
+
+func (c *SearchConsumer) Close() error {
+	return c.Consumer.Close()
+}
+
+func (c *SearchConsumer) handleBlock(ctx context.Context, m *kafka.Message) error {
+	var block data.Block
+	if err := json.Unmarshal(m.Value, &block); err != nil {
+		return fmt.Errorf("failed to unmarshal block data: %w", err)
+	}
+	block.ChainID = c.chain.ID
+	if err := c.indexer.IndexBlock(ctx, block); err != nil {
+		return fmt.Errorf("failed to index block: %w", err)
+	}
+	if _, err := c.Consumer.StoreMessage(m); err != nil {
+		return fmt.Errorf("failed to store kafka offset after message: %w", err)
+	}
+	return nil
+}
+
+func (c *SearchConsumer) handleTx(ctx context.Context, m *kafka.Message) error {
+	var tx data.Transaction
+	if err := json.Unmarshal(m.Value, &tx); err != nil {
+		return fmt.Errorf("failed to unmarshal tx data: %w", err)
+	}
+	tx.ChainID = c.chain.ID
+	if err := c.indexer.IndexTx(ctx, tx); err != nil {
+		return fmt.Errorf("failed to index tx: %w", err)
+	}
+	if _, err := c.Consumer.StoreMessage(m); err != nil {
+		return fmt.Errorf("failed to store kafka offset after message: %w", err)
+	}
+	return nil
+}
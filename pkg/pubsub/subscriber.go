@@ -19,12 +19,18 @@ type Subscriber interface {
 type KafkaConsumer struct {
 	*kafka.Consumer
 	dbConn db.DB
+	chain  Chain
 }
 
-func NewSubscriber(url string, dbConn db.DB) (Subscriber, error) {
+// NewSubscriber consumes the topics of chain into dbConn, scoped to the same
+// chain. Each chain has its own consumer group. A group without committed
+// offsets, such as a chain's group on its first start, reads its topics from
+// their first message rather than skipping what was published before it
+// joined.
+func NewSubscriber(url string, chain Chain, dbConn db.DB) (Subscriber, error) {
 	c, err := kafka.NewConsumer(&kafka.ConfigMap{
 		"bootstrap.servers":        url,
-		"group.id":                 "evmIndexer",
+		"group.id":                 chain.group("evmIndexer"),
 		"session.timeout.ms":       6000,
 		"auto.offset.reset":        "earliest",
 		"enable.auto.offset.store": false,
@@ -33,42 +39,97 @@ func NewSubscriber(url string, dbConn db.DB) (Subscriber, error) {
 		return nil, err
 	}
 
-	if err := c.SubscribeTopics([]string{blocksTopic, txsTopic}, nil); err != nil {
+	topics := chain.topics(blocksTopic, txsTopic, logsTopic, tokensTopic, tokenTransfersTopic, tokenApprovalsTopic, nftsTopic, nftTransfersTopic, contractsTopic, internalTxsTopic, balancesTopic, unclesTopic)
+	if err := c.SubscribeTopics(topics, nil); err != nil {
 		return nil, fmt.Errorf("failed to subscribe to kafka topics: %w", err)
 	}
 
-	return &KafkaConsumer{c, dbConn}, nil
+	return &KafkaConsumer{c, dbConn, chain}, nil
 }
 
 func (c *KafkaConsumer) StartPoll(ctx context.Context) error {
+	ctx = db.WithoutWriteTimeout(ctx)
+	return poll(ctx, c.Consumer, func(m *kafka.Message) {
+		topic := c.chain.messageTopic(m)
+		if topic == blocksTopic {
+			if err := c.handleBlock(ctx, m); err != nil {
+				slog.Error("failed to consume block message", "err", err)
+			}
+		}
+		if topic == txsTopic {
+			if err := c.handleTx(ctx, m); err != nil {
+				slog.Error("failed to consume tx message", "err", err)
+			}
+		}
+		if topic == logsTopic {
+			if err := c.handleLog(ctx, m); err != nil {
+				slog.Error("failed to consume log message", "err", err)
+			}
+		}
+		if topic == tokensTopic {
+			if err := c.handleToken(ctx, m); err != nil {
+				slog.Error("failed to consume token message", "err", err)
+			}
+		}
+		if topic == tokenTransfersTopic {
+			if err := c.handleTokenTransfer(ctx, m); err != nil {
+				slog.Error("failed to consume token transfer message", "err", err)
+			}
+		}
+		if topic == tokenApprovalsTopic {
+			if err := c.handleTokenApproval(ctx, m); err != nil {
+				slog.Error("failed to consume token approval message", "err", err)
+			}
+		}
+		if topic == nftsTopic {
+			if err := c.handleNFT(ctx, m); err != nil {
+				slog.Error("failed to consume nft message", "err", err)
+			}
+		}
+		if topic == nftTransfersTopic {
+			if err := c.handleNFTTransfer(ctx, m); err != nil {
+				slog.Error("failed to consume nft transfer message", "err", err)
+			}
+		}
+		if topic == contractsTopic {
+			if err := c.handleContract(ctx, m); err != nil {
+				slog.Error("failed to consume contract message", "err", err)
+			}
+		}
+		if topic == internalTxsTopic {
+			if err := c.handleInternalTx(ctx, m); err != nil {
+				slog.Error("failed to consume internal tx message", "err", err)
+			}
+		}
+		if topic == balancesTopic {
+			if err := c.handleBalance(ctx, m); err != nil {
+				slog.Error("failed to consume balance message", "err", err)
+			}
+		}
+		if topic == unclesTopic {
+			if err := c.handleUncle(ctx, m); err != nil {
+				slog.Error("failed to consume uncle message", "err", err)
+			}
+		}
+	})
+}
+
+func poll(ctx context.Context, consumer *kafka.Consumer, handle func(*kafka.Message)) error {
 	for {
 		select {
 		case <-ctx.Done():
 			slog.Info("kafka consumer stopped")
 			return nil
 		default:
-			ev := c.Consumer.Poll(100)
+			ev := consumer.Poll(100)
 			if ev == nil {
 				continue
 			}
 			switch e := ev.(type) {
 			case *kafka.Message:
-				if *e.TopicPartition.Topic == blocksTopic {
-					if err := c.handleBlock(e); err != nil {
-						slog.Error("failed to consume block message", "err", err)
-					}
-				}
-				if *e.TopicPartition.Topic == txsTopic {
-					if err := c.handleTx(e); err != nil {
-						slog.Error("failed to consume tx message", "err", err)
-					}
-				}
+				handle(e)
 			case kafka.Error:
-				fmt.Println(e)
 				slog.Error("kafka subscription failed", "code", e.Code(), "err", e.Error())
-				if e.Code() == kafka.ErrAllBrokersDown {
-					break
-				}
 			}
 		}
 	}
@@ -78,12 +139,12 @@ func (c *KafkaConsumer) Close() error {
 	return c.Consumer.Close()
 }
 
-func (c *KafkaConsumer) handleBlock(m *kafka.Message) error {
+func (c *KafkaConsumer) handleBlock(ctx context.Context, m *kafka.Message) error {
 	var block data.Block
 	if err := json.Unmarshal(m.Value, &block); err != nil {
 		return fmt.Errorf("failed to unmarshal block data: %w", err)
 	}
-	if err := c.dbConn.InsertBlock(block); err != nil {
+	if err := c.dbConn.InsertBlock(ctx, block); err != nil {
 		return fmt.Errorf("failed to store block in db: %w", err)
 	}
 	if _, err := c.Consumer.StoreMessage(m); err != nil {
@@ -92,12 +153,12 @@ func (c *KafkaConsumer) handleBlock(m *kafka.Message) error {
 	return nil
 }
 
-func (c *KafkaConsumer) handleTx(m *kafka.Message) error {
+func (c *KafkaConsumer) handleTx(ctx context.Context, m *kafka.Message) error {
 	var tx data.Transaction
 	if err := json.Unmarshal(m.Value, &tx); err != nil {
 		return fmt.Errorf("failed to unmarshal tx data: %w", err)
 	}
-	if err := c.dbConn.InsertTx(tx); err != nil {
+	if err := c.dbConn.InsertTx(ctx, tx); err != nil {
 		return fmt.Errorf("failed to store tx in db: %w", err)
 	}
 	if _, err := c.Consumer.StoreMessage(m); err != nil {
@@ -105,3 +166,143 @@ func (c *KafkaConsumer) handleTx(m *kafka.Message) error {
 	}
 	return nil
 }
+
+func (c *KafkaConsumer) handleLog(ctx context.Context, m *kafka.Message) error {
+	var log data.Log
+	if err := json.Unmarshal(m.Value, &log); err != nil {
+		return fmt.Errorf("failed to unmarshal log data: %w", err)
+	}
+	if err := c.dbConn.InsertLog(ctx, log); err != nil {
+		return fmt.Errorf("failed to store log in db: %w", err)
+	}
+	if _, err := c.Consumer.StoreMessage(m); err != nil {
+		return fmt.Errorf("failed to store kafka offset after message: %w", err)
+	}
+	return nil
+}
+
+func (c *KafkaConsumer) handleToken(ctx context.Context, m *kafka.Message) error {
+	var token data.Token
+	if err := json.Unmarshal(m.Value, &token); err != nil {
+		return fmt.Errorf("failed to unmarshal token data: %w", err)
+	}
+	if err := c.dbConn.InsertToken(ctx, token); err != nil {
+		return fmt.Errorf("failed to store token in db: %w", err)
+	}
+	if _, err := c.Consumer.StoreMessage(m); err != nil {
+		return fmt.Errorf("failed to store kafka offset after message: %w", err)
+	}
+	return nil
+}
+
+func (c *KafkaConsumer) handleTokenTransfer(ctx context.Context, m *kafka.Message) error {
+	var transfer data.TokenTransfer
+	if err := json.Unmarshal(m.Value, &transfer); err != nil {
+		return fmt.Errorf("failed to unmarshal token transfer data: %w", err)
+	}
+	if err := c.dbConn.InsertTokenTransfer(ctx, transfer); err != nil {
+		return fmt.Errorf("failed to store token transfer in db: %w", err)
+	}
+	if _, err := c.Consumer.StoreMessage(m); err != nil {
+		return fmt.Errorf("failed to store kafka offset after message: %w", err)
+	}
+	return nil
+}
+
+func (c *KafkaConsumer) handleTokenApproval(ctx context.Context, m *kafka.Message) error {
+	var approval data.TokenApproval
+	if err := json.Unmarshal(m.Value, &approval); err != nil {
+		return fmt.Errorf("failed to unmarshal token approval data: %w", err)
+	}
+	if err := c.dbConn.InsertTokenApproval(ctx, approval); err != nil {
+		return fmt.Errorf("failed to store token approval in db: %w", err)
+	}
+	if _, err := c.Consumer.StoreMessage(m); err != nil {
+		return fmt.Errorf("failed to store kafka offset after message: %w", err)
+	}
+	return nil
+}
+
+func (c *KafkaConsumer) handleNFT(ctx context.Context, m *kafka.Message) error {
+	var nft data.NFT
+	if err := json.Unmarshal(m.Value, &nft); err != nil {
+		return fmt.Errorf("failed to unmarshal nft data: %w", err)
+	}
+	if err := c.dbConn.InsertNFT(ctx, nft); err != nil {
+		return fmt.Errorf("failed to store nft in db: %w", err)
+	}
+	if _, err := c.Consumer.StoreMessage(m); err != nil {
+		return fmt.Errorf("failed to store kafka offset after message: %w", err)
+	}
+	return nil
+}
+
+func (c *KafkaConsumer) handleNFTTransfer(ctx context.Context, m *kafka.Message) error {
+	var transfer data.NFTTransfer
+	if err := json.Unmarshal(m.Value, &transfer); err != nil {
+		return fmt.Errorf("failed to unmarshal nft transfer data: %w", err)
+	}
+	if err := c.dbConn.InsertNFTTransfer(ctx, transfer); err != nil {
+		return fmt.Errorf("failed to store nft transfer in db: %w", err)
+	}
+	if _, err := c.Consumer.StoreMessage(m); err != nil {
+		return fmt.Errorf("failed to store kafka offset after message: %w", err)
+	}
+	return nil
+}
+
+func (c *KafkaConsumer) handleContract(ctx context.Context, m *kafka.Message) error {
+	var contract data.Contract
+	if err := json.Unmarshal(m.Value, &contract); err != nil {
+		return fmt.Errorf("failed to unmarshal contract data: %w", err)
+	}
+	if err := c.dbConn.InsertContract(ctx, contract); err != nil {
+		return fmt.Errorf("failed to store contract in db: %w", err)
+	}
+	if _, err := c.Consumer.StoreMessage(m); err != nil {
+		return fmt.Errorf("failed to store kafka offset after message: %w", err)
+	}
+	return nil
+}
+
+func (c *KafkaConsumer) handleInternalTx(ctx context.Context, m *kafka.Message) error {
+	var internalTx data.InternalTx
+	if err := json.Unmarshal(m.Value, &internalTx); err != nil {
+		return fmt.Errorf("failed to unmarshal internal tx data: %w", err)
+	}
+	if err := c.dbConn.InsertInternalTx(ctx, internalTx); err != nil {
+		return fmt.Errorf("failed to store internal tx in db: %w", err)
+	}
+	if _, err := c.Consumer.StoreMessage(m); err != nil {
+		return fmt.Errorf("failed to store kafka offset after message: %w", err)
+	}
+	return nil
+}
+
+func (c *KafkaConsumer) handleBalance(ctx context.Context, m *kafka.Message) error {
+	var balance data.Balance
+	if err := json.Unmarshal(m.Value, &balance); err != nil {
+		return fmt.Errorf("failed to unmarshal balance data: %w", err)
+	}
+	if err := c.dbConn.InsertBalance(ctx, balance); err != nil {
+		return fmt.Errorf("failed to store balance in db: %w", err)
+	}
+	if _, err := c.Consumer.StoreMessage(m); err != nil {
+		return fmt.Errorf("failed to store kafka offset after message: %w", err)
+	}
+	return nil
+}
+
+func (c *KafkaConsumer) handleUncle(ctx context.Context, m *kafka.Message) error {
+	var uncle data.Uncle
+	if err := json.Unmarshal(m.Value, &uncle); err != nil {
+		return fmt.Errorf("failed to unmarshal uncle data: %w", err)
+	}
+	if err := c.dbConn.InsertUncle(ctx, uncle); err != nil {
+		return fmt.Errorf("failed to store uncle in db: %w", err)
+	}
+	if _, err := c.Consumer.StoreMessage(m); err != nil {
+		return fmt.Errorf("failed to store kafka offset after message: %w", err)
+	}
+	return nil
+}
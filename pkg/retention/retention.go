@@ -0,0 +1,172 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultInterval = time.Minute
+	// BatchSize is the number of blocks deleted per statement.
+	BatchSize = 1000
+)
+
+// Policy bounds how much history is kept. A zero field disables that bound;
+// when both are set a block is pruned as soon as it falls outside either.
+type Policy struct {
+	Blocks uint64
+	Days   uint64
+}
+
+func (p Policy) Enabled() bool {
+	return p.Blocks > 0 || p.Days > 0
+}
+
+func (p Policy) cutoff(now time.Time) uint64 {
+	return uint64(now.Add(-time.Duration(p.Days) * 24 * time.Hour).Unix())
+}
+
+// Keeps reports whether a block lies inside the retention window of a chain
+// whose head is at the given number.
+func (p Policy) Keeps(head, number, blockTime uint64, now time.Time) bool {
+	if p.Blocks > 0 && number+p.Blocks <= head {
+		return false
+	}
+	if p.Days > 0 && blockTime < p.cutoff(now) {
+		return false
+	}
+	return true
+}
+
+type Status struct {
+	Enabled bool   `json:"enabled"`
+	Blocks  uint64 `json:"blocks,omitempty"`
+	Days    uint64 `json:"days,omitempty"`
+	Horizon uint64 `json:"horizon"`
+}
+
+type Pruner struct {
+	dbConn   db.DB
+	policy   Policy
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewPruner(dbConn db.DB, policy Policy, interval time.Duration) *Pruner {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Pruner{
+		dbConn:   dbConn,
+		policy:   policy,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Horizon returns the lowest block number kept by the policy. The latest
+// stored block is never below the horizon, so a stalled chain is not emptied.
+func (p *Pruner) Horizon(ctx context.Context) (uint64, error) {
+	if !p.policy.Enabled() {
+		return 0, nil
+	}
+	latest, err := p.dbConn.GetLatestBlock(ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	var horizon uint64
+	if p.policy.Blocks > 0 && latest.Number+1 > p.policy.Blocks {
+		horizon = latest.Number + 1 - p.policy.Blocks
+	}
+	if p.policy.Days > 0 {
+		first, err := p.dbConn.GetFirstBlockSince(ctx, p.policy.cutoff(p.now()))
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			horizon = latest.Number
+		case err != nil:
+			return 0, fmt.Errorf("failed to get first block since cutoff: %w", err)
+		default:
+			horizon = max(horizon, first.Number)
+		}
+	}
+	return horizon, nil
+}
+
+func (p *Pruner) Status(ctx context.Context) (Status, error) {
+	horizon, err := p.Horizon(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+	return Status{
+		Enabled: p.policy.Enabled(),
+		Blocks:  p.policy.Blocks,
+		Days:    p.policy.Days,
+		Horizon: horizon,
+	}, nil
+}
+
+// Prune deletes everything below the current horizon in batches of BatchSize
+// blocks and returns the number of blocks deleted.
+func (p *Pruner) Prune(ctx context.Context) (int64, error) {
+	horizon, err := p.Horizon(ctx)
+	if err != nil {
+		return 0, err
+	}
+	first, err := p.dbConn.GetFirstBlock(ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get first block: %w", err)
+	}
+
+	var total int64
+	// The first batch starts at zero so transactions and logs stored ahead
+	// of their blocks are pruned too.
+	var from uint64
+	for to := first.Number + BatchSize; from < horizon; to += BatchSize {
+		if err := ctx.Err(); err != nil {
+			return total, nil
+		}
+		to = min(to, horizon)
+		deleted, err := p.dbConn.DeleteBlockRange(ctx, from, to)
+		if err != nil {
+			return total, fmt.Errorf("failed to delete blocks %d-%d: %w", from, to, err)
+		}
+		total += deleted
+		from = to
+	}
+	return total, nil
+}
+
+func (p *Pruner) Start(ctx context.Context) error {
+	if !p.policy.Enabled() {
+		return nil
+	}
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		deleted, err := p.Prune(ctx)
+		if err != nil {
+			slog.Error("pruner failed", "err", err)
+		} else if deleted > 0 {
+			slog.Info("pruned blocks", "count", deleted)
+		}
+		select {
+		case <-ctx.Done():
+			slog.Info("pruner stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package retention
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+type MockDB struct {
+	db.DB
+	mock.Mock
+}
+
+func (m *MockDB) GetFirstBlock(_ context.Context) (*data.Block, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	block := args.Get(0).(data.Block)
+	return &block, args.Error(1)
+}
+
+func (m *MockDB) GetLatestBlock(_ context.Context) (*data.Block, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	block := args.Get(0).(data.Block)
+	return &block, args.Error(1)
+}
+
+func (m *MockDB) GetFirstBlockSince(_ context.Context, t uint64) (*data.Block, error) {
+	args := m.Called(t)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	block := args.Get(0).(data.Block)
+	return &block, args.Error(1)
+}
+
+func (m *MockDB) DeleteBlockRange(_ context.Context, fromNumber, toNumber uint64) (int64, error) {
+	args := m.Called(fromNumber, toNumber)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+var now = time.Unix(1700000000, 0)
+
+func newPruner(dbConn db.DB, policy Policy) *Pruner {
+	p := NewPruner(dbConn, policy, 0)
+	p.now = func() time.Time { return now }
+	return p
+}
+
+func TestPolicyKeeps(t *testing.T) {
+	day := uint64(24 * time.Hour / time.Second)
+	recent := uint64(now.Unix())
+	old := recent - 3*day
+
+	tests := []struct {
+		name   string
+		policy Policy
+		number uint64
+		time   uint64
+		keeps  bool
+	}{
+		{"disabled", Policy{}, 1, old, true},
+		{"inside block window", Policy{Blocks: 10}, 91, recent, true},
+		{"outside block window", Policy{Blocks: 10}, 90, recent, false},
+		{"inside day window", Policy{Days: 4}, 1, old, true},
+		{"outside day window", Policy{Days: 2}, 1, old, false},
+		{"outside either window", Policy{Blocks: 1000, Days: 2}, 99, old, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.keeps, tt.policy.Keeps(100, tt.number, tt.time, now))
+		})
+	}
+}
+
+func TestHorizon(t *testing.T) {
+	cutoff := uint64(now.Add(-48 * time.Hour).Unix())
+
+	t.Run("blocks", func(t *testing.T) {
+		dbMock := new(MockDB)
+		dbMock.On("GetLatestBlock").Return(data.Block{Number: 100}, nil)
+
+		horizon, err := newPruner(dbMock, Policy{Blocks: 10}).Horizon(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(91), horizon)
+	})
+
+	t.Run("stricter bound wins", func(t *testing.T) {
+		dbMock := new(MockDB)
+		dbMock.On("GetLatestBlock").Return(data.Block{Number: 100}, nil)
+		dbMock.On("GetFirstBlockSince", cutoff).Return(data.Block{Number: 95}, nil)
+
+		horizon, err := newPruner(dbMock, Policy{Blocks: 10, Days: 2}).Horizon(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(95), horizon)
+	})
+
+	t.Run("keeps latest block", func(t *testing.T) {
+		dbMock := new(MockDB)
+		dbMock.On("GetLatestBlock").Return(data.Block{Number: 100}, nil)
+		dbMock.On("GetFirstBlockSince", cutoff).Return(nil, gorm.ErrRecordNotFound)
+
+		horizon, err := newPruner(dbMock, Policy{Days: 2}).Horizon(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(100), horizon)
+	})
+
+	t.Run("empty db", func(t *testing.T) {
+		dbMock := new(MockDB)
+		dbMock.On("GetLatestBlock").Return(nil, gorm.ErrRecordNotFound)
+
+		horizon, err := newPruner(dbMock, Policy{Blocks: 10}).Horizon(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, horizon)
+	})
+}
+
+func TestPrune(t *testing.T) {
+	dbMock := new(MockDB)
+	dbMock.On("GetLatestBlock").Return(data.Block{Number: 3499}, nil)
+	dbMock.On("GetFirstBlock").Return(data.Block{Number: 500}, nil)
+	dbMock.On("DeleteBlockRange", uint64(0), uint64(1500)).Return(int64(1000), nil)
+	dbMock.On("DeleteBlockRange", uint64(1500), uint64(2500)).Return(int64(1000), nil)
+	dbMock.On("DeleteBlockRange", uint64(2500), uint64(3000)).Return(int64(500), nil)
+
+	deleted, err := newPruner(dbMock, Policy{Blocks: 500}).Prune(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2500), deleted)
+	dbMock.AssertExpectations(t)
+}
+
+func TestPruneNothingToDelete(t *testing.T) {
+	dbMock := new(MockDB)
+	dbMock.On("GetLatestBlock").Return(data.Block{Number: 100}, nil)
+	dbMock.On("GetFirstBlock").Return(data.Block{Number: 50}, nil)
+
+	deleted, err := newPruner(dbMock, Policy{Blocks: 1000}).Prune(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+	dbMock.AssertNotCalled(t, "DeleteBlockRange", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,234 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+type BleveIndex struct {
+	index bleve.Index
+}
+
+// NewBleveIndex opens the index at path, creating it if it does not exist. An
+// empty path creates an in-memory index.
+func NewBleveIndex(path string) (*BleveIndex, error) {
+	if path == "" {
+		index, err := bleve.NewMemOnly(newMapping())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bleve index: %w", err)
+		}
+		return &BleveIndex{index}, nil
+	}
+	index, err := bleve.Open(path)
+	if errors.Is(err, bleve.ErrorIndexPathDoesNotExist) {
+		index, err = bleve.New(path, newMapping())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bleve index: %w", err)
+	}
+	return &BleveIndex{index}, nil
+}
+
+func newMapping() mapping.IndexMapping {
+	keyword := bleve.NewKeywordFieldMapping()
+	number := bleve.NewNumericFieldMapping()
+	text := bleve.NewTextFieldMapping()
+	text.Store = false
+
+	block := bleve.NewDocumentStaticMapping()
+	block.AddFieldMappingsAt("chain_id", number)
+	block.AddFieldMappingsAt("type", keyword)
+	block.AddFieldMappingsAt("hash", keyword)
+	block.AddFieldMappingsAt("block_number", number)
+	block.AddFieldMappingsAt("miner", keyword)
+	block.AddFieldMappingsAt("time", number)
+	block.AddFieldMappingsAt("text", text)
+
+	tx := bleve.NewDocumentStaticMapping()
+	tx.AddFieldMappingsAt("chain_id", number)
+	tx.AddFieldMappingsAt("type", keyword)
+	tx.AddFieldMappingsAt("hash", keyword)
+	tx.AddFieldMappingsAt("block_hash", keyword)
+	tx.AddFieldMappingsAt("block_number", number)
+	tx.AddFieldMappingsAt("from", keyword)
+	tx.AddFieldMappingsAt("to", keyword)
+	tx.AddFieldMappingsAt("contract", keyword)
+	tx.AddFieldMappingsAt("input", keyword)
+	tx.AddFieldMappingsAt("status", number)
+
+	m := bleve.NewIndexMapping()
+	m.TypeField = "type"
+	m.DefaultMapping = bleve.NewDocumentDisabledMapping()
+	m.AddDocumentMapping(TypeBlock, block)
+	m.AddDocumentMapping(TypeTx, tx)
+	return m
+}
+
+func (b *BleveIndex) IndexBlock(ctx context.Context, block data.Block) error {
+	if err := b.removeReorgedBlocks(ctx, block); err != nil {
+		return err
+	}
+	return b.index.Index(documentID(block.ChainID, TypeBlock, block.Hash), blockDocument(block))
+}
+
+// removeReorgedBlocks deletes the blocks indexed at the height of block under
+// another hash, with the transactions indexed with their hash.
+func (b *BleveIndex) removeReorgedBlocks(ctx context.Context, block data.Block) error {
+	n, inclusive := float64(block.Number), true
+	number := bleve.NewNumericRangeInclusiveQuery(&n, &n, &inclusive, &inclusive)
+	number.SetField("block_number")
+	hash := bleve.NewTermQuery(strings.ToLower(block.Hash))
+	hash.SetField("hash")
+	reorged := bleve.NewBooleanQuery()
+	reorged.AddMust(chainQuery(block.ChainID), typeQuery(TypeBlock), number)
+	reorged.AddMustNot(hash)
+	req := bleve.NewSearchRequestOptions(reorged, maxLimit, 0, false)
+	req.Fields = []string{"hash"}
+	res, err := b.index.SearchInContext(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to find reorged blocks in bleve index: %w", err)
+	}
+	for _, h := range res.Hits {
+		oldHash, _ := h.Fields["hash"].(string)
+		if err := b.removeBlockTxs(ctx, block.ChainID, oldHash); err != nil {
+			return err
+		}
+		if err := b.index.Delete(h.ID); err != nil {
+			return fmt.Errorf("failed to remove reorged block from bleve index: %w", err)
+		}
+	}
+	return nil
+}
+
+// removeBlockTxs deletes the transactions of the block hash a page at a time.
+func (b *BleveIndex) removeBlockTxs(ctx context.Context, chainID uint64, hash string) error {
+	blockHash := bleve.NewTermQuery(hash)
+	blockHash.SetField("block_hash")
+	txs := bleve.NewConjunctionQuery(chainQuery(chainID), typeQuery(TypeTx), blockHash)
+	for {
+		res, err := b.index.SearchInContext(ctx, bleve.NewSearchRequestOptions(txs, maxLimit, 0, false))
+		if err != nil {
+			return fmt.Errorf("failed to find reorged transactions in bleve index: %w", err)
+		}
+		if len(res.Hits) == 0 {
+			return nil
+		}
+		batch := b.index.NewBatch()
+		for _, h := range res.Hits {
+			batch.Delete(h.ID)
+		}
+		if err := b.index.Batch(batch); err != nil {
+			return fmt.Errorf("failed to remove reorged transactions from bleve index: %w", err)
+		}
+	}
+}
+
+func (b *BleveIndex) IndexTx(_ context.Context, tx data.Transaction) error {
+	return b.index.Index(documentID(tx.ChainID, TypeTx, tx.Hash), txDocument(tx))
+}
+
+func (b *BleveIndex) Search(ctx context.Context, q Query) (*Result, error) {
+	from, to, err := q.blockRange(func() (uint64, error) { return b.head(q.ChainID) })
+	if err != nil {
+		return nil, err
+	}
+
+	conjuncts := []query.Query{chainQuery(q.ChainID)}
+	term := func(field, value string) {
+		if value == "" {
+			return
+		}
+		tq := bleve.NewTermQuery(strings.ToLower(value))
+		tq.SetField(field)
+		conjuncts = append(conjuncts, tq)
+	}
+	term("type", q.Type)
+	term("from", q.From)
+	term("to", q.To)
+	term("miner", q.Miner)
+	if q.InputPrefix != "" {
+		pq := bleve.NewPrefixQuery(strings.ToLower(q.InputPrefix))
+		pq.SetField("input")
+		conjuncts = append(conjuncts, pq)
+	}
+	if from != nil || to != nil {
+		var min, max *float64
+		if from != nil {
+			f := float64(*from)
+			min = &f
+		}
+		if to != nil {
+			t := float64(*to)
+			max = &t
+		}
+		inclusive := true
+		rq := bleve.NewNumericRangeInclusiveQuery(min, max, &inclusive, &inclusive)
+		rq.SetField("block_number")
+		conjuncts = append(conjuncts, rq)
+	}
+	if q.Text != "" {
+		mq := bleve.NewMatchQuery(q.Text)
+		mq.SetField("text")
+		conjuncts = append(conjuncts, mq)
+	}
+
+	req := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(conjuncts...), q.limit(), q.offset(), false)
+	req.Fields = []string{"type", "hash", "block_number"}
+	req.SortBy([]string{"-block_number", "-_score"})
+
+	res, err := b.index.SearchInContext(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search bleve index: %w", err)
+	}
+	result := &Result{Total: res.Total, Hits: make([]Hit, 0, len(res.Hits))}
+	for _, h := range res.Hits {
+		hit := Hit{Score: h.Score}
+		hit.Type, _ = h.Fields["type"].(string)
+		hit.Hash, _ = h.Fields["hash"].(string)
+		if n, ok := h.Fields["block_number"].(float64); ok {
+			hit.BlockNumber = uint64(n)
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+	return result, nil
+}
+
+func typeQuery(docType string) query.Query {
+	tq := bleve.NewTermQuery(docType)
+	tq.SetField("type")
+	return tq
+}
+
+// chainQuery matches the documents of chainID.
+func chainQuery(chainID uint64) query.Query {
+	n, inclusive := float64(chainID), true
+	rq := bleve.NewNumericRangeInclusiveQuery(&n, &n, &inclusive, &inclusive)
+	rq.SetField("chain_id")
+	return rq
+}
+
+func (b *BleveIndex) head(chainID uint64) (uint64, error) {
+	req := bleve.NewSearchRequestOptions(chainQuery(chainID), 1, 0, false)
+	req.Fields = []string{"block_number"}
+	req.SortBy([]string{"-block_number"})
+	res, err := b.index.Search(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find head of bleve index: %w", err)
+	}
+	if len(res.Hits) == 0 {
+		return 0, nil
+	}
+	n, _ := res.Hits[0].Fields["block_number"].(float64)
+	return uint64(n), nil
+}
+
+func (b *BleveIndex) Close() error {
+	return b.index.Close()
+}
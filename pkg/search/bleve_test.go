@@ -0,0 +1,97 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	alice = "0x00000000000000000000000000000000000a11ce"
+)
+
+func transferInput() []byte {
+	return hexutil.MustDecode("0xa9059cbb000000000000000000000000000000000000000000000000000000000000b0b00000000000000000000000000000000000000000000000000000000000000064")
+}
+
+func seedIndex(t *testing.T, idx Indexer) {
+	ctx := context.Background()
+	require.NoError(t, idx.IndexBlock(ctx, data.Block{Hash: "0xb1", Number: 100, Miner: alice, ExtraData: []byte("hello pool")}))
+	require.NoError(t, idx.IndexBlock(ctx, data.Block{Hash: "0xb2", Number: 1500, Miner: alice}))
+	txs := []data.Transaction{
+		{Hash: "0x01", BlockNumber: 100, From: alice, To: token, Data: transferInput()},
+		{Hash: "0x02", BlockNumber: 1200, From: alice, To: token, Data: transferInput()},
+		{Hash: "0x03", BlockNumber: 1300, From: alice, To: token, Data: hexutil.MustDecode("0x095ea7b3")},
+		{Hash: "0x04", BlockNumber: 1400, From: alice, To: alice, Data: transferInput()},
+		{Hash: "0x05", BlockNumber: 1500, From: alice, To: token, Data: transferInput()},
+		// Another chain's head doesn't move this chain's block window.
+		{ChainID: 5, Hash: "0x06", BlockNumber: 2300, From: alice, To: token, Data: transferInput()},
+	}
+	for _, tx := range txs {
+		require.NoError(t, idx.IndexTx(ctx, tx))
+	}
+}
+
+func TestBleveSearch(t *testing.T) {
+	idx, err := NewBleveIndex("")
+	require.NoError(t, err)
+	defer idx.Close()
+	seedIndex(t, idx)
+
+	tests := []struct {
+		name     string
+		query    Query
+		expected []string
+	}{
+		{
+			name:     "transfers to token in last 1000 blocks",
+			query:    Query{Type: TypeTx, To: token, InputPrefix: "0xa9059cbb", LastBlocks: 1000},
+			expected: []string{"0x05", "0x02"},
+		},
+		{
+			name:     "transfers to token in block range",
+			query:    Query{Type: TypeTx, To: token, InputPrefix: "0xA9059CBB", FromBlock: ptr(0), ToBlock: ptr(1200)},
+			expected: []string{"0x02", "0x01"},
+		},
+		{
+			name:     "blocks by miner",
+			query:    Query{Type: TypeBlock, Miner: alice},
+			expected: []string{"0xb2", "0xb1"},
+		},
+		{
+			name:     "block extra data",
+			query:    Query{Text: "pool"},
+			expected: []string{"0xb1"},
+		},
+		{
+			name:     "paged",
+			query:    Query{Type: TypeTx, Limit: 2, Offset: 1},
+			expected: []string{"0x04", "0x03"},
+		},
+		{
+			name:     "other chain",
+			query:    Query{ChainID: 5, To: token, LastBlocks: 1000},
+			expected: []string{"0x06"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := idx.Search(context.Background(), tt.query)
+			require.NoError(t, err)
+			hashes := make([]string, 0, len(result.Hits))
+			for _, h := range result.Hits {
+				hashes = append(hashes, h.Hash)
+			}
+			assert.Equal(t, tt.expected, hashes)
+		})
+	}
+}
+
+func ptr(n uint64) *uint64 {
+	return &n
+}
@@ -0,0 +1,335 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+)
+
+const defaultElasticIndex = "evm-indexer"
+
+var elasticMapping = map[string]any{
+	"mappings": map[string]any{
+		"dynamic": "strict",
+		"properties": map[string]any{
+			"chain_id":     map[string]string{"type": "unsigned_long"},
+			"type":         map[string]string{"type": "keyword"},
+			"hash":         map[string]string{"type": "keyword"},
+			"block_hash":   map[string]string{"type": "keyword"},
+			"block_number": map[string]string{"type": "unsigned_long"},
+			"miner":        map[string]string{"type": "keyword"},
+			"time":         map[string]string{"type": "unsigned_long"},
+			"text":         map[string]string{"type": "text"},
+			"from":         map[string]string{"type": "keyword"},
+			"to":           map[string]string{"type": "keyword"},
+			"contract":     map[string]string{"type": "keyword"},
+			"input":        map[string]string{"type": "keyword"},
+			"status":       map[string]string{"type": "unsigned_long"},
+		},
+	},
+}
+
+type ElasticIndex struct {
+	baseURL string
+	index   string
+	client  *http.Client
+}
+
+// NewElasticIndex connects to the Elasticsearch node at rawURL, the first
+// path segment selects the index name. The index is created if it is missing.
+func NewElasticIndex(rawURL string, client *http.Client) (*ElasticIndex, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid elasticsearch url: %w", err)
+	}
+	index := strings.Trim(u.Path, "/")
+	if index == "" {
+		index = defaultElasticIndex
+	}
+	u.Path = ""
+	if client == nil {
+		client = http.DefaultClient
+	}
+	e := &ElasticIndex{baseURL: u.String(), index: index, client: client}
+	if err := e.ensureIndex(context.Background()); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *ElasticIndex) ensureIndex(ctx context.Context) error {
+	status, _, err := e.do(ctx, http.MethodHead, "/"+e.index, nil)
+	if err != nil {
+		return fmt.Errorf("failed to check elasticsearch index: %w", err)
+	}
+	if status == http.StatusOK {
+		return e.ensureFields(ctx)
+	}
+	status, body, err := e.do(ctx, http.MethodPut, "/"+e.index, elasticMapping)
+	if err != nil {
+		return fmt.Errorf("failed to create elasticsearch index: %w", err)
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("failed to create elasticsearch index: %d: %s", status, body)
+	}
+	return nil
+}
+
+// ensureFields adds the chain_id and block_hash fields to indexes created
+// before they existed, the strict mapping rejects documents with unknown
+// fields.
+func (e *ElasticIndex) ensureFields(ctx context.Context) error {
+	properties := map[string]any{"properties": map[string]any{
+		"chain_id":   map[string]string{"type": "unsigned_long"},
+		"block_hash": map[string]string{"type": "keyword"},
+	}}
+	status, body, err := e.do(ctx, http.MethodPut, "/"+e.index+"/_mapping", properties)
+	if err != nil {
+		return fmt.Errorf("failed to update elasticsearch mapping: %w", err)
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("failed to update elasticsearch mapping: %d: %s", status, body)
+	}
+	return nil
+}
+
+func (e *ElasticIndex) IndexBlock(ctx context.Context, block data.Block) error {
+	if err := e.removeReorgedBlocks(ctx, block); err != nil {
+		return err
+	}
+	return e.put(ctx, documentID(block.ChainID, TypeBlock, block.Hash), blockDocument(block))
+}
+
+// removeReorgedBlocks deletes the blocks indexed at the height of block under
+// another hash, with the transactions indexed with their hash.
+func (e *ElasticIndex) removeReorgedBlocks(ctx context.Context, block data.Block) error {
+	reqBody := map[string]any{
+		"size":    maxLimit,
+		"_source": []string{"hash"},
+		"query": map[string]any{"bool": map[string]any{
+			"filter": []any{
+				chainFilter(block.ChainID),
+				map[string]any{"term": map[string]string{"type": TypeBlock}},
+				map[string]any{"term": map[string]uint64{"block_number": block.Number}},
+			},
+			"must_not": map[string]any{"term": map[string]string{"hash": strings.ToLower(block.Hash)}},
+		}},
+	}
+	status, body, err := e.do(ctx, http.MethodPost, "/"+e.index+"/_search", reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to find reorged blocks in elasticsearch: %w", err)
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("failed to find reorged blocks in elasticsearch: %d: %s", status, body)
+	}
+	var resp struct {
+		Hits struct {
+			Hits []struct {
+				Source struct {
+					Hash string `json:"hash"`
+				} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("failed to decode elasticsearch response: %w", err)
+	}
+	if len(resp.Hits.Hits) == 0 {
+		return nil
+	}
+	hashes := make([]string, len(resp.Hits.Hits))
+	for i, h := range resp.Hits.Hits {
+		hashes[i] = h.Source.Hash
+	}
+
+	reqBody = map[string]any{
+		"query": map[string]any{"bool": map[string]any{
+			"filter": []any{chainFilter(block.ChainID)},
+			"should": []any{
+				map[string]any{"bool": map[string]any{"filter": []any{
+					map[string]any{"term": map[string]string{"type": TypeBlock}},
+					map[string]any{"terms": map[string][]string{"hash": hashes}},
+				}}},
+				map[string]any{"bool": map[string]any{"filter": []any{
+					map[string]any{"term": map[string]string{"type": TypeTx}},
+					map[string]any{"terms": map[string][]string{"block_hash": hashes}},
+				}}},
+			},
+			"minimum_should_match": 1,
+		}},
+	}
+	status, body, err = e.do(ctx, http.MethodPost, "/"+e.index+"/_delete_by_query?conflicts=proceed&refresh=true", reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to remove reorged blocks from elasticsearch: %w", err)
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("failed to remove reorged blocks from elasticsearch: %d: %s", status, body)
+	}
+	return nil
+}
+
+func (e *ElasticIndex) IndexTx(ctx context.Context, tx data.Transaction) error {
+	return e.put(ctx, documentID(tx.ChainID, TypeTx, tx.Hash), txDocument(tx))
+}
+
+func (e *ElasticIndex) put(ctx context.Context, id string, doc document) error {
+	status, body, err := e.do(ctx, http.MethodPut, "/"+e.index+"/_doc/"+url.PathEscape(id), doc)
+	if err != nil {
+		return fmt.Errorf("failed to index document: %w", err)
+	}
+	if status != http.StatusOK && status != http.StatusCreated {
+		return fmt.Errorf("failed to index document: %d: %s", status, body)
+	}
+	return nil
+}
+
+func (e *ElasticIndex) Search(ctx context.Context, q Query) (*Result, error) {
+	from, to, err := q.blockRange(func() (uint64, error) { return e.head(ctx, q.ChainID) })
+	if err != nil {
+		return nil, err
+	}
+
+	filters := []any{chainFilter(q.ChainID)}
+	term := func(field, value string) {
+		if value != "" {
+			filters = append(filters, map[string]any{"term": map[string]string{field: strings.ToLower(value)}})
+		}
+	}
+	term("type", q.Type)
+	term("from", q.From)
+	term("to", q.To)
+	term("miner", q.Miner)
+	if q.InputPrefix != "" {
+		filters = append(filters, map[string]any{"prefix": map[string]string{"input": strings.ToLower(q.InputPrefix)}})
+	}
+	if from != nil || to != nil {
+		r := map[string]uint64{}
+		if from != nil {
+			r["gte"] = *from
+		}
+		if to != nil {
+			r["lte"] = *to
+		}
+		filters = append(filters, map[string]any{"range": map[string]any{"block_number": r}})
+	}
+	boolQuery := map[string]any{"filter": filters}
+	if q.Text != "" {
+		boolQuery["must"] = map[string]any{"match": map[string]string{"text": q.Text}}
+	}
+	reqBody := map[string]any{
+		"from":             q.offset(),
+		"size":             q.limit(),
+		"track_total_hits": true,
+		"sort":             []any{map[string]string{"block_number": "desc"}, "_score"},
+		"query":            map[string]any{"bool": boolQuery},
+	}
+
+	status, body, err := e.do(ctx, http.MethodPost, "/"+e.index+"/_search", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search elasticsearch: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to search elasticsearch: %d: %s", status, body)
+	}
+	var resp struct {
+		Hits struct {
+			Total struct {
+				Value uint64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				Score  float64 `json:"_score"`
+				Source struct {
+					Type        string `json:"type"`
+					Hash        string `json:"hash"`
+					BlockNumber uint64 `json:"block_number"`
+				} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode elasticsearch response: %w", err)
+	}
+	result := &Result{Total: resp.Hits.Total.Value, Hits: make([]Hit, 0, len(resp.Hits.Hits))}
+	for _, h := range resp.Hits.Hits {
+		result.Hits = append(result.Hits, Hit{
+			Type:        h.Source.Type,
+			Hash:        h.Source.Hash,
+			BlockNumber: h.Source.BlockNumber,
+			Score:       h.Score,
+		})
+	}
+	return result, nil
+}
+
+// chainFilter matches the documents of chainID.
+func chainFilter(chainID uint64) map[string]any {
+	return map[string]any{"term": map[string]uint64{"chain_id": chainID}}
+}
+
+func (e *ElasticIndex) head(ctx context.Context, chainID uint64) (uint64, error) {
+	reqBody := map[string]any{
+		"size":  0,
+		"query": chainFilter(chainID),
+		"aggs":  map[string]any{"head": map[string]any{"max": map[string]string{"field": "block_number"}}},
+	}
+	status, body, err := e.do(ctx, http.MethodPost, "/"+e.index+"/_search", reqBody)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find head of elasticsearch index: %w", err)
+	}
+	if status != http.StatusOK {
+		return 0, fmt.Errorf("failed to find head of elasticsearch index: %d: %s", status, body)
+	}
+	var resp struct {
+		Aggregations struct {
+			Head struct {
+				Value *float64 `json:"value"`
+			} `json:"head"`
+		} `json:"aggregations"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return 0, fmt.Errorf("failed to decode elasticsearch response: %w", err)
+	}
+	if resp.Aggregations.Head.Value == nil {
+		return 0, nil
+	}
+	return uint64(*resp.Aggregations.Head.Value), nil
+}
+
+func (e *ElasticIndex) do(ctx context.Context, method, path string, body any) (int, []byte, error) {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, e.baseURL+path, reqBody)
+	if err != nil {
+		return 0, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, respBody, nil
+}
+
+func (e *ElasticIndex) Close() error {
+	return nil
+}
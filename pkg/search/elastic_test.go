@@ -0,0 +1,64 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticIndex(t *testing.T) {
+	var requests []string
+	var searches []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/blocks":
+			w.Write([]byte(`{"acknowledged":true}`))
+		case r.URL.Path == "/blocks/_search":
+			var req map[string]any
+			require.NoError(t, json.Unmarshal(body, &req))
+			searches = append(searches, req)
+			if _, ok := req["aggs"]; ok {
+				w.Write([]byte(`{"aggregations":{"head":{"value":1500}}}`))
+				return
+			}
+			w.Write([]byte(`{"hits":{"total":{"value":1},"hits":[{"_score":1.5,"_source":{"type":"transaction","hash":"0x05","block_number":1500}}]}}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	idx, err := NewElasticIndex(server.URL+"/blocks", server.Client())
+	require.NoError(t, err)
+	require.NoError(t, idx.IndexTx(context.Background(), data.Transaction{ChainID: 5, Hash: "0x05", BlockNumber: 1500, To: token}))
+
+	result, err := idx.Search(context.Background(), Query{ChainID: 5, Type: TypeTx, To: token, InputPrefix: "0xa9059cbb", LastBlocks: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Total: 1, Hits: []Hit{{Type: TypeTx, Hash: "0x05", BlockNumber: 1500, Score: 1.5}}}, result)
+
+	assert.Equal(t, []string{
+		"HEAD /blocks",
+		"PUT /blocks",
+		"PUT /blocks/_doc/5:transaction:0x05",
+		"POST /blocks/_search",
+		"POST /blocks/_search",
+	}, requests)
+	assert.Equal(t, map[string]any{"term": map[string]any{"chain_id": float64(5)}}, searches[0]["query"])
+	filters := searches[1]["query"].(map[string]any)["bool"].(map[string]any)["filter"].([]any)
+	assert.Contains(t, filters, map[string]any{"term": map[string]any{"chain_id": float64(5)}})
+	assert.Contains(t, filters, map[string]any{"term": map[string]any{"to": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"}})
+	assert.Contains(t, filters, map[string]any{"prefix": map[string]any{"input": "0xa9059cbb"}})
+	assert.Contains(t, filters, map[string]any{"range": map[string]any{"block_number": map[string]any{"gte": float64(501)}}})
+}
@@ -0,0 +1,175 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	TypeBlock = "block"
+	TypeTx    = "transaction"
+
+	// InputPrefixBytes is how much of a transaction's input is indexed, enough
+	// for the method selector and its first two arguments.
+	InputPrefixBytes = 68
+
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+type Query struct {
+	// ChainID restricts results to the documents of one chain.
+	ChainID     uint64
+	Type        string
+	Text        string
+	From        string
+	To          string
+	Miner       string
+	InputPrefix string
+	FromBlock   *uint64
+	ToBlock     *uint64
+	// LastBlocks restricts results to the given number of blocks below the
+	// highest indexed block.
+	LastBlocks uint64
+	Limit      int
+	Offset     int
+}
+
+type Hit struct {
+	Type        string  `json:"type"`
+	Hash        string  `json:"hash"`
+	BlockNumber uint64  `json:"blockNumber"`
+	Score       float64 `json:"score"`
+}
+
+type Result struct {
+	Total uint64 `json:"total"`
+	Hits  []Hit  `json:"hits"`
+}
+
+type Searcher interface {
+	Search(context.Context, Query) (*Result, error)
+}
+
+type Indexer interface {
+	Searcher
+	// IndexBlock replaces the blocks of the chain indexed at the same height
+	// under another hash, and their transactions, as the block reorged them.
+	IndexBlock(context.Context, data.Block) error
+	IndexTx(context.Context, data.Transaction) error
+	Close() error
+}
+
+// New opens the index at url, http(s) URLs select Elasticsearch and bleve://
+// URLs or plain paths select an embedded Bleve index.
+func New(rawURL string) (Indexer, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search url: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return NewElasticIndex(rawURL, nil)
+	case "bleve":
+		return NewBleveIndex(u.Host + u.Path)
+	case "":
+		return NewBleveIndex(rawURL)
+	default:
+		return nil, fmt.Errorf("unsupported search url scheme: %s", u.Scheme)
+	}
+}
+
+// document is the shape stored by both backends, addresses and hex strings are
+// lowercased so lookups are case insensitive.
+type document map[string]interface{}
+
+func blockDocument(block data.Block) document {
+	return document{
+		"chain_id":     block.ChainID,
+		"type":         TypeBlock,
+		"hash":         strings.ToLower(block.Hash),
+		"block_number": block.Number,
+		"miner":        strings.ToLower(block.Miner),
+		"time":         block.Time,
+		"text":         printable(block.ExtraData),
+	}
+}
+
+func txDocument(tx data.Transaction) document {
+	input := tx.Data
+	if len(input) > InputPrefixBytes {
+		input = input[:InputPrefixBytes]
+	}
+	return document{
+		"chain_id":     tx.ChainID,
+		"type":         TypeTx,
+		"hash":         strings.ToLower(tx.Hash),
+		"block_hash":   strings.ToLower(tx.BlockHash),
+		"block_number": tx.BlockNumber,
+		"from":         strings.ToLower(tx.From),
+		"to":           strings.ToLower(tx.To),
+		"contract":     strings.ToLower(tx.Contract),
+		"input":        hexutil.Encode(input),
+		"status":       tx.Status,
+	}
+}
+
+// documentID is unique across chains, the same hash can be indexed on
+// several of them.
+func documentID(chainID uint64, docType, hash string) string {
+	return fmt.Sprintf("%d:%s:%s", chainID, docType, strings.ToLower(hash))
+}
+
+// blockRange resolves the block bounds of q, head is only called when the
+// query is relative to the highest indexed block.
+func (q Query) blockRange(head func() (uint64, error)) (*uint64, *uint64, error) {
+	from, to := q.FromBlock, q.ToBlock
+	if q.LastBlocks > 0 {
+		h, err := head()
+		if err != nil {
+			return nil, nil, err
+		}
+		start := uint64(0)
+		if h >= q.LastBlocks {
+			start = h - q.LastBlocks + 1
+		}
+		if from == nil || *from < start {
+			from = &start
+		}
+	}
+	return from, to, nil
+}
+
+func (q Query) limit() int {
+	if q.Limit <= 0 {
+		return defaultLimit
+	}
+	if q.Limit > maxLimit {
+		return maxLimit
+	}
+	return q.Limit
+}
+
+func (q Query) offset() int {
+	if q.Offset < 0 {
+		return 0
+	}
+	return q.Offset
+}
+
+func printable(b []byte) string {
+	var sb strings.Builder
+	for _, r := range string(b) {
+		if r >= 0x20 && r != 0x7f && r != 0xfffd {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune(' ')
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
@@ -0,0 +1,106 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexBlockRemovesReorgedBlock(t *testing.T) {
+	idx, err := NewBleveIndex("")
+	require.NoError(t, err)
+	defer idx.Close()
+	ctx := context.Background()
+
+	require.NoError(t, idx.IndexBlock(ctx, data.Block{Hash: "0xa1", Number: 100}))
+	require.NoError(t, idx.IndexBlock(ctx, data.Block{Hash: "0xa2", Number: 101}))
+	require.NoError(t, idx.IndexBlock(ctx, data.Block{ChainID: 5, Hash: "0xc1", Number: 100}))
+	txs := []data.Transaction{
+		{Hash: "0x01", BlockHash: "0xa1", BlockNumber: 100},
+		{Hash: "0x02", BlockHash: "0xa1", BlockNumber: 100},
+		{Hash: "0x03", BlockHash: "0xa2", BlockNumber: 101},
+		{ChainID: 5, Hash: "0x04", BlockHash: "0xc1", BlockNumber: 100},
+		// The replacing block's transactions can be indexed before it, 0x02
+		// was included again.
+		{Hash: "0x02", BlockHash: "0xb1", BlockNumber: 100},
+		{Hash: "0x05", BlockHash: "0xb1", BlockNumber: 100},
+	}
+	for _, tx := range txs {
+		require.NoError(t, idx.IndexTx(ctx, tx))
+	}
+
+	require.NoError(t, idx.IndexBlock(ctx, data.Block{Hash: "0xb1", Number: 100}))
+
+	search := func(q Query) []string {
+		result, err := idx.Search(ctx, q)
+		require.NoError(t, err)
+		hashes := make([]string, 0, len(result.Hits))
+		for _, h := range result.Hits {
+			hashes = append(hashes, h.Hash)
+		}
+		sort.Strings(hashes)
+		return hashes
+	}
+	assert.Equal(t, []string{"0xa2", "0xb1"}, search(Query{Type: TypeBlock}))
+	assert.Equal(t, []string{"0x02", "0x03", "0x05"}, search(Query{Type: TypeTx}))
+	assert.Equal(t, []string{"0x04", "0xc1"}, search(Query{ChainID: 5}))
+}
+
+func TestElasticIndexRemovesReorgedBlock(t *testing.T) {
+	var requests []string
+	var bodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		body, _ := io.ReadAll(r.Body)
+		var req map[string]any
+		if len(body) > 0 {
+			require.NoError(t, json.Unmarshal(body, &req))
+		}
+		bodies = append(bodies, req)
+		switch {
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/blocks/_search":
+			w.Write([]byte(`{"hits":{"hits":[{"_source":{"hash":"0xa1"}}]}}`))
+		default:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	idx, err := NewElasticIndex(server.URL+"/blocks", server.Client())
+	require.NoError(t, err)
+	require.NoError(t, idx.IndexBlock(context.Background(), data.Block{ChainID: 5, Hash: "0xB1", Number: 100}))
+
+	assert.Equal(t, []string{
+		"HEAD /blocks",
+		"PUT /blocks/_mapping",
+		"POST /blocks/_search",
+		"POST /blocks/_delete_by_query?conflicts=proceed&refresh=true",
+		"PUT /blocks/_doc/5:block:0xb1",
+	}, requests)
+	reorged := bodies[2]["query"].(map[string]any)["bool"].(map[string]any)
+	assert.Contains(t, reorged["filter"], map[string]any{"term": map[string]any{"block_number": float64(100)}})
+	assert.Equal(t, map[string]any{"term": map[string]any{"hash": "0xb1"}}, reorged["must_not"])
+	removed := bodies[3]["query"].(map[string]any)["bool"].(map[string]any)
+	assert.Equal(t, []any{map[string]any{"term": map[string]any{"chain_id": float64(5)}}}, removed["filter"])
+	assert.Equal(t, []any{
+		map[string]any{"bool": map[string]any{"filter": []any{
+			map[string]any{"term": map[string]any{"type": TypeBlock}},
+			map[string]any{"terms": map[string]any{"hash": []any{"0xa1"}}},
+		}}},
+		map[string]any{"bool": map[string]any{"filter": []any{
+			map[string]any{"term": map[string]any{"type": TypeTx}},
+			map[string]any{"terms": map[string]any{"block_hash": []any{"0xa1"}}},
+		}}},
+	}, removed["should"])
+}
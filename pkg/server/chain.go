@@ -0,0 +1,64 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/retention"
+)
+
+// ChainConfig configures the indexing of one chain.
+type ChainConfig struct {
+	ChainID uint64 `json:"chainId"`
+	// NodeURL is expanded with the environment, so credentials can be kept
+	// in variables like ${MAINNET_NODE_URL}.
+	NodeURL         string `json:"nodeUrl"`
+	Tracer          string `json:"tracer"`
+	RetentionBlocks uint64 `json:"retentionBlocks"`
+	RetentionDays   uint64 `json:"retentionDays"`
+	// unprefixedTopics is set for the chain of NODE_URL, indexed without
+	// -chains, so it keeps the topics of single chain deployments.
+	unprefixedTopics bool
+}
+
+func (c ChainConfig) retention() retention.Policy {
+	return retention.Policy{Blocks: c.RetentionBlocks, Days: c.RetentionDays}
+}
+
+// LoadChains reads a JSON array of chain configs from path. The first chain
+// is the one served at the API's root.
+func LoadChains(path string) ([]ChainConfig, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open chains config: %w", err)
+	}
+	defer f.Close()
+
+	var chains []ChainConfig
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&chains); err != nil {
+		return nil, fmt.Errorf("failed to decode chains config: %w", err)
+	}
+	if len(chains) == 0 {
+		return nil, errors.New("chains config lists no chains")
+	}
+	seen := map[uint64]bool{}
+	for i := range chains {
+		chain := &chains[i]
+		if chain.ChainID == 0 {
+			return nil, fmt.Errorf("chain %d has no chainId", i)
+		}
+		if seen[chain.ChainID] {
+			return nil, fmt.Errorf("chain %d is listed twice", chain.ChainID)
+		}
+		seen[chain.ChainID] = true
+		chain.NodeURL = os.ExpandEnv(chain.NodeURL)
+		if chain.NodeURL == "" {
+			return nil, fmt.Errorf("chain %d has no nodeUrl", chain.ChainID)
+		}
+	}
+	return chains, nil
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeChains(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "chains.json")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadChains(t *testing.T) {
+	t.Setenv("MAINNET_NODE_URL", "wss://mainnet.example")
+	path := writeChains(t, `[
+		{"chainId": 1, "nodeUrl": "${MAINNET_NODE_URL}", "tracer": "debug", "retentionDays": 30},
+		{"chainId": 137, "nodeUrl": "wss://polygon.example", "retentionBlocks": 1000}
+	]`)
+
+	chains, err := LoadChains(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []ChainConfig{
+		{ChainID: 1, NodeURL: "wss://mainnet.example", Tracer: "debug", RetentionDays: 30},
+		{ChainID: 137, NodeURL: "wss://polygon.example", RetentionBlocks: 1000},
+	}, chains)
+}
+
+func TestLoadChainsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{"empty", `[]`, "chains config lists no chains"},
+		{"missing chain id", `[{"nodeUrl": "wss://node"}]`, "chain 0 has no chainId"},
+		{"duplicate", `[{"chainId": 1, "nodeUrl": "wss://a"}, {"chainId": 1, "nodeUrl": "wss://b"}]`, "chain 1 is listed twice"},
+		{"missing node url", `[{"chainId": 1, "nodeUrl": "${UNSET_NODE_URL}"}]`, "chain 1 has no nodeUrl"},
+		{"unknown field", `[{"chainId": 1, "nodeUrl": "wss://a", "retention": 5}]`, `json: unknown field "retention"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadChains(writeChains(t, tt.content))
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
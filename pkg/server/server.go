@@ -3,8 +3,10 @@ package server
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,55 +14,147 @@ import (
 	"github.com/CaelRowley/geth-indexer-service/pkg/eth"
 	"github.com/CaelRowley/geth-indexer-service/pkg/handlers"
 	"github.com/CaelRowley/geth-indexer-service/pkg/pubsub"
+	"github.com/CaelRowley/geth-indexer-service/pkg/retention"
 	"github.com/CaelRowley/geth-indexer-service/pkg/router"
+	"github.com/CaelRowley/geth-indexer-service/pkg/search"
+	"github.com/CaelRowley/geth-indexer-service/pkg/stats"
 	"golang.org/x/exp/slog"
 )
 
 type ServerConfig struct {
-	Sync bool
-	Port string
+	Sync          bool
+	Port          string
+	DB            db.Config
+	Retention     retention.Policy
+	PruneInterval time.Duration
+	StatsInterval time.Duration
+	Tracer        string
+	// Chains are the indexed chains. Without them the chain of NODE_URL is
+	// indexed with Retention and Tracer.
+	Chains []ChainConfig
 }
 
 type Server struct {
-	router    http.Handler
-	dbConn    db.DB
-	ethClient eth.Client
-	pubsub    pubsub.PubSub
-	sync      bool
-	port      string
+	router      http.Handler
+	dbConn      db.DB
+	chains      []*chain
+	searchIndex search.Indexer
+	sync        bool
+	port        string
+}
+
+// chain holds the instances indexing one chain.
+type chain struct {
+	id               uint64
+	dbConn           db.DB
+	ethClient        eth.Client
+	pubsub           pubsub.PubSub
+	searchSubscriber pubsub.Subscriber
+	pruner           *retention.Pruner
+	statsRoller      *stats.Roller
 }
 
 func New(cfg ServerConfig) (*Server, error) {
-	dbConn, err := db.NewConnection(os.Getenv("DB_URL"))
+	if len(cfg.Chains) == 0 {
+		nodeURL := os.Getenv("NODE_URL")
+		chainID, err := eth.NodeChainID(nodeURL)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Chains = []ChainConfig{{
+			ChainID:          chainID,
+			NodeURL:          nodeURL,
+			Tracer:           cfg.Tracer,
+			RetentionBlocks:  cfg.Retention.Blocks,
+			RetentionDays:    cfg.Retention.Days,
+			unprefixedTopics: true,
+		}}
+	}
+	if urls := os.Getenv("DB_REPLICA_URLS"); urls != "" {
+		cfg.DB.ReplicaURLs = strings.Split(urls, ",")
+	}
+	dbConn, err := db.NewConnection(os.Getenv("DB_URL"), cfg.DB)
 	if err != nil {
 		return nil, err
 	}
-	pubsubClient, err := pubsub.NewPubSub(os.Getenv("MSG_BROKER_URL"), dbConn)
+	if cfg.Sync {
+		// Rows synced under a chain id would collide with the chain 0 rows
+		// when these are assigned to it later.
+		tables, err := dbConn.UnassignedTables(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		if len(tables) > 0 {
+			return nil, fmt.Errorf("%s hold rows indexed before migration 15, run `migrate assign-chain <chainId>` before syncing", strings.Join(tables, ", "))
+		}
+	}
+	var searchIndex search.Indexer
+	if searchURL := os.Getenv("SEARCH_URL"); searchURL != "" {
+		searchIndex, err = search.New(searchURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s := &Server{
+		dbConn:      dbConn,
+		searchIndex: searchIndex,
+		sync:        cfg.Sync,
+		port:        cfg.Port,
+	}
+	var routes []handlers.Chain
+	for _, chainCfg := range cfg.Chains {
+		c, err := newChain(cfg, chainCfg, dbConn.ForChain(chainCfg.ChainID), searchIndex)
+		if err != nil {
+			return nil, fmt.Errorf("chain %d: %w", chainCfg.ChainID, err)
+		}
+		s.chains = append(s.chains, c)
+		routes = append(routes, handlers.Chain{ID: c.id, DB: c.dbConn, Pruner: c.pruner})
+	}
+	router := router.NewRouter()
+	handlers.Init(routes, searchIndex, os.Getenv("ADMIN_TOKEN"), router)
+	s.router = router
+	return s, nil
+}
+
+func newChain(cfg ServerConfig, chainCfg ChainConfig, dbConn db.DB, searchIndex search.Indexer) (*chain, error) {
+	topics := pubsub.Chain{ID: chainCfg.ChainID, Unprefixed: chainCfg.unprefixedTopics}
+	pubsubClient, err := pubsub.NewPubSub(os.Getenv("MSG_BROKER_URL"), topics, dbConn)
 	if err != nil {
 		return nil, err
 	}
-	ethClient, err := eth.NewClient(os.Getenv("NODE_URL"), pubsubClient)
+	ethClient, err := eth.NewClient(chainCfg.NodeURL, chainCfg.ChainID, pubsubClient, chainCfg.retention(), chainCfg.Tracer)
 	if err != nil {
 		return nil, err
 	}
-	router := router.NewRouter()
-	handlers.Init(dbConn, router)
-
-	s := &Server{
-		router:    router,
-		dbConn:    dbConn,
-		ethClient: ethClient,
-		pubsub:    pubsubClient,
-		sync:      cfg.Sync,
-		port:      cfg.Port,
+	var searchSubscriber pubsub.Subscriber
+	if searchIndex != nil {
+		searchSubscriber, err = pubsub.NewSearchSubscriber(os.Getenv("MSG_BROKER_URL"), topics, searchIndex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kafka search consumer: %w", err)
+		}
 	}
-	return s, nil
+	var pruner *retention.Pruner
+	if chainCfg.retention().Enabled() {
+		pruner = retention.NewPruner(dbConn.Primary(), chainCfg.retention(), cfg.PruneInterval)
+	}
+	return &chain{
+		id:               chainCfg.ChainID,
+		dbConn:           dbConn,
+		ethClient:        ethClient,
+		pubsub:           pubsubClient,
+		searchSubscriber: searchSubscriber,
+		pruner:           pruner,
+		statsRoller:      stats.NewRoller(dbConn.Primary(), cfg.StatsInterval),
+	}, nil
 }
 
 func (s *Server) Start(ctx context.Context) error {
 	httpServer := &http.Server{
 		Addr:    ":" + s.port,
 		Handler: s.router,
+		// Request contexts derive from ctx so shutdown cancels in-flight queries.
+		BaseContext: func(net.Listener) context.Context { return ctx },
 	}
 	errCh := make(chan error)
 	var wg sync.WaitGroup
@@ -73,36 +167,20 @@ func (s *Server) Start(ctx context.Context) error {
 	}()
 
 	if s.sync {
-		slog.Info("starting sync with eth node...")
-		go func() {
-			wg.Add(1)
-			defer wg.Done()
-			if err := s.ethClient.StartListener(ctx, s.dbConn); err != nil {
-				errCh <- fmt.Errorf("listener failed: %w", err)
-			}
-		}()
-		go func() {
-			wg.Add(1)
-			defer wg.Done()
-			if err := s.ethClient.StartSyncer(ctx, s.dbConn); err != nil {
-				errCh <- fmt.Errorf("syncer failed: %w", err)
-			}
-		}()
-		go func() {
-			wg.Add(1)
-			defer wg.Done()
-			if err := s.pubsub.GetSubscriber().StartPoll(ctx); err != nil {
-				errCh <- fmt.Errorf("consumer failed: %w", err)
-			}
-		}()
-		go s.pubsub.GetPublisher().StartEventHandler()
+		for _, c := range s.chains {
+			c.start(ctx, &wg, errCh)
+		}
 	}
 
 	defer func() {
-		if err := s.pubsub.Close(); err != nil {
-			slog.Error("failed to close pubsub", "err", err)
+		for _, c := range s.chains {
+			c.close()
+		}
+		if s.searchIndex != nil {
+			if err := s.searchIndex.Close(); err != nil {
+				slog.Error("failed to close search index", "err", err)
+			}
 		}
-		s.ethClient.Close()
 		if err := s.dbConn.Close(); err != nil {
 			slog.Error("failed to close db", "err", err)
 		}
@@ -118,3 +196,67 @@ func (s *Server) Start(ctx context.Context) error {
 		return httpServer.Shutdown(timeoutCtx)
 	}
 }
+
+// start starts syncing c, its goroutines are added to wg.
+func (c *chain) start(ctx context.Context, wg *sync.WaitGroup, errCh chan<- error) {
+	slog.Info("starting sync with eth node...", "chain", c.id)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := c.ethClient.StartListener(ctx, c.dbConn); err != nil {
+			errCh <- fmt.Errorf("chain %d listener failed: %w", c.id, err)
+		}
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := c.ethClient.StartSyncer(ctx, c.dbConn.Primary()); err != nil {
+			errCh <- fmt.Errorf("chain %d syncer failed: %w", c.id, err)
+		}
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := c.pubsub.GetSubscriber().StartPoll(ctx); err != nil {
+			errCh <- fmt.Errorf("chain %d consumer failed: %w", c.id, err)
+		}
+	}()
+	if c.searchSubscriber != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.searchSubscriber.StartPoll(ctx); err != nil {
+				errCh <- fmt.Errorf("chain %d search consumer failed: %w", c.id, err)
+			}
+		}()
+	}
+	if c.pruner != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.pruner.Start(ctx); err != nil {
+				errCh <- fmt.Errorf("chain %d pruner failed: %w", c.id, err)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := c.statsRoller.Start(ctx); err != nil {
+			errCh <- fmt.Errorf("chain %d stats roller failed: %w", c.id, err)
+		}
+	}()
+	go c.pubsub.GetPublisher().StartEventHandler()
+}
+
+func (c *chain) close() {
+	if err := c.pubsub.Close(); err != nil {
+		slog.Error("failed to close pubsub", "chain", c.id, "err", err)
+	}
+	c.ethClient.Close()
+	if c.searchSubscriber != nil {
+		if err := c.searchSubscriber.Close(); err != nil {
+			slog.Error("failed to close search consumer", "chain", c.id, "err", err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+const (
+	DefaultInterval = time.Minute
+	// BatchSize is the number of dirty buckets read per query.
+	BatchSize = 100
+)
+
+// Roller keeps the hourly and daily rollups up to date. Storing a block or
+// transaction marks the hour of its block dirty, each roll recomputes the
+// dirty hours and then the days holding them.
+type Roller struct {
+	dbConn   db.DB
+	interval time.Duration
+}
+
+func NewRoller(dbConn db.DB, interval time.Duration) *Roller {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Roller{
+		dbConn:   dbConn,
+		interval: interval,
+	}
+}
+
+// Roll recomputes every dirty hour, then every dirty day, and returns the
+// number of buckets rolled up.
+func (r *Roller) Roll(ctx context.Context) (int, error) {
+	var total int
+	for _, period := range []db.StatsPeriod{db.StatsHourly, db.StatsDaily} {
+		rolled, err := r.rollPeriod(ctx, period)
+		total += rolled
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func (r *Roller) rollPeriod(ctx context.Context, period db.StatsPeriod) (int, error) {
+	var rolled int
+	for {
+		buckets, err := r.dbConn.GetDirtyStats(ctx, period, BatchSize)
+		if err != nil {
+			return rolled, fmt.Errorf("failed to get dirty %s stats: %w", period, err)
+		}
+		for _, start := range buckets {
+			if err := ctx.Err(); err != nil {
+				return rolled, nil
+			}
+			if err := r.dbConn.RollupStats(ctx, period, start); err != nil {
+				return rolled, fmt.Errorf("failed to roll up %s stats at %d: %w", period, start, err)
+			}
+			rolled++
+		}
+		if len(buckets) < BatchSize {
+			return rolled, nil
+		}
+	}
+}
+
+func (r *Roller) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		rolled, err := r.Roll(ctx)
+		if err != nil {
+			slog.Error("stats rollup failed", "err", err)
+		} else if rolled > 0 {
+			slog.Debug("rolled up stats", "buckets", rolled)
+		}
+		select {
+		case <-ctx.Done():
+			slog.Info("stats roller stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CaelRowley/geth-indexer-service/pkg/db"
+)
+
+type MockDB struct {
+	db.DB
+	mock.Mock
+}
+
+func (m *MockDB) GetDirtyStats(_ context.Context, period db.StatsPeriod, limit int) ([]uint64, error) {
+	args := m.Called(period, limit)
+	return args.Get(0).([]uint64), args.Error(1)
+}
+
+func (m *MockDB) RollupStats(_ context.Context, period db.StatsPeriod, start uint64) error {
+	args := m.Called(period, start)
+	return args.Error(0)
+}
+
+func TestRoll(t *testing.T) {
+	full := make([]uint64, BatchSize)
+	for i := range full {
+		full[i] = uint64(i) * 3600
+	}
+
+	dbMock := new(MockDB)
+	dbMock.On("GetDirtyStats", db.StatsHourly, BatchSize).Return(full, nil).Once()
+	dbMock.On("GetDirtyStats", db.StatsHourly, BatchSize).Return([]uint64{360000}, nil).Once()
+	dbMock.On("GetDirtyStats", db.StatsDaily, BatchSize).Return([]uint64{0, 86400, 172800, 259200, 345600}, nil).Once()
+	dbMock.On("RollupStats", mock.Anything, mock.Anything).Return(nil)
+
+	rolled, err := NewRoller(dbMock, 0).Roll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, BatchSize+1+5, rolled)
+	dbMock.AssertExpectations(t)
+	dbMock.AssertNumberOfCalls(t, "RollupStats", BatchSize+1+5)
+}
+
+func TestRollStopsOnError(t *testing.T) {
+	dbMock := new(MockDB)
+	dbMock.On("GetDirtyStats", db.StatsHourly, BatchSize).Return([]uint64{0, 3600}, nil)
+	dbMock.On("RollupStats", db.StatsHourly, uint64(0)).Return(errors.New("boom"))
+
+	rolled, err := NewRoller(dbMock, 0).Roll(context.Background())
+	assert.Error(t, err)
+	assert.Zero(t, rolled)
+	dbMock.AssertNotCalled(t, "RollupStats", db.StatsHourly, uint64(3600))
+	dbMock.AssertNotCalled(t, "GetDirtyStats", db.StatsDaily, BatchSize)
+}